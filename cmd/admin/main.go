@@ -0,0 +1,325 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/mailsuppression"
+	"github.com/mikestefanello/pagoda/ent/user"
+	"github.com/mikestefanello/pagoda/pkg/services"
+)
+
+// command is an administrative command which can be executed from the command line
+type command struct {
+	// description describes what the command does
+	description string
+
+	// run executes the command with the provided arguments
+	run func(c *services.Container, args []string) error
+}
+
+// commands stores all available commands keyed by name
+var commands = map[string]command{
+	"unlock": {
+		description: "Unlock a user account that was locked due to failed login attempts",
+		run:         unlock,
+	},
+	"seed-roles": {
+		description: "Create the default roles and permissions",
+		run:         seedRoles,
+	},
+	"assign-role": {
+		description: "Assign a role to a user",
+		run:         assignRole,
+	},
+	"revoke-role": {
+		description: "Revoke a role from a user",
+		run:         revokeRole,
+	},
+	"create-invite-code": {
+		description: "Create an invite code, which is required to register when registration is invite-only",
+		run:         createInviteCode,
+	},
+	"suppress-email": {
+		description: "Stop sending email to an address which bounced or complained",
+		run:         suppressEmail,
+	},
+	"unsuppress-email": {
+		description: "Remove every suppression of an address, so email is sent to it again",
+		run:         unsuppressEmail,
+	},
+	"generate-dkim-key": {
+		description: "Generate a key to sign email with DKIM and print the DNS record to publish",
+		run:         generateDKIMKey,
+	},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(1)
+	}
+
+	// Start a new container
+	c := services.NewContainer()
+	defer func() {
+		if err := c.Shutdown(); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	if err := cmd.run(c, os.Args[2:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// usage prints the available commands
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("Usage: admin <command> [flags]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	for _, name := range names {
+		fmt.Printf("  %-20s %s\n", name, commands[name].description)
+	}
+}
+
+// unlock unlocks a user account
+func unlock(c *services.Container, args []string) error {
+	fs := flag.NewFlagSet("unlock", flag.ExitOnError)
+	email := fs.String("email", "", "The email address of the user")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *email == "" {
+		return fmt.Errorf("an email address is required")
+	}
+
+	ctx := context.Background()
+	u, err := loadUser(ctx, c, *email)
+	if err != nil {
+		return err
+	}
+
+	if err = c.Auth.UnlockUser(ctx, u.ID); err != nil {
+		return fmt.Errorf("unable to unlock user: %w", err)
+	}
+
+	fmt.Printf("Unlocked %s\n", u.Email)
+	return nil
+}
+
+// seedRoles creates the default roles and permissions
+func seedRoles(c *services.Container, args []string) error {
+	if err := c.Authz.Seed(context.Background()); err != nil {
+		return fmt.Errorf("unable to seed roles: %w", err)
+	}
+
+	fmt.Println("Seeded roles and permissions")
+	return nil
+}
+
+// assignRole assigns a role to a user
+func assignRole(c *services.Container, args []string) error {
+	return changeRole(c, "assign-role", args, c.Authz.AssignRole)
+}
+
+// revokeRole revokes a role from a user
+func revokeRole(c *services.Container, args []string) error {
+	return changeRole(c, "revoke-role", args, c.Authz.RevokeRole)
+}
+
+// changeRole parses the user and role flags and applies a given change to the user's roles
+func changeRole(
+	c *services.Container,
+	name string,
+	args []string,
+	change func(ctx context.Context, userID int, role string) error,
+) error {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	email := fs.String("email", "", "The email address of the user")
+	role := fs.String("role", "", "The name of the role")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *email == "" || *role == "" {
+		return fmt.Errorf("an email address and role are required")
+	}
+
+	ctx := context.Background()
+	u, err := loadUser(ctx, c, *email)
+	if err != nil {
+		return err
+	}
+
+	if err = change(ctx, u.ID, *role); err != nil {
+		return fmt.Errorf("unable to change role: %w", err)
+	}
+
+	fmt.Printf("Updated role %s for %s\n", *role, u.Email)
+	return nil
+}
+
+// createInviteCode creates an invite code and prints it, since it cannot be retrieved later
+func createInviteCode(c *services.Container, args []string) error {
+	fs := flag.NewFlagSet("create-invite-code", flag.ExitOnError)
+	uses := fs.Int("uses", 1, "The amount of times the code can be used, or 0 for unlimited")
+	expires := fs.Duration("expires", 0, "How long until the code expires, ie: 72h, or 0 to never expire")
+	note := fs.String("note", "", "A note describing who or what the code is for")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *uses < 0 || *expires < 0 {
+		return fmt.Errorf("uses and expires cannot be negative")
+	}
+
+	params := services.InviteCodeParams{
+		MaxUses: *uses,
+		Note:    *note,
+	}
+
+	if *expires > 0 {
+		t := time.Now().Add(*expires)
+		params.ExpiresAt = &t
+	}
+
+	code, _, err := c.Registration.CreateInviteCode(context.Background(), params)
+	if err != nil {
+		return fmt.Errorf("unable to create invite code: %w", err)
+	}
+
+	fmt.Printf("Created invite code %s\n", code)
+	return nil
+}
+
+// suppressEmail adds an address to the suppression list
+func suppressEmail(c *services.Container, args []string) error {
+	fs := flag.NewFlagSet("suppress-email", flag.ExitOnError)
+	email := fs.String("email", "", "The email address to suppress")
+	reason := fs.String("reason", "bounced", "Why the address is suppressed, either bounced or complained")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *email == "" {
+		return fmt.Errorf("an email address is required")
+	}
+
+	r := mailsuppression.Reason(*reason)
+	if r != mailsuppression.ReasonBounced && r != mailsuppression.ReasonComplained {
+		return fmt.Errorf("invalid reason: %q", *reason)
+	}
+
+	if err := c.MailSuppression.Suppress(context.Background(), *email, r); err != nil {
+		return fmt.Errorf("unable to suppress email address: %w", err)
+	}
+
+	fmt.Printf("Suppressed %s\n", strings.ToLower(*email))
+	return nil
+}
+
+// unsuppressEmail removes an address from the suppression list
+func unsuppressEmail(c *services.Container, args []string) error {
+	fs := flag.NewFlagSet("unsuppress-email", flag.ExitOnError)
+	email := fs.String("email", "", "The email address to unsuppress")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *email == "" {
+		return fmt.Errorf("an email address is required")
+	}
+
+	n, err := c.MailSuppression.Remove(context.Background(), *email)
+	if err != nil {
+		return fmt.Errorf("unable to unsuppress email address: %w", err)
+	}
+
+	fmt.Printf("Removed %d suppressions of %s\n", n, strings.ToLower(*email))
+	return nil
+}
+
+// generateDKIMKey generates a private key to sign email with and prints the DNS TXT record which publishes the
+// public key
+func generateDKIMKey(c *services.Container, args []string) error {
+	fs := flag.NewFlagSet("generate-dkim-key", flag.ExitOnError)
+	domain := fs.String("domain", c.Config.Mail.DKIM.Domain, "The domain email is sent from")
+	selector := fs.String("selector", c.Config.Mail.DKIM.Selector, "The selector which identifies the key")
+	keyType := fs.String("type", "rsa", "The type of key, either rsa or ed25519")
+	bits := fs.Int("bits", 2048, "The size of rsa keys")
+	out := fs.String("out", "", "The file to write the private key to, rather than printing it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *domain == "" || *selector == "" {
+		return fmt.Errorf("a domain and selector are required")
+	}
+
+	key, pem, err := services.GenerateDKIMKey(*keyType, *bits)
+	if err != nil {
+		return fmt.Errorf("unable to generate key: %w", err)
+	}
+
+	record, err := services.DKIMRecord(key)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		fmt.Printf("Private key, to be set in configuration at Mail.DKIM.PrivateKey:\n\n%s\n", pem)
+	} else {
+		if err = os.WriteFile(*out, pem, 0o600); err != nil {
+			return fmt.Errorf("unable to write private key: %w", err)
+		}
+		fmt.Printf("Wrote the private key to %s, to be set in configuration at Mail.DKIM.PrivateKeyFile\n\n", *out)
+	}
+
+	// DNS TXT records are made of strings of up to 255 characters, which are joined when the record is read
+	var parts []string
+	for len(record) > 255 {
+		parts = append(parts, strconv.Quote(record[:255]))
+		record = record[255:]
+	}
+	parts = append(parts, strconv.Quote(record))
+
+	fmt.Println("Publish this DNS TXT record:")
+	fmt.Println()
+	fmt.Printf("%s._domainkey.%s. IN TXT ( %s )\n", *selector, strings.ToLower(*domain), strings.Join(parts, " "))
+	return nil
+}
+
+// loadUser loads a user by email address
+func loadUser(ctx context.Context, c *services.Container, email string) (*ent.User, error) {
+	u, err := c.ORM.User.
+		Query().
+		Where(user.Email(strings.ToLower(email))).
+		Only(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load user: %w", err)
+	}
+
+	return u, nil
+}
@@ -61,7 +61,12 @@ func main() {
 	tasks.Register(c)
 
 	// Start the task runner to execute queued tasks
-	c.Tasks.Start(context.Background())
+	tasksCtx, stopTasks := context.WithCancel(context.Background())
+	defer stopTasks()
+	c.Tasks.Start(tasksCtx)
+
+	// Queue recurring tasks, such as audit log retention
+	tasks.Schedule(tasksCtx, c)
 
 	// Wait for interrupt signal to gracefully shut down the server with a timeout of 10 seconds.
 	quit := make(chan os.Signal, 1)
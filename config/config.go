@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -41,6 +42,51 @@ const (
 	EnvProduction environment = "prod"
 )
 
+// RegistrationMode determines who is allowed to register an account
+type RegistrationMode string
+
+const (
+	// RegistrationModeOpen allows anyone to register
+	RegistrationModeOpen RegistrationMode = "open"
+
+	// RegistrationModeInvite only allows registering with a valid invite code
+	RegistrationModeInvite RegistrationMode = "invite"
+
+	// RegistrationModeClosed does not allow anyone to register
+	RegistrationModeClosed RegistrationMode = "closed"
+)
+
+// MailEncryption is how connections to the SMTP server are encrypted
+type MailEncryption string
+
+const (
+	// MailEncryptionSTARTTLS upgrades connections with STARTTLS, which the server is required to support
+	MailEncryptionSTARTTLS MailEncryption = "starttls"
+
+	// MailEncryptionTLS uses TLS from the start of the connection, which is usually done on port 465
+	MailEncryptionTLS MailEncryption = "tls"
+
+	// MailEncryptionNone does not encrypt connections, which should only be used for local servers
+	MailEncryptionNone MailEncryption = "none"
+)
+
+// MailTransportType is how email is delivered
+type MailTransportType string
+
+const (
+	// MailTransportSMTP delivers email to the configured SMTP server
+	MailTransportSMTP MailTransportType = "smtp"
+
+	// MailTransportSendmail delivers email by piping it to the sendmail binary
+	MailTransportSendmail MailTransportType = "sendmail"
+
+	// MailTransportFile writes email as .eml files to a directory rather than delivering it
+	MailTransportFile MailTransportType = "file"
+
+	// MailTransportMemory captures email in memory rather than delivering it
+	MailTransportMemory MailTransportType = "memory"
+)
+
 // SwitchEnvironment sets the environment variable used to dictate which environment the application is
 // currently running in.
 // This must be called prior to loading the configuration in order for it to take effect.
@@ -77,15 +123,114 @@ type (
 
 	// AppConfig stores application configuration
 	AppConfig struct {
-		Name          string
-		Environment   environment
-		EncryptionKey string
-		Timeout       time.Duration
+		Name        string
+		Environment environment
+		URL         string
+		Keyring     KeyringConfig
+		Timeout     time.Duration
+		Session     struct {
+			IdleTimeout     time.Duration
+			AbsoluteTimeout time.Duration
+		}
+		Registration struct {
+			Mode           RegistrationMode
+			AllowedDomains []string
+		}
 		PasswordToken struct {
 			Expiration time.Duration
 			Length     int
 		}
 		EmailVerificationTokenExpiration time.Duration
+		LoginToken                       struct {
+			Expiration time.Duration
+			Length     int
+		}
+		LoginThrottle struct {
+			Window          time.Duration
+			FreeAttempts    int
+			BaseDelay       time.Duration
+			MaxDelay        time.Duration
+			MaxIPAttempts   int
+			LockoutAttempts int
+			LockoutDuration time.Duration
+		}
+		ForgotPasswordThrottle     EmailThrottleConfig
+		LoginLinkThrottle          EmailThrottleConfig
+		VerificationResendThrottle struct {
+			Window      time.Duration
+			MaxAttempts int
+		}
+		DataExport struct {
+			Expiration time.Duration
+		}
+		AccountDeletion struct {
+			GracePeriod time.Duration
+			Mode        string
+		}
+		AuditLog struct {
+			Retention time.Duration
+		}
+		Invitation struct {
+			Expiration time.Duration
+			Length     int
+		}
+		SecurityNotification struct {
+			RevokeTokenExpiration time.Duration
+		}
+		Contact struct {
+			Address     string
+			Departments map[string]string
+		}
+		BotProtection struct {
+			MinFillTime time.Duration
+			MaxAge      time.Duration
+			ProofOfWork bool
+			Difficulty  int
+		}
+		PasswordPolicy struct {
+			MinLength    int
+			MaxLength    int
+			MinStrength  int
+			BreachedList string
+		}
+		PasswordHashing struct {
+			Algorithm string
+			Argon2id  struct {
+				Memory      uint32
+				Iterations  uint32
+				Parallelism uint8
+				SaltLength  uint32
+				KeyLength   uint32
+			}
+			Bcrypt struct {
+				Cost int
+			}
+		}
+	}
+
+	// KeyringConfig stores the keys used to sign and encrypt data, such as sessions and tokens.
+	// The primary key is used to sign and encrypt, while data signed or encrypted with a retired key can still be
+	// verified and decrypted, so keys can be rotated without invalidating existing data.
+	// Legacy is the encryption key which was used directly before the keyring existed. It is never used to sign or
+	// encrypt, only to verify data issued before upgrading, and can be removed once all of that data has expired.
+	KeyringConfig struct {
+		Primary KeyConfig
+		Retired []KeyConfig
+		Legacy  string
+	}
+
+	// KeyConfig stores a secret key along with the ID used to identify it
+	KeyConfig struct {
+		ID     string
+		Secret string
+	}
+
+	// EmailThrottleConfig stores the limits of requests which send an email to a given address, per email and
+	// IP address
+	EmailThrottleConfig struct {
+		Window           time.Duration
+		MaxEmailAttempts int
+		MaxIPAttempts    int
 	}
 
 	// CacheConfig stores the cache configuration
@@ -113,11 +258,31 @@ type (
 
 	// MailConfig stores the mail configuration
 	MailConfig struct {
+		Transport   MailTransportType
 		Hostname    string
 		Port        uint16
 		User        string
 		Password    string
 		FromAddress string
+		Encryption  MailEncryption
+		Timeout     time.Duration
+		KeepAlive   time.Duration
+		Sendmail    struct {
+			Path string
+			Args []string
+		}
+		File struct {
+			Directory string
+		}
+		Memory struct {
+			Capacity int
+		}
+		DKIM struct {
+			Domain         string
+			Selector       string
+			PrivateKey     string
+			PrivateKeyFile string
+		}
 	}
 )
 
@@ -146,5 +311,20 @@ func GetConfig() (Config, error) {
 		return c, err
 	}
 
+	// Fall back to the encryption key which was used before the keyring existed, so deployments which still set it
+	// keep verifying the sessions and tokens issued before upgrading
+	if c.App.Keyring.Legacy == "" {
+		c.App.Keyring.Legacy = viper.GetString("app.encryptionKey")
+	}
+
+	// Resolve relative file paths from the directory of the config file so they work regardless of the
+	// working directory, such as within tests
+	if p := c.App.PasswordPolicy.BreachedList; p != "" && !filepath.IsAbs(p) {
+		c.App.PasswordPolicy.BreachedList = filepath.Join(filepath.Dir(viper.ConfigFileUsed()), p)
+	}
+	if p := c.Mail.DKIM.PrivateKeyFile; p != "" && !filepath.IsAbs(p) {
+		c.Mail.DKIM.PrivateKeyFile = filepath.Join(filepath.Dir(viper.ConfigFileUsed()), p)
+	}
+
 	return c, nil
 }
@@ -0,0 +1,327 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/device"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+// DeviceCreate is the builder for creating a Device entity.
+type DeviceCreate struct {
+	config
+	mutation *DeviceMutation
+	hooks    []Hook
+}
+
+// SetHash sets the "hash" field.
+func (dc *DeviceCreate) SetHash(s string) *DeviceCreate {
+	dc.mutation.SetHash(s)
+	return dc
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (dc *DeviceCreate) SetUserAgent(s string) *DeviceCreate {
+	dc.mutation.SetUserAgent(s)
+	return dc
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (dc *DeviceCreate) SetNillableUserAgent(s *string) *DeviceCreate {
+	if s != nil {
+		dc.SetUserAgent(*s)
+	}
+	return dc
+}
+
+// SetIPAddress sets the "ip_address" field.
+func (dc *DeviceCreate) SetIPAddress(s string) *DeviceCreate {
+	dc.mutation.SetIPAddress(s)
+	return dc
+}
+
+// SetNillableIPAddress sets the "ip_address" field if the given value is not nil.
+func (dc *DeviceCreate) SetNillableIPAddress(s *string) *DeviceCreate {
+	if s != nil {
+		dc.SetIPAddress(*s)
+	}
+	return dc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (dc *DeviceCreate) SetCreatedAt(t time.Time) *DeviceCreate {
+	dc.mutation.SetCreatedAt(t)
+	return dc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (dc *DeviceCreate) SetNillableCreatedAt(t *time.Time) *DeviceCreate {
+	if t != nil {
+		dc.SetCreatedAt(*t)
+	}
+	return dc
+}
+
+// SetLastLoginAt sets the "last_login_at" field.
+func (dc *DeviceCreate) SetLastLoginAt(t time.Time) *DeviceCreate {
+	dc.mutation.SetLastLoginAt(t)
+	return dc
+}
+
+// SetNillableLastLoginAt sets the "last_login_at" field if the given value is not nil.
+func (dc *DeviceCreate) SetNillableLastLoginAt(t *time.Time) *DeviceCreate {
+	if t != nil {
+		dc.SetLastLoginAt(*t)
+	}
+	return dc
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (dc *DeviceCreate) SetUserID(id int) *DeviceCreate {
+	dc.mutation.SetUserID(id)
+	return dc
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (dc *DeviceCreate) SetUser(u *User) *DeviceCreate {
+	return dc.SetUserID(u.ID)
+}
+
+// Mutation returns the DeviceMutation object of the builder.
+func (dc *DeviceCreate) Mutation() *DeviceMutation {
+	return dc.mutation
+}
+
+// Save creates the Device in the database.
+func (dc *DeviceCreate) Save(ctx context.Context) (*Device, error) {
+	dc.defaults()
+	return withHooks(ctx, dc.sqlSave, dc.mutation, dc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (dc *DeviceCreate) SaveX(ctx context.Context) *Device {
+	v, err := dc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (dc *DeviceCreate) Exec(ctx context.Context) error {
+	_, err := dc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dc *DeviceCreate) ExecX(ctx context.Context) {
+	if err := dc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (dc *DeviceCreate) defaults() {
+	if _, ok := dc.mutation.UserAgent(); !ok {
+		v := device.DefaultUserAgent
+		dc.mutation.SetUserAgent(v)
+	}
+	if _, ok := dc.mutation.IPAddress(); !ok {
+		v := device.DefaultIPAddress
+		dc.mutation.SetIPAddress(v)
+	}
+	if _, ok := dc.mutation.CreatedAt(); !ok {
+		v := device.DefaultCreatedAt()
+		dc.mutation.SetCreatedAt(v)
+	}
+	if _, ok := dc.mutation.LastLoginAt(); !ok {
+		v := device.DefaultLastLoginAt()
+		dc.mutation.SetLastLoginAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (dc *DeviceCreate) check() error {
+	if _, ok := dc.mutation.Hash(); !ok {
+		return &ValidationError{Name: "hash", err: errors.New(`ent: missing required field "Device.hash"`)}
+	}
+	if v, ok := dc.mutation.Hash(); ok {
+		if err := device.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "Device.hash": %w`, err)}
+		}
+	}
+	if _, ok := dc.mutation.UserAgent(); !ok {
+		return &ValidationError{Name: "user_agent", err: errors.New(`ent: missing required field "Device.user_agent"`)}
+	}
+	if _, ok := dc.mutation.IPAddress(); !ok {
+		return &ValidationError{Name: "ip_address", err: errors.New(`ent: missing required field "Device.ip_address"`)}
+	}
+	if _, ok := dc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "Device.created_at"`)}
+	}
+	if _, ok := dc.mutation.LastLoginAt(); !ok {
+		return &ValidationError{Name: "last_login_at", err: errors.New(`ent: missing required field "Device.last_login_at"`)}
+	}
+	if _, ok := dc.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "Device.user"`)}
+	}
+	return nil
+}
+
+func (dc *DeviceCreate) sqlSave(ctx context.Context) (*Device, error) {
+	if err := dc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := dc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, dc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	dc.mutation.id = &_node.ID
+	dc.mutation.done = true
+	return _node, nil
+}
+
+func (dc *DeviceCreate) createSpec() (*Device, *sqlgraph.CreateSpec) {
+	var (
+		_node = &Device{config: dc.config}
+		_spec = sqlgraph.NewCreateSpec(device.Table, sqlgraph.NewFieldSpec(device.FieldID, field.TypeInt))
+	)
+	if value, ok := dc.mutation.Hash(); ok {
+		_spec.SetField(device.FieldHash, field.TypeString, value)
+		_node.Hash = value
+	}
+	if value, ok := dc.mutation.UserAgent(); ok {
+		_spec.SetField(device.FieldUserAgent, field.TypeString, value)
+		_node.UserAgent = value
+	}
+	if value, ok := dc.mutation.IPAddress(); ok {
+		_spec.SetField(device.FieldIPAddress, field.TypeString, value)
+		_node.IPAddress = value
+	}
+	if value, ok := dc.mutation.CreatedAt(); ok {
+		_spec.SetField(device.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := dc.mutation.LastLoginAt(); ok {
+		_spec.SetField(device.FieldLastLoginAt, field.TypeTime, value)
+		_node.LastLoginAt = value
+	}
+	if nodes := dc.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   device.UserTable,
+			Columns: []string{device.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.device_user = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// DeviceCreateBulk is the builder for creating many Device entities in bulk.
+type DeviceCreateBulk struct {
+	config
+	err      error
+	builders []*DeviceCreate
+}
+
+// Save creates the Device entities in the database.
+func (dcb *DeviceCreateBulk) Save(ctx context.Context) ([]*Device, error) {
+	if dcb.err != nil {
+		return nil, dcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(dcb.builders))
+	nodes := make([]*Device, len(dcb.builders))
+	mutators := make([]Mutator, len(dcb.builders))
+	for i := range dcb.builders {
+		func(i int, root context.Context) {
+			builder := dcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*DeviceMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, dcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, dcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, dcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (dcb *DeviceCreateBulk) SaveX(ctx context.Context) []*Device {
+	v, err := dcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (dcb *DeviceCreateBulk) Exec(ctx context.Context) error {
+	_, err := dcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dcb *DeviceCreateBulk) ExecX(ctx context.Context) {
+	if err := dcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,437 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/device"
+	"github.com/mikestefanello/pagoda/ent/predicate"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+// DeviceUpdate is the builder for updating Device entities.
+type DeviceUpdate struct {
+	config
+	hooks    []Hook
+	mutation *DeviceMutation
+}
+
+// Where appends a list predicates to the DeviceUpdate builder.
+func (du *DeviceUpdate) Where(ps ...predicate.Device) *DeviceUpdate {
+	du.mutation.Where(ps...)
+	return du
+}
+
+// SetHash sets the "hash" field.
+func (du *DeviceUpdate) SetHash(s string) *DeviceUpdate {
+	du.mutation.SetHash(s)
+	return du
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (du *DeviceUpdate) SetNillableHash(s *string) *DeviceUpdate {
+	if s != nil {
+		du.SetHash(*s)
+	}
+	return du
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (du *DeviceUpdate) SetUserAgent(s string) *DeviceUpdate {
+	du.mutation.SetUserAgent(s)
+	return du
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (du *DeviceUpdate) SetNillableUserAgent(s *string) *DeviceUpdate {
+	if s != nil {
+		du.SetUserAgent(*s)
+	}
+	return du
+}
+
+// SetIPAddress sets the "ip_address" field.
+func (du *DeviceUpdate) SetIPAddress(s string) *DeviceUpdate {
+	du.mutation.SetIPAddress(s)
+	return du
+}
+
+// SetNillableIPAddress sets the "ip_address" field if the given value is not nil.
+func (du *DeviceUpdate) SetNillableIPAddress(s *string) *DeviceUpdate {
+	if s != nil {
+		du.SetIPAddress(*s)
+	}
+	return du
+}
+
+// SetLastLoginAt sets the "last_login_at" field.
+func (du *DeviceUpdate) SetLastLoginAt(t time.Time) *DeviceUpdate {
+	du.mutation.SetLastLoginAt(t)
+	return du
+}
+
+// SetNillableLastLoginAt sets the "last_login_at" field if the given value is not nil.
+func (du *DeviceUpdate) SetNillableLastLoginAt(t *time.Time) *DeviceUpdate {
+	if t != nil {
+		du.SetLastLoginAt(*t)
+	}
+	return du
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (du *DeviceUpdate) SetUserID(id int) *DeviceUpdate {
+	du.mutation.SetUserID(id)
+	return du
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (du *DeviceUpdate) SetUser(u *User) *DeviceUpdate {
+	return du.SetUserID(u.ID)
+}
+
+// Mutation returns the DeviceMutation object of the builder.
+func (du *DeviceUpdate) Mutation() *DeviceMutation {
+	return du.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (du *DeviceUpdate) ClearUser() *DeviceUpdate {
+	du.mutation.ClearUser()
+	return du
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (du *DeviceUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, du.sqlSave, du.mutation, du.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (du *DeviceUpdate) SaveX(ctx context.Context) int {
+	affected, err := du.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (du *DeviceUpdate) Exec(ctx context.Context) error {
+	_, err := du.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (du *DeviceUpdate) ExecX(ctx context.Context) {
+	if err := du.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (du *DeviceUpdate) check() error {
+	if v, ok := du.mutation.Hash(); ok {
+		if err := device.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "Device.hash": %w`, err)}
+		}
+	}
+	if _, ok := du.mutation.UserID(); du.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Device.user"`)
+	}
+	return nil
+}
+
+func (du *DeviceUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := du.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(device.Table, device.Columns, sqlgraph.NewFieldSpec(device.FieldID, field.TypeInt))
+	if ps := du.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := du.mutation.Hash(); ok {
+		_spec.SetField(device.FieldHash, field.TypeString, value)
+	}
+	if value, ok := du.mutation.UserAgent(); ok {
+		_spec.SetField(device.FieldUserAgent, field.TypeString, value)
+	}
+	if value, ok := du.mutation.IPAddress(); ok {
+		_spec.SetField(device.FieldIPAddress, field.TypeString, value)
+	}
+	if value, ok := du.mutation.LastLoginAt(); ok {
+		_spec.SetField(device.FieldLastLoginAt, field.TypeTime, value)
+	}
+	if du.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   device.UserTable,
+			Columns: []string{device.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := du.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   device.UserTable,
+			Columns: []string{device.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, du.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{device.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	du.mutation.done = true
+	return n, nil
+}
+
+// DeviceUpdateOne is the builder for updating a single Device entity.
+type DeviceUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *DeviceMutation
+}
+
+// SetHash sets the "hash" field.
+func (duo *DeviceUpdateOne) SetHash(s string) *DeviceUpdateOne {
+	duo.mutation.SetHash(s)
+	return duo
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (duo *DeviceUpdateOne) SetNillableHash(s *string) *DeviceUpdateOne {
+	if s != nil {
+		duo.SetHash(*s)
+	}
+	return duo
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (duo *DeviceUpdateOne) SetUserAgent(s string) *DeviceUpdateOne {
+	duo.mutation.SetUserAgent(s)
+	return duo
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (duo *DeviceUpdateOne) SetNillableUserAgent(s *string) *DeviceUpdateOne {
+	if s != nil {
+		duo.SetUserAgent(*s)
+	}
+	return duo
+}
+
+// SetIPAddress sets the "ip_address" field.
+func (duo *DeviceUpdateOne) SetIPAddress(s string) *DeviceUpdateOne {
+	duo.mutation.SetIPAddress(s)
+	return duo
+}
+
+// SetNillableIPAddress sets the "ip_address" field if the given value is not nil.
+func (duo *DeviceUpdateOne) SetNillableIPAddress(s *string) *DeviceUpdateOne {
+	if s != nil {
+		duo.SetIPAddress(*s)
+	}
+	return duo
+}
+
+// SetLastLoginAt sets the "last_login_at" field.
+func (duo *DeviceUpdateOne) SetLastLoginAt(t time.Time) *DeviceUpdateOne {
+	duo.mutation.SetLastLoginAt(t)
+	return duo
+}
+
+// SetNillableLastLoginAt sets the "last_login_at" field if the given value is not nil.
+func (duo *DeviceUpdateOne) SetNillableLastLoginAt(t *time.Time) *DeviceUpdateOne {
+	if t != nil {
+		duo.SetLastLoginAt(*t)
+	}
+	return duo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (duo *DeviceUpdateOne) SetUserID(id int) *DeviceUpdateOne {
+	duo.mutation.SetUserID(id)
+	return duo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (duo *DeviceUpdateOne) SetUser(u *User) *DeviceUpdateOne {
+	return duo.SetUserID(u.ID)
+}
+
+// Mutation returns the DeviceMutation object of the builder.
+func (duo *DeviceUpdateOne) Mutation() *DeviceMutation {
+	return duo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (duo *DeviceUpdateOne) ClearUser() *DeviceUpdateOne {
+	duo.mutation.ClearUser()
+	return duo
+}
+
+// Where appends a list predicates to the DeviceUpdate builder.
+func (duo *DeviceUpdateOne) Where(ps ...predicate.Device) *DeviceUpdateOne {
+	duo.mutation.Where(ps...)
+	return duo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (duo *DeviceUpdateOne) Select(field string, fields ...string) *DeviceUpdateOne {
+	duo.fields = append([]string{field}, fields...)
+	return duo
+}
+
+// Save executes the query and returns the updated Device entity.
+func (duo *DeviceUpdateOne) Save(ctx context.Context) (*Device, error) {
+	return withHooks(ctx, duo.sqlSave, duo.mutation, duo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (duo *DeviceUpdateOne) SaveX(ctx context.Context) *Device {
+	node, err := duo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (duo *DeviceUpdateOne) Exec(ctx context.Context) error {
+	_, err := duo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (duo *DeviceUpdateOne) ExecX(ctx context.Context) {
+	if err := duo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (duo *DeviceUpdateOne) check() error {
+	if v, ok := duo.mutation.Hash(); ok {
+		if err := device.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "Device.hash": %w`, err)}
+		}
+	}
+	if _, ok := duo.mutation.UserID(); duo.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Device.user"`)
+	}
+	return nil
+}
+
+func (duo *DeviceUpdateOne) sqlSave(ctx context.Context) (_node *Device, err error) {
+	if err := duo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(device.Table, device.Columns, sqlgraph.NewFieldSpec(device.FieldID, field.TypeInt))
+	id, ok := duo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Device.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := duo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, device.FieldID)
+		for _, f := range fields {
+			if !device.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != device.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := duo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := duo.mutation.Hash(); ok {
+		_spec.SetField(device.FieldHash, field.TypeString, value)
+	}
+	if value, ok := duo.mutation.UserAgent(); ok {
+		_spec.SetField(device.FieldUserAgent, field.TypeString, value)
+	}
+	if value, ok := duo.mutation.IPAddress(); ok {
+		_spec.SetField(device.FieldIPAddress, field.TypeString, value)
+	}
+	if value, ok := duo.mutation.LastLoginAt(); ok {
+		_spec.SetField(device.FieldLastLoginAt, field.TypeTime, value)
+	}
+	if duo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   device.UserTable,
+			Columns: []string{device.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := duo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   device.UserTable,
+			Columns: []string{device.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &Device{config: duo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, duo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{device.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	duo.mutation.done = true
+	return _node, nil
+}
@@ -12,7 +12,20 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/mikestefanello/pagoda/ent/auditevent"
+	"github.com/mikestefanello/pagoda/ent/dataexport"
+	"github.com/mikestefanello/pagoda/ent/device"
+	"github.com/mikestefanello/pagoda/ent/impersonation"
+	"github.com/mikestefanello/pagoda/ent/invitation"
+	"github.com/mikestefanello/pagoda/ent/invitecode"
+	"github.com/mikestefanello/pagoda/ent/logintoken"
+	"github.com/mikestefanello/pagoda/ent/mailsuppression"
+	"github.com/mikestefanello/pagoda/ent/membership"
+	"github.com/mikestefanello/pagoda/ent/organization"
 	"github.com/mikestefanello/pagoda/ent/passwordtoken"
+	"github.com/mikestefanello/pagoda/ent/permission"
+	"github.com/mikestefanello/pagoda/ent/personalaccesstoken"
+	"github.com/mikestefanello/pagoda/ent/role"
 	"github.com/mikestefanello/pagoda/ent/user"
 )
 
@@ -74,8 +87,21 @@ var (
 func checkColumn(table, column string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			passwordtoken.Table: passwordtoken.ValidColumn,
-			user.Table:          user.ValidColumn,
+			auditevent.Table:          auditevent.ValidColumn,
+			dataexport.Table:          dataexport.ValidColumn,
+			device.Table:              device.ValidColumn,
+			impersonation.Table:       impersonation.ValidColumn,
+			invitation.Table:          invitation.ValidColumn,
+			invitecode.Table:          invitecode.ValidColumn,
+			logintoken.Table:          logintoken.ValidColumn,
+			mailsuppression.Table:     mailsuppression.ValidColumn,
+			membership.Table:          membership.ValidColumn,
+			organization.Table:        organization.ValidColumn,
+			passwordtoken.Table:       passwordtoken.ValidColumn,
+			permission.Table:          permission.ValidColumn,
+			personalaccesstoken.Table: personalaccesstoken.ValidColumn,
+			role.Table:                role.ValidColumn,
+			user.Table:                user.ValidColumn,
 		})
 	})
 	return columnCheck(table, column)
@@ -0,0 +1,185 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/mikestefanello/pagoda/ent/impersonation"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+// Impersonation is the model entity for the Impersonation schema.
+type Impersonation struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// StartedAt holds the value of the "started_at" field.
+	StartedAt time.Time `json:"started_at,omitempty"`
+	// EndedAt holds the value of the "ended_at" field.
+	EndedAt *time.Time `json:"ended_at,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the ImpersonationQuery when eager-loading is set.
+	Edges                      ImpersonationEdges `json:"edges"`
+	impersonation_impersonator *int
+	impersonation_user         *int
+	selectValues               sql.SelectValues
+}
+
+// ImpersonationEdges holds the relations/edges for other nodes in the graph.
+type ImpersonationEdges struct {
+	// Impersonator holds the value of the impersonator edge.
+	Impersonator *User `json:"impersonator,omitempty"`
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [2]bool
+}
+
+// ImpersonatorOrErr returns the Impersonator value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e ImpersonationEdges) ImpersonatorOrErr() (*User, error) {
+	if e.Impersonator != nil {
+		return e.Impersonator, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "impersonator"}
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e ImpersonationEdges) UserOrErr() (*User, error) {
+	if e.User != nil {
+		return e.User, nil
+	} else if e.loadedTypes[1] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*Impersonation) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case impersonation.FieldID:
+			values[i] = new(sql.NullInt64)
+		case impersonation.FieldStartedAt, impersonation.FieldEndedAt:
+			values[i] = new(sql.NullTime)
+		case impersonation.ForeignKeys[0]: // impersonation_impersonator
+			values[i] = new(sql.NullInt64)
+		case impersonation.ForeignKeys[1]: // impersonation_user
+			values[i] = new(sql.NullInt64)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the Impersonation fields.
+func (i *Impersonation) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for j := range columns {
+		switch columns[j] {
+		case impersonation.FieldID:
+			value, ok := values[j].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			i.ID = int(value.Int64)
+		case impersonation.FieldStartedAt:
+			if value, ok := values[j].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field started_at", values[j])
+			} else if value.Valid {
+				i.StartedAt = value.Time
+			}
+		case impersonation.FieldEndedAt:
+			if value, ok := values[j].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field ended_at", values[j])
+			} else if value.Valid {
+				i.EndedAt = new(time.Time)
+				*i.EndedAt = value.Time
+			}
+		case impersonation.ForeignKeys[0]:
+			if value, ok := values[j].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for edge-field impersonation_impersonator", value)
+			} else if value.Valid {
+				i.impersonation_impersonator = new(int)
+				*i.impersonation_impersonator = int(value.Int64)
+			}
+		case impersonation.ForeignKeys[1]:
+			if value, ok := values[j].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for edge-field impersonation_user", value)
+			} else if value.Valid {
+				i.impersonation_user = new(int)
+				*i.impersonation_user = int(value.Int64)
+			}
+		default:
+			i.selectValues.Set(columns[j], values[j])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the Impersonation.
+// This includes values selected through modifiers, order, etc.
+func (i *Impersonation) Value(name string) (ent.Value, error) {
+	return i.selectValues.Get(name)
+}
+
+// QueryImpersonator queries the "impersonator" edge of the Impersonation entity.
+func (i *Impersonation) QueryImpersonator() *UserQuery {
+	return NewImpersonationClient(i.config).QueryImpersonator(i)
+}
+
+// QueryUser queries the "user" edge of the Impersonation entity.
+func (i *Impersonation) QueryUser() *UserQuery {
+	return NewImpersonationClient(i.config).QueryUser(i)
+}
+
+// Update returns a builder for updating this Impersonation.
+// Note that you need to call Impersonation.Unwrap() before calling this method if this Impersonation
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (i *Impersonation) Update() *ImpersonationUpdateOne {
+	return NewImpersonationClient(i.config).UpdateOne(i)
+}
+
+// Unwrap unwraps the Impersonation entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (i *Impersonation) Unwrap() *Impersonation {
+	_tx, ok := i.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: Impersonation is not a transactional entity")
+	}
+	i.config.driver = _tx.drv
+	return i
+}
+
+// String implements the fmt.Stringer.
+func (i *Impersonation) String() string {
+	var builder strings.Builder
+	builder.WriteString("Impersonation(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", i.ID))
+	builder.WriteString("started_at=")
+	builder.WriteString(i.StartedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	if v := i.EndedAt; v != nil {
+		builder.WriteString("ended_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// Impersonations is a parsable slice of Impersonation.
+type Impersonations []*Impersonation
@@ -0,0 +1,121 @@
+// Code generated by ent, DO NOT EDIT.
+
+package impersonation
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+)
+
+const (
+	// Label holds the string label denoting the impersonation type in the database.
+	Label = "impersonation"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldStartedAt holds the string denoting the started_at field in the database.
+	FieldStartedAt = "started_at"
+	// FieldEndedAt holds the string denoting the ended_at field in the database.
+	FieldEndedAt = "ended_at"
+	// EdgeImpersonator holds the string denoting the impersonator edge name in mutations.
+	EdgeImpersonator = "impersonator"
+	// EdgeUser holds the string denoting the user edge name in mutations.
+	EdgeUser = "user"
+	// Table holds the table name of the impersonation in the database.
+	Table = "impersonations"
+	// ImpersonatorTable is the table that holds the impersonator relation/edge.
+	ImpersonatorTable = "impersonations"
+	// ImpersonatorInverseTable is the table name for the User entity.
+	// It exists in this package in order to avoid circular dependency with the "user" package.
+	ImpersonatorInverseTable = "users"
+	// ImpersonatorColumn is the table column denoting the impersonator relation/edge.
+	ImpersonatorColumn = "impersonation_impersonator"
+	// UserTable is the table that holds the user relation/edge.
+	UserTable = "impersonations"
+	// UserInverseTable is the table name for the User entity.
+	// It exists in this package in order to avoid circular dependency with the "user" package.
+	UserInverseTable = "users"
+	// UserColumn is the table column denoting the user relation/edge.
+	UserColumn = "impersonation_user"
+)
+
+// Columns holds all SQL columns for impersonation fields.
+var Columns = []string{
+	FieldID,
+	FieldStartedAt,
+	FieldEndedAt,
+}
+
+// ForeignKeys holds the SQL foreign-keys that are owned by the "impersonations"
+// table and are not defined as standalone fields in the schema.
+var ForeignKeys = []string{
+	"impersonation_impersonator",
+	"impersonation_user",
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	for i := range ForeignKeys {
+		if column == ForeignKeys[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// DefaultStartedAt holds the default value on creation for the "started_at" field.
+	DefaultStartedAt func() time.Time
+)
+
+// OrderOption defines the ordering options for the Impersonation queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByStartedAt orders the results by the started_at field.
+func ByStartedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldStartedAt, opts...).ToFunc()
+}
+
+// ByEndedAt orders the results by the ended_at field.
+func ByEndedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldEndedAt, opts...).ToFunc()
+}
+
+// ByImpersonatorField orders the results by impersonator field.
+func ByImpersonatorField(field string, opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newImpersonatorStep(), sql.OrderByField(field, opts...))
+	}
+}
+
+// ByUserField orders the results by user field.
+func ByUserField(field string, opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newUserStep(), sql.OrderByField(field, opts...))
+	}
+}
+func newImpersonatorStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(ImpersonatorInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.M2O, false, ImpersonatorTable, ImpersonatorColumn),
+	)
+}
+func newUserStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(UserInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.M2O, false, UserTable, UserColumn),
+	)
+}
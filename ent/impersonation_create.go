@@ -0,0 +1,283 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/impersonation"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+// ImpersonationCreate is the builder for creating a Impersonation entity.
+type ImpersonationCreate struct {
+	config
+	mutation *ImpersonationMutation
+	hooks    []Hook
+}
+
+// SetStartedAt sets the "started_at" field.
+func (ic *ImpersonationCreate) SetStartedAt(t time.Time) *ImpersonationCreate {
+	ic.mutation.SetStartedAt(t)
+	return ic
+}
+
+// SetNillableStartedAt sets the "started_at" field if the given value is not nil.
+func (ic *ImpersonationCreate) SetNillableStartedAt(t *time.Time) *ImpersonationCreate {
+	if t != nil {
+		ic.SetStartedAt(*t)
+	}
+	return ic
+}
+
+// SetEndedAt sets the "ended_at" field.
+func (ic *ImpersonationCreate) SetEndedAt(t time.Time) *ImpersonationCreate {
+	ic.mutation.SetEndedAt(t)
+	return ic
+}
+
+// SetNillableEndedAt sets the "ended_at" field if the given value is not nil.
+func (ic *ImpersonationCreate) SetNillableEndedAt(t *time.Time) *ImpersonationCreate {
+	if t != nil {
+		ic.SetEndedAt(*t)
+	}
+	return ic
+}
+
+// SetImpersonatorID sets the "impersonator" edge to the User entity by ID.
+func (ic *ImpersonationCreate) SetImpersonatorID(id int) *ImpersonationCreate {
+	ic.mutation.SetImpersonatorID(id)
+	return ic
+}
+
+// SetImpersonator sets the "impersonator" edge to the User entity.
+func (ic *ImpersonationCreate) SetImpersonator(u *User) *ImpersonationCreate {
+	return ic.SetImpersonatorID(u.ID)
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (ic *ImpersonationCreate) SetUserID(id int) *ImpersonationCreate {
+	ic.mutation.SetUserID(id)
+	return ic
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (ic *ImpersonationCreate) SetUser(u *User) *ImpersonationCreate {
+	return ic.SetUserID(u.ID)
+}
+
+// Mutation returns the ImpersonationMutation object of the builder.
+func (ic *ImpersonationCreate) Mutation() *ImpersonationMutation {
+	return ic.mutation
+}
+
+// Save creates the Impersonation in the database.
+func (ic *ImpersonationCreate) Save(ctx context.Context) (*Impersonation, error) {
+	ic.defaults()
+	return withHooks(ctx, ic.sqlSave, ic.mutation, ic.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (ic *ImpersonationCreate) SaveX(ctx context.Context) *Impersonation {
+	v, err := ic.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ic *ImpersonationCreate) Exec(ctx context.Context) error {
+	_, err := ic.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ic *ImpersonationCreate) ExecX(ctx context.Context) {
+	if err := ic.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ic *ImpersonationCreate) defaults() {
+	if _, ok := ic.mutation.StartedAt(); !ok {
+		v := impersonation.DefaultStartedAt()
+		ic.mutation.SetStartedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ic *ImpersonationCreate) check() error {
+	if _, ok := ic.mutation.StartedAt(); !ok {
+		return &ValidationError{Name: "started_at", err: errors.New(`ent: missing required field "Impersonation.started_at"`)}
+	}
+	if _, ok := ic.mutation.ImpersonatorID(); !ok {
+		return &ValidationError{Name: "impersonator", err: errors.New(`ent: missing required edge "Impersonation.impersonator"`)}
+	}
+	if _, ok := ic.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "Impersonation.user"`)}
+	}
+	return nil
+}
+
+func (ic *ImpersonationCreate) sqlSave(ctx context.Context) (*Impersonation, error) {
+	if err := ic.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := ic.createSpec()
+	if err := sqlgraph.CreateNode(ctx, ic.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	ic.mutation.id = &_node.ID
+	ic.mutation.done = true
+	return _node, nil
+}
+
+func (ic *ImpersonationCreate) createSpec() (*Impersonation, *sqlgraph.CreateSpec) {
+	var (
+		_node = &Impersonation{config: ic.config}
+		_spec = sqlgraph.NewCreateSpec(impersonation.Table, sqlgraph.NewFieldSpec(impersonation.FieldID, field.TypeInt))
+	)
+	if value, ok := ic.mutation.StartedAt(); ok {
+		_spec.SetField(impersonation.FieldStartedAt, field.TypeTime, value)
+		_node.StartedAt = value
+	}
+	if value, ok := ic.mutation.EndedAt(); ok {
+		_spec.SetField(impersonation.FieldEndedAt, field.TypeTime, value)
+		_node.EndedAt = &value
+	}
+	if nodes := ic.mutation.ImpersonatorIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   impersonation.ImpersonatorTable,
+			Columns: []string{impersonation.ImpersonatorColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.impersonation_impersonator = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := ic.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   impersonation.UserTable,
+			Columns: []string{impersonation.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.impersonation_user = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// ImpersonationCreateBulk is the builder for creating many Impersonation entities in bulk.
+type ImpersonationCreateBulk struct {
+	config
+	err      error
+	builders []*ImpersonationCreate
+}
+
+// Save creates the Impersonation entities in the database.
+func (icb *ImpersonationCreateBulk) Save(ctx context.Context) ([]*Impersonation, error) {
+	if icb.err != nil {
+		return nil, icb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(icb.builders))
+	nodes := make([]*Impersonation, len(icb.builders))
+	mutators := make([]Mutator, len(icb.builders))
+	for i := range icb.builders {
+		func(i int, root context.Context) {
+			builder := icb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*ImpersonationMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, icb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, icb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, icb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (icb *ImpersonationCreateBulk) SaveX(ctx context.Context) []*Impersonation {
+	v, err := icb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (icb *ImpersonationCreateBulk) Exec(ctx context.Context) error {
+	_, err := icb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (icb *ImpersonationCreateBulk) ExecX(ctx context.Context) {
+	if err := icb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,441 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/impersonation"
+	"github.com/mikestefanello/pagoda/ent/predicate"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+// ImpersonationUpdate is the builder for updating Impersonation entities.
+type ImpersonationUpdate struct {
+	config
+	hooks    []Hook
+	mutation *ImpersonationMutation
+}
+
+// Where appends a list predicates to the ImpersonationUpdate builder.
+func (iu *ImpersonationUpdate) Where(ps ...predicate.Impersonation) *ImpersonationUpdate {
+	iu.mutation.Where(ps...)
+	return iu
+}
+
+// SetEndedAt sets the "ended_at" field.
+func (iu *ImpersonationUpdate) SetEndedAt(t time.Time) *ImpersonationUpdate {
+	iu.mutation.SetEndedAt(t)
+	return iu
+}
+
+// SetNillableEndedAt sets the "ended_at" field if the given value is not nil.
+func (iu *ImpersonationUpdate) SetNillableEndedAt(t *time.Time) *ImpersonationUpdate {
+	if t != nil {
+		iu.SetEndedAt(*t)
+	}
+	return iu
+}
+
+// ClearEndedAt clears the value of the "ended_at" field.
+func (iu *ImpersonationUpdate) ClearEndedAt() *ImpersonationUpdate {
+	iu.mutation.ClearEndedAt()
+	return iu
+}
+
+// SetImpersonatorID sets the "impersonator" edge to the User entity by ID.
+func (iu *ImpersonationUpdate) SetImpersonatorID(id int) *ImpersonationUpdate {
+	iu.mutation.SetImpersonatorID(id)
+	return iu
+}
+
+// SetImpersonator sets the "impersonator" edge to the User entity.
+func (iu *ImpersonationUpdate) SetImpersonator(u *User) *ImpersonationUpdate {
+	return iu.SetImpersonatorID(u.ID)
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (iu *ImpersonationUpdate) SetUserID(id int) *ImpersonationUpdate {
+	iu.mutation.SetUserID(id)
+	return iu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (iu *ImpersonationUpdate) SetUser(u *User) *ImpersonationUpdate {
+	return iu.SetUserID(u.ID)
+}
+
+// Mutation returns the ImpersonationMutation object of the builder.
+func (iu *ImpersonationUpdate) Mutation() *ImpersonationMutation {
+	return iu.mutation
+}
+
+// ClearImpersonator clears the "impersonator" edge to the User entity.
+func (iu *ImpersonationUpdate) ClearImpersonator() *ImpersonationUpdate {
+	iu.mutation.ClearImpersonator()
+	return iu
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (iu *ImpersonationUpdate) ClearUser() *ImpersonationUpdate {
+	iu.mutation.ClearUser()
+	return iu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (iu *ImpersonationUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, iu.sqlSave, iu.mutation, iu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (iu *ImpersonationUpdate) SaveX(ctx context.Context) int {
+	affected, err := iu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (iu *ImpersonationUpdate) Exec(ctx context.Context) error {
+	_, err := iu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (iu *ImpersonationUpdate) ExecX(ctx context.Context) {
+	if err := iu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (iu *ImpersonationUpdate) check() error {
+	if _, ok := iu.mutation.ImpersonatorID(); iu.mutation.ImpersonatorCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Impersonation.impersonator"`)
+	}
+	if _, ok := iu.mutation.UserID(); iu.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Impersonation.user"`)
+	}
+	return nil
+}
+
+func (iu *ImpersonationUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := iu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(impersonation.Table, impersonation.Columns, sqlgraph.NewFieldSpec(impersonation.FieldID, field.TypeInt))
+	if ps := iu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := iu.mutation.EndedAt(); ok {
+		_spec.SetField(impersonation.FieldEndedAt, field.TypeTime, value)
+	}
+	if iu.mutation.EndedAtCleared() {
+		_spec.ClearField(impersonation.FieldEndedAt, field.TypeTime)
+	}
+	if iu.mutation.ImpersonatorCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   impersonation.ImpersonatorTable,
+			Columns: []string{impersonation.ImpersonatorColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := iu.mutation.ImpersonatorIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   impersonation.ImpersonatorTable,
+			Columns: []string{impersonation.ImpersonatorColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if iu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   impersonation.UserTable,
+			Columns: []string{impersonation.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := iu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   impersonation.UserTable,
+			Columns: []string{impersonation.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, iu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{impersonation.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	iu.mutation.done = true
+	return n, nil
+}
+
+// ImpersonationUpdateOne is the builder for updating a single Impersonation entity.
+type ImpersonationUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *ImpersonationMutation
+}
+
+// SetEndedAt sets the "ended_at" field.
+func (iuo *ImpersonationUpdateOne) SetEndedAt(t time.Time) *ImpersonationUpdateOne {
+	iuo.mutation.SetEndedAt(t)
+	return iuo
+}
+
+// SetNillableEndedAt sets the "ended_at" field if the given value is not nil.
+func (iuo *ImpersonationUpdateOne) SetNillableEndedAt(t *time.Time) *ImpersonationUpdateOne {
+	if t != nil {
+		iuo.SetEndedAt(*t)
+	}
+	return iuo
+}
+
+// ClearEndedAt clears the value of the "ended_at" field.
+func (iuo *ImpersonationUpdateOne) ClearEndedAt() *ImpersonationUpdateOne {
+	iuo.mutation.ClearEndedAt()
+	return iuo
+}
+
+// SetImpersonatorID sets the "impersonator" edge to the User entity by ID.
+func (iuo *ImpersonationUpdateOne) SetImpersonatorID(id int) *ImpersonationUpdateOne {
+	iuo.mutation.SetImpersonatorID(id)
+	return iuo
+}
+
+// SetImpersonator sets the "impersonator" edge to the User entity.
+func (iuo *ImpersonationUpdateOne) SetImpersonator(u *User) *ImpersonationUpdateOne {
+	return iuo.SetImpersonatorID(u.ID)
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (iuo *ImpersonationUpdateOne) SetUserID(id int) *ImpersonationUpdateOne {
+	iuo.mutation.SetUserID(id)
+	return iuo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (iuo *ImpersonationUpdateOne) SetUser(u *User) *ImpersonationUpdateOne {
+	return iuo.SetUserID(u.ID)
+}
+
+// Mutation returns the ImpersonationMutation object of the builder.
+func (iuo *ImpersonationUpdateOne) Mutation() *ImpersonationMutation {
+	return iuo.mutation
+}
+
+// ClearImpersonator clears the "impersonator" edge to the User entity.
+func (iuo *ImpersonationUpdateOne) ClearImpersonator() *ImpersonationUpdateOne {
+	iuo.mutation.ClearImpersonator()
+	return iuo
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (iuo *ImpersonationUpdateOne) ClearUser() *ImpersonationUpdateOne {
+	iuo.mutation.ClearUser()
+	return iuo
+}
+
+// Where appends a list predicates to the ImpersonationUpdate builder.
+func (iuo *ImpersonationUpdateOne) Where(ps ...predicate.Impersonation) *ImpersonationUpdateOne {
+	iuo.mutation.Where(ps...)
+	return iuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (iuo *ImpersonationUpdateOne) Select(field string, fields ...string) *ImpersonationUpdateOne {
+	iuo.fields = append([]string{field}, fields...)
+	return iuo
+}
+
+// Save executes the query and returns the updated Impersonation entity.
+func (iuo *ImpersonationUpdateOne) Save(ctx context.Context) (*Impersonation, error) {
+	return withHooks(ctx, iuo.sqlSave, iuo.mutation, iuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (iuo *ImpersonationUpdateOne) SaveX(ctx context.Context) *Impersonation {
+	node, err := iuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (iuo *ImpersonationUpdateOne) Exec(ctx context.Context) error {
+	_, err := iuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (iuo *ImpersonationUpdateOne) ExecX(ctx context.Context) {
+	if err := iuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (iuo *ImpersonationUpdateOne) check() error {
+	if _, ok := iuo.mutation.ImpersonatorID(); iuo.mutation.ImpersonatorCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Impersonation.impersonator"`)
+	}
+	if _, ok := iuo.mutation.UserID(); iuo.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Impersonation.user"`)
+	}
+	return nil
+}
+
+func (iuo *ImpersonationUpdateOne) sqlSave(ctx context.Context) (_node *Impersonation, err error) {
+	if err := iuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(impersonation.Table, impersonation.Columns, sqlgraph.NewFieldSpec(impersonation.FieldID, field.TypeInt))
+	id, ok := iuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Impersonation.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := iuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, impersonation.FieldID)
+		for _, f := range fields {
+			if !impersonation.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != impersonation.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := iuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := iuo.mutation.EndedAt(); ok {
+		_spec.SetField(impersonation.FieldEndedAt, field.TypeTime, value)
+	}
+	if iuo.mutation.EndedAtCleared() {
+		_spec.ClearField(impersonation.FieldEndedAt, field.TypeTime)
+	}
+	if iuo.mutation.ImpersonatorCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   impersonation.ImpersonatorTable,
+			Columns: []string{impersonation.ImpersonatorColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := iuo.mutation.ImpersonatorIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   impersonation.ImpersonatorTable,
+			Columns: []string{impersonation.ImpersonatorColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if iuo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   impersonation.UserTable,
+			Columns: []string{impersonation.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := iuo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   impersonation.UserTable,
+			Columns: []string{impersonation.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &Impersonation{config: iuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, iuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{impersonation.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	iuo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,531 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/invitation"
+	"github.com/mikestefanello/pagoda/ent/organization"
+	"github.com/mikestefanello/pagoda/ent/predicate"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+// InvitationUpdate is the builder for updating Invitation entities.
+type InvitationUpdate struct {
+	config
+	hooks    []Hook
+	mutation *InvitationMutation
+}
+
+// Where appends a list predicates to the InvitationUpdate builder.
+func (iu *InvitationUpdate) Where(ps ...predicate.Invitation) *InvitationUpdate {
+	iu.mutation.Where(ps...)
+	return iu
+}
+
+// SetEmail sets the "email" field.
+func (iu *InvitationUpdate) SetEmail(s string) *InvitationUpdate {
+	iu.mutation.SetEmail(s)
+	return iu
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (iu *InvitationUpdate) SetNillableEmail(s *string) *InvitationUpdate {
+	if s != nil {
+		iu.SetEmail(*s)
+	}
+	return iu
+}
+
+// SetRole sets the "role" field.
+func (iu *InvitationUpdate) SetRole(i invitation.Role) *InvitationUpdate {
+	iu.mutation.SetRole(i)
+	return iu
+}
+
+// SetNillableRole sets the "role" field if the given value is not nil.
+func (iu *InvitationUpdate) SetNillableRole(i *invitation.Role) *InvitationUpdate {
+	if i != nil {
+		iu.SetRole(*i)
+	}
+	return iu
+}
+
+// SetHash sets the "hash" field.
+func (iu *InvitationUpdate) SetHash(s string) *InvitationUpdate {
+	iu.mutation.SetHash(s)
+	return iu
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (iu *InvitationUpdate) SetNillableHash(s *string) *InvitationUpdate {
+	if s != nil {
+		iu.SetHash(*s)
+	}
+	return iu
+}
+
+// SetOrganizationID sets the "organization" edge to the Organization entity by ID.
+func (iu *InvitationUpdate) SetOrganizationID(id int) *InvitationUpdate {
+	iu.mutation.SetOrganizationID(id)
+	return iu
+}
+
+// SetOrganization sets the "organization" edge to the Organization entity.
+func (iu *InvitationUpdate) SetOrganization(o *Organization) *InvitationUpdate {
+	return iu.SetOrganizationID(o.ID)
+}
+
+// SetInviterID sets the "inviter" edge to the User entity by ID.
+func (iu *InvitationUpdate) SetInviterID(id int) *InvitationUpdate {
+	iu.mutation.SetInviterID(id)
+	return iu
+}
+
+// SetNillableInviterID sets the "inviter" edge to the User entity by ID if the given value is not nil.
+func (iu *InvitationUpdate) SetNillableInviterID(id *int) *InvitationUpdate {
+	if id != nil {
+		iu = iu.SetInviterID(*id)
+	}
+	return iu
+}
+
+// SetInviter sets the "inviter" edge to the User entity.
+func (iu *InvitationUpdate) SetInviter(u *User) *InvitationUpdate {
+	return iu.SetInviterID(u.ID)
+}
+
+// Mutation returns the InvitationMutation object of the builder.
+func (iu *InvitationUpdate) Mutation() *InvitationMutation {
+	return iu.mutation
+}
+
+// ClearOrganization clears the "organization" edge to the Organization entity.
+func (iu *InvitationUpdate) ClearOrganization() *InvitationUpdate {
+	iu.mutation.ClearOrganization()
+	return iu
+}
+
+// ClearInviter clears the "inviter" edge to the User entity.
+func (iu *InvitationUpdate) ClearInviter() *InvitationUpdate {
+	iu.mutation.ClearInviter()
+	return iu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (iu *InvitationUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, iu.sqlSave, iu.mutation, iu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (iu *InvitationUpdate) SaveX(ctx context.Context) int {
+	affected, err := iu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (iu *InvitationUpdate) Exec(ctx context.Context) error {
+	_, err := iu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (iu *InvitationUpdate) ExecX(ctx context.Context) {
+	if err := iu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (iu *InvitationUpdate) check() error {
+	if v, ok := iu.mutation.Email(); ok {
+		if err := invitation.EmailValidator(v); err != nil {
+			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "Invitation.email": %w`, err)}
+		}
+	}
+	if v, ok := iu.mutation.Role(); ok {
+		if err := invitation.RoleValidator(v); err != nil {
+			return &ValidationError{Name: "role", err: fmt.Errorf(`ent: validator failed for field "Invitation.role": %w`, err)}
+		}
+	}
+	if v, ok := iu.mutation.Hash(); ok {
+		if err := invitation.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "Invitation.hash": %w`, err)}
+		}
+	}
+	if _, ok := iu.mutation.OrganizationID(); iu.mutation.OrganizationCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Invitation.organization"`)
+	}
+	return nil
+}
+
+func (iu *InvitationUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := iu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(invitation.Table, invitation.Columns, sqlgraph.NewFieldSpec(invitation.FieldID, field.TypeInt))
+	if ps := iu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := iu.mutation.Email(); ok {
+		_spec.SetField(invitation.FieldEmail, field.TypeString, value)
+	}
+	if value, ok := iu.mutation.Role(); ok {
+		_spec.SetField(invitation.FieldRole, field.TypeEnum, value)
+	}
+	if value, ok := iu.mutation.Hash(); ok {
+		_spec.SetField(invitation.FieldHash, field.TypeString, value)
+	}
+	if iu.mutation.OrganizationCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   invitation.OrganizationTable,
+			Columns: []string{invitation.OrganizationColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(organization.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := iu.mutation.OrganizationIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   invitation.OrganizationTable,
+			Columns: []string{invitation.OrganizationColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(organization.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if iu.mutation.InviterCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   invitation.InviterTable,
+			Columns: []string{invitation.InviterColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := iu.mutation.InviterIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   invitation.InviterTable,
+			Columns: []string{invitation.InviterColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, iu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{invitation.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	iu.mutation.done = true
+	return n, nil
+}
+
+// InvitationUpdateOne is the builder for updating a single Invitation entity.
+type InvitationUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *InvitationMutation
+}
+
+// SetEmail sets the "email" field.
+func (iuo *InvitationUpdateOne) SetEmail(s string) *InvitationUpdateOne {
+	iuo.mutation.SetEmail(s)
+	return iuo
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (iuo *InvitationUpdateOne) SetNillableEmail(s *string) *InvitationUpdateOne {
+	if s != nil {
+		iuo.SetEmail(*s)
+	}
+	return iuo
+}
+
+// SetRole sets the "role" field.
+func (iuo *InvitationUpdateOne) SetRole(i invitation.Role) *InvitationUpdateOne {
+	iuo.mutation.SetRole(i)
+	return iuo
+}
+
+// SetNillableRole sets the "role" field if the given value is not nil.
+func (iuo *InvitationUpdateOne) SetNillableRole(i *invitation.Role) *InvitationUpdateOne {
+	if i != nil {
+		iuo.SetRole(*i)
+	}
+	return iuo
+}
+
+// SetHash sets the "hash" field.
+func (iuo *InvitationUpdateOne) SetHash(s string) *InvitationUpdateOne {
+	iuo.mutation.SetHash(s)
+	return iuo
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (iuo *InvitationUpdateOne) SetNillableHash(s *string) *InvitationUpdateOne {
+	if s != nil {
+		iuo.SetHash(*s)
+	}
+	return iuo
+}
+
+// SetOrganizationID sets the "organization" edge to the Organization entity by ID.
+func (iuo *InvitationUpdateOne) SetOrganizationID(id int) *InvitationUpdateOne {
+	iuo.mutation.SetOrganizationID(id)
+	return iuo
+}
+
+// SetOrganization sets the "organization" edge to the Organization entity.
+func (iuo *InvitationUpdateOne) SetOrganization(o *Organization) *InvitationUpdateOne {
+	return iuo.SetOrganizationID(o.ID)
+}
+
+// SetInviterID sets the "inviter" edge to the User entity by ID.
+func (iuo *InvitationUpdateOne) SetInviterID(id int) *InvitationUpdateOne {
+	iuo.mutation.SetInviterID(id)
+	return iuo
+}
+
+// SetNillableInviterID sets the "inviter" edge to the User entity by ID if the given value is not nil.
+func (iuo *InvitationUpdateOne) SetNillableInviterID(id *int) *InvitationUpdateOne {
+	if id != nil {
+		iuo = iuo.SetInviterID(*id)
+	}
+	return iuo
+}
+
+// SetInviter sets the "inviter" edge to the User entity.
+func (iuo *InvitationUpdateOne) SetInviter(u *User) *InvitationUpdateOne {
+	return iuo.SetInviterID(u.ID)
+}
+
+// Mutation returns the InvitationMutation object of the builder.
+func (iuo *InvitationUpdateOne) Mutation() *InvitationMutation {
+	return iuo.mutation
+}
+
+// ClearOrganization clears the "organization" edge to the Organization entity.
+func (iuo *InvitationUpdateOne) ClearOrganization() *InvitationUpdateOne {
+	iuo.mutation.ClearOrganization()
+	return iuo
+}
+
+// ClearInviter clears the "inviter" edge to the User entity.
+func (iuo *InvitationUpdateOne) ClearInviter() *InvitationUpdateOne {
+	iuo.mutation.ClearInviter()
+	return iuo
+}
+
+// Where appends a list predicates to the InvitationUpdate builder.
+func (iuo *InvitationUpdateOne) Where(ps ...predicate.Invitation) *InvitationUpdateOne {
+	iuo.mutation.Where(ps...)
+	return iuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (iuo *InvitationUpdateOne) Select(field string, fields ...string) *InvitationUpdateOne {
+	iuo.fields = append([]string{field}, fields...)
+	return iuo
+}
+
+// Save executes the query and returns the updated Invitation entity.
+func (iuo *InvitationUpdateOne) Save(ctx context.Context) (*Invitation, error) {
+	return withHooks(ctx, iuo.sqlSave, iuo.mutation, iuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (iuo *InvitationUpdateOne) SaveX(ctx context.Context) *Invitation {
+	node, err := iuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (iuo *InvitationUpdateOne) Exec(ctx context.Context) error {
+	_, err := iuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (iuo *InvitationUpdateOne) ExecX(ctx context.Context) {
+	if err := iuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (iuo *InvitationUpdateOne) check() error {
+	if v, ok := iuo.mutation.Email(); ok {
+		if err := invitation.EmailValidator(v); err != nil {
+			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "Invitation.email": %w`, err)}
+		}
+	}
+	if v, ok := iuo.mutation.Role(); ok {
+		if err := invitation.RoleValidator(v); err != nil {
+			return &ValidationError{Name: "role", err: fmt.Errorf(`ent: validator failed for field "Invitation.role": %w`, err)}
+		}
+	}
+	if v, ok := iuo.mutation.Hash(); ok {
+		if err := invitation.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "Invitation.hash": %w`, err)}
+		}
+	}
+	if _, ok := iuo.mutation.OrganizationID(); iuo.mutation.OrganizationCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Invitation.organization"`)
+	}
+	return nil
+}
+
+func (iuo *InvitationUpdateOne) sqlSave(ctx context.Context) (_node *Invitation, err error) {
+	if err := iuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(invitation.Table, invitation.Columns, sqlgraph.NewFieldSpec(invitation.FieldID, field.TypeInt))
+	id, ok := iuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Invitation.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := iuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, invitation.FieldID)
+		for _, f := range fields {
+			if !invitation.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != invitation.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := iuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := iuo.mutation.Email(); ok {
+		_spec.SetField(invitation.FieldEmail, field.TypeString, value)
+	}
+	if value, ok := iuo.mutation.Role(); ok {
+		_spec.SetField(invitation.FieldRole, field.TypeEnum, value)
+	}
+	if value, ok := iuo.mutation.Hash(); ok {
+		_spec.SetField(invitation.FieldHash, field.TypeString, value)
+	}
+	if iuo.mutation.OrganizationCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   invitation.OrganizationTable,
+			Columns: []string{invitation.OrganizationColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(organization.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := iuo.mutation.OrganizationIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   invitation.OrganizationTable,
+			Columns: []string{invitation.OrganizationColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(organization.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if iuo.mutation.InviterCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   invitation.InviterTable,
+			Columns: []string{invitation.InviterColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := iuo.mutation.InviterIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   invitation.InviterTable,
+			Columns: []string{invitation.InviterColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &Invitation{config: iuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, iuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{invitation.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	iuo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,202 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/mikestefanello/pagoda/ent/invitecode"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+// InviteCode is the model entity for the InviteCode schema.
+type InviteCode struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// Hash holds the value of the "hash" field.
+	Hash string `json:"-"`
+	// Note holds the value of the "note" field.
+	Note string `json:"note,omitempty"`
+	// MaxUses holds the value of the "max_uses" field.
+	MaxUses int `json:"max_uses,omitempty"`
+	// Uses holds the value of the "uses" field.
+	Uses int `json:"uses,omitempty"`
+	// ExpiresAt holds the value of the "expires_at" field.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the InviteCodeQuery when eager-loading is set.
+	Edges               InviteCodeEdges `json:"edges"`
+	invite_code_creator *int
+	selectValues        sql.SelectValues
+}
+
+// InviteCodeEdges holds the relations/edges for other nodes in the graph.
+type InviteCodeEdges struct {
+	// Creator holds the value of the creator edge.
+	Creator *User `json:"creator,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// CreatorOrErr returns the Creator value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e InviteCodeEdges) CreatorOrErr() (*User, error) {
+	if e.Creator != nil {
+		return e.Creator, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "creator"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*InviteCode) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case invitecode.FieldID, invitecode.FieldMaxUses, invitecode.FieldUses:
+			values[i] = new(sql.NullInt64)
+		case invitecode.FieldHash, invitecode.FieldNote:
+			values[i] = new(sql.NullString)
+		case invitecode.FieldExpiresAt, invitecode.FieldCreatedAt:
+			values[i] = new(sql.NullTime)
+		case invitecode.ForeignKeys[0]: // invite_code_creator
+			values[i] = new(sql.NullInt64)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the InviteCode fields.
+func (ic *InviteCode) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case invitecode.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			ic.ID = int(value.Int64)
+		case invitecode.FieldHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field hash", values[i])
+			} else if value.Valid {
+				ic.Hash = value.String
+			}
+		case invitecode.FieldNote:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field note", values[i])
+			} else if value.Valid {
+				ic.Note = value.String
+			}
+		case invitecode.FieldMaxUses:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field max_uses", values[i])
+			} else if value.Valid {
+				ic.MaxUses = int(value.Int64)
+			}
+		case invitecode.FieldUses:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field uses", values[i])
+			} else if value.Valid {
+				ic.Uses = int(value.Int64)
+			}
+		case invitecode.FieldExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field expires_at", values[i])
+			} else if value.Valid {
+				ic.ExpiresAt = new(time.Time)
+				*ic.ExpiresAt = value.Time
+			}
+		case invitecode.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				ic.CreatedAt = value.Time
+			}
+		case invitecode.ForeignKeys[0]:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for edge-field invite_code_creator", value)
+			} else if value.Valid {
+				ic.invite_code_creator = new(int)
+				*ic.invite_code_creator = int(value.Int64)
+			}
+		default:
+			ic.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the InviteCode.
+// This includes values selected through modifiers, order, etc.
+func (ic *InviteCode) Value(name string) (ent.Value, error) {
+	return ic.selectValues.Get(name)
+}
+
+// QueryCreator queries the "creator" edge of the InviteCode entity.
+func (ic *InviteCode) QueryCreator() *UserQuery {
+	return NewInviteCodeClient(ic.config).QueryCreator(ic)
+}
+
+// Update returns a builder for updating this InviteCode.
+// Note that you need to call InviteCode.Unwrap() before calling this method if this InviteCode
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (ic *InviteCode) Update() *InviteCodeUpdateOne {
+	return NewInviteCodeClient(ic.config).UpdateOne(ic)
+}
+
+// Unwrap unwraps the InviteCode entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (ic *InviteCode) Unwrap() *InviteCode {
+	_tx, ok := ic.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: InviteCode is not a transactional entity")
+	}
+	ic.config.driver = _tx.drv
+	return ic
+}
+
+// String implements the fmt.Stringer.
+func (ic *InviteCode) String() string {
+	var builder strings.Builder
+	builder.WriteString("InviteCode(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", ic.ID))
+	builder.WriteString("hash=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("note=")
+	builder.WriteString(ic.Note)
+	builder.WriteString(", ")
+	builder.WriteString("max_uses=")
+	builder.WriteString(fmt.Sprintf("%v", ic.MaxUses))
+	builder.WriteString(", ")
+	builder.WriteString("uses=")
+	builder.WriteString(fmt.Sprintf("%v", ic.Uses))
+	builder.WriteString(", ")
+	if v := ic.ExpiresAt; v != nil {
+		builder.WriteString("expires_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(ic.CreatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// InviteCodes is a parsable slice of InviteCode.
+type InviteCodes []*InviteCode
@@ -0,0 +1,353 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/invitecode"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+// InviteCodeCreate is the builder for creating a InviteCode entity.
+type InviteCodeCreate struct {
+	config
+	mutation *InviteCodeMutation
+	hooks    []Hook
+}
+
+// SetHash sets the "hash" field.
+func (icc *InviteCodeCreate) SetHash(s string) *InviteCodeCreate {
+	icc.mutation.SetHash(s)
+	return icc
+}
+
+// SetNote sets the "note" field.
+func (icc *InviteCodeCreate) SetNote(s string) *InviteCodeCreate {
+	icc.mutation.SetNote(s)
+	return icc
+}
+
+// SetNillableNote sets the "note" field if the given value is not nil.
+func (icc *InviteCodeCreate) SetNillableNote(s *string) *InviteCodeCreate {
+	if s != nil {
+		icc.SetNote(*s)
+	}
+	return icc
+}
+
+// SetMaxUses sets the "max_uses" field.
+func (icc *InviteCodeCreate) SetMaxUses(i int) *InviteCodeCreate {
+	icc.mutation.SetMaxUses(i)
+	return icc
+}
+
+// SetNillableMaxUses sets the "max_uses" field if the given value is not nil.
+func (icc *InviteCodeCreate) SetNillableMaxUses(i *int) *InviteCodeCreate {
+	if i != nil {
+		icc.SetMaxUses(*i)
+	}
+	return icc
+}
+
+// SetUses sets the "uses" field.
+func (icc *InviteCodeCreate) SetUses(i int) *InviteCodeCreate {
+	icc.mutation.SetUses(i)
+	return icc
+}
+
+// SetNillableUses sets the "uses" field if the given value is not nil.
+func (icc *InviteCodeCreate) SetNillableUses(i *int) *InviteCodeCreate {
+	if i != nil {
+		icc.SetUses(*i)
+	}
+	return icc
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (icc *InviteCodeCreate) SetExpiresAt(t time.Time) *InviteCodeCreate {
+	icc.mutation.SetExpiresAt(t)
+	return icc
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (icc *InviteCodeCreate) SetNillableExpiresAt(t *time.Time) *InviteCodeCreate {
+	if t != nil {
+		icc.SetExpiresAt(*t)
+	}
+	return icc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (icc *InviteCodeCreate) SetCreatedAt(t time.Time) *InviteCodeCreate {
+	icc.mutation.SetCreatedAt(t)
+	return icc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (icc *InviteCodeCreate) SetNillableCreatedAt(t *time.Time) *InviteCodeCreate {
+	if t != nil {
+		icc.SetCreatedAt(*t)
+	}
+	return icc
+}
+
+// SetCreatorID sets the "creator" edge to the User entity by ID.
+func (icc *InviteCodeCreate) SetCreatorID(id int) *InviteCodeCreate {
+	icc.mutation.SetCreatorID(id)
+	return icc
+}
+
+// SetNillableCreatorID sets the "creator" edge to the User entity by ID if the given value is not nil.
+func (icc *InviteCodeCreate) SetNillableCreatorID(id *int) *InviteCodeCreate {
+	if id != nil {
+		icc = icc.SetCreatorID(*id)
+	}
+	return icc
+}
+
+// SetCreator sets the "creator" edge to the User entity.
+func (icc *InviteCodeCreate) SetCreator(u *User) *InviteCodeCreate {
+	return icc.SetCreatorID(u.ID)
+}
+
+// Mutation returns the InviteCodeMutation object of the builder.
+func (icc *InviteCodeCreate) Mutation() *InviteCodeMutation {
+	return icc.mutation
+}
+
+// Save creates the InviteCode in the database.
+func (icc *InviteCodeCreate) Save(ctx context.Context) (*InviteCode, error) {
+	icc.defaults()
+	return withHooks(ctx, icc.sqlSave, icc.mutation, icc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (icc *InviteCodeCreate) SaveX(ctx context.Context) *InviteCode {
+	v, err := icc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (icc *InviteCodeCreate) Exec(ctx context.Context) error {
+	_, err := icc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (icc *InviteCodeCreate) ExecX(ctx context.Context) {
+	if err := icc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (icc *InviteCodeCreate) defaults() {
+	if _, ok := icc.mutation.MaxUses(); !ok {
+		v := invitecode.DefaultMaxUses
+		icc.mutation.SetMaxUses(v)
+	}
+	if _, ok := icc.mutation.Uses(); !ok {
+		v := invitecode.DefaultUses
+		icc.mutation.SetUses(v)
+	}
+	if _, ok := icc.mutation.CreatedAt(); !ok {
+		v := invitecode.DefaultCreatedAt()
+		icc.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (icc *InviteCodeCreate) check() error {
+	if _, ok := icc.mutation.Hash(); !ok {
+		return &ValidationError{Name: "hash", err: errors.New(`ent: missing required field "InviteCode.hash"`)}
+	}
+	if v, ok := icc.mutation.Hash(); ok {
+		if err := invitecode.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "InviteCode.hash": %w`, err)}
+		}
+	}
+	if _, ok := icc.mutation.MaxUses(); !ok {
+		return &ValidationError{Name: "max_uses", err: errors.New(`ent: missing required field "InviteCode.max_uses"`)}
+	}
+	if v, ok := icc.mutation.MaxUses(); ok {
+		if err := invitecode.MaxUsesValidator(v); err != nil {
+			return &ValidationError{Name: "max_uses", err: fmt.Errorf(`ent: validator failed for field "InviteCode.max_uses": %w`, err)}
+		}
+	}
+	if _, ok := icc.mutation.Uses(); !ok {
+		return &ValidationError{Name: "uses", err: errors.New(`ent: missing required field "InviteCode.uses"`)}
+	}
+	if v, ok := icc.mutation.Uses(); ok {
+		if err := invitecode.UsesValidator(v); err != nil {
+			return &ValidationError{Name: "uses", err: fmt.Errorf(`ent: validator failed for field "InviteCode.uses": %w`, err)}
+		}
+	}
+	if _, ok := icc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "InviteCode.created_at"`)}
+	}
+	return nil
+}
+
+func (icc *InviteCodeCreate) sqlSave(ctx context.Context) (*InviteCode, error) {
+	if err := icc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := icc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, icc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	icc.mutation.id = &_node.ID
+	icc.mutation.done = true
+	return _node, nil
+}
+
+func (icc *InviteCodeCreate) createSpec() (*InviteCode, *sqlgraph.CreateSpec) {
+	var (
+		_node = &InviteCode{config: icc.config}
+		_spec = sqlgraph.NewCreateSpec(invitecode.Table, sqlgraph.NewFieldSpec(invitecode.FieldID, field.TypeInt))
+	)
+	if value, ok := icc.mutation.Hash(); ok {
+		_spec.SetField(invitecode.FieldHash, field.TypeString, value)
+		_node.Hash = value
+	}
+	if value, ok := icc.mutation.Note(); ok {
+		_spec.SetField(invitecode.FieldNote, field.TypeString, value)
+		_node.Note = value
+	}
+	if value, ok := icc.mutation.MaxUses(); ok {
+		_spec.SetField(invitecode.FieldMaxUses, field.TypeInt, value)
+		_node.MaxUses = value
+	}
+	if value, ok := icc.mutation.Uses(); ok {
+		_spec.SetField(invitecode.FieldUses, field.TypeInt, value)
+		_node.Uses = value
+	}
+	if value, ok := icc.mutation.ExpiresAt(); ok {
+		_spec.SetField(invitecode.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = &value
+	}
+	if value, ok := icc.mutation.CreatedAt(); ok {
+		_spec.SetField(invitecode.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if nodes := icc.mutation.CreatorIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   invitecode.CreatorTable,
+			Columns: []string{invitecode.CreatorColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.invite_code_creator = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// InviteCodeCreateBulk is the builder for creating many InviteCode entities in bulk.
+type InviteCodeCreateBulk struct {
+	config
+	err      error
+	builders []*InviteCodeCreate
+}
+
+// Save creates the InviteCode entities in the database.
+func (iccb *InviteCodeCreateBulk) Save(ctx context.Context) ([]*InviteCode, error) {
+	if iccb.err != nil {
+		return nil, iccb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(iccb.builders))
+	nodes := make([]*InviteCode, len(iccb.builders))
+	mutators := make([]Mutator, len(iccb.builders))
+	for i := range iccb.builders {
+		func(i int, root context.Context) {
+			builder := iccb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*InviteCodeMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, iccb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, iccb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, iccb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (iccb *InviteCodeCreateBulk) SaveX(ctx context.Context) []*InviteCode {
+	v, err := iccb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (iccb *InviteCodeCreateBulk) Exec(ctx context.Context) error {
+	_, err := iccb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (iccb *InviteCodeCreateBulk) ExecX(ctx context.Context) {
+	if err := iccb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,577 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/invitecode"
+	"github.com/mikestefanello/pagoda/ent/predicate"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+// InviteCodeUpdate is the builder for updating InviteCode entities.
+type InviteCodeUpdate struct {
+	config
+	hooks    []Hook
+	mutation *InviteCodeMutation
+}
+
+// Where appends a list predicates to the InviteCodeUpdate builder.
+func (icu *InviteCodeUpdate) Where(ps ...predicate.InviteCode) *InviteCodeUpdate {
+	icu.mutation.Where(ps...)
+	return icu
+}
+
+// SetHash sets the "hash" field.
+func (icu *InviteCodeUpdate) SetHash(s string) *InviteCodeUpdate {
+	icu.mutation.SetHash(s)
+	return icu
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (icu *InviteCodeUpdate) SetNillableHash(s *string) *InviteCodeUpdate {
+	if s != nil {
+		icu.SetHash(*s)
+	}
+	return icu
+}
+
+// SetNote sets the "note" field.
+func (icu *InviteCodeUpdate) SetNote(s string) *InviteCodeUpdate {
+	icu.mutation.SetNote(s)
+	return icu
+}
+
+// SetNillableNote sets the "note" field if the given value is not nil.
+func (icu *InviteCodeUpdate) SetNillableNote(s *string) *InviteCodeUpdate {
+	if s != nil {
+		icu.SetNote(*s)
+	}
+	return icu
+}
+
+// ClearNote clears the value of the "note" field.
+func (icu *InviteCodeUpdate) ClearNote() *InviteCodeUpdate {
+	icu.mutation.ClearNote()
+	return icu
+}
+
+// SetMaxUses sets the "max_uses" field.
+func (icu *InviteCodeUpdate) SetMaxUses(i int) *InviteCodeUpdate {
+	icu.mutation.ResetMaxUses()
+	icu.mutation.SetMaxUses(i)
+	return icu
+}
+
+// SetNillableMaxUses sets the "max_uses" field if the given value is not nil.
+func (icu *InviteCodeUpdate) SetNillableMaxUses(i *int) *InviteCodeUpdate {
+	if i != nil {
+		icu.SetMaxUses(*i)
+	}
+	return icu
+}
+
+// AddMaxUses adds i to the "max_uses" field.
+func (icu *InviteCodeUpdate) AddMaxUses(i int) *InviteCodeUpdate {
+	icu.mutation.AddMaxUses(i)
+	return icu
+}
+
+// SetUses sets the "uses" field.
+func (icu *InviteCodeUpdate) SetUses(i int) *InviteCodeUpdate {
+	icu.mutation.ResetUses()
+	icu.mutation.SetUses(i)
+	return icu
+}
+
+// SetNillableUses sets the "uses" field if the given value is not nil.
+func (icu *InviteCodeUpdate) SetNillableUses(i *int) *InviteCodeUpdate {
+	if i != nil {
+		icu.SetUses(*i)
+	}
+	return icu
+}
+
+// AddUses adds i to the "uses" field.
+func (icu *InviteCodeUpdate) AddUses(i int) *InviteCodeUpdate {
+	icu.mutation.AddUses(i)
+	return icu
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (icu *InviteCodeUpdate) SetExpiresAt(t time.Time) *InviteCodeUpdate {
+	icu.mutation.SetExpiresAt(t)
+	return icu
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (icu *InviteCodeUpdate) SetNillableExpiresAt(t *time.Time) *InviteCodeUpdate {
+	if t != nil {
+		icu.SetExpiresAt(*t)
+	}
+	return icu
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (icu *InviteCodeUpdate) ClearExpiresAt() *InviteCodeUpdate {
+	icu.mutation.ClearExpiresAt()
+	return icu
+}
+
+// SetCreatorID sets the "creator" edge to the User entity by ID.
+func (icu *InviteCodeUpdate) SetCreatorID(id int) *InviteCodeUpdate {
+	icu.mutation.SetCreatorID(id)
+	return icu
+}
+
+// SetNillableCreatorID sets the "creator" edge to the User entity by ID if the given value is not nil.
+func (icu *InviteCodeUpdate) SetNillableCreatorID(id *int) *InviteCodeUpdate {
+	if id != nil {
+		icu = icu.SetCreatorID(*id)
+	}
+	return icu
+}
+
+// SetCreator sets the "creator" edge to the User entity.
+func (icu *InviteCodeUpdate) SetCreator(u *User) *InviteCodeUpdate {
+	return icu.SetCreatorID(u.ID)
+}
+
+// Mutation returns the InviteCodeMutation object of the builder.
+func (icu *InviteCodeUpdate) Mutation() *InviteCodeMutation {
+	return icu.mutation
+}
+
+// ClearCreator clears the "creator" edge to the User entity.
+func (icu *InviteCodeUpdate) ClearCreator() *InviteCodeUpdate {
+	icu.mutation.ClearCreator()
+	return icu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (icu *InviteCodeUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, icu.sqlSave, icu.mutation, icu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (icu *InviteCodeUpdate) SaveX(ctx context.Context) int {
+	affected, err := icu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (icu *InviteCodeUpdate) Exec(ctx context.Context) error {
+	_, err := icu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (icu *InviteCodeUpdate) ExecX(ctx context.Context) {
+	if err := icu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (icu *InviteCodeUpdate) check() error {
+	if v, ok := icu.mutation.Hash(); ok {
+		if err := invitecode.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "InviteCode.hash": %w`, err)}
+		}
+	}
+	if v, ok := icu.mutation.MaxUses(); ok {
+		if err := invitecode.MaxUsesValidator(v); err != nil {
+			return &ValidationError{Name: "max_uses", err: fmt.Errorf(`ent: validator failed for field "InviteCode.max_uses": %w`, err)}
+		}
+	}
+	if v, ok := icu.mutation.Uses(); ok {
+		if err := invitecode.UsesValidator(v); err != nil {
+			return &ValidationError{Name: "uses", err: fmt.Errorf(`ent: validator failed for field "InviteCode.uses": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (icu *InviteCodeUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := icu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(invitecode.Table, invitecode.Columns, sqlgraph.NewFieldSpec(invitecode.FieldID, field.TypeInt))
+	if ps := icu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := icu.mutation.Hash(); ok {
+		_spec.SetField(invitecode.FieldHash, field.TypeString, value)
+	}
+	if value, ok := icu.mutation.Note(); ok {
+		_spec.SetField(invitecode.FieldNote, field.TypeString, value)
+	}
+	if icu.mutation.NoteCleared() {
+		_spec.ClearField(invitecode.FieldNote, field.TypeString)
+	}
+	if value, ok := icu.mutation.MaxUses(); ok {
+		_spec.SetField(invitecode.FieldMaxUses, field.TypeInt, value)
+	}
+	if value, ok := icu.mutation.AddedMaxUses(); ok {
+		_spec.AddField(invitecode.FieldMaxUses, field.TypeInt, value)
+	}
+	if value, ok := icu.mutation.Uses(); ok {
+		_spec.SetField(invitecode.FieldUses, field.TypeInt, value)
+	}
+	if value, ok := icu.mutation.AddedUses(); ok {
+		_spec.AddField(invitecode.FieldUses, field.TypeInt, value)
+	}
+	if value, ok := icu.mutation.ExpiresAt(); ok {
+		_spec.SetField(invitecode.FieldExpiresAt, field.TypeTime, value)
+	}
+	if icu.mutation.ExpiresAtCleared() {
+		_spec.ClearField(invitecode.FieldExpiresAt, field.TypeTime)
+	}
+	if icu.mutation.CreatorCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   invitecode.CreatorTable,
+			Columns: []string{invitecode.CreatorColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := icu.mutation.CreatorIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   invitecode.CreatorTable,
+			Columns: []string{invitecode.CreatorColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, icu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{invitecode.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	icu.mutation.done = true
+	return n, nil
+}
+
+// InviteCodeUpdateOne is the builder for updating a single InviteCode entity.
+type InviteCodeUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *InviteCodeMutation
+}
+
+// SetHash sets the "hash" field.
+func (icuo *InviteCodeUpdateOne) SetHash(s string) *InviteCodeUpdateOne {
+	icuo.mutation.SetHash(s)
+	return icuo
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (icuo *InviteCodeUpdateOne) SetNillableHash(s *string) *InviteCodeUpdateOne {
+	if s != nil {
+		icuo.SetHash(*s)
+	}
+	return icuo
+}
+
+// SetNote sets the "note" field.
+func (icuo *InviteCodeUpdateOne) SetNote(s string) *InviteCodeUpdateOne {
+	icuo.mutation.SetNote(s)
+	return icuo
+}
+
+// SetNillableNote sets the "note" field if the given value is not nil.
+func (icuo *InviteCodeUpdateOne) SetNillableNote(s *string) *InviteCodeUpdateOne {
+	if s != nil {
+		icuo.SetNote(*s)
+	}
+	return icuo
+}
+
+// ClearNote clears the value of the "note" field.
+func (icuo *InviteCodeUpdateOne) ClearNote() *InviteCodeUpdateOne {
+	icuo.mutation.ClearNote()
+	return icuo
+}
+
+// SetMaxUses sets the "max_uses" field.
+func (icuo *InviteCodeUpdateOne) SetMaxUses(i int) *InviteCodeUpdateOne {
+	icuo.mutation.ResetMaxUses()
+	icuo.mutation.SetMaxUses(i)
+	return icuo
+}
+
+// SetNillableMaxUses sets the "max_uses" field if the given value is not nil.
+func (icuo *InviteCodeUpdateOne) SetNillableMaxUses(i *int) *InviteCodeUpdateOne {
+	if i != nil {
+		icuo.SetMaxUses(*i)
+	}
+	return icuo
+}
+
+// AddMaxUses adds i to the "max_uses" field.
+func (icuo *InviteCodeUpdateOne) AddMaxUses(i int) *InviteCodeUpdateOne {
+	icuo.mutation.AddMaxUses(i)
+	return icuo
+}
+
+// SetUses sets the "uses" field.
+func (icuo *InviteCodeUpdateOne) SetUses(i int) *InviteCodeUpdateOne {
+	icuo.mutation.ResetUses()
+	icuo.mutation.SetUses(i)
+	return icuo
+}
+
+// SetNillableUses sets the "uses" field if the given value is not nil.
+func (icuo *InviteCodeUpdateOne) SetNillableUses(i *int) *InviteCodeUpdateOne {
+	if i != nil {
+		icuo.SetUses(*i)
+	}
+	return icuo
+}
+
+// AddUses adds i to the "uses" field.
+func (icuo *InviteCodeUpdateOne) AddUses(i int) *InviteCodeUpdateOne {
+	icuo.mutation.AddUses(i)
+	return icuo
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (icuo *InviteCodeUpdateOne) SetExpiresAt(t time.Time) *InviteCodeUpdateOne {
+	icuo.mutation.SetExpiresAt(t)
+	return icuo
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (icuo *InviteCodeUpdateOne) SetNillableExpiresAt(t *time.Time) *InviteCodeUpdateOne {
+	if t != nil {
+		icuo.SetExpiresAt(*t)
+	}
+	return icuo
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (icuo *InviteCodeUpdateOne) ClearExpiresAt() *InviteCodeUpdateOne {
+	icuo.mutation.ClearExpiresAt()
+	return icuo
+}
+
+// SetCreatorID sets the "creator" edge to the User entity by ID.
+func (icuo *InviteCodeUpdateOne) SetCreatorID(id int) *InviteCodeUpdateOne {
+	icuo.mutation.SetCreatorID(id)
+	return icuo
+}
+
+// SetNillableCreatorID sets the "creator" edge to the User entity by ID if the given value is not nil.
+func (icuo *InviteCodeUpdateOne) SetNillableCreatorID(id *int) *InviteCodeUpdateOne {
+	if id != nil {
+		icuo = icuo.SetCreatorID(*id)
+	}
+	return icuo
+}
+
+// SetCreator sets the "creator" edge to the User entity.
+func (icuo *InviteCodeUpdateOne) SetCreator(u *User) *InviteCodeUpdateOne {
+	return icuo.SetCreatorID(u.ID)
+}
+
+// Mutation returns the InviteCodeMutation object of the builder.
+func (icuo *InviteCodeUpdateOne) Mutation() *InviteCodeMutation {
+	return icuo.mutation
+}
+
+// ClearCreator clears the "creator" edge to the User entity.
+func (icuo *InviteCodeUpdateOne) ClearCreator() *InviteCodeUpdateOne {
+	icuo.mutation.ClearCreator()
+	return icuo
+}
+
+// Where appends a list predicates to the InviteCodeUpdate builder.
+func (icuo *InviteCodeUpdateOne) Where(ps ...predicate.InviteCode) *InviteCodeUpdateOne {
+	icuo.mutation.Where(ps...)
+	return icuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (icuo *InviteCodeUpdateOne) Select(field string, fields ...string) *InviteCodeUpdateOne {
+	icuo.fields = append([]string{field}, fields...)
+	return icuo
+}
+
+// Save executes the query and returns the updated InviteCode entity.
+func (icuo *InviteCodeUpdateOne) Save(ctx context.Context) (*InviteCode, error) {
+	return withHooks(ctx, icuo.sqlSave, icuo.mutation, icuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (icuo *InviteCodeUpdateOne) SaveX(ctx context.Context) *InviteCode {
+	node, err := icuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (icuo *InviteCodeUpdateOne) Exec(ctx context.Context) error {
+	_, err := icuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (icuo *InviteCodeUpdateOne) ExecX(ctx context.Context) {
+	if err := icuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (icuo *InviteCodeUpdateOne) check() error {
+	if v, ok := icuo.mutation.Hash(); ok {
+		if err := invitecode.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "InviteCode.hash": %w`, err)}
+		}
+	}
+	if v, ok := icuo.mutation.MaxUses(); ok {
+		if err := invitecode.MaxUsesValidator(v); err != nil {
+			return &ValidationError{Name: "max_uses", err: fmt.Errorf(`ent: validator failed for field "InviteCode.max_uses": %w`, err)}
+		}
+	}
+	if v, ok := icuo.mutation.Uses(); ok {
+		if err := invitecode.UsesValidator(v); err != nil {
+			return &ValidationError{Name: "uses", err: fmt.Errorf(`ent: validator failed for field "InviteCode.uses": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (icuo *InviteCodeUpdateOne) sqlSave(ctx context.Context) (_node *InviteCode, err error) {
+	if err := icuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(invitecode.Table, invitecode.Columns, sqlgraph.NewFieldSpec(invitecode.FieldID, field.TypeInt))
+	id, ok := icuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "InviteCode.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := icuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, invitecode.FieldID)
+		for _, f := range fields {
+			if !invitecode.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != invitecode.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := icuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := icuo.mutation.Hash(); ok {
+		_spec.SetField(invitecode.FieldHash, field.TypeString, value)
+	}
+	if value, ok := icuo.mutation.Note(); ok {
+		_spec.SetField(invitecode.FieldNote, field.TypeString, value)
+	}
+	if icuo.mutation.NoteCleared() {
+		_spec.ClearField(invitecode.FieldNote, field.TypeString)
+	}
+	if value, ok := icuo.mutation.MaxUses(); ok {
+		_spec.SetField(invitecode.FieldMaxUses, field.TypeInt, value)
+	}
+	if value, ok := icuo.mutation.AddedMaxUses(); ok {
+		_spec.AddField(invitecode.FieldMaxUses, field.TypeInt, value)
+	}
+	if value, ok := icuo.mutation.Uses(); ok {
+		_spec.SetField(invitecode.FieldUses, field.TypeInt, value)
+	}
+	if value, ok := icuo.mutation.AddedUses(); ok {
+		_spec.AddField(invitecode.FieldUses, field.TypeInt, value)
+	}
+	if value, ok := icuo.mutation.ExpiresAt(); ok {
+		_spec.SetField(invitecode.FieldExpiresAt, field.TypeTime, value)
+	}
+	if icuo.mutation.ExpiresAtCleared() {
+		_spec.ClearField(invitecode.FieldExpiresAt, field.TypeTime)
+	}
+	if icuo.mutation.CreatorCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   invitecode.CreatorTable,
+			Columns: []string{invitecode.CreatorColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := icuo.mutation.CreatorIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   invitecode.CreatorTable,
+			Columns: []string{invitecode.CreatorColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &InviteCode{config: icuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, icuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{invitecode.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	icuo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,279 @@
+// Code generated by ent, DO NOT EDIT.
+
+package logintoken
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/mikestefanello/pagoda/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldLTE(FieldID, id))
+}
+
+// Hash applies equality check predicate on the "hash" field. It's identical to HashEQ.
+func Hash(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldEQ(FieldHash, v))
+}
+
+// BrowserHash applies equality check predicate on the "browser_hash" field. It's identical to BrowserHashEQ.
+func BrowserHash(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldEQ(FieldBrowserHash, v))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// HashEQ applies the EQ predicate on the "hash" field.
+func HashEQ(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldEQ(FieldHash, v))
+}
+
+// HashNEQ applies the NEQ predicate on the "hash" field.
+func HashNEQ(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldNEQ(FieldHash, v))
+}
+
+// HashIn applies the In predicate on the "hash" field.
+func HashIn(vs ...string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldIn(FieldHash, vs...))
+}
+
+// HashNotIn applies the NotIn predicate on the "hash" field.
+func HashNotIn(vs ...string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldNotIn(FieldHash, vs...))
+}
+
+// HashGT applies the GT predicate on the "hash" field.
+func HashGT(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldGT(FieldHash, v))
+}
+
+// HashGTE applies the GTE predicate on the "hash" field.
+func HashGTE(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldGTE(FieldHash, v))
+}
+
+// HashLT applies the LT predicate on the "hash" field.
+func HashLT(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldLT(FieldHash, v))
+}
+
+// HashLTE applies the LTE predicate on the "hash" field.
+func HashLTE(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldLTE(FieldHash, v))
+}
+
+// HashContains applies the Contains predicate on the "hash" field.
+func HashContains(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldContains(FieldHash, v))
+}
+
+// HashHasPrefix applies the HasPrefix predicate on the "hash" field.
+func HashHasPrefix(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldHasPrefix(FieldHash, v))
+}
+
+// HashHasSuffix applies the HasSuffix predicate on the "hash" field.
+func HashHasSuffix(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldHasSuffix(FieldHash, v))
+}
+
+// HashEqualFold applies the EqualFold predicate on the "hash" field.
+func HashEqualFold(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldEqualFold(FieldHash, v))
+}
+
+// HashContainsFold applies the ContainsFold predicate on the "hash" field.
+func HashContainsFold(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldContainsFold(FieldHash, v))
+}
+
+// BrowserHashEQ applies the EQ predicate on the "browser_hash" field.
+func BrowserHashEQ(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldEQ(FieldBrowserHash, v))
+}
+
+// BrowserHashNEQ applies the NEQ predicate on the "browser_hash" field.
+func BrowserHashNEQ(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldNEQ(FieldBrowserHash, v))
+}
+
+// BrowserHashIn applies the In predicate on the "browser_hash" field.
+func BrowserHashIn(vs ...string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldIn(FieldBrowserHash, vs...))
+}
+
+// BrowserHashNotIn applies the NotIn predicate on the "browser_hash" field.
+func BrowserHashNotIn(vs ...string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldNotIn(FieldBrowserHash, vs...))
+}
+
+// BrowserHashGT applies the GT predicate on the "browser_hash" field.
+func BrowserHashGT(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldGT(FieldBrowserHash, v))
+}
+
+// BrowserHashGTE applies the GTE predicate on the "browser_hash" field.
+func BrowserHashGTE(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldGTE(FieldBrowserHash, v))
+}
+
+// BrowserHashLT applies the LT predicate on the "browser_hash" field.
+func BrowserHashLT(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldLT(FieldBrowserHash, v))
+}
+
+// BrowserHashLTE applies the LTE predicate on the "browser_hash" field.
+func BrowserHashLTE(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldLTE(FieldBrowserHash, v))
+}
+
+// BrowserHashContains applies the Contains predicate on the "browser_hash" field.
+func BrowserHashContains(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldContains(FieldBrowserHash, v))
+}
+
+// BrowserHashHasPrefix applies the HasPrefix predicate on the "browser_hash" field.
+func BrowserHashHasPrefix(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldHasPrefix(FieldBrowserHash, v))
+}
+
+// BrowserHashHasSuffix applies the HasSuffix predicate on the "browser_hash" field.
+func BrowserHashHasSuffix(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldHasSuffix(FieldBrowserHash, v))
+}
+
+// BrowserHashEqualFold applies the EqualFold predicate on the "browser_hash" field.
+func BrowserHashEqualFold(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldEqualFold(FieldBrowserHash, v))
+}
+
+// BrowserHashContainsFold applies the ContainsFold predicate on the "browser_hash" field.
+func BrowserHashContainsFold(v string) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldContainsFold(FieldBrowserHash, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.LoginToken {
+	return predicate.LoginToken(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// HasUser applies the HasEdge predicate on the "user" edge.
+func HasUser() predicate.LoginToken {
+	return predicate.LoginToken(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, false, UserTable, UserColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasUserWith applies the HasEdge predicate on the "user" edge with a given conditions (other predicates).
+func HasUserWith(preds ...predicate.User) predicate.LoginToken {
+	return predicate.LoginToken(func(s *sql.Selector) {
+		step := newUserStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.LoginToken) predicate.LoginToken {
+	return predicate.LoginToken(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.LoginToken) predicate.LoginToken {
+	return predicate.LoginToken(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.LoginToken) predicate.LoginToken {
+	return predicate.LoginToken(sql.NotPredicates(p))
+}
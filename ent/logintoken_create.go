@@ -0,0 +1,270 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/logintoken"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+// LoginTokenCreate is the builder for creating a LoginToken entity.
+type LoginTokenCreate struct {
+	config
+	mutation *LoginTokenMutation
+	hooks    []Hook
+}
+
+// SetHash sets the "hash" field.
+func (ltc *LoginTokenCreate) SetHash(s string) *LoginTokenCreate {
+	ltc.mutation.SetHash(s)
+	return ltc
+}
+
+// SetBrowserHash sets the "browser_hash" field.
+func (ltc *LoginTokenCreate) SetBrowserHash(s string) *LoginTokenCreate {
+	ltc.mutation.SetBrowserHash(s)
+	return ltc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (ltc *LoginTokenCreate) SetCreatedAt(t time.Time) *LoginTokenCreate {
+	ltc.mutation.SetCreatedAt(t)
+	return ltc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (ltc *LoginTokenCreate) SetNillableCreatedAt(t *time.Time) *LoginTokenCreate {
+	if t != nil {
+		ltc.SetCreatedAt(*t)
+	}
+	return ltc
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (ltc *LoginTokenCreate) SetUserID(id int) *LoginTokenCreate {
+	ltc.mutation.SetUserID(id)
+	return ltc
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (ltc *LoginTokenCreate) SetUser(u *User) *LoginTokenCreate {
+	return ltc.SetUserID(u.ID)
+}
+
+// Mutation returns the LoginTokenMutation object of the builder.
+func (ltc *LoginTokenCreate) Mutation() *LoginTokenMutation {
+	return ltc.mutation
+}
+
+// Save creates the LoginToken in the database.
+func (ltc *LoginTokenCreate) Save(ctx context.Context) (*LoginToken, error) {
+	ltc.defaults()
+	return withHooks(ctx, ltc.sqlSave, ltc.mutation, ltc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (ltc *LoginTokenCreate) SaveX(ctx context.Context) *LoginToken {
+	v, err := ltc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ltc *LoginTokenCreate) Exec(ctx context.Context) error {
+	_, err := ltc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ltc *LoginTokenCreate) ExecX(ctx context.Context) {
+	if err := ltc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ltc *LoginTokenCreate) defaults() {
+	if _, ok := ltc.mutation.CreatedAt(); !ok {
+		v := logintoken.DefaultCreatedAt()
+		ltc.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ltc *LoginTokenCreate) check() error {
+	if _, ok := ltc.mutation.Hash(); !ok {
+		return &ValidationError{Name: "hash", err: errors.New(`ent: missing required field "LoginToken.hash"`)}
+	}
+	if v, ok := ltc.mutation.Hash(); ok {
+		if err := logintoken.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "LoginToken.hash": %w`, err)}
+		}
+	}
+	if _, ok := ltc.mutation.BrowserHash(); !ok {
+		return &ValidationError{Name: "browser_hash", err: errors.New(`ent: missing required field "LoginToken.browser_hash"`)}
+	}
+	if v, ok := ltc.mutation.BrowserHash(); ok {
+		if err := logintoken.BrowserHashValidator(v); err != nil {
+			return &ValidationError{Name: "browser_hash", err: fmt.Errorf(`ent: validator failed for field "LoginToken.browser_hash": %w`, err)}
+		}
+	}
+	if _, ok := ltc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "LoginToken.created_at"`)}
+	}
+	if _, ok := ltc.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "LoginToken.user"`)}
+	}
+	return nil
+}
+
+func (ltc *LoginTokenCreate) sqlSave(ctx context.Context) (*LoginToken, error) {
+	if err := ltc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := ltc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, ltc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	ltc.mutation.id = &_node.ID
+	ltc.mutation.done = true
+	return _node, nil
+}
+
+func (ltc *LoginTokenCreate) createSpec() (*LoginToken, *sqlgraph.CreateSpec) {
+	var (
+		_node = &LoginToken{config: ltc.config}
+		_spec = sqlgraph.NewCreateSpec(logintoken.Table, sqlgraph.NewFieldSpec(logintoken.FieldID, field.TypeInt))
+	)
+	if value, ok := ltc.mutation.Hash(); ok {
+		_spec.SetField(logintoken.FieldHash, field.TypeString, value)
+		_node.Hash = value
+	}
+	if value, ok := ltc.mutation.BrowserHash(); ok {
+		_spec.SetField(logintoken.FieldBrowserHash, field.TypeString, value)
+		_node.BrowserHash = value
+	}
+	if value, ok := ltc.mutation.CreatedAt(); ok {
+		_spec.SetField(logintoken.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if nodes := ltc.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   logintoken.UserTable,
+			Columns: []string{logintoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.login_token_user = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// LoginTokenCreateBulk is the builder for creating many LoginToken entities in bulk.
+type LoginTokenCreateBulk struct {
+	config
+	err      error
+	builders []*LoginTokenCreate
+}
+
+// Save creates the LoginToken entities in the database.
+func (ltcb *LoginTokenCreateBulk) Save(ctx context.Context) ([]*LoginToken, error) {
+	if ltcb.err != nil {
+		return nil, ltcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(ltcb.builders))
+	nodes := make([]*LoginToken, len(ltcb.builders))
+	mutators := make([]Mutator, len(ltcb.builders))
+	for i := range ltcb.builders {
+		func(i int, root context.Context) {
+			builder := ltcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*LoginTokenMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, ltcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, ltcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, ltcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ltcb *LoginTokenCreateBulk) SaveX(ctx context.Context) []*LoginToken {
+	v, err := ltcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ltcb *LoginTokenCreateBulk) Exec(ctx context.Context) error {
+	_, err := ltcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ltcb *LoginTokenCreateBulk) ExecX(ctx context.Context) {
+	if err := ltcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,613 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/logintoken"
+	"github.com/mikestefanello/pagoda/ent/predicate"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+// LoginTokenQuery is the builder for querying LoginToken entities.
+type LoginTokenQuery struct {
+	config
+	ctx        *QueryContext
+	order      []logintoken.OrderOption
+	inters     []Interceptor
+	predicates []predicate.LoginToken
+	withUser   *UserQuery
+	withFKs    bool
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the LoginTokenQuery builder.
+func (ltq *LoginTokenQuery) Where(ps ...predicate.LoginToken) *LoginTokenQuery {
+	ltq.predicates = append(ltq.predicates, ps...)
+	return ltq
+}
+
+// Limit the number of records to be returned by this query.
+func (ltq *LoginTokenQuery) Limit(limit int) *LoginTokenQuery {
+	ltq.ctx.Limit = &limit
+	return ltq
+}
+
+// Offset to start from.
+func (ltq *LoginTokenQuery) Offset(offset int) *LoginTokenQuery {
+	ltq.ctx.Offset = &offset
+	return ltq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (ltq *LoginTokenQuery) Unique(unique bool) *LoginTokenQuery {
+	ltq.ctx.Unique = &unique
+	return ltq
+}
+
+// Order specifies how the records should be ordered.
+func (ltq *LoginTokenQuery) Order(o ...logintoken.OrderOption) *LoginTokenQuery {
+	ltq.order = append(ltq.order, o...)
+	return ltq
+}
+
+// QueryUser chains the current query on the "user" edge.
+func (ltq *LoginTokenQuery) QueryUser() *UserQuery {
+	query := (&UserClient{config: ltq.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := ltq.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := ltq.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(logintoken.Table, logintoken.FieldID, selector),
+			sqlgraph.To(user.Table, user.FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, false, logintoken.UserTable, logintoken.UserColumn),
+		)
+		fromU = sqlgraph.SetNeighbors(ltq.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
+// First returns the first LoginToken entity from the query.
+// Returns a *NotFoundError when no LoginToken was found.
+func (ltq *LoginTokenQuery) First(ctx context.Context) (*LoginToken, error) {
+	nodes, err := ltq.Limit(1).All(setContextOp(ctx, ltq.ctx, "First"))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{logintoken.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (ltq *LoginTokenQuery) FirstX(ctx context.Context) *LoginToken {
+	node, err := ltq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first LoginToken ID from the query.
+// Returns a *NotFoundError when no LoginToken ID was found.
+func (ltq *LoginTokenQuery) FirstID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = ltq.Limit(1).IDs(setContextOp(ctx, ltq.ctx, "FirstID")); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{logintoken.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (ltq *LoginTokenQuery) FirstIDX(ctx context.Context) int {
+	id, err := ltq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single LoginToken entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one LoginToken entity is found.
+// Returns a *NotFoundError when no LoginToken entities are found.
+func (ltq *LoginTokenQuery) Only(ctx context.Context) (*LoginToken, error) {
+	nodes, err := ltq.Limit(2).All(setContextOp(ctx, ltq.ctx, "Only"))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{logintoken.Label}
+	default:
+		return nil, &NotSingularError{logintoken.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (ltq *LoginTokenQuery) OnlyX(ctx context.Context) *LoginToken {
+	node, err := ltq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only LoginToken ID in the query.
+// Returns a *NotSingularError when more than one LoginToken ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (ltq *LoginTokenQuery) OnlyID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = ltq.Limit(2).IDs(setContextOp(ctx, ltq.ctx, "OnlyID")); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{logintoken.Label}
+	default:
+		err = &NotSingularError{logintoken.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (ltq *LoginTokenQuery) OnlyIDX(ctx context.Context) int {
+	id, err := ltq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of LoginTokens.
+func (ltq *LoginTokenQuery) All(ctx context.Context) ([]*LoginToken, error) {
+	ctx = setContextOp(ctx, ltq.ctx, "All")
+	if err := ltq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*LoginToken, *LoginTokenQuery]()
+	return withInterceptors[[]*LoginToken](ctx, ltq, qr, ltq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (ltq *LoginTokenQuery) AllX(ctx context.Context) []*LoginToken {
+	nodes, err := ltq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of LoginToken IDs.
+func (ltq *LoginTokenQuery) IDs(ctx context.Context) (ids []int, err error) {
+	if ltq.ctx.Unique == nil && ltq.path != nil {
+		ltq.Unique(true)
+	}
+	ctx = setContextOp(ctx, ltq.ctx, "IDs")
+	if err = ltq.Select(logintoken.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (ltq *LoginTokenQuery) IDsX(ctx context.Context) []int {
+	ids, err := ltq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (ltq *LoginTokenQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, ltq.ctx, "Count")
+	if err := ltq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, ltq, querierCount[*LoginTokenQuery](), ltq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (ltq *LoginTokenQuery) CountX(ctx context.Context) int {
+	count, err := ltq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (ltq *LoginTokenQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, ltq.ctx, "Exist")
+	switch _, err := ltq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("ent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (ltq *LoginTokenQuery) ExistX(ctx context.Context) bool {
+	exist, err := ltq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the LoginTokenQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (ltq *LoginTokenQuery) Clone() *LoginTokenQuery {
+	if ltq == nil {
+		return nil
+	}
+	return &LoginTokenQuery{
+		config:     ltq.config,
+		ctx:        ltq.ctx.Clone(),
+		order:      append([]logintoken.OrderOption{}, ltq.order...),
+		inters:     append([]Interceptor{}, ltq.inters...),
+		predicates: append([]predicate.LoginToken{}, ltq.predicates...),
+		withUser:   ltq.withUser.Clone(),
+		// clone intermediate query.
+		sql:  ltq.sql.Clone(),
+		path: ltq.path,
+	}
+}
+
+// WithUser tells the query-builder to eager-load the nodes that are connected to
+// the "user" edge. The optional arguments are used to configure the query builder of the edge.
+func (ltq *LoginTokenQuery) WithUser(opts ...func(*UserQuery)) *LoginTokenQuery {
+	query := (&UserClient{config: ltq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	ltq.withUser = query
+	return ltq
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		Hash string `json:"hash,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.LoginToken.Query().
+//		GroupBy(logintoken.FieldHash).
+//		Aggregate(ent.Count()).
+//		Scan(ctx, &v)
+func (ltq *LoginTokenQuery) GroupBy(field string, fields ...string) *LoginTokenGroupBy {
+	ltq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &LoginTokenGroupBy{build: ltq}
+	grbuild.flds = &ltq.ctx.Fields
+	grbuild.label = logintoken.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		Hash string `json:"hash,omitempty"`
+//	}
+//
+//	client.LoginToken.Query().
+//		Select(logintoken.FieldHash).
+//		Scan(ctx, &v)
+func (ltq *LoginTokenQuery) Select(fields ...string) *LoginTokenSelect {
+	ltq.ctx.Fields = append(ltq.ctx.Fields, fields...)
+	sbuild := &LoginTokenSelect{LoginTokenQuery: ltq}
+	sbuild.label = logintoken.Label
+	sbuild.flds, sbuild.scan = &ltq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a LoginTokenSelect configured with the given aggregations.
+func (ltq *LoginTokenQuery) Aggregate(fns ...AggregateFunc) *LoginTokenSelect {
+	return ltq.Select().Aggregate(fns...)
+}
+
+func (ltq *LoginTokenQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range ltq.inters {
+		if inter == nil {
+			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, ltq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range ltq.ctx.Fields {
+		if !logintoken.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+		}
+	}
+	if ltq.path != nil {
+		prev, err := ltq.path(ctx)
+		if err != nil {
+			return err
+		}
+		ltq.sql = prev
+	}
+	return nil
+}
+
+func (ltq *LoginTokenQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*LoginToken, error) {
+	var (
+		nodes       = []*LoginToken{}
+		withFKs     = ltq.withFKs
+		_spec       = ltq.querySpec()
+		loadedTypes = [1]bool{
+			ltq.withUser != nil,
+		}
+	)
+	if ltq.withUser != nil {
+		withFKs = true
+	}
+	if withFKs {
+		_spec.Node.Columns = append(_spec.Node.Columns, logintoken.ForeignKeys...)
+	}
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*LoginToken).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &LoginToken{config: ltq.config}
+		nodes = append(nodes, node)
+		node.Edges.loadedTypes = loadedTypes
+		return node.assignValues(columns, values)
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, ltq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	if query := ltq.withUser; query != nil {
+		if err := ltq.loadUser(ctx, query, nodes, nil,
+			func(n *LoginToken, e *User) { n.Edges.User = e }); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func (ltq *LoginTokenQuery) loadUser(ctx context.Context, query *UserQuery, nodes []*LoginToken, init func(*LoginToken), assign func(*LoginToken, *User)) error {
+	ids := make([]int, 0, len(nodes))
+	nodeids := make(map[int][]*LoginToken)
+	for i := range nodes {
+		if nodes[i].login_token_user == nil {
+			continue
+		}
+		fk := *nodes[i].login_token_user
+		if _, ok := nodeids[fk]; !ok {
+			ids = append(ids, fk)
+		}
+		nodeids[fk] = append(nodeids[fk], nodes[i])
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	query.Where(user.IDIn(ids...))
+	neighbors, err := query.All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		nodes, ok := nodeids[n.ID]
+		if !ok {
+			return fmt.Errorf(`unexpected foreign-key "login_token_user" returned %v`, n.ID)
+		}
+		for i := range nodes {
+			assign(nodes[i], n)
+		}
+	}
+	return nil
+}
+
+func (ltq *LoginTokenQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := ltq.querySpec()
+	_spec.Node.Columns = ltq.ctx.Fields
+	if len(ltq.ctx.Fields) > 0 {
+		_spec.Unique = ltq.ctx.Unique != nil && *ltq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, ltq.driver, _spec)
+}
+
+func (ltq *LoginTokenQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := sqlgraph.NewQuerySpec(logintoken.Table, logintoken.Columns, sqlgraph.NewFieldSpec(logintoken.FieldID, field.TypeInt))
+	_spec.From = ltq.sql
+	if unique := ltq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	} else if ltq.path != nil {
+		_spec.Unique = true
+	}
+	if fields := ltq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, logintoken.FieldID)
+		for i := range fields {
+			if fields[i] != logintoken.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+	}
+	if ps := ltq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := ltq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := ltq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := ltq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (ltq *LoginTokenQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(ltq.driver.Dialect())
+	t1 := builder.Table(logintoken.Table)
+	columns := ltq.ctx.Fields
+	if len(columns) == 0 {
+		columns = logintoken.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if ltq.sql != nil {
+		selector = ltq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if ltq.ctx.Unique != nil && *ltq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, p := range ltq.predicates {
+		p(selector)
+	}
+	for _, p := range ltq.order {
+		p(selector)
+	}
+	if offset := ltq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := ltq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// LoginTokenGroupBy is the group-by builder for LoginToken entities.
+type LoginTokenGroupBy struct {
+	selector
+	build *LoginTokenQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (ltgb *LoginTokenGroupBy) Aggregate(fns ...AggregateFunc) *LoginTokenGroupBy {
+	ltgb.fns = append(ltgb.fns, fns...)
+	return ltgb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (ltgb *LoginTokenGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, ltgb.build.ctx, "GroupBy")
+	if err := ltgb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*LoginTokenQuery, *LoginTokenGroupBy](ctx, ltgb.build, ltgb, ltgb.build.inters, v)
+}
+
+func (ltgb *LoginTokenGroupBy) sqlScan(ctx context.Context, root *LoginTokenQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(ltgb.fns))
+	for _, fn := range ltgb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*ltgb.flds)+len(ltgb.fns))
+		for _, f := range *ltgb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*ltgb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := ltgb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// LoginTokenSelect is the builder for selecting fields of LoginToken entities.
+type LoginTokenSelect struct {
+	*LoginTokenQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (lts *LoginTokenSelect) Aggregate(fns ...AggregateFunc) *LoginTokenSelect {
+	lts.fns = append(lts.fns, fns...)
+	return lts
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (lts *LoginTokenSelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, lts.ctx, "Select")
+	if err := lts.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*LoginTokenQuery, *LoginTokenSelect](ctx, lts.LoginTokenQuery, lts, lts.inters, v)
+}
+
+func (lts *LoginTokenSelect) sqlScan(ctx context.Context, root *LoginTokenQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(lts.fns))
+	for _, fn := range lts.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*lts.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := lts.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
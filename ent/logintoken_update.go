@@ -0,0 +1,378 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/logintoken"
+	"github.com/mikestefanello/pagoda/ent/predicate"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+// LoginTokenUpdate is the builder for updating LoginToken entities.
+type LoginTokenUpdate struct {
+	config
+	hooks    []Hook
+	mutation *LoginTokenMutation
+}
+
+// Where appends a list predicates to the LoginTokenUpdate builder.
+func (ltu *LoginTokenUpdate) Where(ps ...predicate.LoginToken) *LoginTokenUpdate {
+	ltu.mutation.Where(ps...)
+	return ltu
+}
+
+// SetHash sets the "hash" field.
+func (ltu *LoginTokenUpdate) SetHash(s string) *LoginTokenUpdate {
+	ltu.mutation.SetHash(s)
+	return ltu
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (ltu *LoginTokenUpdate) SetNillableHash(s *string) *LoginTokenUpdate {
+	if s != nil {
+		ltu.SetHash(*s)
+	}
+	return ltu
+}
+
+// SetBrowserHash sets the "browser_hash" field.
+func (ltu *LoginTokenUpdate) SetBrowserHash(s string) *LoginTokenUpdate {
+	ltu.mutation.SetBrowserHash(s)
+	return ltu
+}
+
+// SetNillableBrowserHash sets the "browser_hash" field if the given value is not nil.
+func (ltu *LoginTokenUpdate) SetNillableBrowserHash(s *string) *LoginTokenUpdate {
+	if s != nil {
+		ltu.SetBrowserHash(*s)
+	}
+	return ltu
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (ltu *LoginTokenUpdate) SetUserID(id int) *LoginTokenUpdate {
+	ltu.mutation.SetUserID(id)
+	return ltu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (ltu *LoginTokenUpdate) SetUser(u *User) *LoginTokenUpdate {
+	return ltu.SetUserID(u.ID)
+}
+
+// Mutation returns the LoginTokenMutation object of the builder.
+func (ltu *LoginTokenUpdate) Mutation() *LoginTokenMutation {
+	return ltu.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (ltu *LoginTokenUpdate) ClearUser() *LoginTokenUpdate {
+	ltu.mutation.ClearUser()
+	return ltu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (ltu *LoginTokenUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, ltu.sqlSave, ltu.mutation, ltu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ltu *LoginTokenUpdate) SaveX(ctx context.Context) int {
+	affected, err := ltu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (ltu *LoginTokenUpdate) Exec(ctx context.Context) error {
+	_, err := ltu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ltu *LoginTokenUpdate) ExecX(ctx context.Context) {
+	if err := ltu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ltu *LoginTokenUpdate) check() error {
+	if v, ok := ltu.mutation.Hash(); ok {
+		if err := logintoken.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "LoginToken.hash": %w`, err)}
+		}
+	}
+	if v, ok := ltu.mutation.BrowserHash(); ok {
+		if err := logintoken.BrowserHashValidator(v); err != nil {
+			return &ValidationError{Name: "browser_hash", err: fmt.Errorf(`ent: validator failed for field "LoginToken.browser_hash": %w`, err)}
+		}
+	}
+	if _, ok := ltu.mutation.UserID(); ltu.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "LoginToken.user"`)
+	}
+	return nil
+}
+
+func (ltu *LoginTokenUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := ltu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(logintoken.Table, logintoken.Columns, sqlgraph.NewFieldSpec(logintoken.FieldID, field.TypeInt))
+	if ps := ltu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := ltu.mutation.Hash(); ok {
+		_spec.SetField(logintoken.FieldHash, field.TypeString, value)
+	}
+	if value, ok := ltu.mutation.BrowserHash(); ok {
+		_spec.SetField(logintoken.FieldBrowserHash, field.TypeString, value)
+	}
+	if ltu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   logintoken.UserTable,
+			Columns: []string{logintoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ltu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   logintoken.UserTable,
+			Columns: []string{logintoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, ltu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{logintoken.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	ltu.mutation.done = true
+	return n, nil
+}
+
+// LoginTokenUpdateOne is the builder for updating a single LoginToken entity.
+type LoginTokenUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *LoginTokenMutation
+}
+
+// SetHash sets the "hash" field.
+func (ltuo *LoginTokenUpdateOne) SetHash(s string) *LoginTokenUpdateOne {
+	ltuo.mutation.SetHash(s)
+	return ltuo
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (ltuo *LoginTokenUpdateOne) SetNillableHash(s *string) *LoginTokenUpdateOne {
+	if s != nil {
+		ltuo.SetHash(*s)
+	}
+	return ltuo
+}
+
+// SetBrowserHash sets the "browser_hash" field.
+func (ltuo *LoginTokenUpdateOne) SetBrowserHash(s string) *LoginTokenUpdateOne {
+	ltuo.mutation.SetBrowserHash(s)
+	return ltuo
+}
+
+// SetNillableBrowserHash sets the "browser_hash" field if the given value is not nil.
+func (ltuo *LoginTokenUpdateOne) SetNillableBrowserHash(s *string) *LoginTokenUpdateOne {
+	if s != nil {
+		ltuo.SetBrowserHash(*s)
+	}
+	return ltuo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (ltuo *LoginTokenUpdateOne) SetUserID(id int) *LoginTokenUpdateOne {
+	ltuo.mutation.SetUserID(id)
+	return ltuo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (ltuo *LoginTokenUpdateOne) SetUser(u *User) *LoginTokenUpdateOne {
+	return ltuo.SetUserID(u.ID)
+}
+
+// Mutation returns the LoginTokenMutation object of the builder.
+func (ltuo *LoginTokenUpdateOne) Mutation() *LoginTokenMutation {
+	return ltuo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (ltuo *LoginTokenUpdateOne) ClearUser() *LoginTokenUpdateOne {
+	ltuo.mutation.ClearUser()
+	return ltuo
+}
+
+// Where appends a list predicates to the LoginTokenUpdate builder.
+func (ltuo *LoginTokenUpdateOne) Where(ps ...predicate.LoginToken) *LoginTokenUpdateOne {
+	ltuo.mutation.Where(ps...)
+	return ltuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (ltuo *LoginTokenUpdateOne) Select(field string, fields ...string) *LoginTokenUpdateOne {
+	ltuo.fields = append([]string{field}, fields...)
+	return ltuo
+}
+
+// Save executes the query and returns the updated LoginToken entity.
+func (ltuo *LoginTokenUpdateOne) Save(ctx context.Context) (*LoginToken, error) {
+	return withHooks(ctx, ltuo.sqlSave, ltuo.mutation, ltuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ltuo *LoginTokenUpdateOne) SaveX(ctx context.Context) *LoginToken {
+	node, err := ltuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (ltuo *LoginTokenUpdateOne) Exec(ctx context.Context) error {
+	_, err := ltuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ltuo *LoginTokenUpdateOne) ExecX(ctx context.Context) {
+	if err := ltuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ltuo *LoginTokenUpdateOne) check() error {
+	if v, ok := ltuo.mutation.Hash(); ok {
+		if err := logintoken.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "LoginToken.hash": %w`, err)}
+		}
+	}
+	if v, ok := ltuo.mutation.BrowserHash(); ok {
+		if err := logintoken.BrowserHashValidator(v); err != nil {
+			return &ValidationError{Name: "browser_hash", err: fmt.Errorf(`ent: validator failed for field "LoginToken.browser_hash": %w`, err)}
+		}
+	}
+	if _, ok := ltuo.mutation.UserID(); ltuo.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "LoginToken.user"`)
+	}
+	return nil
+}
+
+func (ltuo *LoginTokenUpdateOne) sqlSave(ctx context.Context) (_node *LoginToken, err error) {
+	if err := ltuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(logintoken.Table, logintoken.Columns, sqlgraph.NewFieldSpec(logintoken.FieldID, field.TypeInt))
+	id, ok := ltuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "LoginToken.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := ltuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, logintoken.FieldID)
+		for _, f := range fields {
+			if !logintoken.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != logintoken.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := ltuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := ltuo.mutation.Hash(); ok {
+		_spec.SetField(logintoken.FieldHash, field.TypeString, value)
+	}
+	if value, ok := ltuo.mutation.BrowserHash(); ok {
+		_spec.SetField(logintoken.FieldBrowserHash, field.TypeString, value)
+	}
+	if ltuo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   logintoken.UserTable,
+			Columns: []string{logintoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := ltuo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   logintoken.UserTable,
+			Columns: []string{logintoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &LoginToken{config: ltuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, ltuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{logintoken.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	ltuo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,139 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/mikestefanello/pagoda/ent/mailsuppression"
+)
+
+// MailSuppression is the model entity for the MailSuppression schema.
+type MailSuppression struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// Email holds the value of the "email" field.
+	Email string `json:"email,omitempty"`
+	// Reason holds the value of the "reason" field.
+	Reason mailsuppression.Reason `json:"reason,omitempty"`
+	// Category holds the value of the "category" field.
+	Category string `json:"category,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*MailSuppression) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case mailsuppression.FieldID:
+			values[i] = new(sql.NullInt64)
+		case mailsuppression.FieldEmail, mailsuppression.FieldReason, mailsuppression.FieldCategory:
+			values[i] = new(sql.NullString)
+		case mailsuppression.FieldCreatedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the MailSuppression fields.
+func (ms *MailSuppression) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case mailsuppression.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			ms.ID = int(value.Int64)
+		case mailsuppression.FieldEmail:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field email", values[i])
+			} else if value.Valid {
+				ms.Email = value.String
+			}
+		case mailsuppression.FieldReason:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field reason", values[i])
+			} else if value.Valid {
+				ms.Reason = mailsuppression.Reason(value.String)
+			}
+		case mailsuppression.FieldCategory:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field category", values[i])
+			} else if value.Valid {
+				ms.Category = value.String
+			}
+		case mailsuppression.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				ms.CreatedAt = value.Time
+			}
+		default:
+			ms.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the MailSuppression.
+// This includes values selected through modifiers, order, etc.
+func (ms *MailSuppression) Value(name string) (ent.Value, error) {
+	return ms.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this MailSuppression.
+// Note that you need to call MailSuppression.Unwrap() before calling this method if this MailSuppression
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (ms *MailSuppression) Update() *MailSuppressionUpdateOne {
+	return NewMailSuppressionClient(ms.config).UpdateOne(ms)
+}
+
+// Unwrap unwraps the MailSuppression entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (ms *MailSuppression) Unwrap() *MailSuppression {
+	_tx, ok := ms.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: MailSuppression is not a transactional entity")
+	}
+	ms.config.driver = _tx.drv
+	return ms
+}
+
+// String implements the fmt.Stringer.
+func (ms *MailSuppression) String() string {
+	var builder strings.Builder
+	builder.WriteString("MailSuppression(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", ms.ID))
+	builder.WriteString("email=")
+	builder.WriteString(ms.Email)
+	builder.WriteString(", ")
+	builder.WriteString("reason=")
+	builder.WriteString(fmt.Sprintf("%v", ms.Reason))
+	builder.WriteString(", ")
+	builder.WriteString("category=")
+	builder.WriteString(ms.Category)
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(ms.CreatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// MailSuppressions is a parsable slice of MailSuppression.
+type MailSuppressions []*MailSuppression
@@ -0,0 +1,263 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/mailsuppression"
+)
+
+// MailSuppressionCreate is the builder for creating a MailSuppression entity.
+type MailSuppressionCreate struct {
+	config
+	mutation *MailSuppressionMutation
+	hooks    []Hook
+}
+
+// SetEmail sets the "email" field.
+func (msc *MailSuppressionCreate) SetEmail(s string) *MailSuppressionCreate {
+	msc.mutation.SetEmail(s)
+	return msc
+}
+
+// SetReason sets the "reason" field.
+func (msc *MailSuppressionCreate) SetReason(m mailsuppression.Reason) *MailSuppressionCreate {
+	msc.mutation.SetReason(m)
+	return msc
+}
+
+// SetCategory sets the "category" field.
+func (msc *MailSuppressionCreate) SetCategory(s string) *MailSuppressionCreate {
+	msc.mutation.SetCategory(s)
+	return msc
+}
+
+// SetNillableCategory sets the "category" field if the given value is not nil.
+func (msc *MailSuppressionCreate) SetNillableCategory(s *string) *MailSuppressionCreate {
+	if s != nil {
+		msc.SetCategory(*s)
+	}
+	return msc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (msc *MailSuppressionCreate) SetCreatedAt(t time.Time) *MailSuppressionCreate {
+	msc.mutation.SetCreatedAt(t)
+	return msc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (msc *MailSuppressionCreate) SetNillableCreatedAt(t *time.Time) *MailSuppressionCreate {
+	if t != nil {
+		msc.SetCreatedAt(*t)
+	}
+	return msc
+}
+
+// Mutation returns the MailSuppressionMutation object of the builder.
+func (msc *MailSuppressionCreate) Mutation() *MailSuppressionMutation {
+	return msc.mutation
+}
+
+// Save creates the MailSuppression in the database.
+func (msc *MailSuppressionCreate) Save(ctx context.Context) (*MailSuppression, error) {
+	msc.defaults()
+	return withHooks(ctx, msc.sqlSave, msc.mutation, msc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (msc *MailSuppressionCreate) SaveX(ctx context.Context) *MailSuppression {
+	v, err := msc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (msc *MailSuppressionCreate) Exec(ctx context.Context) error {
+	_, err := msc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (msc *MailSuppressionCreate) ExecX(ctx context.Context) {
+	if err := msc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (msc *MailSuppressionCreate) defaults() {
+	if _, ok := msc.mutation.Category(); !ok {
+		v := mailsuppression.DefaultCategory
+		msc.mutation.SetCategory(v)
+	}
+	if _, ok := msc.mutation.CreatedAt(); !ok {
+		v := mailsuppression.DefaultCreatedAt()
+		msc.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (msc *MailSuppressionCreate) check() error {
+	if _, ok := msc.mutation.Email(); !ok {
+		return &ValidationError{Name: "email", err: errors.New(`ent: missing required field "MailSuppression.email"`)}
+	}
+	if v, ok := msc.mutation.Email(); ok {
+		if err := mailsuppression.EmailValidator(v); err != nil {
+			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "MailSuppression.email": %w`, err)}
+		}
+	}
+	if _, ok := msc.mutation.Reason(); !ok {
+		return &ValidationError{Name: "reason", err: errors.New(`ent: missing required field "MailSuppression.reason"`)}
+	}
+	if v, ok := msc.mutation.Reason(); ok {
+		if err := mailsuppression.ReasonValidator(v); err != nil {
+			return &ValidationError{Name: "reason", err: fmt.Errorf(`ent: validator failed for field "MailSuppression.reason": %w`, err)}
+		}
+	}
+	if _, ok := msc.mutation.Category(); !ok {
+		return &ValidationError{Name: "category", err: errors.New(`ent: missing required field "MailSuppression.category"`)}
+	}
+	if _, ok := msc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "MailSuppression.created_at"`)}
+	}
+	return nil
+}
+
+func (msc *MailSuppressionCreate) sqlSave(ctx context.Context) (*MailSuppression, error) {
+	if err := msc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := msc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, msc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	msc.mutation.id = &_node.ID
+	msc.mutation.done = true
+	return _node, nil
+}
+
+func (msc *MailSuppressionCreate) createSpec() (*MailSuppression, *sqlgraph.CreateSpec) {
+	var (
+		_node = &MailSuppression{config: msc.config}
+		_spec = sqlgraph.NewCreateSpec(mailsuppression.Table, sqlgraph.NewFieldSpec(mailsuppression.FieldID, field.TypeInt))
+	)
+	if value, ok := msc.mutation.Email(); ok {
+		_spec.SetField(mailsuppression.FieldEmail, field.TypeString, value)
+		_node.Email = value
+	}
+	if value, ok := msc.mutation.Reason(); ok {
+		_spec.SetField(mailsuppression.FieldReason, field.TypeEnum, value)
+		_node.Reason = value
+	}
+	if value, ok := msc.mutation.Category(); ok {
+		_spec.SetField(mailsuppression.FieldCategory, field.TypeString, value)
+		_node.Category = value
+	}
+	if value, ok := msc.mutation.CreatedAt(); ok {
+		_spec.SetField(mailsuppression.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	return _node, _spec
+}
+
+// MailSuppressionCreateBulk is the builder for creating many MailSuppression entities in bulk.
+type MailSuppressionCreateBulk struct {
+	config
+	err      error
+	builders []*MailSuppressionCreate
+}
+
+// Save creates the MailSuppression entities in the database.
+func (mscb *MailSuppressionCreateBulk) Save(ctx context.Context) ([]*MailSuppression, error) {
+	if mscb.err != nil {
+		return nil, mscb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(mscb.builders))
+	nodes := make([]*MailSuppression, len(mscb.builders))
+	mutators := make([]Mutator, len(mscb.builders))
+	for i := range mscb.builders {
+		func(i int, root context.Context) {
+			builder := mscb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*MailSuppressionMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, mscb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, mscb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, mscb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (mscb *MailSuppressionCreateBulk) SaveX(ctx context.Context) []*MailSuppression {
+	v, err := mscb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (mscb *MailSuppressionCreateBulk) Exec(ctx context.Context) error {
+	_, err := mscb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (mscb *MailSuppressionCreateBulk) ExecX(ctx context.Context) {
+	if err := mscb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,88 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/mailsuppression"
+	"github.com/mikestefanello/pagoda/ent/predicate"
+)
+
+// MailSuppressionDelete is the builder for deleting a MailSuppression entity.
+type MailSuppressionDelete struct {
+	config
+	hooks    []Hook
+	mutation *MailSuppressionMutation
+}
+
+// Where appends a list predicates to the MailSuppressionDelete builder.
+func (msd *MailSuppressionDelete) Where(ps ...predicate.MailSuppression) *MailSuppressionDelete {
+	msd.mutation.Where(ps...)
+	return msd
+}
+
+// Exec executes the deletion query and returns how many vertices were deleted.
+func (msd *MailSuppressionDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks(ctx, msd.sqlExec, msd.mutation, msd.hooks)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (msd *MailSuppressionDelete) ExecX(ctx context.Context) int {
+	n, err := msd.Exec(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (msd *MailSuppressionDelete) sqlExec(ctx context.Context) (int, error) {
+	_spec := sqlgraph.NewDeleteSpec(mailsuppression.Table, sqlgraph.NewFieldSpec(mailsuppression.FieldID, field.TypeInt))
+	if ps := msd.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	affected, err := sqlgraph.DeleteNodes(ctx, msd.driver, _spec)
+	if err != nil && sqlgraph.IsConstraintError(err) {
+		err = &ConstraintError{msg: err.Error(), wrap: err}
+	}
+	msd.mutation.done = true
+	return affected, err
+}
+
+// MailSuppressionDeleteOne is the builder for deleting a single MailSuppression entity.
+type MailSuppressionDeleteOne struct {
+	msd *MailSuppressionDelete
+}
+
+// Where appends a list predicates to the MailSuppressionDelete builder.
+func (msdo *MailSuppressionDeleteOne) Where(ps ...predicate.MailSuppression) *MailSuppressionDeleteOne {
+	msdo.msd.mutation.Where(ps...)
+	return msdo
+}
+
+// Exec executes the deletion query.
+func (msdo *MailSuppressionDeleteOne) Exec(ctx context.Context) error {
+	n, err := msdo.msd.Exec(ctx)
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return &NotFoundError{mailsuppression.Label}
+	default:
+		return nil
+	}
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (msdo *MailSuppressionDeleteOne) ExecX(ctx context.Context) {
+	if err := msdo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
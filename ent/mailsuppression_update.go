@@ -0,0 +1,175 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/mailsuppression"
+	"github.com/mikestefanello/pagoda/ent/predicate"
+)
+
+// MailSuppressionUpdate is the builder for updating MailSuppression entities.
+type MailSuppressionUpdate struct {
+	config
+	hooks    []Hook
+	mutation *MailSuppressionMutation
+}
+
+// Where appends a list predicates to the MailSuppressionUpdate builder.
+func (msu *MailSuppressionUpdate) Where(ps ...predicate.MailSuppression) *MailSuppressionUpdate {
+	msu.mutation.Where(ps...)
+	return msu
+}
+
+// Mutation returns the MailSuppressionMutation object of the builder.
+func (msu *MailSuppressionUpdate) Mutation() *MailSuppressionMutation {
+	return msu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (msu *MailSuppressionUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, msu.sqlSave, msu.mutation, msu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (msu *MailSuppressionUpdate) SaveX(ctx context.Context) int {
+	affected, err := msu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (msu *MailSuppressionUpdate) Exec(ctx context.Context) error {
+	_, err := msu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (msu *MailSuppressionUpdate) ExecX(ctx context.Context) {
+	if err := msu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (msu *MailSuppressionUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(mailsuppression.Table, mailsuppression.Columns, sqlgraph.NewFieldSpec(mailsuppression.FieldID, field.TypeInt))
+	if ps := msu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, msu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{mailsuppression.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	msu.mutation.done = true
+	return n, nil
+}
+
+// MailSuppressionUpdateOne is the builder for updating a single MailSuppression entity.
+type MailSuppressionUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *MailSuppressionMutation
+}
+
+// Mutation returns the MailSuppressionMutation object of the builder.
+func (msuo *MailSuppressionUpdateOne) Mutation() *MailSuppressionMutation {
+	return msuo.mutation
+}
+
+// Where appends a list predicates to the MailSuppressionUpdate builder.
+func (msuo *MailSuppressionUpdateOne) Where(ps ...predicate.MailSuppression) *MailSuppressionUpdateOne {
+	msuo.mutation.Where(ps...)
+	return msuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (msuo *MailSuppressionUpdateOne) Select(field string, fields ...string) *MailSuppressionUpdateOne {
+	msuo.fields = append([]string{field}, fields...)
+	return msuo
+}
+
+// Save executes the query and returns the updated MailSuppression entity.
+func (msuo *MailSuppressionUpdateOne) Save(ctx context.Context) (*MailSuppression, error) {
+	return withHooks(ctx, msuo.sqlSave, msuo.mutation, msuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (msuo *MailSuppressionUpdateOne) SaveX(ctx context.Context) *MailSuppression {
+	node, err := msuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (msuo *MailSuppressionUpdateOne) Exec(ctx context.Context) error {
+	_, err := msuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (msuo *MailSuppressionUpdateOne) ExecX(ctx context.Context) {
+	if err := msuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (msuo *MailSuppressionUpdateOne) sqlSave(ctx context.Context) (_node *MailSuppression, err error) {
+	_spec := sqlgraph.NewUpdateSpec(mailsuppression.Table, mailsuppression.Columns, sqlgraph.NewFieldSpec(mailsuppression.FieldID, field.TypeInt))
+	id, ok := msuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "MailSuppression.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := msuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, mailsuppression.FieldID)
+		for _, f := range fields {
+			if !mailsuppression.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != mailsuppression.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := msuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	_node = &MailSuppression{config: msuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, msuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{mailsuppression.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	msuo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,524 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/personalaccesstoken"
+	"github.com/mikestefanello/pagoda/ent/predicate"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+// PersonalAccessTokenUpdate is the builder for updating PersonalAccessToken entities.
+type PersonalAccessTokenUpdate struct {
+	config
+	hooks    []Hook
+	mutation *PersonalAccessTokenMutation
+}
+
+// Where appends a list predicates to the PersonalAccessTokenUpdate builder.
+func (patu *PersonalAccessTokenUpdate) Where(ps ...predicate.PersonalAccessToken) *PersonalAccessTokenUpdate {
+	patu.mutation.Where(ps...)
+	return patu
+}
+
+// SetName sets the "name" field.
+func (patu *PersonalAccessTokenUpdate) SetName(s string) *PersonalAccessTokenUpdate {
+	patu.mutation.SetName(s)
+	return patu
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (patu *PersonalAccessTokenUpdate) SetNillableName(s *string) *PersonalAccessTokenUpdate {
+	if s != nil {
+		patu.SetName(*s)
+	}
+	return patu
+}
+
+// SetHash sets the "hash" field.
+func (patu *PersonalAccessTokenUpdate) SetHash(s string) *PersonalAccessTokenUpdate {
+	patu.mutation.SetHash(s)
+	return patu
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (patu *PersonalAccessTokenUpdate) SetNillableHash(s *string) *PersonalAccessTokenUpdate {
+	if s != nil {
+		patu.SetHash(*s)
+	}
+	return patu
+}
+
+// SetScopes sets the "scopes" field.
+func (patu *PersonalAccessTokenUpdate) SetScopes(s []string) *PersonalAccessTokenUpdate {
+	patu.mutation.SetScopes(s)
+	return patu
+}
+
+// AppendScopes appends s to the "scopes" field.
+func (patu *PersonalAccessTokenUpdate) AppendScopes(s []string) *PersonalAccessTokenUpdate {
+	patu.mutation.AppendScopes(s)
+	return patu
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (patu *PersonalAccessTokenUpdate) SetExpiresAt(t time.Time) *PersonalAccessTokenUpdate {
+	patu.mutation.SetExpiresAt(t)
+	return patu
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (patu *PersonalAccessTokenUpdate) SetNillableExpiresAt(t *time.Time) *PersonalAccessTokenUpdate {
+	if t != nil {
+		patu.SetExpiresAt(*t)
+	}
+	return patu
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (patu *PersonalAccessTokenUpdate) ClearExpiresAt() *PersonalAccessTokenUpdate {
+	patu.mutation.ClearExpiresAt()
+	return patu
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (patu *PersonalAccessTokenUpdate) SetLastUsedAt(t time.Time) *PersonalAccessTokenUpdate {
+	patu.mutation.SetLastUsedAt(t)
+	return patu
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (patu *PersonalAccessTokenUpdate) SetNillableLastUsedAt(t *time.Time) *PersonalAccessTokenUpdate {
+	if t != nil {
+		patu.SetLastUsedAt(*t)
+	}
+	return patu
+}
+
+// ClearLastUsedAt clears the value of the "last_used_at" field.
+func (patu *PersonalAccessTokenUpdate) ClearLastUsedAt() *PersonalAccessTokenUpdate {
+	patu.mutation.ClearLastUsedAt()
+	return patu
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (patu *PersonalAccessTokenUpdate) SetUserID(id int) *PersonalAccessTokenUpdate {
+	patu.mutation.SetUserID(id)
+	return patu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (patu *PersonalAccessTokenUpdate) SetUser(u *User) *PersonalAccessTokenUpdate {
+	return patu.SetUserID(u.ID)
+}
+
+// Mutation returns the PersonalAccessTokenMutation object of the builder.
+func (patu *PersonalAccessTokenUpdate) Mutation() *PersonalAccessTokenMutation {
+	return patu.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (patu *PersonalAccessTokenUpdate) ClearUser() *PersonalAccessTokenUpdate {
+	patu.mutation.ClearUser()
+	return patu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (patu *PersonalAccessTokenUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, patu.sqlSave, patu.mutation, patu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (patu *PersonalAccessTokenUpdate) SaveX(ctx context.Context) int {
+	affected, err := patu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (patu *PersonalAccessTokenUpdate) Exec(ctx context.Context) error {
+	_, err := patu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (patu *PersonalAccessTokenUpdate) ExecX(ctx context.Context) {
+	if err := patu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (patu *PersonalAccessTokenUpdate) check() error {
+	if v, ok := patu.mutation.Name(); ok {
+		if err := personalaccesstoken.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "PersonalAccessToken.name": %w`, err)}
+		}
+	}
+	if v, ok := patu.mutation.Hash(); ok {
+		if err := personalaccesstoken.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "PersonalAccessToken.hash": %w`, err)}
+		}
+	}
+	if _, ok := patu.mutation.UserID(); patu.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "PersonalAccessToken.user"`)
+	}
+	return nil
+}
+
+func (patu *PersonalAccessTokenUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := patu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(personalaccesstoken.Table, personalaccesstoken.Columns, sqlgraph.NewFieldSpec(personalaccesstoken.FieldID, field.TypeInt))
+	if ps := patu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := patu.mutation.Name(); ok {
+		_spec.SetField(personalaccesstoken.FieldName, field.TypeString, value)
+	}
+	if value, ok := patu.mutation.Hash(); ok {
+		_spec.SetField(personalaccesstoken.FieldHash, field.TypeString, value)
+	}
+	if value, ok := patu.mutation.Scopes(); ok {
+		_spec.SetField(personalaccesstoken.FieldScopes, field.TypeJSON, value)
+	}
+	if value, ok := patu.mutation.AppendedScopes(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, personalaccesstoken.FieldScopes, value)
+		})
+	}
+	if value, ok := patu.mutation.ExpiresAt(); ok {
+		_spec.SetField(personalaccesstoken.FieldExpiresAt, field.TypeTime, value)
+	}
+	if patu.mutation.ExpiresAtCleared() {
+		_spec.ClearField(personalaccesstoken.FieldExpiresAt, field.TypeTime)
+	}
+	if value, ok := patu.mutation.LastUsedAt(); ok {
+		_spec.SetField(personalaccesstoken.FieldLastUsedAt, field.TypeTime, value)
+	}
+	if patu.mutation.LastUsedAtCleared() {
+		_spec.ClearField(personalaccesstoken.FieldLastUsedAt, field.TypeTime)
+	}
+	if patu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   personalaccesstoken.UserTable,
+			Columns: []string{personalaccesstoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := patu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   personalaccesstoken.UserTable,
+			Columns: []string{personalaccesstoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, patu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{personalaccesstoken.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	patu.mutation.done = true
+	return n, nil
+}
+
+// PersonalAccessTokenUpdateOne is the builder for updating a single PersonalAccessToken entity.
+type PersonalAccessTokenUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *PersonalAccessTokenMutation
+}
+
+// SetName sets the "name" field.
+func (patuo *PersonalAccessTokenUpdateOne) SetName(s string) *PersonalAccessTokenUpdateOne {
+	patuo.mutation.SetName(s)
+	return patuo
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (patuo *PersonalAccessTokenUpdateOne) SetNillableName(s *string) *PersonalAccessTokenUpdateOne {
+	if s != nil {
+		patuo.SetName(*s)
+	}
+	return patuo
+}
+
+// SetHash sets the "hash" field.
+func (patuo *PersonalAccessTokenUpdateOne) SetHash(s string) *PersonalAccessTokenUpdateOne {
+	patuo.mutation.SetHash(s)
+	return patuo
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (patuo *PersonalAccessTokenUpdateOne) SetNillableHash(s *string) *PersonalAccessTokenUpdateOne {
+	if s != nil {
+		patuo.SetHash(*s)
+	}
+	return patuo
+}
+
+// SetScopes sets the "scopes" field.
+func (patuo *PersonalAccessTokenUpdateOne) SetScopes(s []string) *PersonalAccessTokenUpdateOne {
+	patuo.mutation.SetScopes(s)
+	return patuo
+}
+
+// AppendScopes appends s to the "scopes" field.
+func (patuo *PersonalAccessTokenUpdateOne) AppendScopes(s []string) *PersonalAccessTokenUpdateOne {
+	patuo.mutation.AppendScopes(s)
+	return patuo
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (patuo *PersonalAccessTokenUpdateOne) SetExpiresAt(t time.Time) *PersonalAccessTokenUpdateOne {
+	patuo.mutation.SetExpiresAt(t)
+	return patuo
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (patuo *PersonalAccessTokenUpdateOne) SetNillableExpiresAt(t *time.Time) *PersonalAccessTokenUpdateOne {
+	if t != nil {
+		patuo.SetExpiresAt(*t)
+	}
+	return patuo
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (patuo *PersonalAccessTokenUpdateOne) ClearExpiresAt() *PersonalAccessTokenUpdateOne {
+	patuo.mutation.ClearExpiresAt()
+	return patuo
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (patuo *PersonalAccessTokenUpdateOne) SetLastUsedAt(t time.Time) *PersonalAccessTokenUpdateOne {
+	patuo.mutation.SetLastUsedAt(t)
+	return patuo
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (patuo *PersonalAccessTokenUpdateOne) SetNillableLastUsedAt(t *time.Time) *PersonalAccessTokenUpdateOne {
+	if t != nil {
+		patuo.SetLastUsedAt(*t)
+	}
+	return patuo
+}
+
+// ClearLastUsedAt clears the value of the "last_used_at" field.
+func (patuo *PersonalAccessTokenUpdateOne) ClearLastUsedAt() *PersonalAccessTokenUpdateOne {
+	patuo.mutation.ClearLastUsedAt()
+	return patuo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (patuo *PersonalAccessTokenUpdateOne) SetUserID(id int) *PersonalAccessTokenUpdateOne {
+	patuo.mutation.SetUserID(id)
+	return patuo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (patuo *PersonalAccessTokenUpdateOne) SetUser(u *User) *PersonalAccessTokenUpdateOne {
+	return patuo.SetUserID(u.ID)
+}
+
+// Mutation returns the PersonalAccessTokenMutation object of the builder.
+func (patuo *PersonalAccessTokenUpdateOne) Mutation() *PersonalAccessTokenMutation {
+	return patuo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (patuo *PersonalAccessTokenUpdateOne) ClearUser() *PersonalAccessTokenUpdateOne {
+	patuo.mutation.ClearUser()
+	return patuo
+}
+
+// Where appends a list predicates to the PersonalAccessTokenUpdate builder.
+func (patuo *PersonalAccessTokenUpdateOne) Where(ps ...predicate.PersonalAccessToken) *PersonalAccessTokenUpdateOne {
+	patuo.mutation.Where(ps...)
+	return patuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (patuo *PersonalAccessTokenUpdateOne) Select(field string, fields ...string) *PersonalAccessTokenUpdateOne {
+	patuo.fields = append([]string{field}, fields...)
+	return patuo
+}
+
+// Save executes the query and returns the updated PersonalAccessToken entity.
+func (patuo *PersonalAccessTokenUpdateOne) Save(ctx context.Context) (*PersonalAccessToken, error) {
+	return withHooks(ctx, patuo.sqlSave, patuo.mutation, patuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (patuo *PersonalAccessTokenUpdateOne) SaveX(ctx context.Context) *PersonalAccessToken {
+	node, err := patuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (patuo *PersonalAccessTokenUpdateOne) Exec(ctx context.Context) error {
+	_, err := patuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (patuo *PersonalAccessTokenUpdateOne) ExecX(ctx context.Context) {
+	if err := patuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (patuo *PersonalAccessTokenUpdateOne) check() error {
+	if v, ok := patuo.mutation.Name(); ok {
+		if err := personalaccesstoken.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "PersonalAccessToken.name": %w`, err)}
+		}
+	}
+	if v, ok := patuo.mutation.Hash(); ok {
+		if err := personalaccesstoken.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "PersonalAccessToken.hash": %w`, err)}
+		}
+	}
+	if _, ok := patuo.mutation.UserID(); patuo.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "PersonalAccessToken.user"`)
+	}
+	return nil
+}
+
+func (patuo *PersonalAccessTokenUpdateOne) sqlSave(ctx context.Context) (_node *PersonalAccessToken, err error) {
+	if err := patuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(personalaccesstoken.Table, personalaccesstoken.Columns, sqlgraph.NewFieldSpec(personalaccesstoken.FieldID, field.TypeInt))
+	id, ok := patuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "PersonalAccessToken.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := patuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, personalaccesstoken.FieldID)
+		for _, f := range fields {
+			if !personalaccesstoken.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != personalaccesstoken.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := patuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := patuo.mutation.Name(); ok {
+		_spec.SetField(personalaccesstoken.FieldName, field.TypeString, value)
+	}
+	if value, ok := patuo.mutation.Hash(); ok {
+		_spec.SetField(personalaccesstoken.FieldHash, field.TypeString, value)
+	}
+	if value, ok := patuo.mutation.Scopes(); ok {
+		_spec.SetField(personalaccesstoken.FieldScopes, field.TypeJSON, value)
+	}
+	if value, ok := patuo.mutation.AppendedScopes(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, personalaccesstoken.FieldScopes, value)
+		})
+	}
+	if value, ok := patuo.mutation.ExpiresAt(); ok {
+		_spec.SetField(personalaccesstoken.FieldExpiresAt, field.TypeTime, value)
+	}
+	if patuo.mutation.ExpiresAtCleared() {
+		_spec.ClearField(personalaccesstoken.FieldExpiresAt, field.TypeTime)
+	}
+	if value, ok := patuo.mutation.LastUsedAt(); ok {
+		_spec.SetField(personalaccesstoken.FieldLastUsedAt, field.TypeTime, value)
+	}
+	if patuo.mutation.LastUsedAtCleared() {
+		_spec.ClearField(personalaccesstoken.FieldLastUsedAt, field.TypeTime)
+	}
+	if patuo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   personalaccesstoken.UserTable,
+			Columns: []string{personalaccesstoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := patuo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: false,
+			Table:   personalaccesstoken.UserTable,
+			Columns: []string{personalaccesstoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &PersonalAccessToken{config: patuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, patuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{personalaccesstoken.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	patuo.mutation.done = true
+	return _node, nil
+}
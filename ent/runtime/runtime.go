@@ -5,7 +5,20 @@ package runtime
 import (
 	"time"
 
+	"github.com/mikestefanello/pagoda/ent/auditevent"
+	"github.com/mikestefanello/pagoda/ent/dataexport"
+	"github.com/mikestefanello/pagoda/ent/device"
+	"github.com/mikestefanello/pagoda/ent/impersonation"
+	"github.com/mikestefanello/pagoda/ent/invitation"
+	"github.com/mikestefanello/pagoda/ent/invitecode"
+	"github.com/mikestefanello/pagoda/ent/logintoken"
+	"github.com/mikestefanello/pagoda/ent/mailsuppression"
+	"github.com/mikestefanello/pagoda/ent/membership"
+	"github.com/mikestefanello/pagoda/ent/organization"
 	"github.com/mikestefanello/pagoda/ent/passwordtoken"
+	"github.com/mikestefanello/pagoda/ent/permission"
+	"github.com/mikestefanello/pagoda/ent/personalaccesstoken"
+	"github.com/mikestefanello/pagoda/ent/role"
 	"github.com/mikestefanello/pagoda/ent/schema"
 	"github.com/mikestefanello/pagoda/ent/user"
 )
@@ -14,6 +27,136 @@ import (
 // (default values, validators, hooks and policies) and stitches it
 // to their package variables.
 func init() {
+	auditeventFields := schema.AuditEvent{}.Fields()
+	_ = auditeventFields
+	// auditeventDescAction is the schema descriptor for action field.
+	auditeventDescAction := auditeventFields[0].Descriptor()
+	// auditevent.ActionValidator is a validator for the "action" field. It is called by the builders before save.
+	auditevent.ActionValidator = auditeventDescAction.Validators[0].(func(string) error)
+	// auditeventDescCreatedAt is the schema descriptor for created_at field.
+	auditeventDescCreatedAt := auditeventFields[5].Descriptor()
+	// auditevent.DefaultCreatedAt holds the default value on creation for the created_at field.
+	auditevent.DefaultCreatedAt = auditeventDescCreatedAt.Default.(func() time.Time)
+	dataexportFields := schema.DataExport{}.Fields()
+	_ = dataexportFields
+	// dataexportDescHash is the schema descriptor for hash field.
+	dataexportDescHash := dataexportFields[0].Descriptor()
+	// dataexport.HashValidator is a validator for the "hash" field. It is called by the builders before save.
+	dataexport.HashValidator = dataexportDescHash.Validators[0].(func(string) error)
+	// dataexportDescCreatedAt is the schema descriptor for created_at field.
+	dataexportDescCreatedAt := dataexportFields[2].Descriptor()
+	// dataexport.DefaultCreatedAt holds the default value on creation for the created_at field.
+	dataexport.DefaultCreatedAt = dataexportDescCreatedAt.Default.(func() time.Time)
+	deviceFields := schema.Device{}.Fields()
+	_ = deviceFields
+	// deviceDescHash is the schema descriptor for hash field.
+	deviceDescHash := deviceFields[0].Descriptor()
+	// device.HashValidator is a validator for the "hash" field. It is called by the builders before save.
+	device.HashValidator = deviceDescHash.Validators[0].(func(string) error)
+	// deviceDescUserAgent is the schema descriptor for user_agent field.
+	deviceDescUserAgent := deviceFields[1].Descriptor()
+	// device.DefaultUserAgent holds the default value on creation for the user_agent field.
+	device.DefaultUserAgent = deviceDescUserAgent.Default.(string)
+	// deviceDescIPAddress is the schema descriptor for ip_address field.
+	deviceDescIPAddress := deviceFields[2].Descriptor()
+	// device.DefaultIPAddress holds the default value on creation for the ip_address field.
+	device.DefaultIPAddress = deviceDescIPAddress.Default.(string)
+	// deviceDescCreatedAt is the schema descriptor for created_at field.
+	deviceDescCreatedAt := deviceFields[3].Descriptor()
+	// device.DefaultCreatedAt holds the default value on creation for the created_at field.
+	device.DefaultCreatedAt = deviceDescCreatedAt.Default.(func() time.Time)
+	// deviceDescLastLoginAt is the schema descriptor for last_login_at field.
+	deviceDescLastLoginAt := deviceFields[4].Descriptor()
+	// device.DefaultLastLoginAt holds the default value on creation for the last_login_at field.
+	device.DefaultLastLoginAt = deviceDescLastLoginAt.Default.(func() time.Time)
+	impersonationFields := schema.Impersonation{}.Fields()
+	_ = impersonationFields
+	// impersonationDescStartedAt is the schema descriptor for started_at field.
+	impersonationDescStartedAt := impersonationFields[0].Descriptor()
+	// impersonation.DefaultStartedAt holds the default value on creation for the started_at field.
+	impersonation.DefaultStartedAt = impersonationDescStartedAt.Default.(func() time.Time)
+	invitationHooks := schema.Invitation{}.Hooks()
+	invitation.Hooks[0] = invitationHooks[0]
+	invitationFields := schema.Invitation{}.Fields()
+	_ = invitationFields
+	// invitationDescEmail is the schema descriptor for email field.
+	invitationDescEmail := invitationFields[0].Descriptor()
+	// invitation.EmailValidator is a validator for the "email" field. It is called by the builders before save.
+	invitation.EmailValidator = invitationDescEmail.Validators[0].(func(string) error)
+	// invitationDescHash is the schema descriptor for hash field.
+	invitationDescHash := invitationFields[2].Descriptor()
+	// invitation.HashValidator is a validator for the "hash" field. It is called by the builders before save.
+	invitation.HashValidator = invitationDescHash.Validators[0].(func(string) error)
+	// invitationDescCreatedAt is the schema descriptor for created_at field.
+	invitationDescCreatedAt := invitationFields[3].Descriptor()
+	// invitation.DefaultCreatedAt holds the default value on creation for the created_at field.
+	invitation.DefaultCreatedAt = invitationDescCreatedAt.Default.(func() time.Time)
+	invitecodeFields := schema.InviteCode{}.Fields()
+	_ = invitecodeFields
+	// invitecodeDescHash is the schema descriptor for hash field.
+	invitecodeDescHash := invitecodeFields[0].Descriptor()
+	// invitecode.HashValidator is a validator for the "hash" field. It is called by the builders before save.
+	invitecode.HashValidator = invitecodeDescHash.Validators[0].(func(string) error)
+	// invitecodeDescMaxUses is the schema descriptor for max_uses field.
+	invitecodeDescMaxUses := invitecodeFields[2].Descriptor()
+	// invitecode.DefaultMaxUses holds the default value on creation for the max_uses field.
+	invitecode.DefaultMaxUses = invitecodeDescMaxUses.Default.(int)
+	// invitecode.MaxUsesValidator is a validator for the "max_uses" field. It is called by the builders before save.
+	invitecode.MaxUsesValidator = invitecodeDescMaxUses.Validators[0].(func(int) error)
+	// invitecodeDescUses is the schema descriptor for uses field.
+	invitecodeDescUses := invitecodeFields[3].Descriptor()
+	// invitecode.DefaultUses holds the default value on creation for the uses field.
+	invitecode.DefaultUses = invitecodeDescUses.Default.(int)
+	// invitecode.UsesValidator is a validator for the "uses" field. It is called by the builders before save.
+	invitecode.UsesValidator = invitecodeDescUses.Validators[0].(func(int) error)
+	// invitecodeDescCreatedAt is the schema descriptor for created_at field.
+	invitecodeDescCreatedAt := invitecodeFields[5].Descriptor()
+	// invitecode.DefaultCreatedAt holds the default value on creation for the created_at field.
+	invitecode.DefaultCreatedAt = invitecodeDescCreatedAt.Default.(func() time.Time)
+	logintokenFields := schema.LoginToken{}.Fields()
+	_ = logintokenFields
+	// logintokenDescHash is the schema descriptor for hash field.
+	logintokenDescHash := logintokenFields[0].Descriptor()
+	// logintoken.HashValidator is a validator for the "hash" field. It is called by the builders before save.
+	logintoken.HashValidator = logintokenDescHash.Validators[0].(func(string) error)
+	// logintokenDescBrowserHash is the schema descriptor for browser_hash field.
+	logintokenDescBrowserHash := logintokenFields[1].Descriptor()
+	// logintoken.BrowserHashValidator is a validator for the "browser_hash" field. It is called by the builders before save.
+	logintoken.BrowserHashValidator = logintokenDescBrowserHash.Validators[0].(func(string) error)
+	// logintokenDescCreatedAt is the schema descriptor for created_at field.
+	logintokenDescCreatedAt := logintokenFields[2].Descriptor()
+	// logintoken.DefaultCreatedAt holds the default value on creation for the created_at field.
+	logintoken.DefaultCreatedAt = logintokenDescCreatedAt.Default.(func() time.Time)
+	mailsuppressionFields := schema.MailSuppression{}.Fields()
+	_ = mailsuppressionFields
+	// mailsuppressionDescEmail is the schema descriptor for email field.
+	mailsuppressionDescEmail := mailsuppressionFields[0].Descriptor()
+	// mailsuppression.EmailValidator is a validator for the "email" field. It is called by the builders before save.
+	mailsuppression.EmailValidator = mailsuppressionDescEmail.Validators[0].(func(string) error)
+	// mailsuppressionDescCategory is the schema descriptor for category field.
+	mailsuppressionDescCategory := mailsuppressionFields[2].Descriptor()
+	// mailsuppression.DefaultCategory holds the default value on creation for the category field.
+	mailsuppression.DefaultCategory = mailsuppressionDescCategory.Default.(string)
+	// mailsuppressionDescCreatedAt is the schema descriptor for created_at field.
+	mailsuppressionDescCreatedAt := mailsuppressionFields[3].Descriptor()
+	// mailsuppression.DefaultCreatedAt holds the default value on creation for the created_at field.
+	mailsuppression.DefaultCreatedAt = mailsuppressionDescCreatedAt.Default.(func() time.Time)
+	membershipFields := schema.Membership{}.Fields()
+	_ = membershipFields
+	// membershipDescCreatedAt is the schema descriptor for created_at field.
+	membershipDescCreatedAt := membershipFields[1].Descriptor()
+	// membership.DefaultCreatedAt holds the default value on creation for the created_at field.
+	membership.DefaultCreatedAt = membershipDescCreatedAt.Default.(func() time.Time)
+	organizationFields := schema.Organization{}.Fields()
+	_ = organizationFields
+	// organizationDescName is the schema descriptor for name field.
+	organizationDescName := organizationFields[0].Descriptor()
+	// organization.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	organization.NameValidator = organizationDescName.Validators[0].(func(string) error)
+	// organizationDescCreatedAt is the schema descriptor for created_at field.
+	organizationDescCreatedAt := organizationFields[1].Descriptor()
+	// organization.DefaultCreatedAt holds the default value on creation for the created_at field.
+	organization.DefaultCreatedAt = organizationDescCreatedAt.Default.(func() time.Time)
 	passwordtokenFields := schema.PasswordToken{}.Fields()
 	_ = passwordtokenFields
 	// passwordtokenDescHash is the schema descriptor for hash field.
@@ -24,6 +167,32 @@ func init() {
 	passwordtokenDescCreatedAt := passwordtokenFields[1].Descriptor()
 	// passwordtoken.DefaultCreatedAt holds the default value on creation for the created_at field.
 	passwordtoken.DefaultCreatedAt = passwordtokenDescCreatedAt.Default.(func() time.Time)
+	permissionFields := schema.Permission{}.Fields()
+	_ = permissionFields
+	// permissionDescName is the schema descriptor for name field.
+	permissionDescName := permissionFields[0].Descriptor()
+	// permission.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	permission.NameValidator = permissionDescName.Validators[0].(func(string) error)
+	personalaccesstokenFields := schema.PersonalAccessToken{}.Fields()
+	_ = personalaccesstokenFields
+	// personalaccesstokenDescName is the schema descriptor for name field.
+	personalaccesstokenDescName := personalaccesstokenFields[0].Descriptor()
+	// personalaccesstoken.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	personalaccesstoken.NameValidator = personalaccesstokenDescName.Validators[0].(func(string) error)
+	// personalaccesstokenDescHash is the schema descriptor for hash field.
+	personalaccesstokenDescHash := personalaccesstokenFields[1].Descriptor()
+	// personalaccesstoken.HashValidator is a validator for the "hash" field. It is called by the builders before save.
+	personalaccesstoken.HashValidator = personalaccesstokenDescHash.Validators[0].(func(string) error)
+	// personalaccesstokenDescCreatedAt is the schema descriptor for created_at field.
+	personalaccesstokenDescCreatedAt := personalaccesstokenFields[5].Descriptor()
+	// personalaccesstoken.DefaultCreatedAt holds the default value on creation for the created_at field.
+	personalaccesstoken.DefaultCreatedAt = personalaccesstokenDescCreatedAt.Default.(func() time.Time)
+	roleFields := schema.Role{}.Fields()
+	_ = roleFields
+	// roleDescName is the schema descriptor for name field.
+	roleDescName := roleFields[0].Descriptor()
+	// role.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	role.NameValidator = roleDescName.Validators[0].(func(string) error)
 	userHooks := schema.User{}.Hooks()
 	user.Hooks[0] = userHooks[0]
 	userFields := schema.User{}.Fields()
@@ -44,13 +213,17 @@ func init() {
 	userDescVerified := userFields[3].Descriptor()
 	// user.DefaultVerified holds the default value on creation for the verified field.
 	user.DefaultVerified = userDescVerified.Default.(bool)
+	// userDescSessionVersion is the schema descriptor for session_version field.
+	userDescSessionVersion := userFields[7].Descriptor()
+	// user.DefaultSessionVersion holds the default value on creation for the session_version field.
+	user.DefaultSessionVersion = userDescSessionVersion.Default.(int)
 	// userDescCreatedAt is the schema descriptor for created_at field.
-	userDescCreatedAt := userFields[4].Descriptor()
+	userDescCreatedAt := userFields[8].Descriptor()
 	// user.DefaultCreatedAt holds the default value on creation for the created_at field.
 	user.DefaultCreatedAt = userDescCreatedAt.Default.(func() time.Time)
 }
 
 const (
-	Version = "v0.12.5"                                         // Version of ent codegen.
-	Sum     = "h1:KREM5E4CSoej4zeGa88Ou/gfturAnpUv0mzAjch1sj4=" // Sum of ent codegen.
+	Version = "v0.13.1"                                         // Version of ent codegen.
+	Sum     = "h1:uD8QwN1h6SNphdCCzmkMN3feSUzNnVvV/WIkHKMbzOE=" // Sum of ent codegen.
 )
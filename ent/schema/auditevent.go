@@ -0,0 +1,60 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// AuditEvent holds the schema definition for the AuditEvent entity.
+// This serves as a security audit log of actions such as logins and password resets.
+type AuditEvent struct {
+	ent.Schema
+}
+
+// Fields of the AuditEvent.
+func (AuditEvent) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("action").
+			NotEmpty().
+			Immutable(),
+		field.String("ip").
+			Optional().
+			Immutable(),
+		field.String("user_agent").
+			Optional().
+			Immutable(),
+		field.String("request_id").
+			Optional().
+			Immutable(),
+		field.JSON("payload", map[string]any{}).
+			Optional().
+			Immutable(),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Edges of the AuditEvent.
+// Both are optional, since the actor may be anonymous, ie, a failed login, and users can be deleted without
+// removing the events that reference them.
+func (AuditEvent) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("actor", User.Type).
+			Unique(),
+		edge.To("target", User.Type).
+			Unique(),
+	}
+}
+
+// Indexes of the AuditEvent.
+func (AuditEvent) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("action"),
+		index.Fields("created_at"),
+	}
+}
@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Device holds the schema definition for the Device entity.
+// Devices are the browsers a user has logged in from, which are identified by a long-lived cookie, so users can be
+// notified when their account is logged in to from a device they have not used before.
+type Device struct {
+	ent.Schema
+}
+
+// Fields of the Device.
+func (Device) Fields() []ent.Field {
+	return []ent.Field{
+		// hash is a hash of the random identifier stored in the cookie of the device
+		field.String("hash").
+			Sensitive().
+			NotEmpty(),
+		field.String("user_agent").
+			Default(""),
+		field.String("ip_address").
+			Default(""),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("last_login_at").
+			Default(time.Now),
+	}
+}
+
+// Edges of the Device.
+func (Device) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("user", User.Type).
+			Required().
+			Unique(),
+	}
+}
+
+// Indexes of the Device.
+func (Device) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("hash").
+			Edges("user").
+			Unique(),
+	}
+}
@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// Impersonation holds the schema definition for the Impersonation entity.
+// This serves as an audit trail of when an admin impersonated a user.
+type Impersonation struct {
+	ent.Schema
+}
+
+// Fields of the Impersonation.
+func (Impersonation) Fields() []ent.Field {
+	return []ent.Field{
+		field.Time("started_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("ended_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Edges of the Impersonation.
+func (Impersonation) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("impersonator", User.Type).
+			Required().
+			Unique(),
+		edge.To("user", User.Type).
+			Required().
+			Unique(),
+	}
+}
@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	ge "github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/hook"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// Invitation holds the schema definition for the Invitation entity.
+// This invites an email address to join an organization with a given role.
+type Invitation struct {
+	ent.Schema
+}
+
+// Fields of the Invitation.
+func (Invitation) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("email").
+			NotEmpty(),
+		field.Enum("role").
+			Values("owner", "admin", "member").
+			Default("member"),
+		field.String("hash").
+			Sensitive().
+			NotEmpty().
+			Unique(),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Edges of the Invitation.
+func (Invitation) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("organization", Organization.Type).
+			Required().
+			Unique(),
+		edge.To("inviter", User.Type).
+			Unique(),
+	}
+}
+
+// Hooks of the Invitation.
+func (Invitation) Hooks() []ent.Hook {
+	return []ent.Hook{
+		hook.On(
+			func(next ent.Mutator) ent.Mutator {
+				return hook.InvitationFunc(func(ctx context.Context, m *ge.InvitationMutation) (ent.Value, error) {
+					if v, exists := m.Email(); exists {
+						m.SetEmail(strings.ToLower(v))
+					}
+					return next.Mutate(ctx, m)
+				})
+			},
+			ent.OpCreate|ent.OpUpdate|ent.OpUpdateOne,
+		),
+	}
+}
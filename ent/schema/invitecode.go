@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// InviteCode holds the schema definition for the InviteCode entity.
+// Invite codes are generated by admins and are required to register when registration is invite-only.
+type InviteCode struct {
+	ent.Schema
+}
+
+// Fields of the InviteCode.
+func (InviteCode) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("hash").
+			Sensitive().
+			NotEmpty().
+			Unique(),
+		field.String("note").
+			Optional(),
+		// max_uses is the amount of times the code can be used, with zero meaning unlimited
+		field.Int("max_uses").
+			NonNegative().
+			Default(1),
+		field.Int("uses").
+			NonNegative().
+			Default(0),
+		field.Time("expires_at").
+			Optional().
+			Nillable(),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Edges of the InviteCode.
+func (InviteCode) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("creator", User.Type).
+			Unique(),
+	}
+}
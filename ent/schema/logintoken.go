@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// LoginToken holds the schema definition for the LoginToken entity.
+type LoginToken struct {
+	ent.Schema
+}
+
+// Fields of the LoginToken.
+func (LoginToken) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("hash").
+			Sensitive().
+			NotEmpty().
+			Unique(),
+		field.String("browser_hash").
+			Sensitive().
+			NotEmpty(),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Edges of the LoginToken.
+func (LoginToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("user", User.Type).
+			Required().
+			Unique(),
+	}
+}
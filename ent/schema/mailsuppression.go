@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// MailSuppression holds the schema definition for the MailSuppression entity.
+// Suppressions prevent email from being sent to an address, either because it cannot receive email, ie, it bounced
+// or complained, in which case every email is suppressed, or because the recipient unsubscribed from a category of
+// notifications.
+type MailSuppression struct {
+	ent.Schema
+}
+
+// Fields of the MailSuppression.
+func (MailSuppression) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("email").
+			NotEmpty().
+			Immutable(),
+		field.Enum("reason").
+			Values("unsubscribed", "bounced", "complained").
+			Immutable(),
+		// category is the category of notifications which was unsubscribed from, which is empty for other reasons
+		field.String("category").
+			Default("").
+			Immutable(),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Indexes of the MailSuppression.
+func (MailSuppression) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("email", "reason", "category").
+			Unique(),
+	}
+}
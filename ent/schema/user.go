@@ -31,6 +31,22 @@ func (User) Fields() []ent.Field {
 			NotEmpty(),
 		field.Bool("verified").
 			Default(false),
+		// verification_nonce stores a hash of the nonce contained in the most recent email verification token, so
+		// each token can only be used once
+		field.String("verification_nonce").
+			Sensitive().
+			Optional().
+			Nillable(),
+		field.Time("locked_until").
+			Optional().
+			Nillable(),
+		field.Time("deletion_scheduled_at").
+			Optional().
+			Nillable(),
+		// session_version is stored in each session when the user logs in, so every session can be revoked by
+		// incrementing it
+		field.Int("session_version").
+			Default(0),
 		field.Time("created_at").
 			Default(time.Now).
 			Immutable(),
@@ -42,6 +58,30 @@ func (User) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.From("owner", PasswordToken.Type).
 			Ref("user"),
+		edge.From("roles", Role.Type).
+			Ref("users"),
+		edge.From("personal_access_tokens", PersonalAccessToken.Type).
+			Ref("user"),
+		edge.From("login_tokens", LoginToken.Type).
+			Ref("user"),
+		edge.From("impersonations_made", Impersonation.Type).
+			Ref("impersonator"),
+		edge.From("impersonations_received", Impersonation.Type).
+			Ref("user"),
+		edge.From("data_exports", DataExport.Type).
+			Ref("user"),
+		edge.From("audit_events_made", AuditEvent.Type).
+			Ref("actor"),
+		edge.From("audit_events_received", AuditEvent.Type).
+			Ref("target"),
+		edge.From("memberships", Membership.Type).
+			Ref("user"),
+		edge.From("invitations_sent", Invitation.Type).
+			Ref("inviter"),
+		edge.From("invite_codes", InviteCode.Type).
+			Ref("creator"),
+		edge.From("devices", Device.Type).
+			Ref("user"),
 	}
 }
 
@@ -25,6 +25,14 @@ type User struct {
 	Password string `json:"-"`
 	// Verified holds the value of the "verified" field.
 	Verified bool `json:"verified,omitempty"`
+	// VerificationNonce holds the value of the "verification_nonce" field.
+	VerificationNonce *string `json:"-"`
+	// LockedUntil holds the value of the "locked_until" field.
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	// DeletionScheduledAt holds the value of the "deletion_scheduled_at" field.
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	// SessionVersion holds the value of the "session_version" field.
+	SessionVersion int `json:"session_version,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
@@ -37,9 +45,33 @@ type User struct {
 type UserEdges struct {
 	// Owner holds the value of the owner edge.
 	Owner []*PasswordToken `json:"owner,omitempty"`
+	// Roles holds the value of the roles edge.
+	Roles []*Role `json:"roles,omitempty"`
+	// PersonalAccessTokens holds the value of the personal_access_tokens edge.
+	PersonalAccessTokens []*PersonalAccessToken `json:"personal_access_tokens,omitempty"`
+	// LoginTokens holds the value of the login_tokens edge.
+	LoginTokens []*LoginToken `json:"login_tokens,omitempty"`
+	// ImpersonationsMade holds the value of the impersonations_made edge.
+	ImpersonationsMade []*Impersonation `json:"impersonations_made,omitempty"`
+	// ImpersonationsReceived holds the value of the impersonations_received edge.
+	ImpersonationsReceived []*Impersonation `json:"impersonations_received,omitempty"`
+	// DataExports holds the value of the data_exports edge.
+	DataExports []*DataExport `json:"data_exports,omitempty"`
+	// AuditEventsMade holds the value of the audit_events_made edge.
+	AuditEventsMade []*AuditEvent `json:"audit_events_made,omitempty"`
+	// AuditEventsReceived holds the value of the audit_events_received edge.
+	AuditEventsReceived []*AuditEvent `json:"audit_events_received,omitempty"`
+	// Memberships holds the value of the memberships edge.
+	Memberships []*Membership `json:"memberships,omitempty"`
+	// InvitationsSent holds the value of the invitations_sent edge.
+	InvitationsSent []*Invitation `json:"invitations_sent,omitempty"`
+	// InviteCodes holds the value of the invite_codes edge.
+	InviteCodes []*InviteCode `json:"invite_codes,omitempty"`
+	// Devices holds the value of the devices edge.
+	Devices []*Device `json:"devices,omitempty"`
 	// loadedTypes holds the information for reporting if a
 	// type was loaded (or requested) in eager-loading or not.
-	loadedTypes [1]bool
+	loadedTypes [13]bool
 }
 
 // OwnerOrErr returns the Owner value or an error if the edge
@@ -51,6 +83,114 @@ func (e UserEdges) OwnerOrErr() ([]*PasswordToken, error) {
 	return nil, &NotLoadedError{edge: "owner"}
 }
 
+// RolesOrErr returns the Roles value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) RolesOrErr() ([]*Role, error) {
+	if e.loadedTypes[1] {
+		return e.Roles, nil
+	}
+	return nil, &NotLoadedError{edge: "roles"}
+}
+
+// PersonalAccessTokensOrErr returns the PersonalAccessTokens value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) PersonalAccessTokensOrErr() ([]*PersonalAccessToken, error) {
+	if e.loadedTypes[2] {
+		return e.PersonalAccessTokens, nil
+	}
+	return nil, &NotLoadedError{edge: "personal_access_tokens"}
+}
+
+// LoginTokensOrErr returns the LoginTokens value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) LoginTokensOrErr() ([]*LoginToken, error) {
+	if e.loadedTypes[3] {
+		return e.LoginTokens, nil
+	}
+	return nil, &NotLoadedError{edge: "login_tokens"}
+}
+
+// ImpersonationsMadeOrErr returns the ImpersonationsMade value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) ImpersonationsMadeOrErr() ([]*Impersonation, error) {
+	if e.loadedTypes[4] {
+		return e.ImpersonationsMade, nil
+	}
+	return nil, &NotLoadedError{edge: "impersonations_made"}
+}
+
+// ImpersonationsReceivedOrErr returns the ImpersonationsReceived value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) ImpersonationsReceivedOrErr() ([]*Impersonation, error) {
+	if e.loadedTypes[5] {
+		return e.ImpersonationsReceived, nil
+	}
+	return nil, &NotLoadedError{edge: "impersonations_received"}
+}
+
+// DataExportsOrErr returns the DataExports value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) DataExportsOrErr() ([]*DataExport, error) {
+	if e.loadedTypes[6] {
+		return e.DataExports, nil
+	}
+	return nil, &NotLoadedError{edge: "data_exports"}
+}
+
+// AuditEventsMadeOrErr returns the AuditEventsMade value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) AuditEventsMadeOrErr() ([]*AuditEvent, error) {
+	if e.loadedTypes[7] {
+		return e.AuditEventsMade, nil
+	}
+	return nil, &NotLoadedError{edge: "audit_events_made"}
+}
+
+// AuditEventsReceivedOrErr returns the AuditEventsReceived value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) AuditEventsReceivedOrErr() ([]*AuditEvent, error) {
+	if e.loadedTypes[8] {
+		return e.AuditEventsReceived, nil
+	}
+	return nil, &NotLoadedError{edge: "audit_events_received"}
+}
+
+// MembershipsOrErr returns the Memberships value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) MembershipsOrErr() ([]*Membership, error) {
+	if e.loadedTypes[9] {
+		return e.Memberships, nil
+	}
+	return nil, &NotLoadedError{edge: "memberships"}
+}
+
+// InvitationsSentOrErr returns the InvitationsSent value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) InvitationsSentOrErr() ([]*Invitation, error) {
+	if e.loadedTypes[10] {
+		return e.InvitationsSent, nil
+	}
+	return nil, &NotLoadedError{edge: "invitations_sent"}
+}
+
+// InviteCodesOrErr returns the InviteCodes value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) InviteCodesOrErr() ([]*InviteCode, error) {
+	if e.loadedTypes[11] {
+		return e.InviteCodes, nil
+	}
+	return nil, &NotLoadedError{edge: "invite_codes"}
+}
+
+// DevicesOrErr returns the Devices value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) DevicesOrErr() ([]*Device, error) {
+	if e.loadedTypes[12] {
+		return e.Devices, nil
+	}
+	return nil, &NotLoadedError{edge: "devices"}
+}
+
 // scanValues returns the types for scanning values from sql.Rows.
 func (*User) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
@@ -58,11 +198,11 @@ func (*User) scanValues(columns []string) ([]any, error) {
 		switch columns[i] {
 		case user.FieldVerified:
 			values[i] = new(sql.NullBool)
-		case user.FieldID:
+		case user.FieldID, user.FieldSessionVersion:
 			values[i] = new(sql.NullInt64)
-		case user.FieldName, user.FieldEmail, user.FieldPassword:
+		case user.FieldName, user.FieldEmail, user.FieldPassword, user.FieldVerificationNonce:
 			values[i] = new(sql.NullString)
-		case user.FieldCreatedAt:
+		case user.FieldLockedUntil, user.FieldDeletionScheduledAt, user.FieldCreatedAt:
 			values[i] = new(sql.NullTime)
 		default:
 			values[i] = new(sql.UnknownType)
@@ -109,6 +249,33 @@ func (u *User) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				u.Verified = value.Bool
 			}
+		case user.FieldVerificationNonce:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field verification_nonce", values[i])
+			} else if value.Valid {
+				u.VerificationNonce = new(string)
+				*u.VerificationNonce = value.String
+			}
+		case user.FieldLockedUntil:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field locked_until", values[i])
+			} else if value.Valid {
+				u.LockedUntil = new(time.Time)
+				*u.LockedUntil = value.Time
+			}
+		case user.FieldDeletionScheduledAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field deletion_scheduled_at", values[i])
+			} else if value.Valid {
+				u.DeletionScheduledAt = new(time.Time)
+				*u.DeletionScheduledAt = value.Time
+			}
+		case user.FieldSessionVersion:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field session_version", values[i])
+			} else if value.Valid {
+				u.SessionVersion = int(value.Int64)
+			}
 		case user.FieldCreatedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field created_at", values[i])
@@ -133,6 +300,66 @@ func (u *User) QueryOwner() *PasswordTokenQuery {
 	return NewUserClient(u.config).QueryOwner(u)
 }
 
+// QueryRoles queries the "roles" edge of the User entity.
+func (u *User) QueryRoles() *RoleQuery {
+	return NewUserClient(u.config).QueryRoles(u)
+}
+
+// QueryPersonalAccessTokens queries the "personal_access_tokens" edge of the User entity.
+func (u *User) QueryPersonalAccessTokens() *PersonalAccessTokenQuery {
+	return NewUserClient(u.config).QueryPersonalAccessTokens(u)
+}
+
+// QueryLoginTokens queries the "login_tokens" edge of the User entity.
+func (u *User) QueryLoginTokens() *LoginTokenQuery {
+	return NewUserClient(u.config).QueryLoginTokens(u)
+}
+
+// QueryImpersonationsMade queries the "impersonations_made" edge of the User entity.
+func (u *User) QueryImpersonationsMade() *ImpersonationQuery {
+	return NewUserClient(u.config).QueryImpersonationsMade(u)
+}
+
+// QueryImpersonationsReceived queries the "impersonations_received" edge of the User entity.
+func (u *User) QueryImpersonationsReceived() *ImpersonationQuery {
+	return NewUserClient(u.config).QueryImpersonationsReceived(u)
+}
+
+// QueryDataExports queries the "data_exports" edge of the User entity.
+func (u *User) QueryDataExports() *DataExportQuery {
+	return NewUserClient(u.config).QueryDataExports(u)
+}
+
+// QueryAuditEventsMade queries the "audit_events_made" edge of the User entity.
+func (u *User) QueryAuditEventsMade() *AuditEventQuery {
+	return NewUserClient(u.config).QueryAuditEventsMade(u)
+}
+
+// QueryAuditEventsReceived queries the "audit_events_received" edge of the User entity.
+func (u *User) QueryAuditEventsReceived() *AuditEventQuery {
+	return NewUserClient(u.config).QueryAuditEventsReceived(u)
+}
+
+// QueryMemberships queries the "memberships" edge of the User entity.
+func (u *User) QueryMemberships() *MembershipQuery {
+	return NewUserClient(u.config).QueryMemberships(u)
+}
+
+// QueryInvitationsSent queries the "invitations_sent" edge of the User entity.
+func (u *User) QueryInvitationsSent() *InvitationQuery {
+	return NewUserClient(u.config).QueryInvitationsSent(u)
+}
+
+// QueryInviteCodes queries the "invite_codes" edge of the User entity.
+func (u *User) QueryInviteCodes() *InviteCodeQuery {
+	return NewUserClient(u.config).QueryInviteCodes(u)
+}
+
+// QueryDevices queries the "devices" edge of the User entity.
+func (u *User) QueryDevices() *DeviceQuery {
+	return NewUserClient(u.config).QueryDevices(u)
+}
+
 // Update returns a builder for updating this User.
 // Note that you need to call User.Unwrap() before calling this method if this User
 // was returned from a transaction, and the transaction was committed or rolled back.
@@ -167,6 +394,21 @@ func (u *User) String() string {
 	builder.WriteString("verified=")
 	builder.WriteString(fmt.Sprintf("%v", u.Verified))
 	builder.WriteString(", ")
+	builder.WriteString("verification_nonce=<sensitive>")
+	builder.WriteString(", ")
+	if v := u.LockedUntil; v != nil {
+		builder.WriteString("locked_until=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := u.DeletionScheduledAt; v != nil {
+		builder.WriteString("deletion_scheduled_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("session_version=")
+	builder.WriteString(fmt.Sprintf("%v", u.SessionVersion))
+	builder.WriteString(", ")
 	builder.WriteString("created_at=")
 	builder.WriteString(u.CreatedAt.Format(time.ANSIC))
 	builder.WriteByte(')')
@@ -23,10 +23,42 @@ const (
 	FieldPassword = "password"
 	// FieldVerified holds the string denoting the verified field in the database.
 	FieldVerified = "verified"
+	// FieldVerificationNonce holds the string denoting the verification_nonce field in the database.
+	FieldVerificationNonce = "verification_nonce"
+	// FieldLockedUntil holds the string denoting the locked_until field in the database.
+	FieldLockedUntil = "locked_until"
+	// FieldDeletionScheduledAt holds the string denoting the deletion_scheduled_at field in the database.
+	FieldDeletionScheduledAt = "deletion_scheduled_at"
+	// FieldSessionVersion holds the string denoting the session_version field in the database.
+	FieldSessionVersion = "session_version"
 	// FieldCreatedAt holds the string denoting the created_at field in the database.
 	FieldCreatedAt = "created_at"
 	// EdgeOwner holds the string denoting the owner edge name in mutations.
 	EdgeOwner = "owner"
+	// EdgeRoles holds the string denoting the roles edge name in mutations.
+	EdgeRoles = "roles"
+	// EdgePersonalAccessTokens holds the string denoting the personal_access_tokens edge name in mutations.
+	EdgePersonalAccessTokens = "personal_access_tokens"
+	// EdgeLoginTokens holds the string denoting the login_tokens edge name in mutations.
+	EdgeLoginTokens = "login_tokens"
+	// EdgeImpersonationsMade holds the string denoting the impersonations_made edge name in mutations.
+	EdgeImpersonationsMade = "impersonations_made"
+	// EdgeImpersonationsReceived holds the string denoting the impersonations_received edge name in mutations.
+	EdgeImpersonationsReceived = "impersonations_received"
+	// EdgeDataExports holds the string denoting the data_exports edge name in mutations.
+	EdgeDataExports = "data_exports"
+	// EdgeAuditEventsMade holds the string denoting the audit_events_made edge name in mutations.
+	EdgeAuditEventsMade = "audit_events_made"
+	// EdgeAuditEventsReceived holds the string denoting the audit_events_received edge name in mutations.
+	EdgeAuditEventsReceived = "audit_events_received"
+	// EdgeMemberships holds the string denoting the memberships edge name in mutations.
+	EdgeMemberships = "memberships"
+	// EdgeInvitationsSent holds the string denoting the invitations_sent edge name in mutations.
+	EdgeInvitationsSent = "invitations_sent"
+	// EdgeInviteCodes holds the string denoting the invite_codes edge name in mutations.
+	EdgeInviteCodes = "invite_codes"
+	// EdgeDevices holds the string denoting the devices edge name in mutations.
+	EdgeDevices = "devices"
 	// Table holds the table name of the user in the database.
 	Table = "users"
 	// OwnerTable is the table that holds the owner relation/edge.
@@ -36,6 +68,88 @@ const (
 	OwnerInverseTable = "password_tokens"
 	// OwnerColumn is the table column denoting the owner relation/edge.
 	OwnerColumn = "password_token_user"
+	// RolesTable is the table that holds the roles relation/edge. The primary key declared below.
+	RolesTable = "role_users"
+	// RolesInverseTable is the table name for the Role entity.
+	// It exists in this package in order to avoid circular dependency with the "role" package.
+	RolesInverseTable = "roles"
+	// PersonalAccessTokensTable is the table that holds the personal_access_tokens relation/edge.
+	PersonalAccessTokensTable = "personal_access_tokens"
+	// PersonalAccessTokensInverseTable is the table name for the PersonalAccessToken entity.
+	// It exists in this package in order to avoid circular dependency with the "personalaccesstoken" package.
+	PersonalAccessTokensInverseTable = "personal_access_tokens"
+	// PersonalAccessTokensColumn is the table column denoting the personal_access_tokens relation/edge.
+	PersonalAccessTokensColumn = "personal_access_token_user"
+	// LoginTokensTable is the table that holds the login_tokens relation/edge.
+	LoginTokensTable = "login_tokens"
+	// LoginTokensInverseTable is the table name for the LoginToken entity.
+	// It exists in this package in order to avoid circular dependency with the "logintoken" package.
+	LoginTokensInverseTable = "login_tokens"
+	// LoginTokensColumn is the table column denoting the login_tokens relation/edge.
+	LoginTokensColumn = "login_token_user"
+	// ImpersonationsMadeTable is the table that holds the impersonations_made relation/edge.
+	ImpersonationsMadeTable = "impersonations"
+	// ImpersonationsMadeInverseTable is the table name for the Impersonation entity.
+	// It exists in this package in order to avoid circular dependency with the "impersonation" package.
+	ImpersonationsMadeInverseTable = "impersonations"
+	// ImpersonationsMadeColumn is the table column denoting the impersonations_made relation/edge.
+	ImpersonationsMadeColumn = "impersonation_impersonator"
+	// ImpersonationsReceivedTable is the table that holds the impersonations_received relation/edge.
+	ImpersonationsReceivedTable = "impersonations"
+	// ImpersonationsReceivedInverseTable is the table name for the Impersonation entity.
+	// It exists in this package in order to avoid circular dependency with the "impersonation" package.
+	ImpersonationsReceivedInverseTable = "impersonations"
+	// ImpersonationsReceivedColumn is the table column denoting the impersonations_received relation/edge.
+	ImpersonationsReceivedColumn = "impersonation_user"
+	// DataExportsTable is the table that holds the data_exports relation/edge.
+	DataExportsTable = "data_exports"
+	// DataExportsInverseTable is the table name for the DataExport entity.
+	// It exists in this package in order to avoid circular dependency with the "dataexport" package.
+	DataExportsInverseTable = "data_exports"
+	// DataExportsColumn is the table column denoting the data_exports relation/edge.
+	DataExportsColumn = "data_export_user"
+	// AuditEventsMadeTable is the table that holds the audit_events_made relation/edge.
+	AuditEventsMadeTable = "audit_events"
+	// AuditEventsMadeInverseTable is the table name for the AuditEvent entity.
+	// It exists in this package in order to avoid circular dependency with the "auditevent" package.
+	AuditEventsMadeInverseTable = "audit_events"
+	// AuditEventsMadeColumn is the table column denoting the audit_events_made relation/edge.
+	AuditEventsMadeColumn = "audit_event_actor"
+	// AuditEventsReceivedTable is the table that holds the audit_events_received relation/edge.
+	AuditEventsReceivedTable = "audit_events"
+	// AuditEventsReceivedInverseTable is the table name for the AuditEvent entity.
+	// It exists in this package in order to avoid circular dependency with the "auditevent" package.
+	AuditEventsReceivedInverseTable = "audit_events"
+	// AuditEventsReceivedColumn is the table column denoting the audit_events_received relation/edge.
+	AuditEventsReceivedColumn = "audit_event_target"
+	// MembershipsTable is the table that holds the memberships relation/edge.
+	MembershipsTable = "memberships"
+	// MembershipsInverseTable is the table name for the Membership entity.
+	// It exists in this package in order to avoid circular dependency with the "membership" package.
+	MembershipsInverseTable = "memberships"
+	// MembershipsColumn is the table column denoting the memberships relation/edge.
+	MembershipsColumn = "membership_user"
+	// InvitationsSentTable is the table that holds the invitations_sent relation/edge.
+	InvitationsSentTable = "invitations"
+	// InvitationsSentInverseTable is the table name for the Invitation entity.
+	// It exists in this package in order to avoid circular dependency with the "invitation" package.
+	InvitationsSentInverseTable = "invitations"
+	// InvitationsSentColumn is the table column denoting the invitations_sent relation/edge.
+	InvitationsSentColumn = "invitation_inviter"
+	// InviteCodesTable is the table that holds the invite_codes relation/edge.
+	InviteCodesTable = "invite_codes"
+	// InviteCodesInverseTable is the table name for the InviteCode entity.
+	// It exists in this package in order to avoid circular dependency with the "invitecode" package.
+	InviteCodesInverseTable = "invite_codes"
+	// InviteCodesColumn is the table column denoting the invite_codes relation/edge.
+	InviteCodesColumn = "invite_code_creator"
+	// DevicesTable is the table that holds the devices relation/edge.
+	DevicesTable = "devices"
+	// DevicesInverseTable is the table name for the Device entity.
+	// It exists in this package in order to avoid circular dependency with the "device" package.
+	DevicesInverseTable = "devices"
+	// DevicesColumn is the table column denoting the devices relation/edge.
+	DevicesColumn = "device_user"
 )
 
 // Columns holds all SQL columns for user fields.
@@ -45,9 +159,19 @@ var Columns = []string{
 	FieldEmail,
 	FieldPassword,
 	FieldVerified,
+	FieldVerificationNonce,
+	FieldLockedUntil,
+	FieldDeletionScheduledAt,
+	FieldSessionVersion,
 	FieldCreatedAt,
 }
 
+var (
+	// RolesPrimaryKey and RolesColumn2 are the table columns denoting the
+	// primary key for the roles relation (M2M).
+	RolesPrimaryKey = []string{"role_id", "user_id"}
+)
+
 // ValidColumn reports if the column name is valid (part of the table columns).
 func ValidColumn(column string) bool {
 	for i := range Columns {
@@ -73,6 +197,8 @@ var (
 	PasswordValidator func(string) error
 	// DefaultVerified holds the default value on creation for the "verified" field.
 	DefaultVerified bool
+	// DefaultSessionVersion holds the default value on creation for the "session_version" field.
+	DefaultSessionVersion int
 	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
 	DefaultCreatedAt func() time.Time
 )
@@ -105,6 +231,26 @@ func ByVerified(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldVerified, opts...).ToFunc()
 }
 
+// ByVerificationNonce orders the results by the verification_nonce field.
+func ByVerificationNonce(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldVerificationNonce, opts...).ToFunc()
+}
+
+// ByLockedUntil orders the results by the locked_until field.
+func ByLockedUntil(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldLockedUntil, opts...).ToFunc()
+}
+
+// ByDeletionScheduledAt orders the results by the deletion_scheduled_at field.
+func ByDeletionScheduledAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDeletionScheduledAt, opts...).ToFunc()
+}
+
+// BySessionVersion orders the results by the session_version field.
+func BySessionVersion(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldSessionVersion, opts...).ToFunc()
+}
+
 // ByCreatedAt orders the results by the created_at field.
 func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
@@ -123,6 +269,174 @@ func ByOwner(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
 		sqlgraph.OrderByNeighborTerms(s, newOwnerStep(), append([]sql.OrderTerm{term}, terms...)...)
 	}
 }
+
+// ByRolesCount orders the results by roles count.
+func ByRolesCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newRolesStep(), opts...)
+	}
+}
+
+// ByRoles orders the results by roles terms.
+func ByRoles(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newRolesStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByPersonalAccessTokensCount orders the results by personal_access_tokens count.
+func ByPersonalAccessTokensCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newPersonalAccessTokensStep(), opts...)
+	}
+}
+
+// ByPersonalAccessTokens orders the results by personal_access_tokens terms.
+func ByPersonalAccessTokens(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newPersonalAccessTokensStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByLoginTokensCount orders the results by login_tokens count.
+func ByLoginTokensCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newLoginTokensStep(), opts...)
+	}
+}
+
+// ByLoginTokens orders the results by login_tokens terms.
+func ByLoginTokens(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newLoginTokensStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByImpersonationsMadeCount orders the results by impersonations_made count.
+func ByImpersonationsMadeCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newImpersonationsMadeStep(), opts...)
+	}
+}
+
+// ByImpersonationsMade orders the results by impersonations_made terms.
+func ByImpersonationsMade(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newImpersonationsMadeStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByImpersonationsReceivedCount orders the results by impersonations_received count.
+func ByImpersonationsReceivedCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newImpersonationsReceivedStep(), opts...)
+	}
+}
+
+// ByImpersonationsReceived orders the results by impersonations_received terms.
+func ByImpersonationsReceived(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newImpersonationsReceivedStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByDataExportsCount orders the results by data_exports count.
+func ByDataExportsCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newDataExportsStep(), opts...)
+	}
+}
+
+// ByDataExports orders the results by data_exports terms.
+func ByDataExports(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newDataExportsStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByAuditEventsMadeCount orders the results by audit_events_made count.
+func ByAuditEventsMadeCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newAuditEventsMadeStep(), opts...)
+	}
+}
+
+// ByAuditEventsMade orders the results by audit_events_made terms.
+func ByAuditEventsMade(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newAuditEventsMadeStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByAuditEventsReceivedCount orders the results by audit_events_received count.
+func ByAuditEventsReceivedCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newAuditEventsReceivedStep(), opts...)
+	}
+}
+
+// ByAuditEventsReceived orders the results by audit_events_received terms.
+func ByAuditEventsReceived(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newAuditEventsReceivedStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByMembershipsCount orders the results by memberships count.
+func ByMembershipsCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newMembershipsStep(), opts...)
+	}
+}
+
+// ByMemberships orders the results by memberships terms.
+func ByMemberships(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newMembershipsStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByInvitationsSentCount orders the results by invitations_sent count.
+func ByInvitationsSentCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newInvitationsSentStep(), opts...)
+	}
+}
+
+// ByInvitationsSent orders the results by invitations_sent terms.
+func ByInvitationsSent(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newInvitationsSentStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByInviteCodesCount orders the results by invite_codes count.
+func ByInviteCodesCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newInviteCodesStep(), opts...)
+	}
+}
+
+// ByInviteCodes orders the results by invite_codes terms.
+func ByInviteCodes(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newInviteCodesStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByDevicesCount orders the results by devices count.
+func ByDevicesCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newDevicesStep(), opts...)
+	}
+}
+
+// ByDevices orders the results by devices terms.
+func ByDevices(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newDevicesStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
 func newOwnerStep() *sqlgraph.Step {
 	return sqlgraph.NewStep(
 		sqlgraph.From(Table, FieldID),
@@ -130,3 +444,87 @@ func newOwnerStep() *sqlgraph.Step {
 		sqlgraph.Edge(sqlgraph.O2M, true, OwnerTable, OwnerColumn),
 	)
 }
+func newRolesStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(RolesInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.M2M, true, RolesTable, RolesPrimaryKey...),
+	)
+}
+func newPersonalAccessTokensStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(PersonalAccessTokensInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, true, PersonalAccessTokensTable, PersonalAccessTokensColumn),
+	)
+}
+func newLoginTokensStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(LoginTokensInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, true, LoginTokensTable, LoginTokensColumn),
+	)
+}
+func newImpersonationsMadeStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(ImpersonationsMadeInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, true, ImpersonationsMadeTable, ImpersonationsMadeColumn),
+	)
+}
+func newImpersonationsReceivedStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(ImpersonationsReceivedInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, true, ImpersonationsReceivedTable, ImpersonationsReceivedColumn),
+	)
+}
+func newDataExportsStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(DataExportsInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, true, DataExportsTable, DataExportsColumn),
+	)
+}
+func newAuditEventsMadeStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(AuditEventsMadeInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, true, AuditEventsMadeTable, AuditEventsMadeColumn),
+	)
+}
+func newAuditEventsReceivedStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(AuditEventsReceivedInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, true, AuditEventsReceivedTable, AuditEventsReceivedColumn),
+	)
+}
+func newMembershipsStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(MembershipsInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, true, MembershipsTable, MembershipsColumn),
+	)
+}
+func newInvitationsSentStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(InvitationsSentInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, true, InvitationsSentTable, InvitationsSentColumn),
+	)
+}
+func newInviteCodesStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(InviteCodesInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, true, InviteCodesTable, InviteCodesColumn),
+	)
+}
+func newDevicesStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(DevicesInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, true, DevicesTable, DevicesColumn),
+	)
+}
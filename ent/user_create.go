@@ -10,7 +10,17 @@ import (
 
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent/auditevent"
+	"github.com/mikestefanello/pagoda/ent/dataexport"
+	"github.com/mikestefanello/pagoda/ent/device"
+	"github.com/mikestefanello/pagoda/ent/impersonation"
+	"github.com/mikestefanello/pagoda/ent/invitation"
+	"github.com/mikestefanello/pagoda/ent/invitecode"
+	"github.com/mikestefanello/pagoda/ent/logintoken"
+	"github.com/mikestefanello/pagoda/ent/membership"
 	"github.com/mikestefanello/pagoda/ent/passwordtoken"
+	"github.com/mikestefanello/pagoda/ent/personalaccesstoken"
+	"github.com/mikestefanello/pagoda/ent/role"
 	"github.com/mikestefanello/pagoda/ent/user"
 )
 
@@ -53,6 +63,62 @@ func (uc *UserCreate) SetNillableVerified(b *bool) *UserCreate {
 	return uc
 }
 
+// SetVerificationNonce sets the "verification_nonce" field.
+func (uc *UserCreate) SetVerificationNonce(s string) *UserCreate {
+	uc.mutation.SetVerificationNonce(s)
+	return uc
+}
+
+// SetNillableVerificationNonce sets the "verification_nonce" field if the given value is not nil.
+func (uc *UserCreate) SetNillableVerificationNonce(s *string) *UserCreate {
+	if s != nil {
+		uc.SetVerificationNonce(*s)
+	}
+	return uc
+}
+
+// SetLockedUntil sets the "locked_until" field.
+func (uc *UserCreate) SetLockedUntil(t time.Time) *UserCreate {
+	uc.mutation.SetLockedUntil(t)
+	return uc
+}
+
+// SetNillableLockedUntil sets the "locked_until" field if the given value is not nil.
+func (uc *UserCreate) SetNillableLockedUntil(t *time.Time) *UserCreate {
+	if t != nil {
+		uc.SetLockedUntil(*t)
+	}
+	return uc
+}
+
+// SetDeletionScheduledAt sets the "deletion_scheduled_at" field.
+func (uc *UserCreate) SetDeletionScheduledAt(t time.Time) *UserCreate {
+	uc.mutation.SetDeletionScheduledAt(t)
+	return uc
+}
+
+// SetNillableDeletionScheduledAt sets the "deletion_scheduled_at" field if the given value is not nil.
+func (uc *UserCreate) SetNillableDeletionScheduledAt(t *time.Time) *UserCreate {
+	if t != nil {
+		uc.SetDeletionScheduledAt(*t)
+	}
+	return uc
+}
+
+// SetSessionVersion sets the "session_version" field.
+func (uc *UserCreate) SetSessionVersion(i int) *UserCreate {
+	uc.mutation.SetSessionVersion(i)
+	return uc
+}
+
+// SetNillableSessionVersion sets the "session_version" field if the given value is not nil.
+func (uc *UserCreate) SetNillableSessionVersion(i *int) *UserCreate {
+	if i != nil {
+		uc.SetSessionVersion(*i)
+	}
+	return uc
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (uc *UserCreate) SetCreatedAt(t time.Time) *UserCreate {
 	uc.mutation.SetCreatedAt(t)
@@ -82,6 +148,186 @@ func (uc *UserCreate) AddOwner(p ...*PasswordToken) *UserCreate {
 	return uc.AddOwnerIDs(ids...)
 }
 
+// AddRoleIDs adds the "roles" edge to the Role entity by IDs.
+func (uc *UserCreate) AddRoleIDs(ids ...int) *UserCreate {
+	uc.mutation.AddRoleIDs(ids...)
+	return uc
+}
+
+// AddRoles adds the "roles" edges to the Role entity.
+func (uc *UserCreate) AddRoles(r ...*Role) *UserCreate {
+	ids := make([]int, len(r))
+	for i := range r {
+		ids[i] = r[i].ID
+	}
+	return uc.AddRoleIDs(ids...)
+}
+
+// AddPersonalAccessTokenIDs adds the "personal_access_tokens" edge to the PersonalAccessToken entity by IDs.
+func (uc *UserCreate) AddPersonalAccessTokenIDs(ids ...int) *UserCreate {
+	uc.mutation.AddPersonalAccessTokenIDs(ids...)
+	return uc
+}
+
+// AddPersonalAccessTokens adds the "personal_access_tokens" edges to the PersonalAccessToken entity.
+func (uc *UserCreate) AddPersonalAccessTokens(p ...*PersonalAccessToken) *UserCreate {
+	ids := make([]int, len(p))
+	for i := range p {
+		ids[i] = p[i].ID
+	}
+	return uc.AddPersonalAccessTokenIDs(ids...)
+}
+
+// AddLoginTokenIDs adds the "login_tokens" edge to the LoginToken entity by IDs.
+func (uc *UserCreate) AddLoginTokenIDs(ids ...int) *UserCreate {
+	uc.mutation.AddLoginTokenIDs(ids...)
+	return uc
+}
+
+// AddLoginTokens adds the "login_tokens" edges to the LoginToken entity.
+func (uc *UserCreate) AddLoginTokens(l ...*LoginToken) *UserCreate {
+	ids := make([]int, len(l))
+	for i := range l {
+		ids[i] = l[i].ID
+	}
+	return uc.AddLoginTokenIDs(ids...)
+}
+
+// AddImpersonationsMadeIDs adds the "impersonations_made" edge to the Impersonation entity by IDs.
+func (uc *UserCreate) AddImpersonationsMadeIDs(ids ...int) *UserCreate {
+	uc.mutation.AddImpersonationsMadeIDs(ids...)
+	return uc
+}
+
+// AddImpersonationsMade adds the "impersonations_made" edges to the Impersonation entity.
+func (uc *UserCreate) AddImpersonationsMade(i ...*Impersonation) *UserCreate {
+	ids := make([]int, len(i))
+	for j := range i {
+		ids[j] = i[j].ID
+	}
+	return uc.AddImpersonationsMadeIDs(ids...)
+}
+
+// AddImpersonationsReceivedIDs adds the "impersonations_received" edge to the Impersonation entity by IDs.
+func (uc *UserCreate) AddImpersonationsReceivedIDs(ids ...int) *UserCreate {
+	uc.mutation.AddImpersonationsReceivedIDs(ids...)
+	return uc
+}
+
+// AddImpersonationsReceived adds the "impersonations_received" edges to the Impersonation entity.
+func (uc *UserCreate) AddImpersonationsReceived(i ...*Impersonation) *UserCreate {
+	ids := make([]int, len(i))
+	for j := range i {
+		ids[j] = i[j].ID
+	}
+	return uc.AddImpersonationsReceivedIDs(ids...)
+}
+
+// AddDataExportIDs adds the "data_exports" edge to the DataExport entity by IDs.
+func (uc *UserCreate) AddDataExportIDs(ids ...int) *UserCreate {
+	uc.mutation.AddDataExportIDs(ids...)
+	return uc
+}
+
+// AddDataExports adds the "data_exports" edges to the DataExport entity.
+func (uc *UserCreate) AddDataExports(d ...*DataExport) *UserCreate {
+	ids := make([]int, len(d))
+	for i := range d {
+		ids[i] = d[i].ID
+	}
+	return uc.AddDataExportIDs(ids...)
+}
+
+// AddAuditEventsMadeIDs adds the "audit_events_made" edge to the AuditEvent entity by IDs.
+func (uc *UserCreate) AddAuditEventsMadeIDs(ids ...int) *UserCreate {
+	uc.mutation.AddAuditEventsMadeIDs(ids...)
+	return uc
+}
+
+// AddAuditEventsMade adds the "audit_events_made" edges to the AuditEvent entity.
+func (uc *UserCreate) AddAuditEventsMade(a ...*AuditEvent) *UserCreate {
+	ids := make([]int, len(a))
+	for i := range a {
+		ids[i] = a[i].ID
+	}
+	return uc.AddAuditEventsMadeIDs(ids...)
+}
+
+// AddAuditEventsReceivedIDs adds the "audit_events_received" edge to the AuditEvent entity by IDs.
+func (uc *UserCreate) AddAuditEventsReceivedIDs(ids ...int) *UserCreate {
+	uc.mutation.AddAuditEventsReceivedIDs(ids...)
+	return uc
+}
+
+// AddAuditEventsReceived adds the "audit_events_received" edges to the AuditEvent entity.
+func (uc *UserCreate) AddAuditEventsReceived(a ...*AuditEvent) *UserCreate {
+	ids := make([]int, len(a))
+	for i := range a {
+		ids[i] = a[i].ID
+	}
+	return uc.AddAuditEventsReceivedIDs(ids...)
+}
+
+// AddMembershipIDs adds the "memberships" edge to the Membership entity by IDs.
+func (uc *UserCreate) AddMembershipIDs(ids ...int) *UserCreate {
+	uc.mutation.AddMembershipIDs(ids...)
+	return uc
+}
+
+// AddMemberships adds the "memberships" edges to the Membership entity.
+func (uc *UserCreate) AddMemberships(m ...*Membership) *UserCreate {
+	ids := make([]int, len(m))
+	for i := range m {
+		ids[i] = m[i].ID
+	}
+	return uc.AddMembershipIDs(ids...)
+}
+
+// AddInvitationsSentIDs adds the "invitations_sent" edge to the Invitation entity by IDs.
+func (uc *UserCreate) AddInvitationsSentIDs(ids ...int) *UserCreate {
+	uc.mutation.AddInvitationsSentIDs(ids...)
+	return uc
+}
+
+// AddInvitationsSent adds the "invitations_sent" edges to the Invitation entity.
+func (uc *UserCreate) AddInvitationsSent(i ...*Invitation) *UserCreate {
+	ids := make([]int, len(i))
+	for j := range i {
+		ids[j] = i[j].ID
+	}
+	return uc.AddInvitationsSentIDs(ids...)
+}
+
+// AddInviteCodeIDs adds the "invite_codes" edge to the InviteCode entity by IDs.
+func (uc *UserCreate) AddInviteCodeIDs(ids ...int) *UserCreate {
+	uc.mutation.AddInviteCodeIDs(ids...)
+	return uc
+}
+
+// AddInviteCodes adds the "invite_codes" edges to the InviteCode entity.
+func (uc *UserCreate) AddInviteCodes(i ...*InviteCode) *UserCreate {
+	ids := make([]int, len(i))
+	for j := range i {
+		ids[j] = i[j].ID
+	}
+	return uc.AddInviteCodeIDs(ids...)
+}
+
+// AddDeviceIDs adds the "devices" edge to the Device entity by IDs.
+func (uc *UserCreate) AddDeviceIDs(ids ...int) *UserCreate {
+	uc.mutation.AddDeviceIDs(ids...)
+	return uc
+}
+
+// AddDevices adds the "devices" edges to the Device entity.
+func (uc *UserCreate) AddDevices(d ...*Device) *UserCreate {
+	ids := make([]int, len(d))
+	for i := range d {
+		ids[i] = d[i].ID
+	}
+	return uc.AddDeviceIDs(ids...)
+}
+
 // Mutation returns the UserMutation object of the builder.
 func (uc *UserCreate) Mutation() *UserMutation {
 	return uc.mutation
@@ -123,6 +369,10 @@ func (uc *UserCreate) defaults() error {
 		v := user.DefaultVerified
 		uc.mutation.SetVerified(v)
 	}
+	if _, ok := uc.mutation.SessionVersion(); !ok {
+		v := user.DefaultSessionVersion
+		uc.mutation.SetSessionVersion(v)
+	}
 	if _, ok := uc.mutation.CreatedAt(); !ok {
 		if user.DefaultCreatedAt == nil {
 			return fmt.Errorf("ent: uninitialized user.DefaultCreatedAt (forgotten import ent/runtime?)")
@@ -162,6 +412,9 @@ func (uc *UserCreate) check() error {
 	if _, ok := uc.mutation.Verified(); !ok {
 		return &ValidationError{Name: "verified", err: errors.New(`ent: missing required field "User.verified"`)}
 	}
+	if _, ok := uc.mutation.SessionVersion(); !ok {
+		return &ValidationError{Name: "session_version", err: errors.New(`ent: missing required field "User.session_version"`)}
+	}
 	if _, ok := uc.mutation.CreatedAt(); !ok {
 		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "User.created_at"`)}
 	}
@@ -207,6 +460,22 @@ func (uc *UserCreate) createSpec() (*User, *sqlgraph.CreateSpec) {
 		_spec.SetField(user.FieldVerified, field.TypeBool, value)
 		_node.Verified = value
 	}
+	if value, ok := uc.mutation.VerificationNonce(); ok {
+		_spec.SetField(user.FieldVerificationNonce, field.TypeString, value)
+		_node.VerificationNonce = &value
+	}
+	if value, ok := uc.mutation.LockedUntil(); ok {
+		_spec.SetField(user.FieldLockedUntil, field.TypeTime, value)
+		_node.LockedUntil = &value
+	}
+	if value, ok := uc.mutation.DeletionScheduledAt(); ok {
+		_spec.SetField(user.FieldDeletionScheduledAt, field.TypeTime, value)
+		_node.DeletionScheduledAt = &value
+	}
+	if value, ok := uc.mutation.SessionVersion(); ok {
+		_spec.SetField(user.FieldSessionVersion, field.TypeInt, value)
+		_node.SessionVersion = value
+	}
 	if value, ok := uc.mutation.CreatedAt(); ok {
 		_spec.SetField(user.FieldCreatedAt, field.TypeTime, value)
 		_node.CreatedAt = value
@@ -227,6 +496,198 @@ func (uc *UserCreate) createSpec() (*User, *sqlgraph.CreateSpec) {
 		}
 		_spec.Edges = append(_spec.Edges, edge)
 	}
+	if nodes := uc.mutation.RolesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   user.RolesTable,
+			Columns: user.RolesPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := uc.mutation.PersonalAccessTokensIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: true,
+			Table:   user.PersonalAccessTokensTable,
+			Columns: []string{user.PersonalAccessTokensColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(personalaccesstoken.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := uc.mutation.LoginTokensIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: true,
+			Table:   user.LoginTokensTable,
+			Columns: []string{user.LoginTokensColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(logintoken.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := uc.mutation.ImpersonationsMadeIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: true,
+			Table:   user.ImpersonationsMadeTable,
+			Columns: []string{user.ImpersonationsMadeColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(impersonation.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := uc.mutation.ImpersonationsReceivedIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: true,
+			Table:   user.ImpersonationsReceivedTable,
+			Columns: []string{user.ImpersonationsReceivedColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(impersonation.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := uc.mutation.DataExportsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: true,
+			Table:   user.DataExportsTable,
+			Columns: []string{user.DataExportsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(dataexport.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := uc.mutation.AuditEventsMadeIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: true,
+			Table:   user.AuditEventsMadeTable,
+			Columns: []string{user.AuditEventsMadeColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(auditevent.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := uc.mutation.AuditEventsReceivedIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: true,
+			Table:   user.AuditEventsReceivedTable,
+			Columns: []string{user.AuditEventsReceivedColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(auditevent.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := uc.mutation.MembershipsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: true,
+			Table:   user.MembershipsTable,
+			Columns: []string{user.MembershipsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(membership.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := uc.mutation.InvitationsSentIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: true,
+			Table:   user.InvitationsSentTable,
+			Columns: []string{user.InvitationsSentColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(invitation.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := uc.mutation.InviteCodesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: true,
+			Table:   user.InviteCodesTable,
+			Columns: []string{user.InviteCodesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(invitecode.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := uc.mutation.DevicesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: true,
+			Table:   user.DevicesTable,
+			Columns: []string{user.DevicesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(device.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
 	return _node, _spec
 }
 
@@ -0,0 +1,129 @@
+package bot
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math/bits"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	pctx "github.com/mikestefanello/pagoda/pkg/context"
+)
+
+// DetectedError is an error returned when a form submission fails a bot protection check
+type DetectedError struct {
+	// Reason describes which check failed, which is logged but never shown to the user
+	Reason string
+}
+
+// Error implements the error interface.
+func (e DetectedError) Error() string {
+	return fmt.Sprintf("bot detected: %s", e.Reason)
+}
+
+type (
+	// Policy contains the bot protection checks applied to a form, besides the honeypot, which always applies
+	Policy struct {
+		// MinFillTime is the minimum amount of time between rendering and submitting the form.
+		// Zero disables the check.
+		MinFillTime time.Duration
+
+		// ProofOfWork requires the browser to solve a proof-of-work challenge before submitting the form
+		ProofOfWork bool
+	}
+
+	// Challenge contains the values needed to render the bot protection fields of a form
+	Challenge struct {
+		// Token is the signed token, which is also the challenge of the proof-of-work
+		Token string
+
+		// Difficulty is the amount of leading zero bits required in the hash of the proof-of-work solution, or zero
+		// if no proof-of-work is required
+		Difficulty int
+	}
+
+	// Checker issues and verifies the tokens used to protect forms against bots
+	Checker interface {
+		// DefaultPolicy returns the policy applied to forms which do not provide their own
+		DefaultPolicy() Policy
+
+		// Issue issues a challenge for a form with a given policy
+		Issue(policy Policy) (Challenge, error)
+
+		// Verify verifies the token and proof-of-work solution submitted with a form with a given policy.
+		// Returns a DetectedError if a check failed.
+		Verify(ctx context.Context, policy Policy, token, solution string) error
+	}
+
+	// Protected is satisfied by forms which are protected against bots, by embedding form.BotProtection
+	Protected interface {
+		BotProtected() bool
+	}
+
+	// PolicyProvider is implemented by forms which change the checks applied to them, starting from the default
+	// policy
+	PolicyProvider interface {
+		BotPolicy(defaults Policy) Policy
+	}
+)
+
+// GetChecker returns the checker stored in the request context
+func GetChecker(ctx echo.Context) (Checker, error) {
+	checker, ok := ctx.Get(pctx.BotCheckerKey).(Checker)
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "bot protection is not configured")
+	}
+	return checker, nil
+}
+
+// GetPolicy returns the policy of a given form, which is the default policy of a given checker unless the form
+// implements PolicyProvider
+func GetPolicy(checker Checker, form any) Policy {
+	policy := checker.DefaultPolicy()
+	if p, ok := form.(PolicyProvider); ok {
+		policy = p.BotPolicy(policy)
+	}
+	return policy
+}
+
+// IssueChallenge issues a challenge for a given form, to be rendered within it.
+// Nil is returned if the form is not protected against bots.
+func IssueChallenge(ctx echo.Context, form any) (*Challenge, error) {
+	if p, ok := form.(Protected); !ok || !p.BotProtected() {
+		return nil, nil
+	}
+
+	checker, err := GetChecker(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := checker.Issue(GetPolicy(checker, form))
+	if err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// VerifyProofOfWork determines if a given solution solves the proof-of-work of a given challenge, which requires
+// the SHA-256 hash of the challenge, a colon and the solution to start with a given amount of zero bits
+func VerifyProofOfWork(challenge, solution string, difficulty int) bool {
+	if solution == "" {
+		return false
+	}
+
+	hash := sha256.Sum256([]byte(challenge + ":" + solution))
+
+	zeros := 0
+	for _, b := range hash {
+		zeros += bits.LeadingZeros8(b)
+		if b != 0 {
+			break
+		}
+	}
+
+	return zeros >= difficulty
+}
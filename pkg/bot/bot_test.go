@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	pctx "github.com/mikestefanello/pagoda/pkg/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	testChecker struct{}
+
+	testForm struct{}
+
+	testPolicyForm struct {
+		testForm
+	}
+)
+
+func (testChecker) DefaultPolicy() Policy {
+	return Policy{MinFillTime: time.Second}
+}
+
+func (testChecker) Issue(policy Policy) (Challenge, error) {
+	c := Challenge{Token: "token"}
+	if policy.ProofOfWork {
+		c.Difficulty = 8
+	}
+	return c, nil
+}
+
+func (testChecker) Verify(_ context.Context, _ Policy, _, _ string) error {
+	return nil
+}
+
+func (testForm) BotProtected() bool {
+	return true
+}
+
+func (testPolicyForm) BotPolicy(defaults Policy) Policy {
+	defaults.ProofOfWork = true
+	return defaults
+}
+
+func TestVerifyProofOfWork(t *testing.T) {
+	// Find a solution the slow way
+	solution := ""
+	for i := 0; solution == ""; i++ {
+		if VerifyProofOfWork("challenge", strconv.Itoa(i), 8) {
+			solution = strconv.Itoa(i)
+		}
+	}
+
+	assert.True(t, VerifyProofOfWork("challenge", solution, 8))
+	assert.True(t, VerifyProofOfWork("challenge", solution, 0))
+	assert.False(t, VerifyProofOfWork("other", solution, 8))
+	assert.False(t, VerifyProofOfWork("challenge", "", 0))
+	assert.False(t, VerifyProofOfWork("challenge", solution, 256+1))
+}
+
+func TestGetPolicy(t *testing.T) {
+	assert.Equal(t, Policy{MinFillTime: time.Second}, GetPolicy(testChecker{}, testForm{}))
+	assert.Equal(t, Policy{MinFillTime: time.Second, ProofOfWork: true}, GetPolicy(testChecker{}, testPolicyForm{}))
+}
+
+func TestIssueChallenge(t *testing.T) {
+	e := echo.New()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	// Forms which are not protected do not need a checker
+	challenge, err := IssueChallenge(ctx, struct{}{})
+	require.NoError(t, err)
+	assert.Nil(t, challenge)
+
+	_, err = IssueChallenge(ctx, testForm{})
+	assert.IsType(t, new(echo.HTTPError), err)
+
+	ctx.Set(pctx.BotCheckerKey, testChecker{})
+	challenge, err = IssueChallenge(ctx, testForm{})
+	require.NoError(t, err)
+	assert.Equal(t, &Challenge{Token: "token"}, challenge)
+
+	challenge, err = IssueChallenge(ctx, testPolicyForm{})
+	require.NoError(t, err)
+	assert.Equal(t, &Challenge{Token: "token", Difficulty: 8}, challenge)
+}
@@ -9,6 +9,29 @@ const (
 	// AuthenticatedUserKey is the key value used to store the authenticated user in context
 	AuthenticatedUserKey = "auth_user"
 
+	// AuthenticatedAtKey is the key value used to store when the authenticated user last proved who they are, by
+	// logging in or re-entering their password, in context
+	AuthenticatedAtKey = "auth_at"
+
+	// ImpersonatorKey is the key value used to store the admin impersonating the authenticated user in context
+	ImpersonatorKey = "impersonator"
+
+	// AccessTokenKey is the key value used to store the personal access token used to authenticate the request in context
+	AccessTokenKey = "access_token"
+
+	// PermissionsKey is the key value used to store the set of permissions granted to the authenticated user in context
+	PermissionsKey = "permissions"
+
+	// OrganizationKey is the key value used to store the organization the authenticated user is working in in context
+	OrganizationKey = "organization"
+
+	// MembershipKey is the key value used to store the membership of the authenticated user in the current
+	// organization in context
+	MembershipKey = "membership"
+
+	// MembershipsKey is the key value used to store all memberships of the authenticated user in context
+	MembershipsKey = "memberships"
+
 	// UserKey is the key value used to store a user in context
 	UserKey = "user"
 
@@ -21,6 +44,9 @@ const (
 	// LoggerKey is the key value used to store a structured logger in context
 	LoggerKey = "logger"
 
+	// BotCheckerKey is the key value used to store the checker used to protect forms against bots in context
+	BotCheckerKey = "bot_checker"
+
 	// SessionKey is the key value used to store the session data in context
 	SessionKey = "session"
 )
@@ -0,0 +1,57 @@
+package form
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/pkg/bot"
+)
+
+// BotProtectionField is the name of the field which the errors of the bot protection checks are set on
+const BotProtectionField = "BotProtection"
+
+// BotProtection protects a form against spam bots when embedded in the form struct next to Submission.
+// It combines a honeypot field, which is hidden from humans, a signed token which rejects forms submitted too
+// soon after they were rendered, and an optional proof-of-work challenge solved in the browser.
+// The checks are verified by Submission.Submit, using the bot.Checker stored in context, and the fields are
+// rendered by the bot-protection template component, which must be included within the form.
+// To change the checks applied to a form, the form can implement bot.PolicyProvider.
+type BotProtection struct {
+	// Honeypot is a field hidden from humans, so only bots fill it in
+	Honeypot string `form:"homepage"`
+
+	// BotToken is the signed token issued when the form was rendered
+	BotToken string `form:"bot-token"`
+
+	// BotSolution is the solution to the proof-of-work challenge, if required
+	BotSolution string `form:"bot-solution"`
+}
+
+// BotProtected satisfies bot.Protected
+func (b *BotProtection) BotProtected() bool {
+	return true
+}
+
+// botProtection returns the embedded bot protection of a form
+func (b *BotProtection) botProtection() *BotProtection {
+	return b
+}
+
+// verifyBot performs the bot protection checks of a given form, if it embeds BotProtection.
+// Returns a bot.DetectedError if a check failed.
+func verifyBot(ctx echo.Context, form any) error {
+	bp, ok := form.(interface{ botProtection() *BotProtection })
+	if !ok {
+		return nil
+	}
+
+	checker, err := bot.GetChecker(ctx)
+	if err != nil {
+		return err
+	}
+
+	b := bp.botProtection()
+	if b.Honeypot != "" {
+		return bot.DetectedError{Reason: "honeypot filled in"}
+	}
+
+	return checker.Verify(ctx.Request().Context(), bot.GetPolicy(checker, form), b.BotToken, b.BotSolution)
+}
@@ -0,0 +1,102 @@
+package form
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/pkg/bot"
+	pctx "github.com/mikestefanello/pagoda/pkg/context"
+	"github.com/mikestefanello/pagoda/pkg/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// botChecker is a bot.Checker which only accepts the token "token" and the solution "solution"
+type botChecker struct {
+	policy bot.Policy
+}
+
+func (b *botChecker) DefaultPolicy() bot.Policy {
+	return bot.Policy{}
+}
+
+func (b *botChecker) Issue(_ bot.Policy) (bot.Challenge, error) {
+	return bot.Challenge{Token: "token"}, nil
+}
+
+func (b *botChecker) Verify(_ context.Context, policy bot.Policy, token, solution string) error {
+	b.policy = policy
+	if token != "token" || (policy.ProofOfWork && solution != "solution") {
+		return bot.DetectedError{Reason: "test"}
+	}
+	return nil
+}
+
+type botFormTest struct {
+	Name string `form:"name" validate:"required"`
+	Submission
+	BotProtection
+}
+
+func (f *botFormTest) BotPolicy(defaults bot.Policy) bot.Policy {
+	defaults.ProofOfWork = true
+	return defaults
+}
+
+func TestFormSubmission_BotProtection(t *testing.T) {
+	e := echo.New()
+	e.Validator = services.NewValidator()
+	checker := new(botChecker)
+
+	submit := func(body string, withChecker bool) (*botFormTest, error) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		ctx := e.NewContext(req, httptest.NewRecorder())
+		if withChecker {
+			ctx.Set(pctx.BotCheckerKey, checker)
+		}
+
+		var form botFormTest
+		err := form.Submit(ctx, &form)
+		return &form, err
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		form, err := submit("name=a&bot-token=token&bot-solution=solution", true)
+		require.NoError(t, err)
+		assert.True(t, form.IsDone())
+		assert.True(t, checker.policy.ProofOfWork)
+	})
+
+	t.Run("honeypot", func(t *testing.T) {
+		form, err := submit("name=a&bot-token=token&bot-solution=solution&homepage=spam", true)
+		assert.IsType(t, validator.ValidationErrors{}, err)
+		assert.False(t, form.IsValid())
+		assert.True(t, form.FieldHasErrors(BotProtectionField))
+		assert.False(t, form.FieldHasErrors("Name"))
+	})
+
+	t.Run("failed check", func(t *testing.T) {
+		form, err := submit("name=a&bot-token=token", true)
+		assert.IsType(t, validator.ValidationErrors{}, err)
+		assert.True(t, form.FieldHasErrors(BotProtectionField))
+		assert.False(t, form.IsDone())
+	})
+
+	t.Run("failed check and validation", func(t *testing.T) {
+		form, err := submit("bot-token=invalid", true)
+		assert.IsType(t, validator.ValidationErrors{}, err)
+		assert.True(t, form.FieldHasErrors(BotProtectionField))
+		assert.True(t, form.FieldHasErrors("Name"))
+	})
+
+	t.Run("missing checker", func(t *testing.T) {
+		_, err := submit("name=a&bot-token=token&bot-solution=solution", false)
+		assert.IsType(t, new(echo.HTTPError), err)
+	})
+}
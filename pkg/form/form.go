@@ -9,7 +9,8 @@ import (
 type Form interface {
 	// Submit marks the form as submitted, stores a pointer to it in the context, binds the request
 	// values to the struct fields, and validates the input based on the struct tags.
-	// Returns a validator.ValidationErrors if the form values were not valid.
+	// If the form embeds BotProtection, the bot protection checks are performed as well.
+	// Returns a validator.ValidationErrors if the form values were not valid or a bot protection check failed.
 	// Returns an echo.HTTPError if the request failed to process.
 	Submit(c echo.Context, form any) error
 
@@ -35,10 +36,11 @@ type Form interface {
 	GetFieldStatusClass(fieldName string) string
 }
 
-// Get gets a form from the context or initializes a new copy if one is not set
+// Get gets a form from the context or initializes a new copy if one of the given type is not set.
+// Checking the type allows a single page to render multiple forms, only one of which was submitted.
 func Get[T any](ctx echo.Context) *T {
-	if v := ctx.Get(context.FormKey); v != nil {
-		return v.(*T)
+	if v, ok := ctx.Get(context.FormKey).(*T); ok {
+		return v
 	}
 	var v T
 	return &v
@@ -60,4 +60,17 @@ func TestGetClear(t *testing.T) {
 		require.NotNil(t, got)
 		assert.Empty(t, got.Name)
 	})
+
+	t.Run("get different type", func(t *testing.T) {
+		type other struct {
+			Email string `form:"email"`
+		}
+		ctx, _ := tests.NewContext(e, "/")
+		ctx.Set(context.FormKey, &other{Email: "test"})
+
+		// A form of a different type is not returned
+		got := Get[example](ctx)
+		require.NotNil(t, got)
+		assert.Empty(t, got.Name)
+	})
 }
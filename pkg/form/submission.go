@@ -5,7 +5,9 @@ import (
 	"net/http"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/mikestefanello/pagoda/pkg/bot"
 	"github.com/mikestefanello/pagoda/pkg/context"
+	"github.com/mikestefanello/pagoda/pkg/log"
 
 	"github.com/labstack/echo/v4"
 )
@@ -31,12 +33,30 @@ func (f *Submission) Submit(ctx echo.Context, form any) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("unable to bind form: %v", err))
 	}
 
+	// Check that the form was not submitted by a bot, if it is protected
+	if err := verifyBot(ctx, form); err != nil {
+		detected, ok := err.(bot.DetectedError)
+		if !ok {
+			return err
+		}
+
+		log.Ctx(ctx).Warn("form submission failed bot protection",
+			"reason", detected.Reason,
+		)
+		f.SetFieldError(BotProtectionField, "Your submission could not be verified. Please try again.")
+	}
+
 	// Validate the form
 	if err := ctx.Validate(form); err != nil {
 		f.setErrorMessages(err)
 		return err
 	}
 
+	// Even though all values are valid, the form is not if it failed the bot protection checks
+	if !f.IsValid() {
+		return validator.ValidationErrors{}
+	}
+
 	return nil
 }
 
@@ -96,7 +116,8 @@ func (f *Submission) setErrorMessages(err error) {
 
 		// Provide better error messages depending on the failed validation tag
 		// This should be expanded as you use additional tags in your validation
-		switch ve.Tag() {
+		// The actual tag is used so that the tags behind an alias, such as the password policy, can be matched
+		switch ve.ActualTag() {
 		case "required":
 			message = "This field is required."
 		case "email":
@@ -105,6 +126,16 @@ func (f *Submission) setErrorMessages(err error) {
 			message = "Does not match."
 		case "gte":
 			message = fmt.Sprintf("Must be greater than or equal to %v.", ve.Param())
+		case "pwmin":
+			message = fmt.Sprintf("Must be at least %v characters long.", ve.Param())
+		case "pwmax":
+			message = fmt.Sprintf("Must be no longer than %v bytes.", ve.Param())
+		case "pwpersonal":
+			message = "Must not contain your name or email address."
+		case "pwstrength":
+			message = "Too easy to guess. Try a longer password or mix in upper case letters, numbers and symbols."
+		case "pwbreached":
+			message = "This password has appeared in a data breach and cannot be used. Please choose another."
 		default:
 			message = "Invalid value."
 		}
@@ -0,0 +1,579 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/backlite"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/user"
+	"github.com/mikestefanello/pagoda/pkg/context"
+	"github.com/mikestefanello/pagoda/pkg/form"
+	"github.com/mikestefanello/pagoda/pkg/log"
+	"github.com/mikestefanello/pagoda/pkg/middleware"
+	"github.com/mikestefanello/pagoda/pkg/msg"
+	"github.com/mikestefanello/pagoda/pkg/page"
+	"github.com/mikestefanello/pagoda/pkg/redirect"
+	"github.com/mikestefanello/pagoda/pkg/services"
+	"github.com/mikestefanello/pagoda/pkg/tasks"
+	"github.com/mikestefanello/pagoda/templates"
+)
+
+const (
+	routeNameAccount               = "account"
+	routeNameAccountProfileSubmit  = "account.profile.submit"
+	routeNameAccountEmailSubmit    = "account.email.submit"
+	routeNameAccountEmailConfirm   = "account.email.confirm"
+	routeNameAccountPasswordSubmit = "account.password.submit"
+	routeNameAccountExport         = "account.export"
+	routeNameAccountExportDownload = "account.export.download"
+	routeNameAccountDelete         = "account.delete"
+	routeNameAccountDeleteCancel   = "account.delete.cancel"
+)
+
+// recentLoginMaxAge is how recently users must have entered their password to perform sensitive actions, such as
+// changing their email address
+const recentLoginMaxAge = 15 * time.Minute
+
+type (
+	Account struct {
+		account  *services.AccountClient
+		audit    *services.AuditLogger
+		auth     *services.AuthClient
+		mail     *services.MailClient
+		notify   *services.NotificationClient
+		orm      *ent.Client
+		reg      *services.RegistrationClient
+		tasks    *backlite.Client
+		throttle *services.ThrottleClient
+		*services.TemplateRenderer
+	}
+
+	accountData struct {
+		Profile  *profileForm
+		Email    *changeEmailForm
+		Password *changePasswordForm
+		Delete   *deleteAccountForm
+	}
+
+	profileForm struct {
+		Name string `form:"name" validate:"required"`
+		form.Submission
+	}
+
+	changeEmailForm struct {
+		Email string `form:"email" validate:"required,email"`
+		form.Submission
+	}
+
+	changePasswordForm struct {
+		CurrentPassword string `form:"current-password" validate:"required"`
+		Password        string `form:"password" validate:"required,password"`
+		ConfirmPassword string `form:"password-confirm" validate:"required,eqfield=Password"`
+		// Name and Email are not submitted but are populated from the user so the password policy can check
+		// that the password does not contain them
+		Name  string `form:"-"`
+		Email string `form:"-"`
+		form.Submission
+	}
+
+	deleteAccountForm struct {
+		Password string `form:"delete-password" validate:"required"`
+		form.Submission
+	}
+)
+
+func init() {
+	Register(new(Account))
+}
+
+func (h *Account) Init(c *services.Container) error {
+	h.TemplateRenderer = c.TemplateRenderer
+	h.account = c.Account
+	h.audit = c.Audit
+	h.auth = c.Auth
+	h.mail = c.Mail
+	h.notify = c.Notification
+	h.orm = c.ORM
+	h.reg = c.Registration
+	h.tasks = c.Tasks
+	h.throttle = c.Throttle
+	return nil
+}
+
+func (h *Account) Routes(g *echo.Group) {
+	// The confirmation link may be opened in any browser, so authentication is not required
+	g.GET("/account/email/confirm/:token", h.EmailConfirm).Name = routeNameAccountEmailConfirm
+
+	account := g.Group("/account",
+		middleware.RequireAuthentication(),
+		middleware.RequireNoImpersonation(),
+		middleware.RequireNoAccessToken(),
+	)
+	account.GET("", h.Page).Name = routeNameAccount
+	account.POST("/profile", h.ProfileSubmit).Name = routeNameAccountProfileSubmit
+	account.POST("/email", h.EmailSubmit, middleware.RequireRecentLogin(recentLoginMaxAge)).Name = routeNameAccountEmailSubmit
+	account.POST("/password", h.PasswordSubmit).Name = routeNameAccountPasswordSubmit
+	account.POST("/export", h.Export, middleware.RequireVerifiedEmail(routeNameVerifyEmailNotice)).Name = routeNameAccountExport
+	account.GET("/export/:token", h.ExportDownload).Name = routeNameAccountExportDownload
+	account.POST("/delete", h.Delete, middleware.RequireRecentLogin(recentLoginMaxAge)).Name = routeNameAccountDelete
+	account.POST("/delete/cancel", h.DeleteCancel).Name = routeNameAccountDeleteCancel
+}
+
+func (h *Account) Page(ctx echo.Context) error {
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+
+	data := accountData{
+		Profile:  form.Get[profileForm](ctx),
+		Email:    form.Get[changeEmailForm](ctx),
+		Password: form.Get[changePasswordForm](ctx),
+		Delete:   form.Get[deleteAccountForm](ctx),
+	}
+
+	if !data.Profile.IsSubmitted() {
+		data.Profile.Name = usr.Name
+	}
+
+	p := page.New(ctx)
+	p.Layout = templates.LayoutMain
+	p.Name = templates.PageAccount
+	p.Title = "Account"
+	p.Data = data
+
+	return h.RenderPage(ctx, p)
+}
+
+func (h *Account) ProfileSubmit(ctx echo.Context) error {
+	var input profileForm
+
+	err := form.Submit(ctx, &input)
+
+	switch err.(type) {
+	case nil:
+	case validator.ValidationErrors:
+		return h.Page(ctx)
+	default:
+		return err
+	}
+
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+	err = usr.
+		Update().
+		SetName(input.Name).
+		Exec(ctx.Request().Context())
+
+	if err != nil {
+		return fail(err, "unable to update profile")
+	}
+
+	msg.Success(ctx, "Your profile has been updated.")
+
+	return redirect.New(ctx).
+		Route(routeNameAccount).
+		Go()
+}
+
+func (h *Account) EmailSubmit(ctx echo.Context) error {
+	var input changeEmailForm
+
+	err := form.Submit(ctx, &input)
+
+	switch err.(type) {
+	case nil:
+	case validator.ValidationErrors:
+		return h.Page(ctx)
+	default:
+		return err
+	}
+
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+	email := strings.ToLower(input.Email)
+
+	if email == usr.Email {
+		input.SetFieldError("Email", "This is already your email address.")
+		return h.Page(ctx)
+	}
+
+	// Otherwise, registering with an allowed address and then changing it would get around the restriction
+	if !h.reg.IsEmailAllowed(email) {
+		input.SetFieldError("Email", "This email address is not allowed.")
+		return h.Page(ctx)
+	}
+
+	exists, err := h.orm.User.
+		Query().
+		Where(user.Email(email)).
+		Exist(ctx.Request().Context())
+
+	switch {
+	case err != nil:
+		return fail(err, "unable to check if email address is in use")
+	case exists:
+		input.SetFieldError("Email", "This email address is already in use.")
+		return h.Page(ctx)
+	}
+
+	token, err := h.auth.GenerateEmailChangeToken(usr.ID, usr.Email, email)
+	if err != nil {
+		return fail(err, "unable to generate email change token")
+	}
+
+	// The address is only changed once the link sent to it is visited, which proves ownership
+	url := ctx.Echo().Reverse(routeNameAccountEmailConfirm, token)
+	err = h.mail.
+		Compose().
+		To(email).
+		Subject("Confirm your new email address").
+		Body(fmt.Sprintf("Click here to confirm your new email address: %s", url)).
+		Send(ctx)
+
+	if err != nil {
+		return fail(err, "unable to send email change confirmation")
+	}
+
+	log.Ctx(ctx).Info("email change requested",
+		"user_id", usr.ID,
+	)
+
+	msg.Info(ctx, fmt.Sprintf("An email was sent to <strong>%s</strong>. Your email address will be changed once you click the link in it.", html.EscapeString(email)))
+
+	return redirect.New(ctx).
+		Route(routeNameAccount).
+		Go()
+}
+
+func (h *Account) EmailConfirm(ctx echo.Context) error {
+	invalid := func() error {
+		msg.Warning(ctx, "The link is either invalid or has expired.")
+		return redirect.New(ctx).
+			Route(routeNameHome).
+			Go()
+	}
+
+	userID, current, email, err := h.auth.ValidateEmailChangeToken(ctx.Param("token"))
+	if err != nil {
+		return invalid()
+	}
+
+	usr, err := h.orm.User.Get(ctx.Request().Context(), userID)
+	switch {
+	case err == nil:
+	case ent.IsNotFound(err):
+		return invalid()
+	default:
+		return fail(err, "unable to load user")
+	}
+
+	// The link is no longer valid once the address has changed, which also prevents it from being used again
+	if usr.Email != current {
+		return invalid()
+	}
+
+	// Receiving the link proves ownership of the new address
+	usr, err = usr.
+		Update().
+		SetEmail(email).
+		SetVerified(true).
+		ClearVerificationNonce().
+		Save(ctx.Request().Context())
+
+	switch err.(type) {
+	case nil:
+	case *ent.ConstraintError:
+		msg.Danger(ctx, "This email address is already in use.")
+		return redirect.New(ctx).
+			Route(routeNameHome).
+			Go()
+	default:
+		return fail(err, "unable to change email address")
+	}
+
+	log.Ctx(ctx).Info("email changed",
+		"user_id", usr.ID,
+	)
+
+	h.audit.Event(services.AuditActionEmailChanged).
+		Target(usr.ID).
+		With("from", current).
+		With("to", email).
+		Save(ctx)
+
+	// Let the owner of the old address know in case the change was not made by them
+	if err = h.notify.EmailChanged(ctx, usr, current); err != nil {
+		log.Ctx(ctx).Error("unable to send email changed notification",
+			"user_id", usr.ID,
+			"error", err,
+		)
+	}
+
+	msg.Success(ctx, "Your email address has been changed.")
+
+	return redirect.New(ctx).
+		Route(routeNameHome).
+		Go()
+}
+
+func (h *Account) PasswordSubmit(ctx echo.Context) error {
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+
+	input := changePasswordForm{
+		Name:  usr.Name,
+		Email: usr.Email,
+	}
+
+	err := form.Submit(ctx, &input)
+
+	switch err.(type) {
+	case nil:
+	case validator.ValidationErrors:
+		return h.Page(ctx)
+	default:
+		return err
+	}
+
+	problem, err := h.checkPassword(ctx, usr, input.CurrentPassword, "password_change")
+	if err != nil {
+		return err
+	}
+	if problem != "" {
+		input.SetFieldError("CurrentPassword", problem)
+		return h.Page(ctx)
+	}
+
+	hash, err := h.auth.HashPassword(input.Password)
+	if err != nil {
+		return fail(err, "unable to hash password")
+	}
+
+	err = usr.
+		Update().
+		SetPassword(hash).
+		Exec(ctx.Request().Context())
+
+	if err != nil {
+		return fail(err, "unable to update password")
+	}
+
+	// Any outstanding reset links should no longer work
+	if err = h.auth.DeletePasswordTokens(ctx, usr.ID); err != nil {
+		return fail(err, "unable to delete password tokens")
+	}
+
+	log.Ctx(ctx).Info("password changed",
+		"user_id", usr.ID,
+	)
+
+	h.audit.Event(services.AuditActionPasswordChanged).
+		Target(usr.ID).
+		Save(ctx)
+
+	if err = h.notify.PasswordChanged(ctx, usr); err != nil {
+		log.Ctx(ctx).Error("unable to send password changed notification",
+			"user_id", usr.ID,
+			"error", err,
+		)
+	}
+
+	msg.Success(ctx, "Your password has been changed.")
+
+	return redirect.New(ctx).
+		Route(routeNameAccount).
+		Go()
+}
+
+func (h *Account) Export(ctx echo.Context) error {
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+
+	err := h.tasks.
+		Add(tasks.DataExportTask{
+			UserID: usr.ID,
+		}).
+		Save()
+
+	if err != nil {
+		return fail(err, "unable to queue data export")
+	}
+
+	log.Ctx(ctx).Info("data export requested",
+		"user_id", usr.ID,
+	)
+
+	msg.Info(ctx, "Your data is being exported. You will receive an email with a link to download it once it is ready.")
+
+	return redirect.New(ctx).
+		Route(routeNameAccount).
+		Go()
+}
+
+func (h *Account) ExportDownload(ctx echo.Context) error {
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+
+	de, err := h.account.GetValidDataExport(ctx.Request().Context(), usr.ID, ctx.Param("token"))
+
+	switch err.(type) {
+	case nil:
+	case services.InvalidDataExportError:
+		msg.Warning(ctx, "The link is either invalid or has expired. Please request a new export.")
+		return redirect.New(ctx).
+			Route(routeNameAccount).
+			Go()
+	default:
+		return fail(err, "unable to load data export")
+	}
+
+	ctx.Response().Header().Set(
+		echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="export-%s.zip"`, de.CreatedAt.Format("2006-01-02")),
+	)
+
+	return ctx.Blob(http.StatusOK, "application/zip", de.Data)
+}
+
+func (h *Account) Delete(ctx echo.Context) error {
+	var input deleteAccountForm
+
+	err := form.Submit(ctx, &input)
+
+	switch err.(type) {
+	case nil:
+	case validator.ValidationErrors:
+		return h.Page(ctx)
+	default:
+		return err
+	}
+
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+
+	problem, err := h.checkPassword(ctx, usr, input.Password, "account_deletion")
+	if err != nil {
+		return err
+	}
+	if problem != "" {
+		input.SetFieldError("Password", problem)
+		return h.Page(ctx)
+	}
+
+	at, err := h.account.ScheduleDeletion(ctx.Request().Context(), usr.ID)
+	if err != nil {
+		return fail(err, "unable to schedule account deletion")
+	}
+
+	err = h.tasks.
+		Add(tasks.AccountDeletionTask{
+			UserID: usr.ID,
+		}).
+		At(at).
+		Save()
+
+	if err != nil {
+		return fail(err, "unable to queue account deletion")
+	}
+
+	log.Ctx(ctx).Info("account deletion scheduled",
+		"user_id", usr.ID,
+		"at", at,
+	)
+
+	h.audit.Event(services.AuditActionDeletionScheduled).
+		Target(usr.ID).
+		With("at", at).
+		Save(ctx)
+
+	err = h.mail.
+		Compose().
+		To(usr.Email).
+		Subject("Your account will be deleted").
+		Body(fmt.Sprintf(
+			"Your account will be deleted on %s. If you change your mind, log in and cancel the deletion in your account settings before then.",
+			at.Format(time.RFC1123),
+		)).
+		Send(ctx)
+
+	if err != nil {
+		log.Ctx(ctx).Error("unable to send account deletion email",
+			"user_id", usr.ID,
+			"error", err,
+		)
+	}
+
+	if err = h.auth.Logout(ctx); err != nil {
+		return fail(err, "unable to log out user")
+	}
+
+	msg.Info(ctx, fmt.Sprintf(
+		"Your account will be deleted on %s. You can cancel this by logging in before then.",
+		at.Format("January 2, 2006"),
+	))
+
+	return redirect.New(ctx).
+		Route(routeNameHome).
+		Go()
+}
+
+func (h *Account) DeleteCancel(ctx echo.Context) error {
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+
+	if err := h.account.CancelDeletion(ctx.Request().Context(), usr.ID); err != nil {
+		return fail(err, "unable to cancel account deletion")
+	}
+
+	log.Ctx(ctx).Info("account deletion cancelled",
+		"user_id", usr.ID,
+	)
+
+	h.audit.Event(services.AuditActionDeletionCancelled).
+		Target(usr.ID).
+		Save(ctx)
+
+	msg.Success(ctx, "Your account will no longer be deleted.")
+
+	return redirect.New(ctx).
+		Route(routeNameAccount).
+		Go()
+}
+
+// checkPassword checks that a given password, provided to confirm an action of a given method, is the password of a
+// given user. Guessing the password is throttled and audited just like logging in. If the password is not accepted,
+// the problem is returned so it can be shown to the user.
+func (h *Account) checkPassword(ctx echo.Context, usr *ent.User, password, method string) (string, error) {
+	retryAfter, err := h.throttle.LoginRetryAfter(ctx.Request().Context(), usr.Email, ctx.RealIP())
+	if err != nil {
+		return "", fail(err, "error checking login throttle")
+	}
+
+	if retryAfter > 0 {
+		return fmt.Sprintf(
+			"Too many failed attempts. Please try again in %s.",
+			retryAfter.Round(time.Second),
+		), nil
+	}
+
+	if err = h.auth.CheckPassword(password, usr.Password); err != nil {
+		if _, err = h.throttle.LoginFailed(ctx.Request().Context(), usr.Email, ctx.RealIP()); err != nil {
+			return "", fail(err, "error recording failed password confirmation")
+		}
+
+		h.audit.Event(services.AuditActionLoginFailed).
+			Target(usr.ID).
+			With("email", usr.Email).
+			With("reason", "invalid_password").
+			With("method", method).
+			Save(ctx)
+
+		return "The password is incorrect.", nil
+	}
+
+	if err = h.throttle.ResetLogin(ctx.Request().Context(), usr.Email); err != nil {
+		log.Ctx(ctx).Error("unable to reset failed login attempts",
+			"user_id", usr.ID,
+			"error", err,
+		)
+	}
+
+	return "", nil
+}
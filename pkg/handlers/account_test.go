@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/mikestefanello/pagoda/ent/auditevent"
+	"github.com/mikestefanello/pagoda/ent/user"
+	"github.com/mikestefanello/pagoda/pkg/services"
+	"github.com/mikestefanello/pagoda/pkg/tests"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccount__RequiresAuthentication(t *testing.T) {
+	request(t).
+		setRoute(routeNameAccount).
+		get().
+		assertStatusCode(http.StatusUnauthorized)
+}
+
+func TestAccount__Profile(t *testing.T) {
+	req, usr := login(t, false)
+
+	doc := req.setRoute(routeNameAccount).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	name, _ := doc.Find(`#account-profile input[name="name"]`).Attr("value")
+	assert.Equal(t, usr.Name, name)
+
+	_, err := req.client.PostForm(srv.URL+c.Web.Reverse(routeNameAccountProfileSubmit), url.Values{
+		"name": []string{"New name"},
+		"csrf": []string{csrfToken(doc)},
+	})
+	require.NoError(t, err)
+
+	usr, err = c.ORM.User.Get(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "New name", usr.Name)
+}
+
+func TestAccount__Password(t *testing.T) {
+	req, usr := login(t, false)
+
+	doc := req.setRoute(routeNameAccount).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+
+	submit := func(current string) *goquery.Document {
+		resp, err := req.client.PostForm(srv.URL+c.Web.Reverse(routeNameAccountPasswordSubmit), url.Values{
+			"current-password": []string{current},
+			"password":         []string{"Correct-Horse-Battery-9"},
+			"password-confirm": []string{"Correct-Horse-Battery-9"},
+			"csrf":             []string{csrfToken(doc)},
+		})
+		require.NoError(t, err)
+		return (&httpResponse{Response: resp, t: t}).toDoc()
+	}
+
+	// The current password is required
+	doc = submit("incorrect")
+	assert.Contains(t, doc.Find(".help.is-danger").Text(), "incorrect")
+	u, err := c.ORM.User.Get(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, usr.Password, u.Password)
+
+	n, err := c.ORM.AuditEvent.
+		Query().
+		Where(
+			auditevent.Action(services.AuditActionLoginFailed),
+			auditevent.HasTargetWith(user.ID(usr.ID)),
+		).
+		Count(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	// Guessing the current password is throttled like logging in, even once the correct one is provided
+	for i := 1; i < c.Config.App.LoginThrottle.FreeAttempts; i++ {
+		submit("incorrect")
+	}
+	doc = submit("password")
+	assert.Contains(t, doc.Find(".help.is-danger").Text(), "Too many failed attempts")
+	u, err = c.ORM.User.Get(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, usr.Password, u.Password)
+
+	require.NoError(t, c.Throttle.ResetLogin(context.Background(), usr.Email))
+	submit("password")
+	u, err = c.ORM.User.Get(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.NoError(t, c.Auth.CheckPassword("Correct-Horse-Battery-9", u.Password))
+}
+
+func TestAccount__Email(t *testing.T) {
+	req, usr := login(t, false)
+	other, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	doc := req.setRoute(routeNameAccount).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+
+	// Addresses in use cannot be requested
+	resp, err := req.client.PostForm(srv.URL+c.Web.Reverse(routeNameAccountEmailSubmit), url.Values{
+		"email": []string{other.Email},
+		"csrf":  []string{csrfToken(doc)},
+	})
+	require.NoError(t, err)
+	doc = (&httpResponse{Response: resp, t: t}).toDoc()
+	assert.Equal(t, 1, doc.Find(`#account-email input.is-danger`).Length())
+
+	// The address is only changed once confirmed, from any browser
+	token, err := c.Auth.GenerateEmailChangeToken(usr.ID, usr.Email, "changed-"+usr.Email)
+	require.NoError(t, err)
+	request(t).
+		setRoute(routeNameAccountEmailConfirm, token).
+		get().
+		assertStatusCode(http.StatusOK)
+
+	u, err := c.ORM.User.Get(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "changed-"+usr.Email, u.Email)
+	assert.True(t, u.Verified)
+
+	// The link cannot be used again once the address changed
+	token, err = c.Auth.GenerateEmailChangeToken(usr.ID, usr.Email, "again-"+usr.Email)
+	require.NoError(t, err)
+	request(t).
+		setRoute(routeNameAccountEmailConfirm, token).
+		get().
+		assertStatusCode(http.StatusOK)
+
+	u, err = c.ORM.User.Get(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "changed-"+usr.Email, u.Email)
+}
+
+func TestAccount__ExportDownload(t *testing.T) {
+	req, usr := login(t, false)
+
+	token, _, err := c.Account.CreateDataExport(context.Background(), usr.ID)
+	require.NoError(t, err)
+
+	resp := req.setRoute(routeNameAccountExportDownload, token).
+		get().
+		assertStatusCode(http.StatusOK)
+	assert.Equal(t, "application/zip", resp.Header.Get("Content-Type"))
+	require.NoError(t, resp.Body.Close())
+
+	// Another user cannot download it
+	req, _ = login(t, false)
+	resp = req.setRoute(routeNameAccountExportDownload, token).
+		get().
+		assertStatusCode(http.StatusOK)
+	assert.Equal(t, c.Web.Reverse(routeNameAccount), resp.Request.URL.Path)
+	require.NoError(t, resp.Body.Close())
+}
+
+func TestAccount__Delete(t *testing.T) {
+	req, usr := login(t, false)
+
+	doc := req.setRoute(routeNameAccount).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+
+	submit := func(password string) *httpResponse {
+		resp, err := req.client.PostForm(srv.URL+c.Web.Reverse(routeNameAccountDelete), url.Values{
+			"delete-password": []string{password},
+			"csrf":            []string{csrfToken(doc)},
+		})
+		require.NoError(t, err)
+		return &httpResponse{Response: resp, t: t}
+	}
+
+	// The password is required
+	doc = submit("incorrect").
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, 1, doc.Find(`#account-delete input.is-danger`).Length())
+
+	u, err := c.ORM.User.Get(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.Nil(t, u.DeletionScheduledAt)
+
+	// Guessing the password is throttled like logging in, even once the correct one is provided
+	for i := 1; i < c.Config.App.LoginThrottle.FreeAttempts; i++ {
+		require.NoError(t, submit("incorrect").Body.Close())
+	}
+	doc = submit("password").
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Contains(t, doc.Find("#account-delete .help.is-danger").Text(), "Too many failed attempts")
+
+	u, err = c.ORM.User.Get(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.Nil(t, u.DeletionScheduledAt)
+
+	n, err := c.ORM.AuditEvent.
+		Query().
+		Where(
+			auditevent.Action(services.AuditActionLoginFailed),
+			auditevent.HasTargetWith(user.ID(usr.ID)),
+		).
+		Count(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, c.Config.App.LoginThrottle.FreeAttempts, n)
+
+	require.NoError(t, c.Throttle.ResetLogin(context.Background(), usr.Email))
+
+	// Deletion is scheduled and the user is logged out
+	resp := submit("password").assertStatusCode(http.StatusOK)
+	assert.Equal(t, c.Web.Reverse(routeNameHome), resp.Request.URL.Path)
+	require.NoError(t, resp.Body.Close())
+
+	u, err = c.ORM.User.Get(context.Background(), usr.ID)
+	require.NoError(t, err)
+	require.NotNil(t, u.DeletionScheduledAt)
+
+	request(t).
+		setClient(req.client).
+		setRoute(routeNameAccount).
+		get().
+		assertStatusCode(http.StatusUnauthorized)
+}
@@ -0,0 +1,663 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/pkg/context"
+	"github.com/mikestefanello/pagoda/pkg/form"
+	"github.com/mikestefanello/pagoda/pkg/log"
+	"github.com/mikestefanello/pagoda/pkg/middleware"
+	"github.com/mikestefanello/pagoda/pkg/msg"
+	"github.com/mikestefanello/pagoda/pkg/page"
+	"github.com/mikestefanello/pagoda/pkg/redirect"
+	"github.com/mikestefanello/pagoda/pkg/services"
+	"github.com/mikestefanello/pagoda/templates"
+)
+
+const (
+	routeNameAdmin                  = "admin"
+	routeNameAdminList              = "admin.list"
+	routeNameAdminEdit              = "admin.edit"
+	routeNameAdminEditSubmit        = "admin.edit.submit"
+	routeNameAdminDelete            = "admin.delete"
+	routeNameAdminImpersonate       = "admin.impersonate"
+	routeNameAdminAudit             = "admin.audit"
+	routeNameAdminInviteCodes       = "admin.invite_codes"
+	routeNameAdminInviteCodesSubmit = "admin.invite_codes.submit"
+)
+
+const (
+	// adminFilterPrefix is the prefix of the query parameters used to filter entity lists
+	adminFilterPrefix = "filter_"
+
+	// adminSortKey is the query parameter used to sort entity lists
+	adminSortKey = "sort"
+
+	// adminOrderKey is the query parameter used to set the sort order of entity lists
+	adminOrderKey = "order"
+
+	// adminDateFormat is the format of the date query parameters used to filter the audit log
+	adminDateFormat = "2006-01-02"
+)
+
+type (
+	Admin struct {
+		admin *services.AdminClient
+		audit *services.AuditLogger
+		auth  *services.AuthClient
+		orm   *ent.Client
+		reg   *services.RegistrationClient
+		*services.TemplateRenderer
+	}
+
+	adminListData struct {
+		Entity   *services.AdminEntity
+		Entities []*services.AdminEntity
+		Rows     []services.AdminRow
+		Params   services.AdminListParams
+		path     string
+	}
+
+	adminEditData struct {
+		Entity   *services.AdminEntity
+		Entities []*services.AdminEntity
+		ID       string
+		Row      services.AdminRow
+
+		// Writable indicates if the authenticated user can change and delete the entity
+		Writable bool
+	}
+
+	adminAuditData struct {
+		Events  []*ent.AuditEvent
+		Actions []string
+		Filters adminAuditFilters
+		path    string
+	}
+
+	// adminAuditFilters contains the filters of the audit log, as provided in the query parameters
+	adminAuditFilters struct {
+		Action    string `query:"action"`
+		User      string `query:"user"`
+		IP        string `query:"ip"`
+		RequestID string `query:"request_id"`
+		Since     string `query:"since"`
+		Until     string `query:"until"`
+	}
+
+	adminInviteCodesData struct {
+		Codes []adminInviteCode
+		Mode  config.RegistrationMode
+
+		// Code is a newly generated invite code, which is only available in the request that generated it
+		Code string
+
+		// URL is the URL of the registration page with the newly generated code prefilled
+		URL string
+	}
+
+	adminInviteCode struct {
+		*ent.InviteCode
+
+		// Usable indicates that the code has not expired and has uses remaining
+		Usable bool
+	}
+
+	adminInviteCodeForm struct {
+		Note    string `form:"note" validate:"max=100"`
+		MaxUses int    `form:"max-uses" validate:"gte=0"`
+		Expires int    `form:"expires" validate:"oneof=0 1 7 30 90"`
+		form.Submission
+	}
+
+	adminEditForm struct {
+		// Values stores the submitted values keyed by field name.
+		// These are not bound automatically since the fields differ for each entity type.
+		Values map[string]string
+		form.Submission
+	}
+)
+
+func init() {
+	Register(new(Admin))
+}
+
+func (h *Admin) Init(c *services.Container) error {
+	h.TemplateRenderer = c.TemplateRenderer
+	h.admin = c.Admin
+	h.audit = c.Audit
+	h.auth = c.Auth
+	h.orm = c.ORM
+	h.reg = c.Registration
+	return nil
+}
+
+func (h *Admin) Routes(g *echo.Group) {
+	admin := g.Group("/admin",
+		middleware.RequireAuthentication(),
+		middleware.RequireNoImpersonation(),
+		middleware.RequirePermission(services.PermissionAdminAccess),
+	)
+	admin.GET("", h.Index).Name = routeNameAdmin
+	admin.GET("/audit", h.Audit).Name = routeNameAdminAudit
+	admin.GET("/invite-codes", h.InviteCodes).Name = routeNameAdminInviteCodes
+	admin.POST("/invite-codes", h.InviteCodesSubmit).Name = routeNameAdminInviteCodesSubmit
+	admin.GET("/:entity", h.List).Name = routeNameAdminList
+	admin.GET("/:entity/:id", h.Edit).Name = routeNameAdminEdit
+	admin.POST("/:entity/:id", h.EditSubmit).Name = routeNameAdminEditSubmit
+	admin.POST("/:entity/:id/delete", h.Delete).Name = routeNameAdminDelete
+	admin.POST(
+		"/impersonate/:user",
+		h.Impersonate,
+		middleware.RequirePermission(services.PermissionUsersImpersonate),
+	).Name = routeNameAdminImpersonate
+}
+
+func (h *Admin) Index(ctx echo.Context) error {
+	p := page.New(ctx)
+	p.Layout = templates.LayoutMain
+	p.Name = templates.PageAdmin
+	p.Title = "Admin"
+	p.Data = h.admin.Entities()
+
+	return h.RenderPage(ctx, p)
+}
+
+func (h *Admin) List(ctx echo.Context) error {
+	entity, err := h.entity(ctx)
+	if err != nil {
+		return err
+	}
+
+	data := adminListData{
+		Entity:   entity,
+		Entities: h.admin.Entities(),
+		path:     ctx.Request().URL.Path,
+		Params: services.AdminListParams{
+			Filters: make(map[string]string),
+			Sort:    ctx.QueryParam(adminSortKey),
+			Desc:    ctx.QueryParam(adminOrderKey) == "desc",
+		},
+	}
+
+	for _, f := range entity.Fields {
+		if v := ctx.QueryParam(adminFilterPrefix + f.Name); v != "" {
+			data.Params.Filters[f.Name] = v
+		}
+	}
+
+	p := page.New(ctx)
+	p.Layout = templates.LayoutMain
+	p.Name = templates.PageAdminList
+	p.Title = entity.Label
+
+	count, err := h.admin.Count(ctx.Request().Context(), entity, data.Params)
+	if err != nil {
+		return fail(err, "unable to count entities")
+	}
+
+	p.Pager.SetItems(count)
+	data.Params.Offset = p.Pager.GetOffset()
+	data.Params.Limit = p.Pager.ItemsPerPage
+
+	data.Rows, err = h.admin.List(ctx.Request().Context(), entity, data.Params)
+	if err != nil {
+		return fail(err, "unable to list entities")
+	}
+
+	p.Data = data
+
+	return h.RenderPage(ctx, p)
+}
+
+func (h *Admin) Edit(ctx echo.Context) error {
+	entity, err := h.entity(ctx)
+	if err != nil {
+		return err
+	}
+
+	row, err := h.admin.Get(ctx.Request().Context(), entity, ctx.Param("id"))
+	switch {
+	case err == nil:
+	case ent.IsNotFound(err):
+		return echo.NewHTTPError(http.StatusNotFound)
+	default:
+		return fail(err, "unable to load entity")
+	}
+
+	p := page.New(ctx)
+	p.Layout = templates.LayoutMain
+	p.Name = templates.PageAdminEdit
+	p.Title = fmt.Sprintf("%s #%s", entity.Name, ctx.Param("id"))
+	p.Data = adminEditData{
+		Entity:   entity,
+		Entities: h.admin.Entities(),
+		ID:       ctx.Param("id"),
+		Row:      row,
+		Writable: h.writable(ctx, entity),
+	}
+
+	f := form.Get[adminEditForm](ctx)
+	if f.Values == nil {
+		f.Values = make(map[string]string)
+		for _, field := range entity.Fields {
+			f.Values[field.Name] = field.Input(row[field.Name])
+		}
+	}
+	p.Form = f
+
+	return h.RenderPage(ctx, p)
+}
+
+func (h *Admin) EditSubmit(ctx echo.Context) error {
+	entity, err := h.writableEntity(ctx)
+	if err != nil {
+		return err
+	}
+
+	var input adminEditForm
+	if err = form.Submit(ctx, &input); err != nil {
+		return err
+	}
+
+	input.Values = make(map[string]string)
+	for _, f := range entity.Fields {
+		if !f.Editable {
+			continue
+		}
+
+		v := ctx.FormValue(f.Name)
+
+		// Unchecked checkboxes are not submitted
+		if f.IsBool() {
+			v = strconv.FormatBool(v != "")
+		}
+
+		input.Values[f.Name] = v
+	}
+
+	err = h.admin.Update(ctx.Request().Context(), entity, ctx.Param("id"), input.Values)
+
+	var fieldErrs services.AdminFieldErrors
+	switch {
+	case err == nil:
+	case errors.As(err, &fieldErrs):
+		for name, message := range fieldErrs {
+			input.SetFieldError(name, message)
+		}
+		return h.Edit(ctx)
+	case ent.IsNotFound(err):
+		return echo.NewHTTPError(http.StatusNotFound)
+	case ent.IsConstraintError(err):
+		msg.Danger(ctx, fmt.Sprintf("Unable to save: %v", err))
+		return h.Edit(ctx)
+	default:
+		return fail(err, "unable to update entity")
+	}
+
+	msg.Success(ctx, fmt.Sprintf("%s #%s was saved.", entity.Name, ctx.Param("id")))
+	form.Clear(ctx)
+
+	return redirect.New(ctx).
+		Route(routeNameAdminEdit).
+		Params(entity.Table, ctx.Param("id")).
+		Go()
+}
+
+func (h *Admin) Delete(ctx echo.Context) error {
+	entity, err := h.writableEntity(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.admin.Delete(ctx.Request().Context(), entity, ctx.Param("id"))
+	switch {
+	case err == nil:
+	case ent.IsNotFound(err):
+		return echo.NewHTTPError(http.StatusNotFound)
+	case ent.IsConstraintError(err):
+		msg.Danger(ctx, fmt.Sprintf("Unable to delete %s #%s since other entities depend on it.", entity.Name, ctx.Param("id")))
+		return redirect.New(ctx).
+			Route(routeNameAdminEdit).
+			Params(entity.Table, ctx.Param("id")).
+			Go()
+	default:
+		return fail(err, "unable to delete entity")
+	}
+
+	msg.Success(ctx, fmt.Sprintf("%s #%s was deleted.", entity.Name, ctx.Param("id")))
+
+	return redirect.New(ctx).
+		Route(routeNameAdminList).
+		Params(entity.Table).
+		Go()
+}
+
+func (h *Admin) Impersonate(ctx echo.Context) error {
+	userID, err := strconv.Atoi(ctx.Param("user"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	u, err := h.orm.User.Get(ctx.Request().Context(), userID)
+	switch {
+	case err == nil:
+	case ent.IsNotFound(err):
+		return echo.NewHTTPError(http.StatusNotFound)
+	default:
+		return fail(err, "unable to load user")
+	}
+
+	if admin := ctx.Get(context.AuthenticatedUserKey).(*ent.User); admin.ID == u.ID {
+		msg.Warning(ctx, "You cannot impersonate yourself.")
+		return redirect.New(ctx).
+			Route(routeNameAdminEdit).
+			Params("users", u.ID).
+			Go()
+	}
+
+	if err = h.auth.StartImpersonation(ctx, u.ID); err != nil {
+		return fail(err, "unable to impersonate user")
+	}
+
+	log.Ctx(ctx).Info("impersonation started",
+		"user_id", u.ID,
+	)
+
+	h.audit.Event(services.AuditActionImpersonationStarted).
+		Target(u.ID).
+		Save(ctx)
+
+	msg.Info(ctx, fmt.Sprintf("You are now impersonating <strong>%s</strong>.", html.EscapeString(u.Name)))
+
+	return redirect.New(ctx).
+		Route(routeNameHome).
+		Go()
+}
+
+func (h *Admin) Audit(ctx echo.Context) error {
+	data := adminAuditData{
+		path: ctx.Request().URL.Path,
+	}
+
+	if err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &data.Filters); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	params := services.AuditLogParams{
+		Action:    data.Filters.Action,
+		User:      data.Filters.User,
+		IP:        data.Filters.IP,
+		RequestID: data.Filters.RequestID,
+	}
+
+	// Ignore dates that cannot be parsed, rather than matching nothing
+	if t, err := time.Parse(adminDateFormat, data.Filters.Since); err == nil {
+		params.Since = &t
+	}
+	if t, err := time.Parse(adminDateFormat, data.Filters.Until); err == nil {
+		// Include the entire day
+		t = t.AddDate(0, 0, 1)
+		params.Until = &t
+	}
+
+	p := page.New(ctx)
+	p.Layout = templates.LayoutMain
+	p.Name = templates.PageAdminAudit
+	p.Title = "Audit log"
+
+	count, err := h.audit.Count(ctx.Request().Context(), params)
+	if err != nil {
+		return fail(err, "unable to count audit events")
+	}
+
+	p.Pager.SetItems(count)
+	params.Offset = p.Pager.GetOffset()
+	params.Limit = p.Pager.ItemsPerPage
+
+	data.Events, err = h.audit.List(ctx.Request().Context(), params)
+	if err != nil {
+		return fail(err, "unable to list audit events")
+	}
+
+	data.Actions, err = h.audit.Actions(ctx.Request().Context())
+	if err != nil {
+		return fail(err, "unable to load audit actions")
+	}
+
+	p.Data = data
+
+	return h.RenderPage(ctx, p)
+}
+
+func (h *Admin) InviteCodes(ctx echo.Context) error {
+	return h.renderInviteCodes(ctx, "")
+}
+
+func (h *Admin) InviteCodesSubmit(ctx echo.Context) error {
+	var input adminInviteCodeForm
+
+	err := form.Submit(ctx, &input)
+
+	switch err.(type) {
+	case nil:
+	case validator.ValidationErrors:
+		return h.InviteCodes(ctx)
+	default:
+		return err
+	}
+
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+	params := services.InviteCodeParams{
+		CreatorID: &usr.ID,
+		MaxUses:   input.MaxUses,
+		Note:      input.Note,
+	}
+
+	if input.Expires > 0 {
+		t := time.Now().AddDate(0, 0, input.Expires)
+		params.ExpiresAt = &t
+	}
+
+	code, ic, err := h.reg.CreateInviteCode(ctx.Request().Context(), params)
+	if err != nil {
+		return fail(err, "unable to create invite code")
+	}
+
+	log.Ctx(ctx).Info("invite code created",
+		"invite_code_id", ic.ID,
+	)
+
+	h.audit.Event(services.AuditActionInviteCodeCreated).
+		With("invite_code_id", ic.ID).
+		With("max_uses", ic.MaxUses).
+		Save(ctx)
+
+	msg.Success(ctx, "The invite code was created. Make sure to copy it now since you will not be able to see it again.")
+	form.Clear(ctx)
+
+	return h.renderInviteCodes(ctx, code)
+}
+
+// renderInviteCodes renders the invite code management page, including a newly generated code, if one
+func (h *Admin) renderInviteCodes(ctx echo.Context, code string) error {
+	codes, err := h.reg.GetInviteCodes(ctx.Request().Context())
+	if err != nil {
+		return fail(err, "unable to load invite codes")
+	}
+
+	data := adminInviteCodesData{
+		Codes: make([]adminInviteCode, len(codes)),
+		Mode:  h.reg.Mode(),
+		Code:  code,
+	}
+
+	for i, ic := range codes {
+		data.Codes[i] = adminInviteCode{
+			InviteCode: ic,
+			Usable:     services.IsInviteCodeUsable(ic),
+		}
+	}
+
+	if code != "" {
+		data.URL = fmt.Sprintf("%s?code=%s", ctx.Echo().Reverse(routeNameRegister), url.QueryEscape(code))
+	}
+
+	p := page.New(ctx)
+	p.Layout = templates.LayoutMain
+	p.Name = templates.PageAdminInviteCodes
+	p.Title = "Invite codes"
+	p.Form = form.Get[adminInviteCodeForm](ctx)
+	p.Data = data
+
+	return h.RenderPage(ctx, p)
+}
+
+// ExpiresIn returns the expiration options of the form, in days, with zero meaning never
+func (f *adminInviteCodeForm) ExpiresIn() []int {
+	return []int{1, 7, 30, 90, 0}
+}
+
+// ExpiresLabel returns the label of a given expiration option
+func (f *adminInviteCodeForm) ExpiresLabel(days int) string {
+	switch days {
+	case 0:
+		return "Never"
+	case 1:
+		return "1 day"
+	default:
+		return fmt.Sprintf("%d days", days)
+	}
+}
+
+// entity returns the entity type requested in the path parameters
+func (h *Admin) entity(ctx echo.Context) (*services.AdminEntity, error) {
+	e, ok := h.admin.Entity(ctx.Param("entity"))
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusNotFound)
+	}
+	return e, nil
+}
+
+// writableEntity returns the entity type in the path parameter, if it exists and can be changed by the
+// authenticated user
+func (h *Admin) writableEntity(ctx echo.Context) (*services.AdminEntity, error) {
+	e, err := h.entity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !h.writable(ctx, e) {
+		return nil, echo.NewHTTPError(http.StatusForbidden)
+	}
+	return e, nil
+}
+
+// writable determines if the authenticated user can change and delete entities of a given type.
+// Changing users also requires the users.edit permission, on top of access to the admin panel.
+func (h *Admin) writable(ctx echo.Context, e *services.AdminEntity) bool {
+	switch {
+	case e.ReadOnly:
+		return false
+	case e.Name == "User":
+		perms, _ := ctx.Get(context.PermissionsKey).(map[string]bool)
+		return perms[services.PermissionUsersEdit]
+	default:
+		return true
+	}
+}
+
+// FilterName returns the query parameter used to filter a given field
+func (d adminListData) FilterName(field string) string {
+	return adminFilterPrefix + field
+}
+
+// SortURL returns the URL to sort the list by a given field, toggling the order if it is already sorted by it
+func (d adminListData) SortURL(field string) string {
+	q := d.query()
+	q.Set(adminSortKey, field)
+	q.Del(page.PageQueryKey)
+	if d.Params.Sort == field && !d.Params.Desc {
+		q.Set(adminOrderKey, "desc")
+	} else {
+		q.Del(adminOrderKey)
+	}
+	return d.path + "?" + q.Encode()
+}
+
+// PageURL returns the URL of a given page of the list
+func (d adminListData) PageURL(pg int) string {
+	q := d.query()
+	q.Set(page.PageQueryKey, strconv.Itoa(pg))
+	return d.path + "?" + q.Encode()
+}
+
+// query returns the query parameters of the current list
+func (d adminListData) query() url.Values {
+	q := url.Values{}
+	for name, v := range d.Params.Filters {
+		q.Set(adminFilterPrefix+name, v)
+	}
+	if d.Params.Sort != "" {
+		q.Set(adminSortKey, d.Params.Sort)
+	}
+	if d.Params.Desc {
+		q.Set(adminOrderKey, "desc")
+	}
+	return q
+}
+
+// FilterURL returns the URL of the audit log filtered by a given query parameter and value, in addition to the
+// current filters
+func (d adminAuditData) FilterURL(name, value string) string {
+	q := d.query()
+	q.Set(name, value)
+	return d.path + "?" + q.Encode()
+}
+
+// PageURL returns the URL of a given page of the audit log
+func (d adminAuditData) PageURL(pg int) string {
+	q := d.query()
+	q.Set(page.PageQueryKey, strconv.Itoa(pg))
+	return d.path + "?" + q.Encode()
+}
+
+// Payload returns the payload of a given event as JSON
+func (d adminAuditData) Payload(e *ent.AuditEvent) string {
+	if len(e.Payload) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(e.Payload)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// query returns the query parameters of the current filters
+func (d adminAuditData) query() url.Values {
+	q := url.Values{}
+	for name, v := range map[string]string{
+		"action":     d.Filters.Action,
+		"user":       d.Filters.User,
+		"ip":         d.Filters.IP,
+		"request_id": d.Filters.RequestID,
+		"since":      d.Filters.Since,
+		"until":      d.Filters.Until,
+	} {
+		if v != "" {
+			q.Set(name, v)
+		}
+	}
+	return q
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/auditevent"
+	"github.com/mikestefanello/pagoda/ent/invitecode"
+	"github.com/mikestefanello/pagoda/ent/permission"
+	"github.com/mikestefanello/pagoda/ent/user"
+	"github.com/mikestefanello/pagoda/pkg/services"
+	"github.com/mikestefanello/pagoda/pkg/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmin__RequiresPermission(t *testing.T) {
+	request(t).
+		setRoute(routeNameAdmin).
+		get().
+		assertStatusCode(http.StatusUnauthorized)
+
+	// Logged in without the permission
+	req, _ := login(t, false)
+	req.setRoute(routeNameAdmin).
+		get().
+		assertStatusCode(http.StatusForbidden)
+}
+
+func TestAdmin__List(t *testing.T) {
+	req, usr := login(t, true)
+
+	doc := req.
+		setRoute(routeNameAdminList, "users").
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+
+	// Sensitive fields are never shown
+	assert.Contains(t, doc.Find("thead").Text(), "Email")
+	assert.NotContains(t, doc.Find("thead").Text(), "Password")
+
+	// Filter
+	req.route += "?filter_email=" + url.QueryEscape(usr.Email)
+	doc = req.get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	rows := doc.Find("tbody tr")
+	require.Equal(t, 1, rows.Length())
+	assert.Contains(t, rows.Text(), usr.Email)
+
+	req.setRoute(routeNameAdminList, "invalid").
+		get().
+		assertStatusCode(http.StatusNotFound)
+}
+
+func TestAdmin__Edit(t *testing.T) {
+	req, usr := login(t, true)
+
+	doc := req.
+		setRoute(routeNameAdminEdit, "users", usr.ID).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+
+	name, _ := doc.Find(`input[name="name"]`).Attr("value")
+	assert.Equal(t, usr.Name, name)
+	assert.Zero(t, doc.Find(`input[name="password"]`).Length())
+
+	req.setBody(url.Values{
+		"name":            []string{"Edited"},
+		"email":           []string{usr.Email},
+		"session_version": []string{"0"},
+	}).post()
+
+	u, err := c.ORM.User.Get(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Edited", u.Name)
+	assert.False(t, u.Verified)
+}
+
+func TestAdmin__ReadOnly(t *testing.T) {
+	req, usr := login(t, true)
+
+	// Logging in was recorded
+	event, err := c.ORM.AuditEvent.
+		Query().
+		Where(auditevent.HasTargetWith(user.ID(usr.ID))).
+		First(context.Background())
+	require.NoError(t, err)
+
+	req.setRoute(routeNameAdminEdit, "audit_events", event.ID)
+	doc := req.get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Zero(t, doc.Find(`form[action="`+c.Web.Reverse(routeNameAdminDelete, "audit_events", event.ID)+`"]`).Length())
+	assert.Zero(t, doc.Find(`#admin-edit button`).Length())
+
+	for _, route := range []string{routeNameAdminDelete, routeNameAdminEditSubmit} {
+		resp, err := req.client.PostForm(
+			srv.URL+c.Web.Reverse(route, "audit_events", event.ID),
+			url.Values{"csrf": []string{csrfToken(doc)}},
+		)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, route)
+	}
+
+	_, err = c.ORM.AuditEvent.Get(context.Background(), event.ID)
+	assert.NoError(t, err)
+}
+
+func TestAdmin__UsersEditPermission(t *testing.T) {
+	ctx := context.Background()
+	req, usr := login(t, false)
+
+	// Grant access to the admin panel without the permission to edit users
+	require.NoError(t, c.Authz.Seed(ctx))
+	perm, err := c.ORM.Permission.
+		Query().
+		Where(permission.Name(services.PermissionAdminAccess)).
+		Only(ctx)
+	require.NoError(t, err)
+	r, err := c.ORM.Role.
+		Create().
+		SetName("support").
+		AddPermissions(perm).
+		Save(ctx)
+	require.NoError(t, err)
+	require.NoError(t, c.Authz.AssignRole(ctx, usr.ID, "support"))
+
+	other, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	req.setRoute(routeNameAdminEdit, "users", other.ID)
+	doc := req.get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Zero(t, doc.Find(`form[action="`+c.Web.Reverse(routeNameAdminDelete, "users", other.ID)+`"]`).Length())
+	assert.Zero(t, doc.Find(`#admin-edit button`).Length())
+
+	for _, route := range []string{routeNameAdminDelete, routeNameAdminEditSubmit} {
+		resp, err := req.client.PostForm(
+			srv.URL+c.Web.Reverse(route, "users", other.ID),
+			url.Values{
+				"csrf":            []string{csrfToken(doc)},
+				"name":            []string{"Edited"},
+				"email":           []string{other.Email},
+				"session_version": []string{"0"},
+			},
+		)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, route)
+	}
+
+	u, err := c.ORM.User.Get(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, other.Name, u.Name)
+
+	// Other entity types can still be changed
+	doc = req.setRoute(routeNameAdminEdit, "roles", r.ID).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, 1, doc.Find(`form[action="`+c.Web.Reverse(routeNameAdminDelete, "roles", r.ID)+`"]`).Length())
+	assert.Equal(t, 1, doc.Find(`#admin-edit button`).Length())
+}
+
+// login creates a new, verified user, optionally with the admin role, and logs them in
+func login(t *testing.T, admin bool) (*httpRequest, *ent.User) {
+	ctx := context.Background()
+	usr, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	hash, err := c.Auth.HashPassword("password")
+	require.NoError(t, err)
+	usr, err = usr.Update().SetPassword(hash).SetVerified(true).Save(ctx)
+	require.NoError(t, err)
+
+	if admin {
+		require.NoError(t, c.Authz.Seed(ctx))
+		require.NoError(t, c.Authz.AssignRole(ctx, usr.ID, services.RoleAdmin))
+	}
+
+	req := request(t)
+	req.setRoute(routeNameLoginSubmit).
+		setBody(url.Values{
+			"email":    []string{usr.Email},
+			"password": []string{"password"},
+		}).
+		post().
+		assertStatusCode(http.StatusOK)
+
+	return request(t).setClient(req.client), usr
+}
+
+func TestAdmin__Impersonate(t *testing.T) {
+	req, admin := login(t, true)
+	usr, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	// Start impersonating, which redirects to the home page
+	req.setRoute(routeNameAdminEdit, "users", usr.ID)
+	doc := req.get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, 1, doc.Find(`form[action="`+c.Web.Reverse(routeNameAdminImpersonate, usr.ID)+`"]`).Length())
+
+	req.route = srv.URL + c.Web.Reverse(routeNameAdminImpersonate, usr.ID)
+	resp, err := req.client.PostForm(req.route, url.Values{"csrf": []string{csrfToken(doc)}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	doc = (&httpResponse{t: t, Response: resp}).toDoc()
+
+	banner := doc.Find(".notification.is-warning")
+	require.Equal(t, 1, banner.Length())
+	assert.Contains(t, banner.Text(), usr.Name)
+	assert.Contains(t, banner.Text(), admin.Name)
+
+	// The admin panel is not available while impersonating
+	req.setRoute(routeNameAdmin).
+		get().
+		assertStatusCode(http.StatusForbidden)
+
+	// Stop impersonating
+	req.route = srv.URL + c.Web.Reverse(routeNameStopImpersonation)
+	resp, err = req.client.PostForm(req.route, url.Values{"csrf": []string{csrfToken(doc)}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	doc = (&httpResponse{t: t, Response: resp}).toDoc()
+	assert.Zero(t, doc.Find(".notification.is-warning").Length())
+
+	n, err := c.ORM.Impersonation.Query().Count(context.Background())
+	require.NoError(t, err)
+	assert.NotZero(t, n)
+}
+
+func TestAdmin__Audit(t *testing.T) {
+	request(t).
+		setRoute(routeNameAdminAudit).
+		get().
+		assertStatusCode(http.StatusUnauthorized)
+
+	req, usr := login(t, true)
+
+	// Logging in was recorded
+	req.setRoute(routeNameAdminAudit)
+	req.route += "?user=" + url.QueryEscape(usr.Email)
+	doc := req.get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	rows := doc.Find("tbody tr")
+	require.Equal(t, 1, rows.Length())
+	assert.Contains(t, rows.Text(), services.AuditActionLogin)
+	assert.Contains(t, rows.Text(), usr.Email)
+
+	req.route += "&action=" + services.AuditActionLogout
+	doc = req.get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Contains(t, doc.Find("tbody tr").Text(), "No results")
+}
+
+func TestAdmin__InviteCodes(t *testing.T) {
+	req, usr := login(t, true)
+
+	doc := req.
+		setRoute(routeNameAdminInviteCodes).
+		setBody(url.Values{
+			"note":     []string{"Admin test"},
+			"max-uses": []string{"3"},
+			"expires":  []string{"7"},
+		}).
+		post().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+
+	// The code is shown once
+	code, _ := doc.Find(".message-body input").First().Attr("value")
+	require.NotEmpty(t, code)
+
+	ic, err := c.ORM.InviteCode.
+		Query().
+		Where(invitecode.Note("Admin test")).
+		WithCreator().
+		Only(context.Background())
+	require.NoError(t, err)
+	assert.NotEqual(t, code, ic.Hash)
+	assert.Equal(t, 3, ic.MaxUses)
+	assert.NotNil(t, ic.ExpiresAt)
+	assert.Equal(t, usr.ID, ic.Edges.Creator.ID)
+
+	doc = req.get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Zero(t, doc.Find(".message-body input").Length())
+	assert.Contains(t, doc.Find("tbody").Text(), "Admin test")
+}
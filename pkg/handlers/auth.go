@@ -2,12 +2,17 @@ package handlers
 
 import (
 	"fmt"
+	"html"
+	"net/url"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/mikestefanello/pagoda/ent"
 	"github.com/mikestefanello/pagoda/ent/user"
+	"github.com/mikestefanello/pagoda/pkg/bot"
 	"github.com/mikestefanello/pagoda/pkg/context"
 	"github.com/mikestefanello/pagoda/pkg/form"
 	"github.com/mikestefanello/pagoda/pkg/log"
@@ -24,25 +29,38 @@ const (
 	routeNameForgotPasswordSubmit = "forgot_password.submit"
 	routeNameLogin                = "login"
 	routeNameLoginSubmit          = "login.submit"
+	routeNameLoginLink            = "login_link"
+	routeNameLoginLinkSubmit      = "login_link.submit"
+	routeNameLoginLinkVerify      = "login_link.verify"
 	routeNameLogout               = "logout"
+	routeNameReauthenticate       = "reauthenticate"
+	routeNameReauthenticateSubmit = "reauthenticate.submit"
+	routeNameStopImpersonation    = "impersonate.stop"
 	routeNameRegister             = "register"
 	routeNameRegisterSubmit       = "register.submit"
 	routeNameResetPassword        = "reset_password"
 	routeNameResetPasswordSubmit  = "reset_password.submit"
 	routeNameVerifyEmail          = "verify_email"
+	routeNameVerifyEmailNotice    = "verify_email.notice"
+	routeNameVerifyEmailResend    = "verify_email.resend"
 )
 
 type (
 	Auth struct {
-		auth *services.AuthClient
-		mail *services.MailClient
-		orm  *ent.Client
+		audit    *services.AuditLogger
+		auth     *services.AuthClient
+		mail     *services.MailClient
+		notify   *services.NotificationClient
+		orm      *ent.Client
+		reg      *services.RegistrationClient
+		throttle *services.ThrottleClient
 		*services.TemplateRenderer
 	}
 
 	forgotPasswordForm struct {
 		Email string `form:"email" validate:"required,email"`
 		form.Submission
+		form.BotProtection
 	}
 
 	loginForm struct {
@@ -51,17 +69,39 @@ type (
 		form.Submission
 	}
 
+	reauthenticateForm struct {
+		Password string `form:"password" validate:"required"`
+		Next     string `form:"next"`
+		form.Submission
+	}
+
+	loginLinkForm struct {
+		Email string `form:"email" validate:"required,email"`
+		form.Submission
+	}
+
 	registerForm struct {
 		Name            string `form:"name" validate:"required"`
 		Email           string `form:"email" validate:"required,email"`
-		Password        string `form:"password" validate:"required"`
+		Password        string `form:"password" validate:"required,password"`
 		ConfirmPassword string `form:"password-confirm" validate:"required,eqfield=Password"`
+		InviteCode      string `form:"invite-code"`
 		form.Submission
+		form.BotProtection
+	}
+
+	registerData struct {
+		// RequiresInviteCode indicates that registration is invite-only
+		RequiresInviteCode bool
 	}
 
 	resetPasswordForm struct {
-		Password        string `form:"password" validate:"required"`
+		Password        string `form:"password" validate:"required,password"`
 		ConfirmPassword string `form:"password-confirm" validate:"required,eqfield=Password"`
+		// Name and Email are not submitted but are populated from the user so the password policy can check
+		// that the password does not contain them
+		Name  string `form:"-"`
+		Email string `form:"-"`
 		form.Submission
 	}
 )
@@ -74,19 +114,37 @@ func (h *Auth) Init(c *services.Container) error {
 	h.TemplateRenderer = c.TemplateRenderer
 	h.orm = c.ORM
 	h.auth = c.Auth
+	h.audit = c.Audit
 	h.mail = c.Mail
+	h.notify = c.Notification
+	h.reg = c.Registration
+	h.throttle = c.Throttle
 	return nil
 }
 
 func (h *Auth) Routes(g *echo.Group) {
 	g.GET("/logout", h.Logout, middleware.RequireAuthentication()).Name = routeNameLogout
+	g.POST("/impersonate/stop", h.StopImpersonation, middleware.RequireAuthentication()).Name = routeNameStopImpersonation
+	g.GET("/email/verify", h.VerifyEmailNotice, middleware.RequireAuthentication()).Name = routeNameVerifyEmailNotice
+	g.POST("/email/verify", h.VerifyEmailResend, middleware.RequireAuthentication()).Name = routeNameVerifyEmailResend
 	g.GET("/email/verify/:token", h.VerifyEmail).Name = routeNameVerifyEmail
 
+	reauth := g.Group("/user/reauthenticate",
+		middleware.RequireAuthentication(),
+		middleware.RequireNoImpersonation(),
+		middleware.RequireNoAccessToken(),
+	)
+	reauth.GET("", h.ReauthenticatePage).Name = routeNameReauthenticate
+	reauth.POST("", h.ReauthenticateSubmit).Name = routeNameReauthenticateSubmit
+
 	noAuth := g.Group("/user", middleware.RequireNoAuthentication())
 	noAuth.GET("/login", h.LoginPage).Name = routeNameLogin
 	noAuth.POST("/login", h.LoginSubmit).Name = routeNameLoginSubmit
-	noAuth.GET("/register", h.RegisterPage).Name = routeNameRegister
-	noAuth.POST("/register", h.RegisterSubmit).Name = routeNameRegisterSubmit
+	noAuth.GET("/login/link", h.LoginLinkPage).Name = routeNameLoginLink
+	noAuth.POST("/login/link", h.LoginLinkSubmit).Name = routeNameLoginLinkSubmit
+	noAuth.GET("/login/link/:token", h.LoginLinkVerify).Name = routeNameLoginLinkVerify
+	noAuth.GET("/register", h.RegisterPage, middleware.RequireRegistrationEnabled(h.reg)).Name = routeNameRegister
+	noAuth.POST("/register", h.RegisterSubmit, middleware.RequireRegistrationEnabled(h.reg)).Name = routeNameRegisterSubmit
 	noAuth.GET("/password", h.ForgotPasswordPage).Name = routeNameForgotPassword
 	noAuth.POST("/password", h.ForgotPasswordSubmit).Name = routeNameForgotPasswordSubmit
 
@@ -127,6 +185,25 @@ func (h *Auth) ForgotPasswordSubmit(ctx echo.Context) error {
 		return err
 	}
 
+	// Limit the amount of requests per email address and IP address
+	emailAllowed, ipAllowed, err := h.throttle.ForgotPasswordAllowed(
+		ctx.Request().Context(),
+		input.Email,
+		ctx.RealIP(),
+	)
+
+	switch {
+	case err != nil:
+		return fail(err, "error throttling forgot password request")
+	case !ipAllowed:
+		msg.Danger(ctx, "Too many requests. Please try again later.")
+		return h.ForgotPasswordPage(ctx)
+	case !emailAllowed:
+		// Do not reveal that the limit was reached for this address
+		log.Ctx(ctx).Warn("forgot password email limit reached")
+		return succeed()
+	}
+
 	// Attempt to load the user
 	u, err := h.orm.User.
 		Query().
@@ -151,6 +228,10 @@ func (h *Auth) ForgotPasswordSubmit(ctx echo.Context) error {
 		"user_id", u.ID,
 	)
 
+	h.audit.Event(services.AuditActionPasswordResetRequested).
+		Target(u.ID).
+		Save(ctx)
+
 	// Email the user
 	url := ctx.Echo().Reverse(routeNameResetPassword, u.ID, pt.ID, token)
 	err = h.mail.
@@ -187,6 +268,11 @@ func (h *Auth) LoginSubmit(ctx echo.Context) error {
 		return h.LoginPage(ctx)
 	}
 
+	accountLocked := func() error {
+		msg.Danger(ctx, "This account has been temporarily locked due to too many failed login attempts. Please try again later or reset your password.")
+		return h.LoginPage(ctx)
+	}
+
 	err := form.Submit(ctx, &input)
 
 	switch err.(type) {
@@ -197,6 +283,51 @@ func (h *Auth) LoginSubmit(ctx echo.Context) error {
 		return err
 	}
 
+	// Check if too many failed attempts were recently made
+	retryAfter, err := h.throttle.LoginRetryAfter(ctx.Request().Context(), input.Email, ctx.RealIP())
+	if err != nil {
+		return fail(err, "error checking login throttle")
+	}
+
+	if retryAfter > 0 {
+		h.audit.Event(services.AuditActionLoginFailed).
+			With("email", input.Email).
+			With("reason", "throttled").
+			Save(ctx)
+
+		msg.Danger(ctx, fmt.Sprintf(
+			"Too many failed login attempts. Please try again in %s.",
+			retryAfter.Round(time.Second),
+		))
+		return h.LoginPage(ctx)
+	}
+
+	// recordFailure records the failed attempt and locks the account if too many attempts have failed
+	recordFailure := func(u *ent.User) error {
+		attempts, err := h.throttle.LoginFailed(ctx.Request().Context(), input.Email, ctx.RealIP())
+		if err != nil {
+			return fail(err, "error recording failed login")
+		}
+
+		event := h.audit.Event(services.AuditActionLoginFailed).
+			With("email", input.Email)
+		if u == nil {
+			event.With("reason", "unknown_user").Save(ctx)
+		} else {
+			event.Target(u.ID).With("reason", "invalid_password").Save(ctx)
+		}
+
+		if u == nil || !h.throttle.ShouldLockout(attempts) {
+			return authFailed()
+		}
+
+		if err = h.lock(ctx, u, attempts); err != nil {
+			return err
+		}
+
+		return accountLocked()
+	}
+
 	// Attempt to load the user
 	u, err := h.orm.User.
 		Query().
@@ -205,16 +336,39 @@ func (h *Auth) LoginSubmit(ctx echo.Context) error {
 
 	switch err.(type) {
 	case *ent.NotFoundError:
-		return authFailed()
+		return recordFailure(nil)
 	case nil:
 	default:
 		return fail(err, "error querying user during login")
 	}
 
+	// Locked accounts cannot log in, even with the correct password
+	if h.auth.IsLocked(u) {
+		h.audit.Event(services.AuditActionLoginFailed).
+			Target(u.ID).
+			With("email", input.Email).
+			With("reason", "locked").
+			Save(ctx)
+
+		return accountLocked()
+	}
+
 	// Check if the password is correct
 	err = h.auth.CheckPassword(input.Password, u.Password)
 	if err != nil {
-		return authFailed()
+		return recordFailure(u)
+	}
+
+	// Upgrade the password hash if it was created with an outdated algorithm or parameters
+	if h.auth.PasswordNeedsRehash(u.Password) {
+		h.rehashPassword(ctx, u, input.Password)
+	}
+
+	if err = h.throttle.ResetLogin(ctx.Request().Context(), input.Email); err != nil {
+		log.Ctx(ctx).Error("unable to reset failed login attempts",
+			"user_id", u.ID,
+			"error", err,
+		)
 	}
 
 	// Log the user in
@@ -223,15 +377,427 @@ func (h *Auth) LoginSubmit(ctx echo.Context) error {
 		return fail(err, "unable to log in user")
 	}
 
-	msg.Success(ctx, fmt.Sprintf("Welcome back, <strong>%s</strong>. You are now logged in.", u.Name))
+	h.audit.Event(services.AuditActionLogin).
+		Actor(u.ID).
+		Target(u.ID).
+		With("method", "password").
+		Save(ctx)
+
+	h.recordDevice(ctx, u)
+
+	msg.Success(ctx, fmt.Sprintf("Welcome back, <strong>%s</strong>. You are now logged in.", html.EscapeString(u.Name)))
+	h.warnScheduledDeletion(ctx, u)
 
 	return redirect.New(ctx).
 		Route(routeNameHome).
 		Go()
 }
 
+func (h *Auth) ReauthenticatePage(ctx echo.Context) error {
+	f := form.Get[reauthenticateForm](ctx)
+	if !f.IsSubmitted() {
+		f.Next = ctx.QueryParam("next")
+	}
+
+	p := page.New(ctx)
+	p.Layout = templates.LayoutAuth
+	p.Name = templates.PageReauthenticate
+	p.Title = "Confirm your password"
+	p.Form = f
+
+	return h.RenderPage(ctx, p)
+}
+
+func (h *Auth) ReauthenticateSubmit(ctx echo.Context) error {
+	var input reauthenticateForm
+
+	err := form.Submit(ctx, &input)
+
+	switch err.(type) {
+	case nil:
+	case validator.ValidationErrors:
+		return h.ReauthenticatePage(ctx)
+	default:
+		return err
+	}
+
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+
+	// Guessing the password is throttled just like logging in
+	retryAfter, err := h.throttle.LoginRetryAfter(ctx.Request().Context(), usr.Email, ctx.RealIP())
+	if err != nil {
+		return fail(err, "error checking login throttle")
+	}
+
+	if retryAfter > 0 {
+		msg.Danger(ctx, fmt.Sprintf(
+			"Too many failed attempts. Please try again in %s.",
+			retryAfter.Round(time.Second),
+		))
+		return h.ReauthenticatePage(ctx)
+	}
+
+	// Locked accounts cannot reauthenticate, even with the correct password
+	if h.auth.IsLocked(usr) {
+		h.audit.Event(services.AuditActionLoginFailed).
+			Target(usr.ID).
+			With("email", usr.Email).
+			With("reason", "locked").
+			With("method", "reauthentication").
+			Save(ctx)
+
+		msg.Danger(ctx, "This account has been temporarily locked due to too many failed login attempts. Please try again later or reset your password.")
+		return h.ReauthenticatePage(ctx)
+	}
+
+	if err = h.auth.CheckPassword(input.Password, usr.Password); err != nil {
+		attempts, err := h.throttle.LoginFailed(ctx.Request().Context(), usr.Email, ctx.RealIP())
+		if err != nil {
+			return fail(err, "error recording failed reauthentication")
+		}
+
+		h.audit.Event(services.AuditActionLoginFailed).
+			Target(usr.ID).
+			With("email", usr.Email).
+			With("reason", "invalid_password").
+			With("method", "reauthentication").
+			Save(ctx)
+
+		if !h.throttle.ShouldLockout(attempts) {
+			input.SetFieldError("Password", "The password is incorrect.")
+			return h.ReauthenticatePage(ctx)
+		}
+
+		if err = h.lock(ctx, usr, attempts); err != nil {
+			return err
+		}
+
+		msg.Danger(ctx, "This account has been temporarily locked due to too many failed login attempts. Please try again later or reset your password.")
+		return h.ReauthenticatePage(ctx)
+	}
+
+	if err = h.throttle.ResetLogin(ctx.Request().Context(), usr.Email); err != nil {
+		log.Ctx(ctx).Error("unable to reset failed login attempts",
+			"user_id", usr.ID,
+			"error", err,
+		)
+	}
+
+	if err = h.auth.Reauthenticate(ctx); err != nil {
+		return fail(err, "unable to reauthenticate user")
+	}
+
+	h.audit.Event(services.AuditActionReauthenticated).
+		Target(usr.ID).
+		Save(ctx)
+
+	return redirect.New(ctx).
+		URL(h.localPath(ctx, input.Next)).
+		Go()
+}
+
+// localPath returns a given path if it is within this application, so it is safe to redirect to, otherwise the
+// path of the home page
+func (h *Auth) localPath(ctx echo.Context, path string) string {
+	home := ctx.Echo().Reverse(routeNameHome)
+
+	// Browsers strip control characters from and treat backslashes as slashes in URLs, so either can turn what looks
+	// like a path into a URL of another host, ie: /\t/example.org
+	if strings.ContainsFunc(path, func(r rune) bool {
+		return unicode.IsControl(r) || r == '\\'
+	}) {
+		return home
+	}
+
+	u, err := url.Parse(path)
+	if err != nil || u.Scheme != "" || u.Host != "" || !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") {
+		return home
+	}
+
+	return path
+}
+
+func (h *Auth) LoginLinkPage(ctx echo.Context) error {
+	p := page.New(ctx)
+	p.Layout = templates.LayoutAuth
+	p.Name = templates.PageLoginLink
+	p.Title = "Email me a login link"
+	p.Form = form.Get[loginLinkForm](ctx)
+
+	return h.RenderPage(ctx, p)
+}
+
+func (h *Auth) LoginLinkSubmit(ctx echo.Context) error {
+	var input loginLinkForm
+
+	succeed := func() error {
+		form.Clear(ctx)
+		msg.Success(ctx, "An email containing a link to log in will be sent to this address if it exists in our system. The link can only be used in this browser.")
+		return h.LoginLinkPage(ctx)
+	}
+
+	err := form.Submit(ctx, &input)
+
+	switch err.(type) {
+	case nil:
+	case validator.ValidationErrors:
+		return h.LoginLinkPage(ctx)
+	default:
+		return err
+	}
+
+	// Limit the amount of requests per email address and IP address
+	emailAllowed, ipAllowed, err := h.throttle.LoginLinkAllowed(
+		ctx.Request().Context(),
+		input.Email,
+		ctx.RealIP(),
+	)
+
+	switch {
+	case err != nil:
+		return fail(err, "error throttling login link request")
+	case !ipAllowed:
+		msg.Danger(ctx, "Too many requests. Please try again later.")
+		return h.LoginLinkPage(ctx)
+	case !emailAllowed:
+		// Do not reveal that the limit was reached for this address
+		log.Ctx(ctx).Warn("login link email limit reached")
+		return succeed()
+	}
+
+	// Attempt to load the user
+	u, err := h.orm.User.
+		Query().
+		Where(user.Email(strings.ToLower(input.Email))).
+		Only(ctx.Request().Context())
+
+	switch err.(type) {
+	case *ent.NotFoundError:
+		return succeed()
+	case nil:
+	default:
+		return fail(err, "error querying user during login link request")
+	}
+
+	// Generate the token
+	token, _, err := h.auth.GenerateLoginToken(ctx, u.ID)
+	if err != nil {
+		return fail(err, "error generating login token")
+	}
+
+	log.Ctx(ctx).Info("generated login token",
+		"user_id", u.ID,
+	)
+
+	h.audit.Event(services.AuditActionLoginLinkRequested).
+		Target(u.ID).
+		Save(ctx)
+
+	// Email the user
+	url := ctx.Echo().Reverse(routeNameLoginLinkVerify, token)
+	err = h.mail.
+		Compose().
+		To(u.Email).
+		Subject("Your login link").
+		Body(fmt.Sprintf(
+			"Go here to log in: %s\n\nThe link expires in %s and only works in the browser it was requested from.",
+			url,
+			h.auth.LoginTokenExpiration(),
+		)).
+		Send(ctx)
+
+	if err != nil {
+		return fail(err, "error sending login link email")
+	}
+
+	return succeed()
+}
+
+func (h *Auth) LoginLinkVerify(ctx echo.Context) error {
+	lt, err := h.auth.GetValidLoginToken(ctx, ctx.Param("token"))
+
+	switch err.(type) {
+	case nil:
+	case services.InvalidLoginTokenError:
+		msg.Warning(ctx, "The link is either invalid, has expired, or was requested from a different browser. Please request a new one.")
+		return redirect.New(ctx).
+			Route(routeNameLoginLink).
+			Go()
+	default:
+		return fail(err, "error loading login token")
+	}
+
+	u := lt.Edges.User
+
+	// Locked accounts cannot log in by any means
+	if h.auth.IsLocked(u) {
+		msg.Danger(ctx, "This account has been temporarily locked due to too many failed login attempts. Please try again later or reset your password.")
+		return redirect.New(ctx).
+			Route(routeNameLogin).
+			Go()
+	}
+
+	// Each link can only be used once
+	if err = h.auth.DeleteLoginTokens(ctx, u.ID); err != nil {
+		return fail(err, "unable to delete login tokens")
+	}
+
+	// Receiving the link proves ownership of the email address
+	if !u.Verified {
+		u, err = u.
+			Update().
+			SetVerified(true).
+			ClearVerificationNonce().
+			Save(ctx.Request().Context())
+
+		if err != nil {
+			return fail(err, "failed to set user as verified")
+		}
+	}
+
+	if err = h.throttle.ResetLogin(ctx.Request().Context(), u.Email); err != nil {
+		log.Ctx(ctx).Error("unable to reset failed login attempts",
+			"user_id", u.ID,
+			"error", err,
+		)
+	}
+
+	// Log the user in
+	err = h.auth.Login(ctx, u.ID)
+	if err != nil {
+		return fail(err, "unable to log in user")
+	}
+
+	log.Ctx(ctx).Info("user logged in via login link",
+		"user_id", u.ID,
+	)
+
+	h.audit.Event(services.AuditActionLogin).
+		Actor(u.ID).
+		Target(u.ID).
+		With("method", "login_link").
+		Save(ctx)
+
+	h.recordDevice(ctx, u)
+
+	msg.Success(ctx, fmt.Sprintf("Welcome back, <strong>%s</strong>. You are now logged in.", html.EscapeString(u.Name)))
+	h.warnScheduledDeletion(ctx, u)
+
+	return redirect.New(ctx).
+		Route(routeNameHome).
+		Go()
+}
+
+// warnScheduledDeletion lets a user who just logged in know if their account is scheduled to be deleted, since
+// they may have logged in to cancel it
+func (h *Auth) warnScheduledDeletion(ctx echo.Context, usr *ent.User) {
+	if usr.DeletionScheduledAt == nil {
+		return
+	}
+
+	msg.Warning(ctx, fmt.Sprintf(
+		"Your account is scheduled to be deleted on %s. You can cancel this in your <a href=\"%s\">account settings</a>.",
+		usr.DeletionScheduledAt.Format("January 2, 2006"),
+		ctx.Echo().Reverse(routeNameAccount),
+	))
+}
+
+// recordDevice records the device a user logged in from, and notifies the user if they never logged in from it
+// before, in case it was not them
+func (h *Auth) recordDevice(ctx echo.Context, usr *ent.User) {
+	isNew, err := h.auth.RecordDevice(ctx, usr.ID)
+	if err != nil {
+		log.Ctx(ctx).Error("unable to record device",
+			"user_id", usr.ID,
+			"error", err,
+		)
+		return
+	}
+
+	if !isNew {
+		return
+	}
+
+	if err = h.notify.NewDevice(ctx, usr); err != nil {
+		log.Ctx(ctx).Error("unable to send new device notification",
+			"user_id", usr.ID,
+			"error", err,
+		)
+	}
+}
+
+func (h *Auth) rehashPassword(ctx echo.Context, usr *ent.User, password string) {
+	hash, err := h.auth.HashPassword(password)
+	if err == nil {
+		err = usr.Update().
+			SetPassword(hash).
+			Exec(ctx.Request().Context())
+	}
+
+	if err != nil {
+		log.Ctx(ctx).Error("unable to rehash password",
+			"user_id", usr.ID,
+			"error", err,
+		)
+	}
+}
+
+// lock locks the account of a given user after a given amount of failed login attempts and lets the user know
+func (h *Auth) lock(ctx echo.Context, usr *ent.User, attempts int) error {
+	until, err := h.auth.LockUser(ctx.Request().Context(), usr.ID)
+	if err != nil {
+		return fail(err, "unable to lock user")
+	}
+
+	// Start counting again once the lock is lifted
+	if err = h.throttle.ResetLogin(ctx.Request().Context(), usr.Email); err != nil {
+		return fail(err, "unable to reset failed login attempts")
+	}
+
+	log.Ctx(ctx).Warn("user locked due to failed logins",
+		"user_id", usr.ID,
+		"attempts", attempts,
+	)
+
+	h.audit.Event(services.AuditActionLocked).
+		Target(usr.ID).
+		With("attempts", attempts).
+		With("until", until).
+		Save(ctx)
+
+	h.sendLockedEmail(ctx, usr, until)
+
+	return nil
+}
+
+func (h *Auth) sendLockedEmail(ctx echo.Context, usr *ent.User, until time.Time) {
+	url := ctx.Echo().Reverse(routeNameForgotPassword)
+	err := h.mail.
+		Compose().
+		To(usr.Email).
+		Subject("Your account has been locked").
+		Body(fmt.Sprintf(
+			"Due to too many failed login attempts, your account has been locked until %s. If this wasn't you, we recommend resetting your password: %s",
+			until.Format(time.RFC1123),
+			url,
+		)).
+		Send(ctx)
+
+	if err != nil {
+		log.Ctx(ctx).Error("unable to send account locked email",
+			"user_id", usr.ID,
+			"error", err,
+		)
+	}
+}
+
 func (h *Auth) Logout(ctx echo.Context) error {
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+
 	if err := h.auth.Logout(ctx); err == nil {
+		h.audit.Event(services.AuditActionLogout).
+			Target(usr.ID).
+			Save(ctx)
 		msg.Success(ctx, "You have been logged out successfully.")
 	} else {
 		msg.Danger(ctx, "An error occurred. Please try again.")
@@ -241,12 +807,52 @@ func (h *Auth) Logout(ctx echo.Context) error {
 		Go()
 }
 
+func (h *Auth) StopImpersonation(ctx echo.Context) error {
+	userID, err := h.auth.StopImpersonation(ctx)
+	switch err.(type) {
+	case nil:
+	case services.NotImpersonatingError:
+		return redirect.New(ctx).
+			Route(routeNameHome).
+			Go()
+	default:
+		return fail(err, "unable to stop impersonation")
+	}
+
+	log.Ctx(ctx).Info("impersonation stopped",
+		"user_id", userID,
+	)
+
+	// The impersonator, rather than the impersonated user, is the actor
+	event := h.audit.Event(services.AuditActionImpersonationStopped).Target(userID)
+	if admin, ok := ctx.Get(context.ImpersonatorKey).(*ent.User); ok {
+		event.Actor(admin.ID)
+	}
+	event.Save(ctx)
+
+	msg.Info(ctx, "You are no longer impersonating a user.")
+
+	return redirect.New(ctx).
+		Route(routeNameAdminEdit).
+		Params("users", userID).
+		Go()
+}
+
 func (h *Auth) RegisterPage(ctx echo.Context) error {
 	p := page.New(ctx)
 	p.Layout = templates.LayoutAuth
 	p.Name = templates.PageRegister
 	p.Title = "Register"
-	p.Form = form.Get[registerForm](ctx)
+	p.Data = registerData{
+		RequiresInviteCode: h.reg.RequiresInviteCode(),
+	}
+
+	// Prefill the invite code when following a link which contains it
+	f := form.Get[registerForm](ctx)
+	if !f.IsSubmitted() && f.InviteCode == "" {
+		f.InviteCode = ctx.QueryParam("code")
+	}
+	p.Form = f
 
 	return h.RenderPage(ctx, p)
 }
@@ -271,12 +877,12 @@ func (h *Auth) RegisterSubmit(ctx echo.Context) error {
 	}
 
 	// Attempt creating the user
-	u, err := h.orm.User.
-		Create().
-		SetName(input.Name).
-		SetEmail(input.Email).
-		SetPassword(pwHash).
-		Save(ctx.Request().Context())
+	u, err := h.reg.Register(ctx.Request().Context(), services.Registration{
+		Name:       input.Name,
+		Email:      input.Email,
+		Password:   pwHash,
+		InviteCode: input.InviteCode,
+	})
 
 	switch err.(type) {
 	case nil:
@@ -284,6 +890,17 @@ func (h *Auth) RegisterSubmit(ctx echo.Context) error {
 			"user_name", u.Name,
 			"user_id", u.ID,
 		)
+
+		h.audit.Event(services.AuditActionRegister).
+			Actor(u.ID).
+			Target(u.ID).
+			Save(ctx)
+	case services.EmailDomainNotAllowedError:
+		input.SetFieldError("Email", "Registration is not allowed with this email address.")
+		return h.RegisterPage(ctx)
+	case services.InvalidInviteCodeError:
+		input.SetFieldError("InviteCode", "This invite code is invalid, expired or has already been used.")
+		return h.RegisterPage(ctx)
 	case *ent.ConstraintError:
 		msg.Warning(ctx, "A user with this email address already exists. Please log in.")
 		return redirect.New(ctx).
@@ -306,6 +923,9 @@ func (h *Auth) RegisterSubmit(ctx echo.Context) error {
 			Go()
 	}
 
+	// The device registered from is never new, so this only remembers it
+	h.recordDevice(ctx, u)
+
 	msg.Success(ctx, "Your account has been created. You are now logged in.")
 
 	// Send the verification email
@@ -318,7 +938,7 @@ func (h *Auth) RegisterSubmit(ctx echo.Context) error {
 
 func (h *Auth) sendVerificationEmail(ctx echo.Context, usr *ent.User) {
 	// Generate a token
-	token, err := h.auth.GenerateEmailVerificationToken(usr.Email)
+	token, err := h.auth.GenerateEmailVerificationToken(ctx.Request().Context(), usr)
 	if err != nil {
 		log.Ctx(ctx).Error("unable to generate email verification token",
 			"user_id", usr.ID,
@@ -358,7 +978,13 @@ func (h *Auth) ResetPasswordPage(ctx echo.Context) error {
 }
 
 func (h *Auth) ResetPasswordSubmit(ctx echo.Context) error {
-	var input resetPasswordForm
+	// Get the requesting user
+	usr := ctx.Get(context.UserKey).(*ent.User)
+
+	input := resetPasswordForm{
+		Name:  usr.Name,
+		Email: usr.Email,
+	}
 
 	err := form.Submit(ctx, &input)
 
@@ -376,13 +1002,11 @@ func (h *Auth) ResetPasswordSubmit(ctx echo.Context) error {
 		return fail(err, "unable to hash password")
 	}
 
-	// Get the requesting user
-	usr := ctx.Get(context.UserKey).(*ent.User)
-
-	// Update the user
+	// Update the user and lift any lock since they've proven ownership of the account
 	_, err = usr.
 		Update().
 		SetPassword(hash).
+		ClearLockedUntil().
 		Save(ctx.Request().Context())
 
 	if err != nil {
@@ -395,56 +1019,94 @@ func (h *Auth) ResetPasswordSubmit(ctx echo.Context) error {
 		return fail(err, "unable to delete password tokens")
 	}
 
+	h.audit.Event(services.AuditActionPasswordReset).
+		Actor(usr.ID).
+		Target(usr.ID).
+		Save(ctx)
+
+	if err = h.notify.PasswordReset(ctx, usr); err != nil {
+		log.Ctx(ctx).Error("unable to send password reset notification",
+			"user_id", usr.ID,
+			"error", err,
+		)
+	}
+
 	msg.Success(ctx, "Your password has been updated.")
 	return redirect.New(ctx).
 		Route(routeNameLogin).
 		Go()
 }
 
-func (h *Auth) VerifyEmail(ctx echo.Context) error {
-	var usr *ent.User
-
-	// Validate the token
-	token := ctx.Param("token")
-	email, err := h.auth.ValidateEmailVerificationToken(token)
-	if err != nil {
-		msg.Warning(ctx, "The link is either invalid or has expired.")
+func (h *Auth) VerifyEmailNotice(ctx echo.Context) error {
+	if usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User); usr.Verified {
 		return redirect.New(ctx).
 			Route(routeNameHome).
 			Go()
 	}
 
-	// Check if it matches the authenticated user
-	if u := ctx.Get(context.AuthenticatedUserKey); u != nil {
-		authUser := u.(*ent.User)
+	p := page.New(ctx)
+	p.Layout = templates.LayoutMain
+	p.Name = templates.PageVerifyEmail
+	p.Title = "Verify your email address"
 
-		if authUser.Email == email {
-			usr = authUser
-		}
+	return h.RenderPage(ctx, p)
+}
+
+func (h *Auth) VerifyEmailResend(ctx echo.Context) error {
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+
+	if usr.Verified {
+		return redirect.New(ctx).
+			Route(routeNameHome).
+			Go()
 	}
 
-	// Query to find a matching user, if needed
-	if usr == nil {
-		usr, err = h.orm.User.
-			Query().
-			Where(user.Email(email)).
-			Only(ctx.Request().Context())
+	allowed, err := h.throttle.VerificationResendAllowed(ctx.Request().Context(), usr.ID)
+	switch {
+	case err != nil:
+		return fail(err, "error throttling verification resend request")
+	case !allowed:
+		msg.Danger(ctx, "Too many requests. Please check your inbox or try again later.")
+	default:
+		h.sendVerificationEmail(ctx, usr)
+	}
 
-		if err != nil {
-			return fail(err, "query failed loading email verification token user")
-		}
+	return redirect.New(ctx).
+		Route(routeNameVerifyEmailNotice).
+		Go()
+}
+
+func (h *Auth) VerifyEmail(ctx echo.Context) error {
+	// Validate the token
+	usr, err := h.auth.ValidateEmailVerificationToken(ctx.Request().Context(), ctx.Param("token"))
+
+	switch err.(type) {
+	case nil:
+	case services.InvalidVerificationTokenError:
+		msg.Warning(ctx, "The link is either invalid, has expired, or has already been used.")
+		return redirect.New(ctx).
+			Route(routeNameHome).
+			Go()
+	default:
+		return fail(err, "error validating email verification token")
 	}
 
-	// Verify the user, if needed
-	if !usr.Verified {
-		usr, err = usr.
-			Update().
-			SetVerified(true).
-			Save(ctx.Request().Context())
+	// Verify the user and clear the nonce so the link cannot be used again
+	verified := usr.Verified
+	usr, err = usr.
+		Update().
+		SetVerified(true).
+		ClearVerificationNonce().
+		Save(ctx.Request().Context())
 
-		if err != nil {
-			return fail(err, "failed to set user as verified")
-		}
+	if err != nil {
+		return fail(err, "failed to set user as verified")
+	}
+
+	if !verified {
+		h.audit.Event(services.AuditActionEmailVerified).
+			Target(usr.ID).
+			Save(ctx)
 	}
 
 	msg.Success(ctx, "Your email has been successfully verified.")
@@ -452,3 +1114,9 @@ func (h *Auth) VerifyEmail(ctx echo.Context) error {
 		Route(routeNameHome).
 		Go()
 }
+
+// BotPolicy requires a proof-of-work to register, on top of the default bot protection checks
+func (f *registerForm) BotPolicy(defaults bot.Policy) bot.Policy {
+	defaults.ProofOfWork = true
+	return defaults
+}
@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/sessions"
+	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/ent/auditevent"
+	"github.com/mikestefanello/pagoda/ent/logintoken"
+	"github.com/mikestefanello/pagoda/ent/user"
+	"github.com/mikestefanello/pagoda/pkg/services"
+	"github.com/mikestefanello/pagoda/pkg/session"
+	"github.com/mikestefanello/pagoda/pkg/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuth__LoginLinkSubmit(t *testing.T) {
+	usr, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	request(t).
+		setRoute(routeNameLoginLinkSubmit).
+		setBody(url.Values{
+			"email": []string{usr.Email},
+		}).
+		post().
+		assertStatusCode(http.StatusOK)
+
+	count, err := c.ORM.LoginToken.
+		Query().
+		Where(logintoken.HasUserWith(user.ID(usr.ID))).
+		Count(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestAuth__LoginLinkVerify(t *testing.T) {
+	usr, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+	require.False(t, usr.Verified)
+
+	// Generate the token in the session of a browser
+	ctx, rec := tests.NewContext(c.Web, "/")
+	session.Store(ctx, sessions.NewCookieStore(c.Keyring.SessionKeys()...))
+	token, _, err := c.Auth.GenerateLoginToken(ctx, usr.ID)
+	require.NoError(t, err)
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	// The link cannot be used from another browser
+	resp := request(t).
+		setRoute(routeNameLoginLinkVerify, token).
+		get().
+		assertStatusCode(http.StatusOK)
+	assert.Equal(t, c.Web.Reverse(routeNameLoginLink), resp.Request.URL.Path)
+
+	// The link logs the user in from the browser that requested it
+	req := request(t).setRoute(routeNameLoginLinkVerify, token)
+	req.client.Jar.SetCookies(u, rec.Result().Cookies())
+	resp = req.get().assertStatusCode(http.StatusOK)
+	assert.Equal(t, c.Web.Reverse(routeNameHome), resp.Request.URL.Path)
+
+	usr, err = c.ORM.User.Get(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.True(t, usr.Verified)
+
+	request(t).
+		setClient(req.client).
+		setRoute(routeNameLogout).
+		get().
+		assertStatusCode(http.StatusOK)
+
+	// The link can only be used once
+	resp = req.get().assertStatusCode(http.StatusOK)
+	assert.Equal(t, c.Web.Reverse(routeNameLoginLink), resp.Request.URL.Path)
+}
+
+func TestAuth__VerifyEmail(t *testing.T) {
+	usr, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	token, err := c.Auth.GenerateEmailVerificationToken(context.Background(), usr)
+	require.NoError(t, err)
+
+	req := request(t).setRoute(routeNameVerifyEmail, token)
+	doc := req.get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Contains(t, doc.Find(".notification").Text(), "successfully verified")
+
+	usr, err = c.ORM.User.Get(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.True(t, usr.Verified)
+	assert.Nil(t, usr.VerificationNonce)
+
+	// The link can only be used once
+	doc = req.get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Contains(t, doc.Find(".notification").Text(), "already been used")
+}
+
+func TestAuth__RequireVerifiedEmail(t *testing.T) {
+	req, usr := login(t, false)
+	require.NoError(t, usr.Update().SetVerified(false).Exec(context.Background()))
+
+	// Unverified users are redirected to verify their email address
+	resp := req.setRoute(routeNameTokens).
+		get().
+		assertStatusCode(http.StatusOK)
+	assert.Equal(t, c.Web.Reverse(routeNameVerifyEmailNotice), resp.Request.URL.Path)
+	doc := resp.toDoc()
+	assert.Equal(t, 1, doc.Find("#verify-email-resend").Length())
+
+	// Resend the verification email
+	r, err := req.client.PostForm(srv.URL+c.Web.Reverse(routeNameVerifyEmailResend), url.Values{
+		"csrf": []string{csrfToken(doc)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, r.StatusCode)
+	require.NoError(t, r.Body.Close())
+
+	usr, err = c.ORM.User.Get(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, usr.VerificationNonce)
+
+	// Once verified, the route can be accessed
+	require.NoError(t, usr.Update().SetVerified(true).Exec(context.Background()))
+	resp = req.setRoute(routeNameTokens).
+		get().
+		assertStatusCode(http.StatusOK)
+	assert.Equal(t, c.Web.Reverse(routeNameTokens), resp.Request.URL.Path)
+}
+
+func TestAuth__RegisterInviteOnly(t *testing.T) {
+	registration := c.Config.App.Registration
+	defer func() {
+		c.Config.App.Registration = registration
+	}()
+	c.Config.App.Registration.Mode = config.RegistrationModeInvite
+	c.Config.App.Registration.AllowedDomains = []string{"example.com"}
+
+	code, _, err := c.Registration.CreateInviteCode(context.Background(), services.InviteCodeParams{MaxUses: 1})
+	require.NoError(t, err)
+
+	// The code is prefilled from the link
+	doc := request(t).
+		setRoute(routeNameRegister).
+		get().
+		toDoc()
+	assert.Equal(t, 1, doc.Find(`input[name="invite-code"]`).Length())
+
+	req := request(t).setRoute(routeNameRegister)
+	req.route += "?code=" + url.QueryEscape(code)
+	doc = req.get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	value, _ := doc.Find(`input[name="invite-code"]`).Attr("value")
+	assert.Equal(t, code, value)
+
+	register := func(email, code string) *httpResponse {
+		return request(t).
+			setRoute(routeNameRegisterSubmit).
+			setBody(url.Values{
+				"name":             []string{"Invited"},
+				"email":            []string{email},
+				"password":         []string{"Xy7#plqz!Rw2"},
+				"password-confirm": []string{"Xy7#plqz!Rw2"},
+				"invite-code":      []string{code},
+			}).
+			post().
+			assertStatusCode(http.StatusOK)
+	}
+
+	exists := func(email string) bool {
+		e, err := c.ORM.User.Query().Where(user.Email(email)).Exist(context.Background())
+		require.NoError(t, err)
+		return e
+	}
+
+	// Email addresses of other domains are not allowed
+	register("invited@other.com", code)
+	assert.False(t, exists("invited@other.com"))
+
+	register("invited@example.com", "invalid")
+	assert.False(t, exists("invited@example.com"))
+
+	resp := register("invited@example.com", code)
+	assert.True(t, exists("invited@example.com"))
+	assert.Equal(t, c.Web.Reverse(routeNameHome), resp.Request.URL.Path)
+
+	// The code can only be used once
+	register("invited2@example.com", code)
+	assert.False(t, exists("invited2@example.com"))
+}
+
+func TestAuth__RegisterClosed(t *testing.T) {
+	mode := c.Config.App.Registration.Mode
+	defer func() {
+		c.Config.App.Registration.Mode = mode
+	}()
+	c.Config.App.Registration.Mode = config.RegistrationModeClosed
+
+	request(t).
+		setRoute(routeNameRegister).
+		get().
+		assertStatusCode(http.StatusNotFound)
+
+	// The links to register are hidden
+	doc := request(t).
+		setRoute(routeNameLogin).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	register := c.Web.Reverse(routeNameRegister)
+	assert.Zero(t, doc.Find(`a[href="`+register+`"]`).Length())
+
+	c.Config.App.Registration.Mode = config.RegistrationModeOpen
+	doc = request(t).
+		setRoute(routeNameLogin).
+		get().
+		toDoc()
+	assert.NotZero(t, doc.Find(`a[href="`+register+`"]`).Length())
+}
+
+func TestAuth__Reauthenticate(t *testing.T) {
+	req, usr := login(t, false)
+
+	reauth := func(password, next string) *httpResponse {
+		return request(t).
+			setClient(req.client).
+			setRoute(routeNameReauthenticateSubmit).
+			setBody(url.Values{
+				"password": []string{password},
+				"next":     []string{next},
+			}).
+			post().
+			assertStatusCode(http.StatusOK)
+	}
+
+	// The page returns to where the user came from
+	r := request(t).setClient(req.client).setRoute(routeNameReauthenticate)
+	r.route += "?next=" + url.QueryEscape("/account/tokens")
+	doc := r.get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	next, _ := doc.Find(`input[name="next"]`).Attr("value")
+	assert.Equal(t, "/account/tokens", next)
+
+	doc = reauth("incorrect", "/account/tokens").toDoc()
+	assert.Contains(t, doc.Find(".help.is-danger").Text(), "incorrect")
+
+	resp := reauth("password", "/account/tokens")
+	assert.Equal(t, c.Web.Reverse(routeNameTokens), resp.Request.URL.Path)
+
+	// Only paths within the app can be returned to
+	for _, next := range []string{
+		"//example.org/account",
+		"https://example.org/account",
+		"/\\example.org/account",
+		"/\t/example.org/account",
+		"/\n/example.org/account",
+	} {
+		resp = reauth("password", next)
+		assert.Equal(t, c.Web.Reverse(routeNameHome), resp.Request.URL.Path, next)
+		assert.Equal(t, "127.0.0.1", resp.Request.URL.Hostname(), next)
+	}
+
+	// Locked accounts cannot reauthenticate
+	_, err := c.Auth.LockUser(context.Background(), usr.ID)
+	require.NoError(t, err)
+	doc = reauth("password", "/account/tokens").toDoc()
+	assert.Contains(t, doc.Find(".notification.is-danger").Text(), "locked")
+}
+
+func TestAuth__ReauthenticateLockout(t *testing.T) {
+	req, usr := login(t, false)
+
+	cfg := &c.Config.App.LoginThrottle
+	defer func(attempts int) {
+		cfg.LockoutAttempts = attempts
+	}(cfg.LockoutAttempts)
+	cfg.LockoutAttempts = 1
+
+	// Too many failed attempts lock the account, just like logging in
+	doc := request(t).
+		setClient(req.client).
+		setRoute(routeNameReauthenticateSubmit).
+		setBody(url.Values{
+			"password": []string{"incorrect"},
+			"next":     []string{"/account/tokens"},
+		}).
+		post().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Contains(t, doc.Find(".notification.is-danger").Text(), "locked")
+
+	u, err := c.ORM.User.Get(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.True(t, c.Auth.IsLocked(u))
+
+	n, err := c.ORM.AuditEvent.
+		Query().
+		Where(
+			auditevent.Action(services.AuditActionLocked),
+			auditevent.HasTargetWith(user.ID(usr.ID)),
+		).
+		Count(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
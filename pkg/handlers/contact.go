@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/pkg/bot"
 	"github.com/mikestefanello/pagoda/pkg/form"
 	"github.com/mikestefanello/pagoda/pkg/page"
 	"github.com/mikestefanello/pagoda/pkg/services"
@@ -17,7 +19,8 @@ const (
 
 type (
 	Contact struct {
-		mail *services.MailClient
+		mail   *services.MailClient
+		config *config.Config
 		*services.TemplateRenderer
 	}
 
@@ -26,6 +29,7 @@ type (
 		Department string `form:"department" validate:"required,oneof=sales marketing hr"`
 		Message    string `form:"message" validate:"required"`
 		form.Submission
+		form.BotProtection
 	}
 )
 
@@ -36,6 +40,7 @@ func init() {
 func (h *Contact) Init(c *services.Container) error {
 	h.TemplateRenderer = c.TemplateRenderer
 	h.mail = c.Mail
+	h.config = c.Config
 	return nil
 }
 
@@ -67,12 +72,18 @@ func (h *Contact) Submit(ctx echo.Context) error {
 		return err
 	}
 
-	err = h.mail.
+	email := h.mail.
 		Compose().
-		To(input.Email).
+		To(h.config.App.Contact.Address).
+		ReplyTo(input.Email).
 		Subject("Contact form submitted").
-		Body(fmt.Sprintf("The message is: %s", input.Message)).
-		Send(ctx)
+		Body(fmt.Sprintf("The message is: %s", input.Message))
+
+	if dept := h.config.App.Contact.Departments[input.Department]; dept != "" {
+		email.Cc(dept)
+	}
+
+	err = email.Send(ctx)
 
 	if err != nil {
 		return fail(err, "unable to send email")
@@ -80,3 +91,9 @@ func (h *Contact) Submit(ctx echo.Context) error {
 
 	return h.Page(ctx)
 }
+
+// BotPolicy requires a proof-of-work to submit the contact form, on top of the default bot protection checks
+func (f *contactForm) BotPolicy(defaults bot.Policy) bot.Policy {
+	defaults.ProofOfWork = true
+	return defaults
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContact__BotProtection(t *testing.T) {
+	body := func() url.Values {
+		return url.Values{
+			"email":      []string{"contact@example.com"},
+			"department": []string{"sales"},
+			"message":    []string{"Hello"},
+		}
+	}
+
+	// The form renders the bot protection fields, including a proof-of-work challenge
+	doc := request(t).
+		setRoute(routeNameContact).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, 1, doc.Find(`#contact input[name="homepage"]`).Length())
+	assert.Equal(t, 1, doc.Find(`#contact input[name="bot-token"]`).Length())
+	assert.Equal(t, 1, doc.Find(`#contact input[name="bot-solution"][data-pow-challenge]`).Length())
+
+	inbox := c.Mail.Inbox()
+	inbox.Clear()
+
+	// Human submissions succeed
+	doc = request(t).
+		setRoute(routeNameContactSubmit).
+		setBody(body()).
+		post().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, 0, doc.Find("#contact").Length())
+	msgs := inbox.MessagesTo(c.Config.App.Contact.Address)
+	require.Len(t, msgs, 1)
+	assert.ElementsMatch(t, []string{"contact@localhost", "sales@localhost"}, msgs[0].To)
+	content, err := msgs[0].Content()
+	require.NoError(t, err)
+	replyTo, err := content.Header.AddressList("Reply-To")
+	require.NoError(t, err)
+	require.Len(t, replyTo, 1)
+	assert.Equal(t, "contact@example.com", replyTo[0].Address)
+	cc, err := content.Header.AddressList("Cc")
+	require.NoError(t, err)
+	require.Len(t, cc, 1)
+	assert.Equal(t, "sales@localhost", cc[0].Address)
+
+	// Filling in the honeypot is rejected
+	b := body()
+	b.Set("homepage", "https://spam.example.com")
+	doc = request(t).
+		setRoute(routeNameContactSubmit).
+		setBody(b).
+		post().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, 1, doc.Find("#contact").Length())
+	assert.Contains(t, doc.Find("#contact .help.is-danger").Text(), "could not be verified")
+
+	// Submitting without a token is rejected
+	r := request(t).setRoute(routeNameContactSubmit)
+	b = body()
+	b.Set("csrf", csrfToken(r.get().toDoc()))
+	resp, err := r.client.PostForm(r.route, b)
+	require.NoError(t, err)
+	doc = (&httpResponse{Response: resp, t: t}).
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Contains(t, doc.Find("#contact .help.is-danger").Text(), "could not be verified")
+
+	// Rejected submissions do not send email
+	assert.Len(t, inbox.Messages(), 1)
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"html/template"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/pkg/msg"
+	"github.com/mikestefanello/pagoda/pkg/page"
+	"github.com/mikestefanello/pagoda/pkg/redirect"
+	"github.com/mikestefanello/pagoda/pkg/services"
+	"github.com/mikestefanello/pagoda/templates"
+)
+
+const (
+	routeNameDevMail            = "dev.mail"
+	routeNameDevMailClear       = "dev.mail.clear"
+	routeNameDevMailMessage     = "dev.mail.message"
+	routeNameDevMailMessageHTML = "dev.mail.message.html"
+	routeNameDevMailAttachment  = "dev.mail.message.attachment"
+)
+
+const (
+	devMailViewHTML = "html"
+	devMailViewText = "text"
+	devMailViewRaw  = "raw"
+)
+
+var (
+	// devMailURL matches URLs within plain text email bodies so they can be made clickable
+	devMailURL = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+	// devMailCID matches references to inline attachments within HTML email bodies
+	devMailCID = regexp.MustCompile(`cid:([^"'\s>)]+)`)
+)
+
+type (
+	// DevMail provides a mailbox to view the email sent by the application during local development, without
+	// delivering it anywhere
+	DevMail struct {
+		inbox *services.MemoryTransport
+		*services.TemplateRenderer
+	}
+
+	devMailListData struct {
+		Messages []devMailSummary
+	}
+
+	devMailSummary struct {
+		ID         int
+		ReceivedAt time.Time
+		From       string
+		To         []string
+		Subject    string
+	}
+
+	devMailMessageData struct {
+		Message services.InboxMessage
+		Content *services.InboxContent
+		View    string
+		Views   []string
+		Text    template.HTML
+	}
+)
+
+func init() {
+	Register(new(DevMail))
+}
+
+func (h *DevMail) Init(c *services.Container) error {
+	h.TemplateRenderer = c.TemplateRenderer
+
+	// The mailbox is only available during local development and testing, and only when email is captured in memory
+	switch c.Config.App.Environment {
+	case config.EnvLocal, config.EnvTest:
+		h.inbox = c.Mail.Inbox()
+	}
+
+	return nil
+}
+
+func (h *DevMail) Routes(g *echo.Group) {
+	if h.inbox == nil {
+		return
+	}
+
+	g.GET("/_dev/mail", h.List).Name = routeNameDevMail
+	g.POST("/_dev/mail/clear", h.Clear).Name = routeNameDevMailClear
+	g.GET("/_dev/mail/:message", h.Message).Name = routeNameDevMailMessage
+	g.GET("/_dev/mail/:message/html", h.MessageHTML).Name = routeNameDevMailMessageHTML
+	g.GET("/_dev/mail/:message/attachments/:attachment", h.Attachment).Name = routeNameDevMailAttachment
+}
+
+// List renders every captured message, newest first. The list is polled by HTMX so new messages appear
+// automatically.
+func (h *DevMail) List(ctx echo.Context) error {
+	var data devMailListData
+	messages := h.inbox.Messages()
+	for i := len(messages) - 1; i >= 0; i-- {
+		m := messages[i]
+		summary := devMailSummary{
+			ID:         m.ID,
+			ReceivedAt: m.ReceivedAt,
+			From:       m.From,
+			To:         m.To,
+		}
+
+		if content, err := m.Content(); err == nil {
+			summary.Subject = content.Subject
+		}
+
+		data.Messages = append(data.Messages, summary)
+	}
+
+	p := page.New(ctx)
+	p.Layout = templates.LayoutMain
+	p.Name = templates.PageDevMail
+	p.Title = "Mail"
+	p.Data = data
+
+	return h.RenderPage(ctx, p)
+}
+
+func (h *DevMail) Clear(ctx echo.Context) error {
+	h.inbox.Clear()
+	msg.Success(ctx, "All messages have been cleared.")
+	return redirect.New(ctx).
+		Route(routeNameDevMail).
+		Go()
+}
+
+func (h *DevMail) Message(ctx echo.Context) error {
+	m, err := h.getMessage(ctx)
+	if err != nil {
+		return err
+	}
+
+	content, err := m.Content()
+	if err != nil {
+		return fail(err, "unable to parse message")
+	}
+
+	data := devMailMessageData{
+		Message: m,
+		Content: content,
+		View:    ctx.QueryParam("view"),
+		Text:    linkify(content.Text),
+	}
+
+	if content.HTML != "" {
+		data.Views = append(data.Views, devMailViewHTML)
+	}
+	if content.Text != "" {
+		data.Views = append(data.Views, devMailViewText)
+	}
+	data.Views = append(data.Views, devMailViewRaw)
+
+	if !slices.Contains(data.Views, data.View) {
+		data.View = data.Views[0]
+	}
+
+	p := page.New(ctx)
+	p.Layout = templates.LayoutMain
+	p.Name = templates.PageDevMailMessage
+	p.Title = content.Subject
+	p.Data = data
+
+	return h.RenderPage(ctx, p)
+}
+
+// MessageHTML serves the HTML body of a message on its own, to be displayed within a sandboxed frame
+func (h *DevMail) MessageHTML(ctx echo.Context) error {
+	m, err := h.getMessage(ctx)
+	if err != nil {
+		return err
+	}
+
+	content, err := m.Content()
+	if err != nil {
+		return fail(err, "unable to parse message")
+	}
+
+	// Load inline attachments from the mailbox since browsers cannot resolve content IDs
+	html := devMailCID.ReplaceAllStringFunc(content.HTML, func(ref string) string {
+		for i, a := range content.Attachments {
+			if a.ContentID == strings.TrimPrefix(ref, "cid:") {
+				return ctx.Echo().Reverse(routeNameDevMailAttachment, m.ID, i)
+			}
+		}
+		return ref
+	})
+
+	// Open links in a new window rather than within the frame
+	ctx.Response().Header().Set("Content-Security-Policy", "sandbox allow-popups allow-popups-to-escape-sandbox")
+	return ctx.HTML(http.StatusOK, `<base target="_blank">`+html)
+}
+
+// Attachment serves a file attached to a message, by its position within the message
+func (h *DevMail) Attachment(ctx echo.Context) error {
+	m, err := h.getMessage(ctx)
+	if err != nil {
+		return err
+	}
+
+	content, err := m.Content()
+	if err != nil {
+		return fail(err, "unable to parse message")
+	}
+
+	i, err := strconv.Atoi(ctx.Param("attachment"))
+	if err != nil || i < 0 || i >= len(content.Attachments) {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	a := content.Attachments[i]
+	ctx.Response().Header().Set("Content-Security-Policy", "sandbox")
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": a.Filename}))
+	return ctx.Blob(http.StatusOK, a.ContentType, a.Data)
+}
+
+// getMessage loads the message with the ID in the path
+func (h *DevMail) getMessage(ctx echo.Context) (services.InboxMessage, error) {
+	id, err := strconv.Atoi(ctx.Param("message"))
+	if err != nil {
+		return services.InboxMessage{}, echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	m, ok := h.inbox.Message(id)
+	if !ok {
+		return services.InboxMessage{}, echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	return m, nil
+}
+
+// linkify escapes a given plain text body and turns the URLs within it into links
+func linkify(text string) template.HTML {
+	var b strings.Builder
+	last := 0
+	for _, loc := range devMailURL.FindAllStringIndex(text, -1) {
+		url := template.HTMLEscapeString(text[loc[0]:loc[1]])
+		b.WriteString(template.HTMLEscapeString(text[last:loc[0]]))
+		b.WriteString(`<a href="` + url + `" target="_blank">` + url + `</a>`)
+		last = loc[1]
+	}
+	b.WriteString(template.HTMLEscapeString(text[last:]))
+	return template.HTML(b.String())
+}
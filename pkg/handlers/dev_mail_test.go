@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDevMail(t *testing.T) {
+	inbox := c.Mail.Inbox()
+	inbox.Clear()
+
+	// No messages yet
+	doc := request(t).
+		setRoute(routeNameDevMail).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, "No messages", doc.Find("#dev-mail tbody td").Text())
+
+	err := c.Mail.
+		Compose().
+		To("dev@example.com").
+		Subject("Reset your password").
+		Body("Go here: http://localhost:8000/reset?token=<abc>&x=1").
+		SendContext(context.Background())
+	require.NoError(t, err)
+	err = c.Mail.
+		Compose().
+		To("dev@example.com").
+		Subject("HTML").
+		Template("test").
+		SendContext(context.Background())
+	require.NoError(t, err)
+	msgs := inbox.Messages()
+	require.Len(t, msgs, 2)
+
+	// Messages are listed newest first
+	doc = request(t).
+		setRoute(routeNameDevMail).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	rows := doc.Find("#dev-mail tbody tr")
+	require.Equal(t, 2, rows.Length())
+	assert.Equal(t, "HTML", rows.Eq(0).Find("a").Text())
+	assert.Equal(t, "Reset your password", rows.Eq(1).Find("a").Text())
+	assert.Equal(t, "dev@example.com", rows.Eq(1).Find("td").Eq(2).Text())
+	assert.Equal(t, 1, doc.Find(`form[action="/_dev/mail/clear"]`).Length())
+
+	// Polling only renders the list
+	req, err := http.NewRequest(http.MethodGet, srv.URL+c.Web.Reverse(routeNameDevMail), nil)
+	require.NoError(t, err)
+	req.Header.Set("HX-Request", "true")
+	req.Header.Set("HX-Target", "dev-mail")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	doc = (&httpResponse{Response: resp, t: t}).
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, 2, doc.Find("#dev-mail tbody tr").Length())
+	assert.Equal(t, 0, doc.Find("form").Length())
+
+	// Plain text messages are shown as text, with clickable links
+	doc = request(t).
+		setRoute(routeNameDevMailMessage, msgs[0].ID).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, []string{"Text", "Raw"}, doc.Find(".tabs li").Map(func(_ int, s *goquery.Selection) string {
+		return strings.TrimSpace(s.Text())
+	}))
+	link := doc.Find("#dev-mail-text a")
+	assert.Equal(t, "http://localhost:8000/reset?token=", link.AttrOr("href", ""))
+	assert.Contains(t, doc.Find("#dev-mail-text").Text(), "<abc>&x=1")
+
+	// The raw message is available
+	r := request(t)
+	r.route = srv.URL + c.Web.Reverse(routeNameDevMailMessage, msgs[0].ID) + "?view=raw"
+	doc = r.get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Contains(t, doc.Find("#dev-mail-raw").Text(), "Subject: Reset your password")
+
+	// HTML messages are shown in a sandboxed frame
+	doc = request(t).
+		setRoute(routeNameDevMailMessage, msgs[1].ID).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, "HTML", strings.TrimSpace(doc.Find(".tabs li.is-active").Text()))
+	frame := doc.Find("iframe#dev-mail-html")
+	assert.Equal(t, c.Web.Reverse(routeNameDevMailMessageHTML, msgs[1].ID), frame.AttrOr("src", ""))
+	assert.Contains(t, frame.AttrOr("sandbox", ""), "allow-popups")
+
+	resp = request(t).
+		setRoute(routeNameDevMailMessageHTML, msgs[1].ID).
+		get().
+		assertStatusCode(http.StatusOK).
+		Response
+	assert.Contains(t, resp.Header.Get("Content-Security-Policy"), "sandbox")
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "Test email template")
+
+	// Unknown messages are not found
+	request(t).
+		setRoute(routeNameDevMailMessage, 999999).
+		get().
+		assertStatusCode(http.StatusNotFound)
+
+	// Messages can be cleared
+	r = request(t).setRoute(routeNameDevMail)
+	token := csrfToken(r.get().toDoc())
+	resp, err = r.client.PostForm(srv.URL+c.Web.Reverse(routeNameDevMailClear), url.Values{"csrf": []string{token}})
+	require.NoError(t, err)
+	doc = (&httpResponse{Response: resp, t: t}).
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, "No messages", doc.Find("#dev-mail tbody td").Text())
+	assert.Empty(t, inbox.Messages())
+}
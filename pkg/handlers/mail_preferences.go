@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/pkg/context"
+	"github.com/mikestefanello/pagoda/pkg/form"
+	"github.com/mikestefanello/pagoda/pkg/log"
+	"github.com/mikestefanello/pagoda/pkg/middleware"
+	"github.com/mikestefanello/pagoda/pkg/msg"
+	"github.com/mikestefanello/pagoda/pkg/page"
+	"github.com/mikestefanello/pagoda/pkg/redirect"
+	"github.com/mikestefanello/pagoda/pkg/services"
+	"github.com/mikestefanello/pagoda/templates"
+)
+
+const (
+	routeNameMailPreferences        = "mail.preferences"
+	routeNameMailPreferencesSubmit  = "mail.preferences.submit"
+	routeNameMailUnsubscribe        = "mail.unsubscribe"
+	routeNameMailUnsubscribeSubmit  = "mail.unsubscribe.submit"
+	routeNameAccountMailPreferences = "account.mail.preferences"
+)
+
+type (
+	// MailPreferences lets recipients choose which categories of notification email they receive, using the token
+	// within the links in that email, so logging in is not required
+	MailPreferences struct {
+		suppressions *services.MailSuppressionClient
+		*services.TemplateRenderer
+	}
+
+	mailPreferencesData struct {
+		Token      string
+		Email      string
+		Categories []services.MailCategory
+
+		// Unsubscribe is the category being unsubscribed from, when opened from an unsubscribe link
+		Unsubscribe *services.MailCategory
+
+		// Blocked prevents every email from being sent to the address, if it bounced or complained
+		Blocked *ent.MailSuppression
+	}
+
+	mailPreferencesForm struct {
+		Categories []string `form:"categories"`
+		form.Submission
+	}
+)
+
+func init() {
+	Register(new(MailPreferences))
+}
+
+func (h *MailPreferences) Init(c *services.Container) error {
+	h.TemplateRenderer = c.TemplateRenderer
+	h.suppressions = c.MailSuppression
+	return nil
+}
+
+func (h *MailPreferences) Routes(g *echo.Group) {
+	g.GET("/email/preferences/:token", h.Page).Name = routeNameMailPreferences
+	g.POST("/email/preferences/:token", h.Submit).Name = routeNameMailPreferencesSubmit
+	g.GET("/email/unsubscribe/:token", h.Unsubscribe).Name = routeNameMailUnsubscribe
+
+	// Mail providers unsubscribe on behalf of the recipient, without a session, so the token is the only protection
+	unsubscribe := g.POST("/email/unsubscribe/:token", h.UnsubscribeSubmit)
+	unsubscribe.Name = routeNameMailUnsubscribeSubmit
+	exemptFromCSRF(unsubscribe)
+
+	g.GET("/account/email-preferences", h.Account, middleware.RequireAuthentication()).Name = routeNameAccountMailPreferences
+}
+
+func (h *MailPreferences) Page(ctx echo.Context) error {
+	return h.render(ctx, "")
+}
+
+// Unsubscribe renders the preferences with the category of the unsubscribe link unchecked, since this is what
+// recipients see when their email client opens the link rather than unsubscribing with one click. Nothing is changed
+// until the form is submitted, so links opened by link scanners do not unsubscribe anyone.
+func (h *MailPreferences) Unsubscribe(ctx echo.Context) error {
+	_, category, err := h.suppressions.ValidateToken(ctx.Param("token"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+	return h.render(ctx, category)
+}
+
+func (h *MailPreferences) Submit(ctx echo.Context) error {
+	email, _, err := h.suppressions.ValidateToken(ctx.Param("token"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	var input mailPreferencesForm
+
+	err = form.Submit(ctx, &input)
+
+	switch err.(type) {
+	case nil:
+	case validator.ValidationErrors:
+		return h.Page(ctx)
+	default:
+		return err
+	}
+
+	for _, category := range services.MailCategories {
+		if input.Receives(category.Name) {
+			err = h.suppressions.Resubscribe(ctx.Request().Context(), email, category.Name)
+		} else {
+			err = h.suppressions.Unsubscribe(ctx.Request().Context(), email, category.Name)
+		}
+
+		if err != nil {
+			return fail(err, "unable to update mail preferences")
+		}
+	}
+
+	log.Ctx(ctx).Info("mail preferences updated",
+		"categories", input.Categories,
+	)
+
+	msg.Success(ctx, "Your email preferences have been saved.")
+
+	return redirect.New(ctx).
+		Route(routeNameMailPreferences).
+		Params(ctx.Param("token")).
+		Go()
+}
+
+// UnsubscribeSubmit unsubscribes the recipient from the category of the link with one click, as per RFC 8058,
+// which mail providers do on behalf of the recipient when they click the unsubscribe button shown with the email
+func (h *MailPreferences) UnsubscribeSubmit(ctx echo.Context) error {
+	email, category, err := h.suppressions.ValidateToken(ctx.Param("token"))
+	if err != nil || category == "" {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	if ctx.FormValue("List-Unsubscribe") != "One-Click" {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	if err = h.suppressions.Unsubscribe(ctx.Request().Context(), email, category); err != nil {
+		return fail(err, "unable to unsubscribe")
+	}
+
+	log.Ctx(ctx).Info("unsubscribed from mail category",
+		"category", category,
+	)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// Account sends users to the preferences of their own email address
+func (h *MailPreferences) Account(ctx echo.Context) error {
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+
+	token, err := h.suppressions.GenerateToken(usr.Email, "")
+	if err != nil {
+		return fail(err, "unable to generate mail preferences token")
+	}
+
+	return redirect.New(ctx).
+		Route(routeNameMailPreferences).
+		Params(token).
+		Go()
+}
+
+// render renders the preferences of the address in the token, with a given category unchecked, if provided
+func (h *MailPreferences) render(ctx echo.Context, unsubscribe string) error {
+	email, _, err := h.suppressions.ValidateToken(ctx.Param("token"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	data := mailPreferencesData{
+		Token:      ctx.Param("token"),
+		Email:      email,
+		Categories: services.MailCategories,
+	}
+
+	if data.Blocked, err = h.suppressions.Blocked(ctx.Request().Context(), email); err != nil {
+		return fail(err, "unable to load mail suppressions")
+	}
+
+	f := form.Get[mailPreferencesForm](ctx)
+	if !f.IsSubmitted() {
+		unsubscribed, err := h.suppressions.Unsubscribed(ctx.Request().Context(), email)
+		if err != nil {
+			return fail(err, "unable to load mail preferences")
+		}
+
+		for _, category := range services.MailCategories {
+			if !unsubscribed[category.Name] && category.Name != unsubscribe {
+				f.Categories = append(f.Categories, category.Name)
+			}
+		}
+	}
+
+	if category, ok := services.GetMailCategory(unsubscribe); ok {
+		data.Unsubscribe = &category
+	}
+
+	p := page.New(ctx)
+	p.Layout = templates.LayoutMain
+	p.Name = templates.PageMailPreferences
+	p.Title = "Email preferences"
+	p.Form = f
+	p.Data = data
+
+	return h.RenderPage(ctx, p)
+}
+
+// Receives determines if the form has a given category selected
+func (f *mailPreferencesForm) Receives(category string) bool {
+	for _, c := range f.Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/mikestefanello/pagoda/ent/mailsuppression"
+	"github.com/mikestefanello/pagoda/pkg/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMailPreferences__OneClickUnsubscribe(t *testing.T) {
+	email := "one-click@example.com"
+	token, err := c.MailSuppression.GenerateToken(email, services.MailCategorySecurity)
+	require.NoError(t, err)
+
+	unsubscribe := func(token string, body url.Values) *http.Response {
+		resp, err := http.PostForm(srv.URL+c.Web.Reverse(routeNameMailUnsubscribeSubmit, token), body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp
+	}
+
+	// Mail providers post without a session or CSRF token
+	oneClick := url.Values{"List-Unsubscribe": []string{"One-Click"}}
+	assert.Equal(t, http.StatusNotFound, unsubscribe("invalid", oneClick).StatusCode)
+	assert.Equal(t, http.StatusBadRequest, unsubscribe(token, url.Values{}).StatusCode)
+	assert.Equal(t, http.StatusOK, unsubscribe(token, oneClick).StatusCode)
+
+	categories, err := c.MailSuppression.Unsubscribed(context.Background(), email)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{services.MailCategorySecurity: true}, categories)
+
+	// Tokens without a category only allow managing preferences
+	token, err = c.MailSuppression.GenerateToken(email, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, unsubscribe(token, oneClick).StatusCode)
+}
+
+func TestMailPreferences__Page(t *testing.T) {
+	ctx := context.Background()
+	email := "preferences@example.com"
+	require.NoError(t, c.MailSuppression.Unsubscribe(ctx, email, services.MailCategorySecurity))
+
+	token, err := c.MailSuppression.GenerateToken(email, services.MailCategoryInvitations)
+	require.NoError(t, err)
+
+	request(t).
+		setRoute(routeNameMailPreferences, "invalid").
+		get().
+		assertStatusCode(http.StatusNotFound)
+
+	// The current preferences are shown
+	doc := request(t).
+		setRoute(routeNameMailPreferences, token).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Contains(t, doc.Text(), email)
+	assert.Equal(t, 0, doc.Find(`input[value="security"][checked]`).Length())
+	assert.Equal(t, 1, doc.Find(`input[value="invitations"][checked]`).Length())
+
+	// Opening the unsubscribe link unchecks its category, without unsubscribing
+	doc = request(t).
+		setRoute(routeNameMailUnsubscribe, token).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Contains(t, doc.Find("#mail-preferences-unsubscribe").Text(), "Invitations")
+	assert.Equal(t, 0, doc.Find(`input[value="invitations"][checked]`).Length())
+
+	categories, err := c.MailSuppression.Unsubscribed(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{services.MailCategorySecurity: true}, categories)
+
+	// Saving replaces the preferences
+	doc = request(t).
+		setRoute(routeNameMailPreferencesSubmit, token).
+		setBody(url.Values{"categories": []string{services.MailCategorySecurity}}).
+		post().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, 1, doc.Find(`input[value="security"][checked]`).Length())
+	assert.Equal(t, 0, doc.Find(`input[value="invitations"][checked]`).Length())
+
+	categories, err = c.MailSuppression.Unsubscribed(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{services.MailCategoryInvitations: true}, categories)
+
+	// Addresses which cannot receive email are told so
+	require.NoError(t, c.MailSuppression.Suppress(ctx, email, mailsuppression.ReasonBounced))
+	doc = request(t).
+		setRoute(routeNameMailPreferences, token).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Contains(t, doc.Find(".message.is-warning").Text(), "could not be delivered")
+}
+
+func TestMailPreferences__Account(t *testing.T) {
+	request(t).
+		setRoute(routeNameAccountMailPreferences).
+		get().
+		assertStatusCode(http.StatusUnauthorized)
+
+	req, usr := login(t, false)
+	doc := req.setRoute(routeNameAccountMailPreferences).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Contains(t, doc.Text(), usr.Email)
+	assert.Equal(t, len(services.MailCategories), doc.Find(`#mail-preferences input[checked]`).Length())
+}
@@ -0,0 +1,477 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/membership"
+	"github.com/mikestefanello/pagoda/pkg/context"
+	"github.com/mikestefanello/pagoda/pkg/form"
+	"github.com/mikestefanello/pagoda/pkg/log"
+	"github.com/mikestefanello/pagoda/pkg/middleware"
+	"github.com/mikestefanello/pagoda/pkg/msg"
+	"github.com/mikestefanello/pagoda/pkg/page"
+	"github.com/mikestefanello/pagoda/pkg/redirect"
+	"github.com/mikestefanello/pagoda/pkg/services"
+	"github.com/mikestefanello/pagoda/templates"
+)
+
+const (
+	routeNameOrganizations            = "organizations"
+	routeNameOrganizationsSubmit      = "organizations.submit"
+	routeNameOrganizationsSwitch      = "organizations.switch"
+	routeNameOrganizationMembers      = "organization.members"
+	routeNameOrganizationInvite       = "organization.invite"
+	routeNameOrganizationMemberRemove = "organization.members.remove"
+	routeNameOrganizationInviteRevoke = "organization.invitations.revoke"
+	routeNameInvitation               = "invitation"
+	routeNameInvitationAccept         = "invitation.accept"
+)
+
+type (
+	Organizations struct {
+		audit *services.AuditLogger
+		mail  *services.MailClient
+		orgs  *services.OrganizationClient
+		*services.TemplateRenderer
+	}
+
+	organizationForm struct {
+		Name string `form:"name" validate:"required,max=100"`
+		form.Submission
+	}
+
+	organizationMembersData struct {
+		Members     []*ent.Membership
+		Invitations []*ent.Invitation
+
+		// CanManage indicates if the authenticated user can invite and remove members
+		CanManage bool
+
+		// Role stores the role of the authenticated user in the organization
+		Role membership.Role
+	}
+
+	inviteForm struct {
+		Email string `form:"email" validate:"required,email"`
+		Role  string `form:"role" validate:"required,oneof=owner admin member"`
+		form.Submission
+	}
+
+	invitationData struct {
+		Invitation *ent.Invitation
+		Token      string
+	}
+)
+
+func init() {
+	Register(new(Organizations))
+}
+
+func (h *Organizations) Init(c *services.Container) error {
+	h.TemplateRenderer = c.TemplateRenderer
+	h.audit = c.Audit
+	h.mail = c.Mail
+	h.orgs = c.Organizations
+	return nil
+}
+
+func (h *Organizations) Routes(g *echo.Group) {
+	// The invitation link may be opened before logging in, so authentication is only required to accept it
+	g.GET("/invitations/:invitation/:token", h.Invitation).Name = routeNameInvitation
+	g.POST("/invitations/:invitation/:token", h.InvitationAccept,
+		middleware.RequireAuthentication(),
+		middleware.RequireNoImpersonation(),
+	).Name = routeNameInvitationAccept
+
+	// Organizations send invitations on behalf of their members, so only verified users can use them
+	orgs := g.Group("/organizations",
+		middleware.RequireAuthentication(),
+		middleware.RequireNoAccessToken(),
+		middleware.RequireVerifiedEmail(routeNameVerifyEmailNotice),
+	)
+	orgs.GET("", h.Page).Name = routeNameOrganizations
+	orgs.POST("", h.Submit).Name = routeNameOrganizationsSubmit
+	orgs.POST("/:organization/switch", h.Switch).Name = routeNameOrganizationsSwitch
+
+	members := orgs.Group("/members", middleware.RequireOrganization(routeNameOrganizations))
+	members.GET("", h.Members).Name = routeNameOrganizationMembers
+
+	manage := members.Group("", middleware.RequireOrganizationRole(membership.RoleOwner, membership.RoleAdmin))
+	manage.POST("/invite", h.Invite).Name = routeNameOrganizationInvite
+	manage.POST("/:membership/remove", h.Remove).Name = routeNameOrganizationMemberRemove
+	manage.POST("/invitations/:invitation/revoke", h.Revoke).Name = routeNameOrganizationInviteRevoke
+}
+
+func (h *Organizations) Page(ctx echo.Context) error {
+	p := page.New(ctx)
+	p.Layout = templates.LayoutMain
+	p.Name = templates.PageOrganizations
+	p.Title = "Organizations"
+	p.Form = form.Get[organizationForm](ctx)
+
+	return h.RenderPage(ctx, p)
+}
+
+func (h *Organizations) Submit(ctx echo.Context) error {
+	var input organizationForm
+
+	err := form.Submit(ctx, &input)
+
+	switch err.(type) {
+	case nil:
+	case validator.ValidationErrors:
+		return h.Page(ctx)
+	default:
+		return err
+	}
+
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+	org, err := h.orgs.Create(ctx.Request().Context(), usr.ID, input.Name)
+	if err != nil {
+		return fail(err, "unable to create organization")
+	}
+
+	log.Ctx(ctx).Info("organization created",
+		"organization_id", org.ID,
+	)
+
+	h.audit.Event(services.AuditActionOrganizationCreated).
+		With("organization_id", org.ID).
+		Save(ctx)
+
+	// Start working in the new organization
+	if err = h.orgs.SetCurrent(ctx, org.ID); err != nil {
+		return fail(err, "unable to switch organization")
+	}
+
+	msg.Success(ctx, fmt.Sprintf("The organization <strong>%s</strong> was created.", html.EscapeString(org.Name)))
+
+	return redirect.New(ctx).
+		Route(routeNameOrganizationMembers).
+		Go()
+}
+
+func (h *Organizations) Switch(ctx echo.Context) error {
+	orgID, err := strconv.Atoi(ctx.Param("organization"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	// Users can only switch to organizations they are a member of
+	memberships, _ := ctx.Get(context.MembershipsKey).([]*ent.Membership)
+	var org *ent.Organization
+	for _, m := range memberships {
+		if m.Edges.Organization.ID == orgID {
+			org = m.Edges.Organization
+			break
+		}
+	}
+
+	if org == nil {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	if err = h.orgs.SetCurrent(ctx, org.ID); err != nil {
+		return fail(err, "unable to switch organization")
+	}
+
+	msg.Info(ctx, fmt.Sprintf("You are now working in <strong>%s</strong>.", html.EscapeString(org.Name)))
+
+	return redirect.New(ctx).
+		Route(routeNameHome).
+		Go()
+}
+
+func (h *Organizations) Members(ctx echo.Context) error {
+	org := ctx.Get(context.OrganizationKey).(*ent.Organization)
+	current := ctx.Get(context.MembershipKey).(*ent.Membership)
+
+	members, err := h.orgs.GetMembers(ctx.Request().Context(), org.ID)
+	if err != nil {
+		return fail(err, "unable to load members")
+	}
+
+	data := organizationMembersData{
+		Members:   members,
+		CanManage: services.CanManageMembers(current.Role),
+		Role:      current.Role,
+	}
+
+	if data.CanManage {
+		data.Invitations, err = h.orgs.GetInvitations(ctx.Request().Context(), org.ID)
+		if err != nil {
+			return fail(err, "unable to load invitations")
+		}
+	}
+
+	p := page.New(ctx)
+	p.Layout = templates.LayoutMain
+	p.Name = templates.PageOrganizationMembers
+	p.Title = org.Name
+	p.Form = form.Get[inviteForm](ctx)
+	p.Data = data
+
+	return h.RenderPage(ctx, p)
+}
+
+func (h *Organizations) Invite(ctx echo.Context) error {
+	var input inviteForm
+
+	err := form.Submit(ctx, &input)
+
+	switch err.(type) {
+	case nil:
+	case validator.ValidationErrors:
+		return h.Members(ctx)
+	default:
+		return err
+	}
+
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+	org := ctx.Get(context.OrganizationKey).(*ent.Organization)
+	current := ctx.Get(context.MembershipKey).(*ent.Membership)
+	role := membership.Role(input.Role)
+
+	if !services.CanAssignRole(current.Role, role) {
+		input.SetFieldError("Role", "You are not allowed to invite members with this role.")
+		return h.Members(ctx)
+	}
+
+	token, inv, err := h.orgs.Invite(ctx.Request().Context(), org.ID, usr.ID, input.Email, role)
+
+	switch err.(type) {
+	case nil:
+	case services.AlreadyMemberError:
+		input.SetFieldError("Email", "This person is already a member of the organization.")
+		return h.Members(ctx)
+	default:
+		return fail(err, "unable to create invitation")
+	}
+
+	log.Ctx(ctx).Info("member invited",
+		"organization_id", org.ID,
+		"invitation_id", inv.ID,
+	)
+
+	h.audit.Event(services.AuditActionMemberInvited).
+		With("organization_id", org.ID).
+		With("email", inv.Email).
+		With("role", inv.Role).
+		Save(ctx)
+
+	url := ctx.Echo().Reverse(routeNameInvitation, inv.ID, token)
+	err = h.mail.
+		Compose().
+		To(inv.Email).
+		Category(services.MailCategoryInvitations).
+		Subject(fmt.Sprintf("You have been invited to join %s", org.Name)).
+		Body(fmt.Sprintf(
+			"%s invited you to join %s. Click here to accept the invitation: %s",
+			usr.Name,
+			org.Name,
+			url,
+		)).
+		Send(ctx)
+
+	if err != nil {
+		return fail(err, "unable to send invitation email")
+	}
+
+	msg.Success(ctx, fmt.Sprintf("An invitation was sent to <strong>%s</strong>.", html.EscapeString(inv.Email)))
+	form.Clear(ctx)
+
+	return redirect.New(ctx).
+		Route(routeNameOrganizationMembers).
+		Go()
+}
+
+func (h *Organizations) Remove(ctx echo.Context) error {
+	membershipID, err := strconv.Atoi(ctx.Param("membership"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	org := ctx.Get(context.OrganizationKey).(*ent.Organization)
+	current := ctx.Get(context.MembershipKey).(*ent.Membership)
+
+	m, err := h.orgs.GetMember(ctx.Request().Context(), org.ID, membershipID)
+	switch {
+	case err == nil:
+	case ent.IsNotFound(err):
+		return echo.NewHTTPError(http.StatusNotFound)
+	default:
+		return fail(err, "unable to load member")
+	}
+
+	if !services.CanAssignRole(current.Role, m.Role) {
+		return echo.NewHTTPError(http.StatusForbidden)
+	}
+
+	err = h.orgs.RemoveMember(ctx.Request().Context(), org.ID, m)
+
+	switch err.(type) {
+	case nil:
+	case services.LastOwnerError:
+		msg.Warning(ctx, "The only owner of an organization cannot be removed.")
+		return redirect.New(ctx).
+			Route(routeNameOrganizationMembers).
+			Go()
+	default:
+		return fail(err, "unable to remove member")
+	}
+
+	userID, err := m.QueryUser().OnlyID(ctx.Request().Context())
+	if err != nil {
+		return fail(err, "unable to load removed member")
+	}
+
+	log.Ctx(ctx).Info("member removed",
+		"organization_id", org.ID,
+		"membership_id", m.ID,
+	)
+
+	h.audit.Event(services.AuditActionMemberRemoved).
+		Target(userID).
+		With("organization_id", org.ID).
+		Save(ctx)
+
+	msg.Success(ctx, "The member was removed.")
+
+	return redirect.New(ctx).
+		Route(routeNameOrganizationMembers).
+		Go()
+}
+
+func (h *Organizations) Revoke(ctx echo.Context) error {
+	invitationID, err := strconv.Atoi(ctx.Param("invitation"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	org := ctx.Get(context.OrganizationKey).(*ent.Organization)
+	err = h.orgs.RevokeInvitation(ctx.Request().Context(), org.ID, invitationID)
+
+	switch err.(type) {
+	case nil:
+	case services.InvalidInvitationError:
+		return echo.NewHTTPError(http.StatusNotFound)
+	default:
+		return fail(err, "unable to revoke invitation")
+	}
+
+	h.audit.Event(services.AuditActionInvitationRevoked).
+		With("organization_id", org.ID).
+		With("invitation_id", invitationID).
+		Save(ctx)
+
+	msg.Success(ctx, "The invitation was revoked.")
+
+	return redirect.New(ctx).
+		Route(routeNameOrganizationMembers).
+		Go()
+}
+
+func (h *Organizations) Invitation(ctx echo.Context) error {
+	inv, err := h.getInvitation(ctx)
+
+	switch err.(type) {
+	case nil:
+	case services.InvalidInvitationError:
+		return h.invalidInvitation(ctx)
+	default:
+		return fail(err, "unable to load invitation")
+	}
+
+	p := page.New(ctx)
+	p.Layout = templates.LayoutMain
+	p.Name = templates.PageInvitation
+	p.Title = "Invitation"
+	p.Data = invitationData{
+		Invitation: inv,
+		Token:      ctx.Param("token"),
+	}
+
+	return h.RenderPage(ctx, p)
+}
+
+func (h *Organizations) InvitationAccept(ctx echo.Context) error {
+	inv, err := h.getInvitation(ctx)
+
+	switch err.(type) {
+	case nil:
+	case services.InvalidInvitationError:
+		return h.invalidInvitation(ctx)
+	default:
+		return fail(err, "unable to load invitation")
+	}
+
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+	m, err := h.orgs.AcceptInvitation(ctx.Request().Context(), inv, usr)
+
+	switch err.(type) {
+	case nil:
+	case services.InvalidInvitationError:
+		msg.Warning(ctx, fmt.Sprintf("This invitation was sent to <strong>%s</strong>. Log in with that email address to accept it.", html.EscapeString(inv.Email)))
+		return redirect.New(ctx).
+			Route(routeNameInvitation).
+			Params(inv.ID, ctx.Param("token")).
+			Go()
+	default:
+		return fail(err, "unable to accept invitation")
+	}
+
+	org := inv.Edges.Organization
+
+	log.Ctx(ctx).Info("invitation accepted",
+		"organization_id", org.ID,
+		"membership_id", m.ID,
+	)
+
+	h.audit.Event(services.AuditActionInvitationAccepted).
+		With("organization_id", org.ID).
+		With("role", m.Role).
+		Save(ctx)
+
+	if err = h.orgs.SetCurrent(ctx, org.ID); err != nil {
+		return fail(err, "unable to switch organization")
+	}
+
+	msg.Success(ctx, fmt.Sprintf("You joined <strong>%s</strong>.", html.EscapeString(org.Name)))
+
+	return redirect.New(ctx).
+		Route(routeNameOrganizationMembers).
+		Go()
+}
+
+// getInvitation loads the valid invitation matching the ID and token provided as path parameters
+func (h *Organizations) getInvitation(ctx echo.Context) (*ent.Invitation, error) {
+	invitationID, err := strconv.Atoi(ctx.Param("invitation"))
+	if err != nil {
+		return nil, services.InvalidInvitationError{}
+	}
+
+	return h.orgs.GetValidInvitation(ctx.Request().Context(), invitationID, ctx.Param("token"))
+}
+
+// invalidInvitation redirects home with a message explaining that the invitation is not valid
+func (h *Organizations) invalidInvitation(ctx echo.Context) error {
+	msg.Warning(ctx, "The invitation is either invalid or has expired.")
+	return redirect.New(ctx).
+		Route(routeNameHome).
+		Go()
+}
+
+// Roles returns the roles that can be selected when inviting a member
+func (f *inviteForm) Roles() []string {
+	return []string{
+		string(membership.RoleMember),
+		string(membership.RoleAdmin),
+		string(membership.RoleOwner),
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/mikestefanello/pagoda/ent/membership"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizations__Create(t *testing.T) {
+	request(t).
+		setRoute(routeNameOrganizations).
+		get().
+		assertStatusCode(http.StatusUnauthorized)
+
+	req, usr := login(t, false)
+
+	// Members cannot be viewed without an organization
+	resp := request(t).setClient(req.client).
+		setRoute(routeNameOrganizationMembers).
+		get().
+		assertStatusCode(http.StatusOK)
+	assert.Equal(t, c.Web.Reverse(routeNameOrganizations), resp.Request.URL.Path)
+
+	doc := req.setRoute(routeNameOrganizationsSubmit).
+		setBody(url.Values{
+			"name": []string{"Acme"},
+		}).
+		post().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Contains(t, doc.Find("h1.title").Text(), "Acme")
+	assert.Contains(t, doc.Find("tbody").Text(), usr.Email)
+
+	memberships, err := c.Organizations.GetMemberships(context.Background(), usr.ID)
+	require.NoError(t, err)
+	require.Len(t, memberships, 1)
+	assert.Equal(t, membership.RoleOwner, memberships[0].Role)
+}
+
+func TestOrganizations__RequireVerifiedEmail(t *testing.T) {
+	req, usr := login(t, false)
+	doc := req.setRoute(routeNameOrganizations).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	require.NoError(t, usr.Update().SetVerified(false).Exec(context.Background()))
+
+	// Unverified users cannot create organizations
+	resp, err := req.client.PostForm(srv.URL+c.Web.Reverse(routeNameOrganizationsSubmit), url.Values{
+		"name": []string{"Unverified"},
+		"csrf": []string{csrfToken(doc)},
+	})
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, c.Web.Reverse(routeNameVerifyEmailNotice), resp.Request.URL.Path)
+
+	memberships, err := c.Organizations.GetMemberships(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.Empty(t, memberships)
+}
+
+func TestOrganizations__Invitation(t *testing.T) {
+	ctx := context.Background()
+	ownerReq, owner := login(t, false)
+	org, err := c.Organizations.Create(ctx, owner.ID, "Invites")
+	require.NoError(t, err)
+
+	// Invite from the members page
+	doc := ownerReq.setRoute(routeNameOrganizationMembers).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	resp, err := ownerReq.client.PostForm(srv.URL+c.Web.Reverse(routeNameOrganizationInvite), url.Values{
+		"csrf":  []string{csrfToken(doc)},
+		"email": []string{"invited@example.com"},
+		"role":  []string{"member"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	invitations, err := c.Organizations.GetInvitations(ctx, org.ID)
+	require.NoError(t, err)
+	require.Len(t, invitations, 1)
+	assert.Equal(t, "invited@example.com", invitations[0].Email)
+
+	// Accept an invitation as the invited user
+	req, usr := login(t, false)
+	token, inv, err := c.Organizations.Invite(ctx, org.ID, owner.ID, usr.Email, membership.RoleMember)
+	require.NoError(t, err)
+
+	resp = req.setRoute(routeNameInvitationAccept, inv.ID, token).
+		setBody(url.Values{}).
+		post().
+		assertStatusCode(http.StatusOK).
+		Response
+	assert.Equal(t, c.Web.Reverse(routeNameOrganizationMembers), resp.Request.URL.Path)
+
+	memberships, err := c.Organizations.GetMemberships(ctx, usr.ID)
+	require.NoError(t, err)
+	require.Len(t, memberships, 1)
+	assert.Equal(t, org.ID, memberships[0].Edges.Organization.ID)
+
+	// Members cannot invite others
+	doc = request(t).setClient(req.client).
+		setRoute(routeNameOrganizationMembers).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Zero(t, doc.Find("#organization-invite").Length())
+	resp, err = req.client.PostForm(srv.URL+c.Web.Reverse(routeNameOrganizationInvite), url.Values{
+		"csrf":  []string{csrfToken(doc)},
+		"email": []string{"other@example.com"},
+		"role":  []string{"member"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// The invitation cannot be used again
+	resp = request(t).
+		setRoute(routeNameInvitation, inv.ID, token).
+		get().
+		assertStatusCode(http.StatusOK).
+		Response
+	assert.Equal(t, "/", resp.Request.URL.Path)
+}
@@ -4,12 +4,23 @@ import (
 	"net/http"
 
 	"github.com/gorilla/sessions"
+	"github.com/labstack/echo/v4"
 	echomw "github.com/labstack/echo/v4/middleware"
 	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/pkg/context"
 	"github.com/mikestefanello/pagoda/pkg/middleware"
 	"github.com/mikestefanello/pagoda/pkg/services"
 )
 
+// csrfExempt stores the routes which do not require a CSRF token, keyed by method and path
+var csrfExempt = make(map[string]bool)
+
+// exemptFromCSRF exempts a given route from CSRF protection, which should only be done for routes which are requested
+// by other parties and are protected by other means, such as a token within the URL
+func exemptFromCSRF(r *echo.Route) {
+	csrfExempt[r.Method+" "+r.Path] = true
+}
+
 // BuildRouter builds the router
 func BuildRouter(c *services.Container) error {
 	// Static files with proper cache control
@@ -39,11 +50,19 @@ func BuildRouter(c *services.Container) error {
 		echomw.TimeoutWithConfig(echomw.TimeoutConfig{
 			Timeout: c.Config.App.Timeout,
 		}),
-		middleware.Session(sessions.NewCookieStore([]byte(c.Config.App.EncryptionKey))),
+		middleware.Session(sessions.NewCookieStore(c.Keyring.SessionKeys()...)),
+		middleware.BotProtection(c.BotProtection),
 		middleware.LoadAuthenticatedUser(c.Auth),
+		middleware.LoadAccessToken(c.Auth),
+		middleware.LoadPermissions(c.Authz),
+		middleware.LoadOrganization(c.Organizations),
 		middleware.ServeCachedPage(c.TemplateRenderer),
 		echomw.CSRFWithConfig(echomw.CSRFConfig{
 			TokenLookup: "form:csrf",
+			// Requests authenticated with an access token do not rely on cookies, so they are not vulnerable to CSRF
+			Skipper: func(ctx echo.Context) bool {
+				return ctx.Get(context.AccessTokenKey) != nil || csrfExempt[ctx.Request().Method+" "+ctx.Path()]
+			},
 		}),
 	)
 
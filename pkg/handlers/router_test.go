@@ -6,9 +6,11 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strconv"
 	"testing"
 
 	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/pkg/bot"
 	"github.com/mikestefanello/pagoda/pkg/services"
 
 	"github.com/PuerkitoBio/goquery"
@@ -29,6 +31,10 @@ func TestMain(m *testing.M) {
 	// Start a new container
 	c = services.NewContainer()
 
+	// Allow forms to be submitted immediately and keep proof-of-work challenges quick to solve
+	c.Config.App.BotProtection.MinFillTime = 0
+	c.Config.App.BotProtection.Difficulty = 4
+
 	// Start a test HTTP server
 	if err := BuildRouter(c); err != nil {
 		panic(err)
@@ -73,7 +79,7 @@ func (h *httpRequest) setClient(client http.Client) *httpRequest {
 }
 
 func (h *httpRequest) setRoute(route string, params ...any) *httpRequest {
-	h.route = srv.URL + c.Web.Reverse(route, params)
+	h.route = srv.URL + c.Web.Reverse(route, params...)
 	return h
 }
 
@@ -104,6 +110,14 @@ func (h *httpRequest) post() *httpResponse {
 	assert.True(h.t, exists)
 	h.body["csrf"] = []string{token}
 
+	// Include the bot protection token and the proof-of-work solution, if the form requires them
+	if token, exists := doc.Find(`input[name="bot-token"]`).First().Attr("value"); exists {
+		h.body.Set("bot-token", token)
+	}
+	if pow := doc.Find(`input[name="bot-solution"]`).First(); pow.Length() > 0 {
+		h.body.Set("bot-solution", solveProofOfWork(h.t, pow))
+	}
+
 	// Make the POST requests
 	resp, err := h.client.PostForm(h.route, h.body)
 	require.NoError(h.t, err)
@@ -136,3 +150,22 @@ func (h *httpResponse) toDoc() *goquery.Document {
 	assert.NoError(h.t, err)
 	return doc
 }
+
+// solveProofOfWork solves the proof-of-work challenge of a given bot protection input
+func solveProofOfWork(t *testing.T, input *goquery.Selection) string {
+	challenge, _ := input.Attr("data-pow-challenge")
+	difficulty, err := strconv.Atoi(input.AttrOr("data-pow-difficulty", ""))
+	require.NoError(t, err)
+
+	for i := 0; ; i++ {
+		if solution := strconv.Itoa(i); bot.VerifyProofOfWork(challenge, solution, difficulty) {
+			return solution
+		}
+	}
+}
+
+// csrfToken extracts the CSRF token from a given document
+func csrfToken(doc *goquery.Document) string {
+	token, _ := doc.Find(`input[name="csrf"]`).First().Attr("value")
+	return token
+}
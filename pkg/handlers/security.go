@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/pkg/log"
+	"github.com/mikestefanello/pagoda/pkg/msg"
+	"github.com/mikestefanello/pagoda/pkg/page"
+	"github.com/mikestefanello/pagoda/pkg/redirect"
+	"github.com/mikestefanello/pagoda/pkg/services"
+	"github.com/mikestefanello/pagoda/templates"
+)
+
+const (
+	routeNameSecurityRevoke       = "security.revoke"
+	routeNameSecurityRevokeSubmit = "security.revoke.submit"
+)
+
+type (
+	// Security handles the "this wasn't me" link within security notifications, which logs out every device and
+	// starts a password reset, using the token within the link, so logging in is not required
+	Security struct {
+		audit *services.AuditLogger
+		auth  *services.AuthClient
+		*services.TemplateRenderer
+	}
+
+	securityRevokeData struct {
+		Token        string
+		Email        string
+		RestoreEmail string
+	}
+)
+
+func init() {
+	Register(new(Security))
+}
+
+func (h *Security) Init(c *services.Container) error {
+	h.TemplateRenderer = c.TemplateRenderer
+	h.audit = c.Audit
+	h.auth = c.Auth
+	return nil
+}
+
+func (h *Security) Routes(g *echo.Group) {
+	g.GET("/security/revoke/:token", h.RevokePage).Name = routeNameSecurityRevoke
+	g.POST("/security/revoke/:token", h.RevokeSubmit).Name = routeNameSecurityRevokeSubmit
+}
+
+// RevokePage asks for confirmation before anything is changed, so links opened by link scanners do not log anyone
+// out
+func (h *Security) RevokePage(ctx echo.Context) error {
+	usr, restore, err := h.auth.ValidateRevokeToken(ctx.Request().Context(), ctx.Param("token"))
+
+	switch err.(type) {
+	case nil:
+	case services.InvalidRevokeTokenError:
+		return h.invalid(ctx)
+	default:
+		return fail(err, "unable to validate revoke token")
+	}
+
+	p := page.New(ctx)
+	p.Layout = templates.LayoutAuth
+	p.Name = templates.PageSecureAccount
+	p.Title = "Secure your account"
+	p.Data = securityRevokeData{
+		Token:        ctx.Param("token"),
+		Email:        usr.Email,
+		RestoreEmail: restore,
+	}
+
+	return h.RenderPage(ctx, p)
+}
+
+func (h *Security) RevokeSubmit(ctx echo.Context) error {
+	usr, restore, err := h.auth.ValidateRevokeToken(ctx.Request().Context(), ctx.Param("token"))
+
+	switch err.(type) {
+	case nil:
+	case services.InvalidRevokeTokenError:
+		return h.invalid(ctx)
+	default:
+		return fail(err, "unable to validate revoke token")
+	}
+
+	err = h.auth.RevokeSessions(ctx.Request().Context(), usr.ID, restore)
+
+	restored := restore != ""
+	switch err.(type) {
+	case nil:
+	case services.EmailRestoreConflictError:
+		// The sessions were still revoked, but the previous email address now belongs to another account, so it
+		// cannot be restored without help
+		restored = false
+		log.Ctx(ctx).Warn("unable to restore email address after revoking sessions",
+			"user_id", usr.ID,
+			"error", err,
+		)
+	default:
+		return fail(err, "unable to revoke sessions")
+	}
+
+	log.Ctx(ctx).Info("sessions revoked",
+		"user_id", usr.ID,
+	)
+
+	event := h.audit.Event(services.AuditActionSessionsRevoked).
+		Actor(usr.ID).
+		Target(usr.ID)
+	switch {
+	case restored:
+		event.With("email", usr.Email).With("restored_email", restore)
+	case restore != "":
+		event.With("email", usr.Email).With("unrestored_email", restore)
+	}
+	event.Save(ctx)
+
+	// This browser may also be logged in, and passwords can only be reset while logged out
+	if err = h.auth.Logout(ctx); err != nil {
+		return fail(err, "unable to log out")
+	}
+
+	// Holding the link proves ownership of the email address, just like a password reset link would
+	token, pt, err := h.auth.GeneratePasswordResetToken(ctx, usr.ID)
+	if err != nil {
+		return fail(err, "unable to generate password reset token")
+	}
+
+	h.audit.Event(services.AuditActionPasswordResetRequested).
+		Target(usr.ID).
+		Save(ctx)
+
+	msg.Success(ctx, "Every device has been logged out of your account. Choose a new password to secure it.")
+	if restore != "" && !restored {
+		msg.Danger(ctx, "Your previous email address could not be restored since it is now used by another account. Please contact support.")
+	}
+
+	return redirect.New(ctx).
+		Route(routeNameResetPassword).
+		Params(usr.ID, pt.ID, token).
+		Go()
+}
+
+// invalid redirects to the home page when the link is invalid, has expired or was already used
+func (h *Security) invalid(ctx echo.Context) error {
+	msg.Warning(ctx, "The link is either invalid, has expired, or has already been used.")
+	return redirect.New(ctx).
+		Route(routeNameHome).
+		Go()
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mikestefanello/pagoda/ent/passwordtoken"
+	"github.com/mikestefanello/pagoda/ent/user"
+	"github.com/mikestefanello/pagoda/pkg/services"
+	"github.com/mikestefanello/pagoda/pkg/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurity__NewDevice(t *testing.T) {
+	inbox := c.Mail.Inbox()
+	inbox.Clear()
+
+	// The first device is not notified
+	_, usr := login(t, false)
+	assert.Empty(t, inbox.MessagesTo(usr.Email))
+
+	// Another device is
+	request(t).
+		setRoute(routeNameLoginSubmit).
+		setBody(url.Values{
+			"email":    []string{usr.Email},
+			"password": []string{"password"},
+		}).
+		post().
+		assertStatusCode(http.StatusOK)
+
+	msgs := inbox.MessagesTo(usr.Email)
+	require.Len(t, msgs, 1)
+	content, err := msgs[0].Content()
+	require.NoError(t, err)
+	assert.Equal(t, "New login to your account", content.Subject)
+	assert.Contains(t, content.HTML, "/security/revoke/")
+}
+
+func TestSecurity__Revoke(t *testing.T) {
+	ctx := context.Background()
+	loggedIn, usr := login(t, false)
+
+	token, err := c.Auth.GenerateRevokeToken(usr, "")
+	require.NoError(t, err)
+
+	// Personal access tokens created before revoking stop working
+	pat, _, err := c.Auth.GeneratePersonalAccessToken(ctx, usr.ID, "test", []string{services.AccessTokenScopeRead}, nil)
+	require.NoError(t, err)
+	bearer := func() int {
+		r, err := http.NewRequest(http.MethodGet, srv.URL+c.Web.Reverse(routeNameAPIUser), nil)
+		require.NoError(t, err)
+		r.Header.Set("Authorization", "Bearer "+pat)
+		resp, err := http.DefaultClient.Do(r)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+	require.Equal(t, http.StatusOK, bearer())
+
+	doc := request(t).
+		setRoute(routeNameSecurityRevoke, "invalid").
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, 0, doc.Find("#secure-account").Length())
+
+	// Nothing is changed until the form is submitted
+	doc = request(t).
+		setRoute(routeNameSecurityRevoke, token).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, 1, doc.Find("#secure-account").Length())
+	loggedIn.setRoute(routeNameAccount).
+		get().
+		assertStatusCode(http.StatusOK)
+
+	// Submitting logs out every device and starts a password reset
+	resp := request(t).
+		setRoute(routeNameSecurityRevokeSubmit, token).
+		post().
+		assertStatusCode(http.StatusOK)
+	assert.True(t, strings.HasPrefix(resp.Request.URL.Path, "/user/password/reset/token/"))
+	require.NoError(t, resp.Body.Close())
+
+	loggedIn.setRoute(routeNameAccount).
+		get().
+		assertStatusCode(http.StatusUnauthorized)
+	assert.Equal(t, http.StatusUnauthorized, bearer())
+
+	tokens, err := c.ORM.PasswordToken.
+		Query().
+		Where(passwordtoken.HasUserWith(user.ID(usr.ID))).
+		Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, tokens)
+
+	// The link can only be used once
+	doc = request(t).
+		setRoute(routeNameSecurityRevoke, token).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Equal(t, 0, doc.Find("#secure-account").Length())
+}
+
+func TestSecurity__RevokeEmailChange(t *testing.T) {
+	ctx := context.Background()
+	_, usr := login(t, false)
+	previous := usr.Email
+
+	// Someone changed the email address to their own
+	usr, err := usr.Update().SetEmail("taken-over@localhost").Save(ctx)
+	require.NoError(t, err)
+	token, err := c.Auth.GenerateRevokeToken(usr, previous)
+	require.NoError(t, err)
+
+	doc := request(t).
+		setRoute(routeNameSecurityRevoke, token).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.Contains(t, doc.Find("#secure-account").Text(), previous)
+
+	// Securing the account changes it back
+	resp := request(t).
+		setRoute(routeNameSecurityRevokeSubmit, token).
+		post().
+		assertStatusCode(http.StatusOK)
+	assert.True(t, strings.HasPrefix(resp.Request.URL.Path, "/user/password/reset/token/"))
+	require.NoError(t, resp.Body.Close())
+
+	usr, err = c.ORM.User.Get(ctx, usr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, previous, usr.Email)
+	assert.True(t, usr.Verified)
+}
+
+func TestSecurity__RevokeEmailChangeConflict(t *testing.T) {
+	ctx := context.Background()
+	loggedIn, usr := login(t, false)
+	previous := usr.Email
+
+	// Someone changed the email address to their own and another account took the previous one
+	usr, err := usr.Update().SetEmail("taken-over-conflict@localhost").Save(ctx)
+	require.NoError(t, err)
+	token, err := c.Auth.GenerateRevokeToken(usr, previous)
+	require.NoError(t, err)
+	other, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+	_, err = other.Update().SetEmail(previous).Save(ctx)
+	require.NoError(t, err)
+
+	// The account is still secured, but the user is told the address could not be restored
+	r := request(t).setRoute(routeNameSecurityRevokeSubmit, token)
+	resp := r.post().assertStatusCode(http.StatusOK)
+	assert.True(t, strings.HasPrefix(resp.Request.URL.Path, "/user/password/reset/token/"))
+	doc := resp.toDoc()
+	assert.Contains(t, doc.Find(".notification.is-danger").Text(), "could not be restored")
+
+	loggedIn.setRoute(routeNameAccount).
+		get().
+		assertStatusCode(http.StatusUnauthorized)
+
+	usr, err = c.ORM.User.Get(ctx, usr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "taken-over-conflict@localhost", usr.Email)
+}
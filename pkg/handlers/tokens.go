@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/pkg/context"
+	"github.com/mikestefanello/pagoda/pkg/form"
+	"github.com/mikestefanello/pagoda/pkg/log"
+	"github.com/mikestefanello/pagoda/pkg/middleware"
+	"github.com/mikestefanello/pagoda/pkg/msg"
+	"github.com/mikestefanello/pagoda/pkg/page"
+	"github.com/mikestefanello/pagoda/pkg/redirect"
+	"github.com/mikestefanello/pagoda/pkg/services"
+	"github.com/mikestefanello/pagoda/templates"
+)
+
+const (
+	routeNameTokens       = "tokens"
+	routeNameTokensSubmit = "tokens.submit"
+	routeNameTokensRevoke = "tokens.revoke"
+	routeNameAPIUser      = "api.user"
+)
+
+type (
+	Tokens struct {
+		audit *services.AuditLogger
+		auth  *services.AuthClient
+		*services.TemplateRenderer
+	}
+
+	tokensData struct {
+		Tokens []*ent.PersonalAccessToken
+
+		// Token is a newly generated token, which is only available in the request that generated it
+		Token string
+	}
+
+	tokenForm struct {
+		Name    string   `form:"name" validate:"required,max=100"`
+		Scopes  []string `form:"scopes" validate:"required,dive,oneof=read write"`
+		Expires int      `form:"expires" validate:"oneof=0 7 30 90 365"`
+		form.Submission
+	}
+
+	apiUser struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+)
+
+func init() {
+	Register(new(Tokens))
+}
+
+func (h *Tokens) Init(c *services.Container) error {
+	h.TemplateRenderer = c.TemplateRenderer
+	h.audit = c.Audit
+	h.auth = c.Auth
+	return nil
+}
+
+func (h *Tokens) Routes(g *echo.Group) {
+	tokens := g.Group("/account/tokens",
+		middleware.RequireAuthentication(),
+		middleware.RequireNoImpersonation(),
+		middleware.RequireNoAccessToken(),
+		middleware.RequireVerifiedEmail(routeNameVerifyEmailNotice),
+		middleware.RequireRecentLogin(recentLoginMaxAge),
+	)
+	tokens.GET("", h.Page).Name = routeNameTokens
+	tokens.POST("", h.Submit).Name = routeNameTokensSubmit
+	tokens.POST("/:token/revoke", h.Revoke).Name = routeNameTokensRevoke
+
+	// An example API route which can be accessed with an access token
+	g.GET("/api/user", h.User, middleware.RequireAuthentication()).Name = routeNameAPIUser
+}
+
+func (h *Tokens) Page(ctx echo.Context) error {
+	return h.render(ctx, "")
+}
+
+func (h *Tokens) Submit(ctx echo.Context) error {
+	var input tokenForm
+
+	err := form.Submit(ctx, &input)
+
+	switch err.(type) {
+	case nil:
+	case validator.ValidationErrors:
+		return h.Page(ctx)
+	default:
+		return err
+	}
+
+	var expiresAt *time.Time
+	if input.Expires > 0 {
+		t := time.Now().AddDate(0, 0, input.Expires)
+		expiresAt = &t
+	}
+
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+	token, pat, err := h.auth.GeneratePersonalAccessToken(
+		ctx.Request().Context(),
+		usr.ID,
+		input.Name,
+		input.Scopes,
+		expiresAt,
+	)
+	if err != nil {
+		return fail(err, "unable to generate access token")
+	}
+
+	log.Ctx(ctx).Info("access token generated",
+		"token_id", pat.ID,
+	)
+
+	h.audit.Event(services.AuditActionAccessTokenCreated).
+		Target(usr.ID).
+		With("token_id", pat.ID).
+		With("scopes", pat.Scopes).
+		Save(ctx)
+
+	msg.Success(ctx, "Your access token was created. Make sure to copy it now since you will not be able to see it again.")
+	form.Clear(ctx)
+
+	return h.render(ctx, token)
+}
+
+func (h *Tokens) Revoke(ctx echo.Context) error {
+	tokenID, err := strconv.Atoi(ctx.Param("token"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+	err = h.auth.RevokePersonalAccessToken(ctx.Request().Context(), usr.ID, tokenID)
+
+	switch err.(type) {
+	case nil:
+	case services.InvalidAccessTokenError:
+		return echo.NewHTTPError(http.StatusNotFound)
+	default:
+		return fail(err, "unable to revoke access token")
+	}
+
+	log.Ctx(ctx).Info("access token revoked",
+		"token_id", tokenID,
+	)
+
+	h.audit.Event(services.AuditActionAccessTokenRevoked).
+		Target(usr.ID).
+		With("token_id", tokenID).
+		Save(ctx)
+
+	msg.Success(ctx, "The access token was revoked.")
+
+	return redirect.New(ctx).
+		Route(routeNameTokens).
+		Go()
+}
+
+func (h *Tokens) User(ctx echo.Context) error {
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+
+	return ctx.JSON(http.StatusOK, apiUser{
+		ID:    usr.ID,
+		Name:  usr.Name,
+		Email: usr.Email,
+	})
+}
+
+// render renders the token management page, including a newly generated token, if one
+func (h *Tokens) render(ctx echo.Context, token string) error {
+	usr := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+
+	tokens, err := h.auth.GetPersonalAccessTokens(ctx.Request().Context(), usr.ID)
+	if err != nil {
+		return fail(err, "unable to load access tokens")
+	}
+
+	p := page.New(ctx)
+	p.Layout = templates.LayoutMain
+	p.Name = templates.PageTokens
+	p.Title = "Access tokens"
+	p.Form = form.Get[tokenForm](ctx)
+	p.Data = tokensData{
+		Tokens: tokens,
+		Token:  token,
+	}
+
+	return h.RenderPage(ctx, p)
+}
+
+// HasScope determines if the form has a given scope selected
+func (f *tokenForm) HasScope(scope string) bool {
+	for _, s := range f.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpiresIn returns the expiration options of the form, in days, with zero meaning never
+func (f *tokenForm) ExpiresIn() []int {
+	return []int{7, 30, 90, 365, 0}
+}
+
+// ExpiresLabel returns the label of a given expiration option
+func (f *tokenForm) ExpiresLabel(days int) string {
+	if days == 0 {
+		return "Never"
+	}
+	return fmt.Sprintf("%d days", days)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokens__RequiresAuthentication(t *testing.T) {
+	request(t).
+		setRoute(routeNameTokens).
+		get().
+		assertStatusCode(http.StatusUnauthorized)
+
+	request(t).
+		setRoute(routeNameAPIUser).
+		get().
+		assertStatusCode(http.StatusUnauthorized)
+}
+
+func TestTokens__Lifecycle(t *testing.T) {
+	req, usr := login(t, false)
+
+	// Create a token, which is only shown once
+	doc := req.setRoute(routeNameTokensSubmit).
+		setBody(url.Values{
+			"name":    []string{"CLI"},
+			"scopes":  []string{"read"},
+			"expires": []string{"30"},
+		}).
+		post().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+
+	token, exists := doc.Find(`.message.is-success input[readonly]`).Attr("value")
+	require.True(t, exists)
+	assert.True(t, strings.HasPrefix(token, "pat_"))
+
+	tokens, err := c.Auth.GetPersonalAccessTokens(context.Background(), usr.ID)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "CLI", tokens[0].Name)
+	assert.NotNil(t, tokens[0].ExpiresAt)
+
+	doc = request(t).setClient(req.client).
+		setRoute(routeNameTokens).
+		get().
+		assertStatusCode(http.StatusOK).
+		toDoc()
+	assert.NotContains(t, doc.Text(), token)
+
+	// Authenticate with the token
+	bearer := func(method, route string) *http.Response {
+		r, err := http.NewRequest(method, srv.URL+route, nil)
+		require.NoError(t, err)
+		r.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(r)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := bearer(http.MethodGet, c.Web.Reverse(routeNameAPIUser))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body apiUser
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, usr.ID, body.ID)
+
+	// Tokens cannot manage tokens, and read tokens cannot write
+	resp = bearer(http.MethodGet, c.Web.Reverse(routeNameTokens))
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = bearer(http.MethodPost, c.Web.Reverse(routeNameTokensRevoke, tokens[0].ID))
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// Revoke
+	resp, err = req.client.PostForm(
+		srv.URL+c.Web.Reverse(routeNameTokensRevoke, tokens[0].ID),
+		url.Values{"csrf": []string{csrfToken(doc)}},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = bearer(http.MethodGet, c.Web.Reverse(routeNameAPIUser))
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
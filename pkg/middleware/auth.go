@@ -3,28 +3,65 @@ package middleware
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/mikestefanello/pagoda/ent"
 	"github.com/mikestefanello/pagoda/pkg/context"
 	"github.com/mikestefanello/pagoda/pkg/log"
 	"github.com/mikestefanello/pagoda/pkg/msg"
+	"github.com/mikestefanello/pagoda/pkg/redirect"
 	"github.com/mikestefanello/pagoda/pkg/services"
 
 	"github.com/labstack/echo/v4"
 )
 
-// LoadAuthenticatedUser loads the authenticated user, if one, and stores in context
+// reauthenticateRouteName is the name of the route which asks the authenticated user for their password again
+const reauthenticateRouteName = "reauthenticate"
+
+// LoadAuthenticatedUser loads the authenticated user, if one, and stores in context, along with when the user last
+// proved who they are.
+// If the user is being impersonated, the impersonating admin is also stored in context.
+// Sessions which exceeded the configured idle or absolute timeout, or were revoked, are logged out.
 func LoadAuthenticatedUser(authClient *services.AuthClient) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			u, err := authClient.GetAuthenticatedUser(c)
+			switch err := authClient.RefreshSession(c); err.(type) {
+			case nil, services.NotAuthenticatedError:
+			case services.SessionExpiredError:
+				log.Ctx(c).Info("session expired")
+				msg.Warning(c, "Your session has expired. Please log in again.")
+			default:
+				return echo.NewHTTPError(
+					http.StatusInternalServerError,
+					fmt.Sprintf("error refreshing session: %v", err),
+				)
+			}
+
+			u, impersonator, err := authClient.GetAuthenticatedUser(c)
 			switch err.(type) {
 			case *ent.NotFoundError:
 				log.Ctx(c).Warn("auth user not found")
 			case services.NotAuthenticatedError:
+			case services.SessionRevokedError:
+				log.Ctx(c).Info("session revoked")
+				msg.Warning(c, "You have been logged out. Please log in again.")
 			case nil:
 				c.Set(context.AuthenticatedUserKey, u)
+				if impersonator != nil {
+					c.Set(context.ImpersonatorKey, impersonator)
+				}
+
+				at, err := authClient.GetAuthenticatedAt(c)
+				if err != nil {
+					return echo.NewHTTPError(
+						http.StatusInternalServerError,
+						fmt.Sprintf("error loading authentication time: %v", err),
+					)
+				}
+				c.Set(context.AuthenticatedAtKey, at)
 			default:
 				return echo.NewHTTPError(
 					http.StatusInternalServerError,
@@ -37,6 +74,70 @@ func LoadAuthenticatedUser(authClient *services.AuthClient) echo.MiddlewareFunc
 	}
 }
 
+// LoadAccessToken authenticates requests which provide a personal access token in the Authorization header, using
+// the bearer scheme, and stores the token and the user owning it in context, replacing any user authenticated by the
+// session. Requests with an invalid token are rejected rather than falling back to the session, and tokens can only
+// be used for requests that their scopes allow.
+func LoadAccessToken(authClient *services.AuthClient) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			if header == "" {
+				return next(c)
+			}
+
+			scheme, token, ok := strings.Cut(header, " ")
+			if !ok || !strings.EqualFold(scheme, "bearer") {
+				return next(c)
+			}
+
+			pat, err := authClient.GetValidPersonalAccessToken(c.Request().Context(), strings.TrimSpace(token))
+			switch err.(type) {
+			case nil:
+			case services.InvalidAccessTokenError:
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid access token.")
+			default:
+				return echo.NewHTTPError(
+					http.StatusInternalServerError,
+					fmt.Sprintf("error querying for access token: %v", err),
+				)
+			}
+
+			if !authClient.AccessTokenAllows(pat, c.Request().Method) {
+				return echo.NewHTTPError(http.StatusForbidden, "The access token does not have the required scope.")
+			}
+
+			c.Set(context.AuthenticatedUserKey, pat.Edges.User)
+			c.Set(context.ImpersonatorKey, nil)
+			c.Set(context.AccessTokenKey, pat)
+
+			return next(c)
+		}
+	}
+}
+
+// LoadPermissions loads the permissions granted to the authenticated user, if one, and stores them in context.
+// This allows any amount of permission checks throughout the request without additional queries.
+// This requires that the authenticated user is loaded in to context.
+func LoadPermissions(authzClient *services.AuthzClient) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if u, ok := c.Get(context.AuthenticatedUserKey).(*ent.User); ok {
+				perms, err := authzClient.GetPermissions(c.Request().Context(), u.ID)
+				if err != nil {
+					return echo.NewHTTPError(
+						http.StatusInternalServerError,
+						fmt.Sprintf("error querying for permissions: %v", err),
+					)
+				}
+				c.Set(context.PermissionsKey, perms)
+			}
+
+			return next(c)
+		}
+	}
+}
+
 // LoadValidPasswordToken loads a valid password token entity that matches the user and token
 // provided in path parameters
 // If the token is invalid, the user will be redirected to the forgot password route
@@ -107,3 +208,131 @@ func RequireNoAuthentication() echo.MiddlewareFunc {
 		}
 	}
 }
+
+// RequirePermission requires that the authenticated user has been granted a given permission in order to proceed.
+// This requires that the permissions are loaded in to context.
+func RequirePermission(permission string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if u := c.Get(context.AuthenticatedUserKey); u == nil {
+				return echo.NewHTTPError(http.StatusUnauthorized)
+			}
+
+			if perms, ok := c.Get(context.PermissionsKey).(map[string]bool); !ok || !perms[permission] {
+				return echo.NewHTTPError(http.StatusForbidden)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RequireVerifiedEmail requires that the authenticated user has verified their email address in order to proceed.
+// Users who have not are redirected to a given route, which should explain that verification is required and allow
+// them to request a new verification email. Requests made with a personal access token cannot be redirected, so
+// they are forbidden instead.
+// This requires that the user be authenticated.
+func RequireVerifiedEmail(routeName string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			u, ok := c.Get(context.AuthenticatedUserKey).(*ent.User)
+			switch {
+			case !ok:
+				return echo.NewHTTPError(http.StatusUnauthorized)
+			case u.Verified:
+				return next(c)
+			case c.Get(context.AccessTokenKey) != nil:
+				return echo.NewHTTPError(http.StatusForbidden, "A verified email address is required.")
+			}
+
+			msg.Warning(c, "Please verify your email address to continue.")
+			return redirect.New(c).
+				Route(routeName).
+				Go()
+		}
+	}
+}
+
+// RequireRecentLogin requires that the authenticated user logged in or re-entered their password within a given
+// duration in order to proceed, which should be used for sensitive actions, such as changing the email address of
+// the account. Otherwise, the user is asked for their password and then returned to the page they were on.
+// Requests made with a personal access token cannot do so, so they are forbidden instead.
+// This requires that the user be authenticated.
+func RequireRecentLogin(maxAge time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			at, _ := c.Get(context.AuthenticatedAtKey).(time.Time)
+			switch {
+			case c.Get(context.AuthenticatedUserKey) == nil:
+				return echo.NewHTTPError(http.StatusUnauthorized)
+			case c.Get(context.AccessTokenKey) != nil:
+				return echo.NewHTTPError(http.StatusForbidden, "This action requires logging in again.")
+			case time.Since(at) <= maxAge:
+				return next(c)
+			}
+
+			msg.Info(c, "Please enter your password again to continue.")
+			return redirect.New(c).
+				Route(reauthenticateRouteName).
+				Query(url.Values{"next": []string{returnPath(c)}}).
+				Go()
+		}
+	}
+}
+
+// returnPath returns the path to return to after being redirected away from the current request.
+// That is the current path for GET requests, otherwise the page the request was submitted from, since other
+// requests, such as form submissions, cannot be repeated by a redirect.
+func returnPath(c echo.Context) string {
+	if c.Request().Method == http.MethodGet {
+		return c.Request().URL.RequestURI()
+	}
+
+	if ref, err := url.Parse(c.Request().Referer()); err == nil && ref.Host == c.Request().Host {
+		return ref.RequestURI()
+	}
+
+	return "/"
+}
+
+// RequireNoAccessToken prevents access to requests authenticated with a personal access token.
+// This should be used for actions that should only be performed by a user who logged in, such as managing tokens.
+func RequireNoAccessToken() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if t := c.Get(context.AccessTokenKey); t != nil {
+				return echo.NewHTTPError(http.StatusForbidden, "This action is not allowed with an access token.")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RequireNoImpersonation prevents access while the authenticated user is being impersonated.
+// This should be used for sensitive actions which an admin should not be able to perform on behalf of a user.
+func RequireNoImpersonation() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if u := c.Get(context.ImpersonatorKey); u != nil {
+				return echo.NewHTTPError(http.StatusForbidden, "This action is not allowed while impersonating a user.")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RequireRegistrationEnabled responds with a 404 when registration is closed, so the registration routes appear to
+// not exist
+func RequireRegistrationEnabled(registrationClient *services.RegistrationClient) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !registrationClient.IsEnabled() {
+				return echo.NewHTTPError(http.StatusNotFound)
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -3,12 +3,17 @@ package middleware
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
+	"github.com/mikestefanello/pagoda/config"
 	"github.com/mikestefanello/pagoda/ent"
 	"github.com/mikestefanello/pagoda/pkg/context"
+	"github.com/mikestefanello/pagoda/pkg/services"
 	"github.com/mikestefanello/pagoda/pkg/tests"
 
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 
 	"github.com/stretchr/testify/assert"
@@ -109,3 +114,214 @@ func TestLoadValidPasswordToken(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, pt.ID, ctxPt.ID)
 }
+
+func TestLoadPermissions(t *testing.T) {
+	ctx, _ := tests.NewContext(c.Web, "/")
+	tests.InitSession(ctx)
+	mw := LoadPermissions(c.Authz)
+
+	// Not authenticated
+	_ = tests.ExecuteMiddleware(ctx, mw)
+	assert.Nil(t, ctx.Get(context.PermissionsKey))
+
+	// Authenticated without any roles
+	ctx.Set(context.AuthenticatedUserKey, usr)
+	_ = tests.ExecuteMiddleware(ctx, mw)
+	perms, ok := ctx.Get(context.PermissionsKey).(map[string]bool)
+	require.True(t, ok)
+	assert.Empty(t, perms)
+
+	// Authenticated with a role
+	require.NoError(t, c.Authz.Seed(ctx.Request().Context()))
+	require.NoError(t, c.Authz.AssignRole(ctx.Request().Context(), usr.ID, services.RoleAdmin))
+	defer c.Authz.RevokeRole(ctx.Request().Context(), usr.ID, services.RoleAdmin)
+	_ = tests.ExecuteMiddleware(ctx, mw)
+	perms, ok = ctx.Get(context.PermissionsKey).(map[string]bool)
+	require.True(t, ok)
+	assert.True(t, perms[services.PermissionUsersEdit])
+}
+
+func TestRequirePermission(t *testing.T) {
+	ctx, _ := tests.NewContext(c.Web, "/")
+	mw := RequirePermission(services.PermissionUsersEdit)
+
+	// Not logged in
+	err := tests.ExecuteMiddleware(ctx, mw)
+	tests.AssertHTTPErrorCode(t, err, http.StatusUnauthorized)
+
+	// Logged in without the permission
+	ctx.Set(context.AuthenticatedUserKey, usr)
+	ctx.Set(context.PermissionsKey, map[string]bool{services.PermissionAdminAccess: true})
+	err = tests.ExecuteMiddleware(ctx, mw)
+	tests.AssertHTTPErrorCode(t, err, http.StatusForbidden)
+
+	// Logged in with the permission
+	ctx.Set(context.PermissionsKey, map[string]bool{services.PermissionUsersEdit: true})
+	err = tests.ExecuteMiddleware(ctx, mw)
+	assert.Nil(t, err)
+}
+
+func TestRequireNoImpersonation(t *testing.T) {
+	ctx, _ := tests.NewContext(c.Web, "/")
+
+	err := tests.ExecuteMiddleware(ctx, RequireNoImpersonation())
+	assert.Nil(t, err)
+
+	ctx.Set(context.ImpersonatorKey, usr)
+	err = tests.ExecuteMiddleware(ctx, RequireNoImpersonation())
+	tests.AssertHTTPErrorCode(t, err, http.StatusForbidden)
+}
+
+func TestLoadAccessToken(t *testing.T) {
+	ctx, _ := tests.NewContext(c.Web, "/")
+	token, _, err := c.Auth.GeneratePersonalAccessToken(
+		ctx.Request().Context(),
+		usr.ID,
+		"test",
+		[]string{services.AccessTokenScopeRead},
+		nil,
+	)
+	require.NoError(t, err)
+
+	// No token
+	err = tests.ExecuteMiddleware(ctx, LoadAccessToken(c.Auth))
+	assert.Nil(t, err)
+	assert.Nil(t, ctx.Get(context.AuthenticatedUserKey))
+
+	// Invalid token
+	ctx, _ = tests.NewContext(c.Web, "/")
+	ctx.Request().Header.Set(echo.HeaderAuthorization, "Bearer pat_invalid")
+	err = tests.ExecuteMiddleware(ctx, LoadAccessToken(c.Auth))
+	tests.AssertHTTPErrorCode(t, err, http.StatusUnauthorized)
+
+	// Valid token
+	ctx, _ = tests.NewContext(c.Web, "/")
+	ctx.Request().Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	err = tests.ExecuteMiddleware(ctx, LoadAccessToken(c.Auth))
+	assert.Nil(t, err)
+	ctxUsr, ok := ctx.Get(context.AuthenticatedUserKey).(*ent.User)
+	require.True(t, ok)
+	assert.Equal(t, usr.ID, ctxUsr.ID)
+	assert.NotNil(t, ctx.Get(context.AccessTokenKey))
+
+	// Missing scope
+	ctx, _ = tests.NewContext(c.Web, "/")
+	ctx.Request().Method = http.MethodPost
+	ctx.Request().Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	err = tests.ExecuteMiddleware(ctx, LoadAccessToken(c.Auth))
+	tests.AssertHTTPErrorCode(t, err, http.StatusForbidden)
+}
+
+func TestRequireNoAccessToken(t *testing.T) {
+	ctx, _ := tests.NewContext(c.Web, "/")
+
+	err := tests.ExecuteMiddleware(ctx, RequireNoAccessToken())
+	assert.Nil(t, err)
+
+	ctx.Set(context.AccessTokenKey, &ent.PersonalAccessToken{})
+	err = tests.ExecuteMiddleware(ctx, RequireNoAccessToken())
+	tests.AssertHTTPErrorCode(t, err, http.StatusForbidden)
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	ctx, rec := tests.NewContext(c.Web, "/")
+	tests.InitSession(ctx)
+
+	err := tests.ExecuteMiddleware(ctx, RequireVerifiedEmail("home"))
+	tests.AssertHTTPErrorCode(t, err, http.StatusUnauthorized)
+
+	// Not verified
+	ctx.Set(context.AuthenticatedUserKey, &ent.User{Verified: false})
+	err = tests.ExecuteMiddleware(ctx, RequireVerifiedEmail("home"))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusFound, rec.Code)
+
+	// Not verified, with an access token
+	ctx.Set(context.AccessTokenKey, &ent.PersonalAccessToken{})
+	err = tests.ExecuteMiddleware(ctx, RequireVerifiedEmail("home"))
+	tests.AssertHTTPErrorCode(t, err, http.StatusForbidden)
+
+	// Verified
+	ctx.Set(context.AuthenticatedUserKey, &ent.User{Verified: true})
+	err = tests.ExecuteMiddleware(ctx, RequireVerifiedEmail("home"))
+	assert.Nil(t, err)
+}
+
+func TestLoadAuthenticatedUser_SessionExpired(t *testing.T) {
+	original := c.Config.App.Session
+	t.Cleanup(func() {
+		c.Config.App.Session = original
+	})
+	c.Config.App.Session.AbsoluteTimeout = time.Nanosecond
+
+	ctx, _ := tests.NewContext(c.Web, "/")
+	tests.InitSession(ctx)
+	require.NoError(t, c.Auth.Login(ctx, usr.ID))
+	time.Sleep(time.Second)
+
+	err := tests.ExecuteMiddleware(ctx, LoadAuthenticatedUser(c.Auth))
+	require.NoError(t, err)
+	assert.Nil(t, ctx.Get(context.AuthenticatedUserKey))
+	assert.Nil(t, ctx.Get(context.AuthenticatedAtKey))
+	_, err = c.Auth.GetAuthenticatedUserID(ctx)
+	assert.Equal(t, services.NotAuthenticatedError{}, err)
+}
+
+func TestRequireRecentLogin(t *testing.T) {
+	ctx, rec := tests.NewContext(c.Web, "/account?tab=email")
+	tests.InitSession(ctx)
+	mw := RequireRecentLogin(15 * time.Minute)
+
+	err := tests.ExecuteMiddleware(ctx, mw)
+	tests.AssertHTTPErrorCode(t, err, http.StatusUnauthorized)
+
+	// Logged in recently
+	require.NoError(t, c.Auth.Login(ctx, usr.ID))
+	require.NoError(t, tests.ExecuteMiddleware(ctx, LoadAuthenticatedUser(c.Auth)))
+	require.NoError(t, tests.ExecuteMiddleware(ctx, mw))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Logged in too long ago, so the user is asked for their password and returned to this page
+	ctx.Set(context.AuthenticatedAtKey, time.Now().Add(-16*time.Minute))
+	require.NoError(t, tests.ExecuteMiddleware(ctx, mw))
+	assert.Equal(t, http.StatusFound, rec.Code)
+	loc, err := url.Parse(rec.Header().Get(echo.HeaderLocation))
+	require.NoError(t, err)
+	assert.Equal(t, "/account?tab=email", loc.Query().Get("next"))
+
+	// Access tokens cannot reauthenticate
+	ctx.Set(context.AccessTokenKey, &ent.PersonalAccessToken{})
+	err = tests.ExecuteMiddleware(ctx, mw)
+	tests.AssertHTTPErrorCode(t, err, http.StatusForbidden)
+}
+
+func TestReturnPath(t *testing.T) {
+	ctx, _ := tests.NewContext(c.Web, "/a?b=c")
+	assert.Equal(t, "/a?b=c", returnPath(ctx))
+
+	ctx.Request().Method = http.MethodPost
+	assert.Equal(t, "/", returnPath(ctx))
+
+	ctx.Request().Header.Set("Referer", "http://"+ctx.Request().Host+"/account")
+	assert.Equal(t, "/account", returnPath(ctx))
+
+	ctx.Request().Header.Set("Referer", "https://example.org/account")
+	assert.Equal(t, "/", returnPath(ctx))
+}
+
+func TestRequireRegistrationEnabled(t *testing.T) {
+	mode := c.Config.App.Registration.Mode
+	defer func() {
+		c.Config.App.Registration.Mode = mode
+	}()
+
+	ctx, _ := tests.NewContext(c.Web, "/")
+
+	c.Config.App.Registration.Mode = config.RegistrationModeInvite
+	err := tests.ExecuteMiddleware(ctx, RequireRegistrationEnabled(c.Registration))
+	assert.Nil(t, err)
+
+	c.Config.App.Registration.Mode = config.RegistrationModeClosed
+	err = tests.ExecuteMiddleware(ctx, RequireRegistrationEnabled(c.Registration))
+	tests.AssertHTTPErrorCode(t, err, http.StatusNotFound)
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/pkg/bot"
+	"github.com/mikestefanello/pagoda/pkg/context"
+)
+
+// BotProtection sets the checker used to protect forms against spam bots in the request context, which is required
+// to render and submit forms that embed form.BotProtection
+func BotProtection(checker bot.Checker) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			ctx.Set(context.BotCheckerKey, checker)
+			return next(ctx)
+		}
+	}
+}
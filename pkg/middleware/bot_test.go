@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/mikestefanello/pagoda/pkg/context"
+	"github.com/mikestefanello/pagoda/pkg/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBotProtection(t *testing.T) {
+	ctx, _ := tests.NewContext(c.Web, "/")
+	err := tests.ExecuteMiddleware(ctx, BotProtection(c.BotProtection))
+	require.NoError(t, err)
+	assert.Equal(t, c.BotProtection, ctx.Get(context.BotCheckerKey))
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/membership"
+	"github.com/mikestefanello/pagoda/pkg/context"
+	"github.com/mikestefanello/pagoda/pkg/msg"
+	"github.com/mikestefanello/pagoda/pkg/redirect"
+	"github.com/mikestefanello/pagoda/pkg/services"
+)
+
+// LoadOrganization loads the organization the authenticated user is currently working in, if one, along with
+// their membership of it, and stores them in context so requests can be scoped to it. All memberships of the user
+// are stored in context as well, which allows the user to switch organizations.
+// This requires that the authenticated user is loaded in to context.
+func LoadOrganization(orgClient *services.OrganizationClient) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			u, ok := c.Get(context.AuthenticatedUserKey).(*ent.User)
+			if !ok {
+				return next(c)
+			}
+
+			memberships, err := orgClient.GetMemberships(c.Request().Context(), u.ID)
+			if err != nil {
+				return echo.NewHTTPError(
+					http.StatusInternalServerError,
+					fmt.Sprintf("error querying for memberships: %v", err),
+				)
+			}
+			c.Set(context.MembershipsKey, memberships)
+
+			if m := orgClient.GetCurrent(c, memberships); m != nil {
+				c.Set(context.MembershipKey, m)
+				c.Set(context.OrganizationKey, m.Edges.Organization)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RequireOrganization requires that the authenticated user is working in an organization in order to proceed.
+// Users who are not a member of any organization are redirected to a given route, which should allow them to
+// create one.
+// This requires that the organization is loaded in to context.
+func RequireOrganization(routeName string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if u := c.Get(context.AuthenticatedUserKey); u == nil {
+				return echo.NewHTTPError(http.StatusUnauthorized)
+			}
+
+			if o := c.Get(context.OrganizationKey); o != nil {
+				return next(c)
+			}
+
+			msg.Info(c, "Create or join an organization to continue.")
+			return redirect.New(c).
+				Route(routeName).
+				Go()
+		}
+	}
+}
+
+// RequireOrganizationRole requires that the authenticated user has one of the given roles in the organization they
+// are currently working in in order to proceed.
+// This requires that the organization is loaded in to context.
+func RequireOrganizationRole(roles ...membership.Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if u := c.Get(context.AuthenticatedUserKey); u == nil {
+				return echo.NewHTTPError(http.StatusUnauthorized)
+			}
+
+			if m, ok := c.Get(context.MembershipKey).(*ent.Membership); ok {
+				for _, role := range roles {
+					if m.Role == role {
+						return next(c)
+					}
+				}
+			}
+
+			return echo.NewHTTPError(http.StatusForbidden)
+		}
+	}
+}
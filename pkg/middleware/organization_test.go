@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/membership"
+	pctx "github.com/mikestefanello/pagoda/pkg/context"
+	"github.com/mikestefanello/pagoda/pkg/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadOrganization(t *testing.T) {
+	ctx, _ := tests.NewContext(c.Web, "/")
+	tests.InitSession(ctx)
+	mw := LoadOrganization(c.Organizations)
+
+	// Not authenticated
+	_ = tests.ExecuteMiddleware(ctx, mw)
+	assert.Nil(t, ctx.Get(pctx.OrganizationKey))
+
+	// Not a member of any organization
+	u, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+	ctx.Set(pctx.AuthenticatedUserKey, u)
+	err = tests.ExecuteMiddleware(ctx, mw)
+	require.NoError(t, err)
+	assert.Nil(t, ctx.Get(pctx.OrganizationKey))
+	assert.Empty(t, ctx.Get(pctx.MembershipsKey))
+
+	// Member of two organizations
+	first, err := c.Organizations.Create(context.Background(), u.ID, "A")
+	require.NoError(t, err)
+	second, err := c.Organizations.Create(context.Background(), u.ID, "B")
+	require.NoError(t, err)
+
+	err = tests.ExecuteMiddleware(ctx, mw)
+	require.NoError(t, err)
+	org, ok := ctx.Get(pctx.OrganizationKey).(*ent.Organization)
+	require.True(t, ok)
+	assert.Equal(t, first.ID, org.ID)
+	assert.Len(t, ctx.Get(pctx.MembershipsKey), 2)
+	m, ok := ctx.Get(pctx.MembershipKey).(*ent.Membership)
+	require.True(t, ok)
+	assert.Equal(t, membership.RoleOwner, m.Role)
+
+	// Switched organizations
+	require.NoError(t, c.Organizations.SetCurrent(ctx, second.ID))
+	err = tests.ExecuteMiddleware(ctx, mw)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, ctx.Get(pctx.OrganizationKey).(*ent.Organization).ID)
+}
+
+func TestRequireOrganization(t *testing.T) {
+	ctx, rec := tests.NewContext(c.Web, "/")
+	tests.InitSession(ctx)
+
+	err := tests.ExecuteMiddleware(ctx, RequireOrganization("home"))
+	tests.AssertHTTPErrorCode(t, err, http.StatusUnauthorized)
+
+	// No organization
+	ctx.Set(pctx.AuthenticatedUserKey, usr)
+	err = tests.ExecuteMiddleware(ctx, RequireOrganization("home"))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusFound, rec.Code)
+
+	ctx.Set(pctx.OrganizationKey, &ent.Organization{})
+	err = tests.ExecuteMiddleware(ctx, RequireOrganization("home"))
+	assert.Nil(t, err)
+}
+
+func TestRequireOrganizationRole(t *testing.T) {
+	ctx, _ := tests.NewContext(c.Web, "/")
+	mw := RequireOrganizationRole(membership.RoleOwner, membership.RoleAdmin)
+
+	err := tests.ExecuteMiddleware(ctx, mw)
+	tests.AssertHTTPErrorCode(t, err, http.StatusUnauthorized)
+
+	// No organization
+	ctx.Set(pctx.AuthenticatedUserKey, usr)
+	err = tests.ExecuteMiddleware(ctx, mw)
+	tests.AssertHTTPErrorCode(t, err, http.StatusForbidden)
+
+	ctx.Set(pctx.MembershipKey, &ent.Membership{Role: membership.RoleMember})
+	err = tests.ExecuteMiddleware(ctx, mw)
+	tests.AssertHTTPErrorCode(t, err, http.StatusForbidden)
+
+	ctx.Set(pctx.MembershipKey, &ent.Membership{Role: membership.RoleAdmin})
+	err = tests.ExecuteMiddleware(ctx, mw)
+	assert.Nil(t, err)
+}
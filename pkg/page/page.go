@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/mikestefanello/pagoda/config"
 	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/pkg/bot"
 	"github.com/mikestefanello/pagoda/pkg/context"
 	"github.com/mikestefanello/pagoda/pkg/htmx"
 	"github.com/mikestefanello/pagoda/pkg/msg"
@@ -28,6 +30,10 @@ type Page struct {
 	// If omitted, the configuration value will be used.
 	AppName string
 
+	// RegistrationMode stores the registration mode, which determines if links to register are shown.
+	// If omitted, the configuration value will be used.
+	RegistrationMode config.RegistrationMode
+
 	// Title stores the title of the page
 	Title string
 
@@ -70,6 +76,25 @@ type Page struct {
 	// AuthUser stores the authenticated user
 	AuthUser *ent.User
 
+	// IsImpersonating stores whether the authenticated user is being impersonated by an admin
+	IsImpersonating bool
+
+	// Impersonator stores the admin impersonating the authenticated user, if one
+	Impersonator *ent.User
+
+	// Permissions stores the set of permissions granted to the authenticated user
+	Permissions map[string]bool
+
+	// Organization stores the organization the authenticated user is currently working in, if one
+	Organization *ent.Organization
+
+	// Membership stores the membership of the authenticated user in the current organization, if one
+	Membership *ent.Membership
+
+	// Memberships stores all memberships of the authenticated user, with their organizations, which allows the user
+	// to switch organizations
+	Memberships []*ent.Membership
+
 	// StatusCode stores the HTTP status code that will be returned
 	StatusCode int
 
@@ -145,11 +170,50 @@ func New(ctx echo.Context) Page {
 		p.AuthUser = u.(*ent.User)
 	}
 
+	if u := ctx.Get(context.ImpersonatorKey); u != nil {
+		p.IsImpersonating = true
+		p.Impersonator = u.(*ent.User)
+	}
+
+	if perms, ok := ctx.Get(context.PermissionsKey).(map[string]bool); ok {
+		p.Permissions = perms
+	}
+
+	if org, ok := ctx.Get(context.OrganizationKey).(*ent.Organization); ok {
+		p.Organization = org
+	}
+
+	if m, ok := ctx.Get(context.MembershipKey).(*ent.Membership); ok {
+		p.Membership = m
+	}
+
+	if m, ok := ctx.Get(context.MembershipsKey).([]*ent.Membership); ok {
+		p.Memberships = m
+	}
+
 	p.HTMX.Request = htmx.GetRequest(ctx)
 
 	return p
 }
 
+// Can determines if the authenticated user has been granted a given permission.
+// This allows for easy permission checks from the templates, ie: {{ if .Can "users.edit" }}
+func (p Page) Can(permission string) bool {
+	return p.Permissions[permission]
+}
+
+// CanRegister determines if users are allowed to register, which is not the case when registration is closed.
+// This allows the templates to hide links to register.
+func (p Page) CanRegister() bool {
+	return p.RegistrationMode != config.RegistrationModeClosed
+}
+
+// BotChallenge issues a bot protection challenge for the form on the page, if it embeds form.BotProtection.
+// This is used by the bot-protection template component to render the fields required to submit the form.
+func (p Page) BotChallenge() (*bot.Challenge, error) {
+	return bot.IssueChallenge(p.Context, p.Form)
+}
+
 // GetMessages gets all flash messages for a given type.
 // This allows for easy access to flash messages from the templates.
 func (p Page) GetMessages(typ msg.Type) []template.HTML {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/config"
 	"github.com/mikestefanello/pagoda/ent"
 	"github.com/mikestefanello/pagoda/pkg/context"
 	"github.com/mikestefanello/pagoda/pkg/msg"
@@ -26,6 +27,9 @@ func TestNew(t *testing.T) {
 	assert.Empty(t, p.Headers)
 	assert.True(t, p.IsHome)
 	assert.False(t, p.IsAuth)
+	assert.False(t, p.IsImpersonating)
+	assert.False(t, p.Can("users.edit"))
+	assert.Nil(t, p.Organization)
 	assert.Empty(t, p.CSRF)
 	assert.Empty(t, p.RequestID)
 	assert.False(t, p.Cache.Enabled)
@@ -35,6 +39,23 @@ func TestNew(t *testing.T) {
 		ID: 1,
 	}
 	ctx.Set(context.AuthenticatedUserKey, usr)
+	admin := &ent.User{
+		ID: 2,
+	}
+	ctx.Set(context.ImpersonatorKey, admin)
+	ctx.Set(context.PermissionsKey, map[string]bool{"users.edit": true})
+	org := &ent.Organization{
+		ID: 3,
+	}
+	m := &ent.Membership{
+		ID: 4,
+		Edges: ent.MembershipEdges{
+			Organization: org,
+		},
+	}
+	ctx.Set(context.OrganizationKey, org)
+	ctx.Set(context.MembershipKey, m)
+	ctx.Set(context.MembershipsKey, []*ent.Membership{m})
 	ctx.Set(echomw.DefaultCSRFConfig.ContextKey, "csrf")
 	p = New(ctx)
 	assert.Equal(t, "/abc", p.Path)
@@ -42,9 +63,24 @@ func TestNew(t *testing.T) {
 	assert.False(t, p.IsHome)
 	assert.True(t, p.IsAuth)
 	assert.Equal(t, usr, p.AuthUser)
+	assert.True(t, p.IsImpersonating)
+	assert.Equal(t, admin, p.Impersonator)
+	assert.True(t, p.Can("users.edit"))
+	assert.False(t, p.Can("admin.access"))
+	assert.Equal(t, org, p.Organization)
+	assert.Equal(t, m, p.Membership)
+	assert.Equal(t, []*ent.Membership{m}, p.Memberships)
 	assert.Equal(t, "csrf", p.CSRF)
 }
 
+func TestPage_CanRegister(t *testing.T) {
+	p := Page{RegistrationMode: config.RegistrationModeInvite}
+	assert.True(t, p.CanRegister())
+
+	p.RegistrationMode = config.RegistrationModeClosed
+	assert.False(t, p.CanRegister())
+}
+
 func TestPage_GetMessages(t *testing.T) {
 	ctx, _ := tests.NewContext(echo.New(), "/")
 	tests.InitSession(ctx)
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/dataexport"
+	"github.com/mikestefanello/pagoda/ent/device"
+	"github.com/mikestefanello/pagoda/ent/impersonation"
+	"github.com/mikestefanello/pagoda/ent/invitation"
+	"github.com/mikestefanello/pagoda/ent/logintoken"
+	"github.com/mikestefanello/pagoda/ent/membership"
+	"github.com/mikestefanello/pagoda/ent/organization"
+	"github.com/mikestefanello/pagoda/ent/passwordtoken"
+	"github.com/mikestefanello/pagoda/ent/personalaccesstoken"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+const (
+	// AccountDeletionModeDelete is the account deletion mode which deletes the user and all related entities
+	AccountDeletionModeDelete = "delete"
+
+	// AccountDeletionModeAnonymize is the account deletion mode which keeps the user, along with any records
+	// that reference it, but removes all personal data from it
+	AccountDeletionModeAnonymize = "anonymize"
+)
+
+// exportSkipEdges stores the names of the user edges which are not included in personal data exports
+var exportSkipEdges = map[string]bool{
+	// Previous exports would otherwise be nested within each export
+	"DataExports": true,
+}
+
+// InvalidDataExportError is an error returned when an invalid or expired data export token is provided
+type InvalidDataExportError struct{}
+
+// Error implements the error interface.
+func (e InvalidDataExportError) Error() string {
+	return "invalid data export"
+}
+
+// AccountClient handles self-service account operations which users are entitled to, such as exporting their
+// personal data and deleting their account
+type AccountClient struct {
+	config *config.Config
+	orm    *ent.Client
+	auth   *AuthClient
+}
+
+// NewAccountClient creates a new AccountClient
+func NewAccountClient(cfg *config.Config, orm *ent.Client, auth *AuthClient) *AccountClient {
+	return &AccountClient{
+		config: cfg,
+		orm:    orm,
+		auth:   auth,
+	}
+}
+
+// Export builds a ZIP archive containing the personal data of a given user, as JSON.
+// The archive contains a file for the user and a file for each edge of the user, which are found by walking the
+// edges of the User entity, so new entities that relate to users are included automatically as long as the User
+// schema declares the edge. Sensitive fields, such as hashes, are never included.
+func (c *AccountClient) Export(ctx context.Context, userID int) ([]byte, error) {
+	u, err := c.orm.User.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]any{
+		"user": u,
+	}
+
+	edges := reflect.TypeOf(u.Edges)
+	for i := 0; i < edges.NumField(); i++ {
+		f := edges.Field(i)
+		if !f.IsExported() || exportSkipEdges[f.Name] {
+			continue
+		}
+
+		// Each edge has a generated method to query it, ie QueryRoles()
+		query := reflect.ValueOf(u).MethodByName("Query" + f.Name)
+		if !query.IsValid() {
+			continue
+		}
+
+		out := query.Call(nil)[0].
+			MethodByName("All").
+			Call([]reflect.Value{reflect.ValueOf(ctx)})
+
+		if err, ok := out[1].Interface().(error); ok && err != nil {
+			return nil, fmt.Errorf("unable to query %s: %w", f.Name, err)
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		files[name] = out[0].Interface()
+	}
+
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+
+	for name, data := range files {
+		b, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		fw, err := w.Create(name + ".json")
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = fw.Write(b); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// CreateDataExport exports the personal data of a given user and stores it so it can be downloaded.
+// Just like password tokens, only a hash of the returned token, which is required to download the export, is stored.
+func (c *AccountClient) CreateDataExport(ctx context.Context, userID int) (string, *ent.DataExport, error) {
+	data, err := c.Export(ctx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token, err := c.auth.RandomToken(c.config.App.PasswordToken.Length)
+	if err != nil {
+		return "", nil, err
+	}
+
+	de, err := c.orm.DataExport.
+		Create().
+		SetHash(c.auth.hashToken(token)).
+		SetData(data).
+		SetUserID(userID).
+		Save(ctx)
+
+	return token, de, err
+}
+
+// GetValidDataExport returns the valid, non-expired data export of a given user matching a given token
+func (c *AccountClient) GetValidDataExport(ctx context.Context, userID int, token string) (*ent.DataExport, error) {
+	expiration := time.Now().Add(-c.config.App.DataExport.Expiration)
+
+	de, err := c.orm.DataExport.
+		Query().
+		Where(dataexport.HashIn(c.auth.tokenHashes(token)...)).
+		Where(dataexport.HasUserWith(user.ID(userID))).
+		Where(dataexport.CreatedAtGTE(expiration)).
+		Only(ctx)
+
+	switch err.(type) {
+	case nil:
+		return de, nil
+	case *ent.NotFoundError:
+		return nil, InvalidDataExportError{}
+	default:
+		return nil, err
+	}
+}
+
+// DeleteExpiredDataExports deletes all data exports which have expired and returns the amount deleted
+func (c *AccountClient) DeleteExpiredDataExports(ctx context.Context) (int, error) {
+	return c.orm.DataExport.
+		Delete().
+		Where(dataexport.CreatedAtLT(time.Now().Add(-c.config.App.DataExport.Expiration))).
+		Exec(ctx)
+}
+
+// ScheduleDeletion schedules the account of a given user to be deleted once the grace period has passed and
+// returns when that will be
+func (c *AccountClient) ScheduleDeletion(ctx context.Context, userID int) (time.Time, error) {
+	at := time.Now().Add(c.config.App.AccountDeletion.GracePeriod)
+
+	err := c.orm.User.
+		UpdateOneID(userID).
+		SetDeletionScheduledAt(at).
+		Exec(ctx)
+
+	return at, err
+}
+
+// CancelDeletion cancels the scheduled deletion of the account of a given user
+func (c *AccountClient) CancelDeletion(ctx context.Context, userID int) error {
+	return c.orm.User.
+		UpdateOneID(userID).
+		ClearDeletionScheduledAt().
+		Exec(ctx)
+}
+
+// IsDeletionDue determines if a given user's account is scheduled to be deleted and the grace period has passed
+func (c *AccountClient) IsDeletionDue(u *ent.User) bool {
+	return u.DeletionScheduledAt != nil && !u.DeletionScheduledAt.After(time.Now())
+}
+
+// Delete deletes the account of a given user, or anonymizes it, depending on the configured mode.
+// Either way, all of the user's tokens, data exports and memberships are deleted, along with any organization left
+// without members.
+func (c *AccountClient) Delete(ctx context.Context, userID int) error {
+	tx, err := c.orm.Tx(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = c.delete(ctx, tx, userID); err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			err = fmt.Errorf("%w: %v", err, rerr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteMemberships removes a given user from all organizations within a given transaction, and deletes any
+// organization which is left without members
+func (c *AccountClient) deleteMemberships(ctx context.Context, tx *ent.Tx, userID int) error {
+	if _, err := tx.Membership.Delete().Where(membership.HasUserWith(user.ID(userID))).Exec(ctx); err != nil {
+		return err
+	}
+
+	empty := organization.Not(organization.HasMemberships())
+
+	if _, err := tx.Invitation.Delete().Where(invitation.HasOrganizationWith(empty)).Exec(ctx); err != nil {
+		return err
+	}
+
+	_, err := tx.Organization.Delete().Where(empty).Exec(ctx)
+	return err
+}
+
+// delete deletes or anonymizes a given user within a given transaction
+func (c *AccountClient) delete(ctx context.Context, tx *ent.Tx, userID int) error {
+	if _, err := tx.PasswordToken.Delete().Where(passwordtoken.HasUserWith(user.ID(userID))).Exec(ctx); err != nil {
+		return err
+	}
+
+	if _, err := tx.LoginToken.Delete().Where(logintoken.HasUserWith(user.ID(userID))).Exec(ctx); err != nil {
+		return err
+	}
+
+	if _, err := tx.Device.Delete().Where(device.HasUserWith(user.ID(userID))).Exec(ctx); err != nil {
+		return err
+	}
+
+	if _, err := tx.PersonalAccessToken.Delete().Where(personalaccesstoken.HasUserWith(user.ID(userID))).Exec(ctx); err != nil {
+		return err
+	}
+
+	if _, err := tx.DataExport.Delete().Where(dataexport.HasUserWith(user.ID(userID))).Exec(ctx); err != nil {
+		return err
+	}
+
+	if err := c.deleteMemberships(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	switch c.config.App.AccountDeletion.Mode {
+	case AccountDeletionModeDelete:
+		_, err := tx.Impersonation.
+			Delete().
+			Where(impersonation.Or(
+				impersonation.HasUserWith(user.ID(userID)),
+				impersonation.HasImpersonatorWith(user.ID(userID)),
+			)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return tx.User.DeleteOneID(userID).Exec(ctx)
+
+	case AccountDeletionModeAnonymize:
+		// Replace the password with a random one so the account can never be logged in to
+		password, err := c.auth.RandomToken(64)
+		if err != nil {
+			return err
+		}
+
+		hash, err := c.auth.HashPassword(password)
+		if err != nil {
+			return err
+		}
+
+		return tx.User.
+			UpdateOneID(userID).
+			SetName("Deleted user").
+			SetEmail(fmt.Sprintf("deleted-%d@deleted.invalid", userID)).
+			SetPassword(hash).
+			SetVerified(false).
+			ClearLockedUntil().
+			ClearDeletionScheduledAt().
+			ClearRoles().
+			Exec(ctx)
+
+	default:
+		return fmt.Errorf("unsupported account deletion mode: %s", c.config.App.AccountDeletion.Mode)
+	}
+}
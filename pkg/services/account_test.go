@@ -0,0 +1,182 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/pkg/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountClient_Export(t *testing.T) {
+	u, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+	_, _, err = c.Auth.GeneratePersonalAccessToken(context.Background(), u.ID, "export", []string{AccessTokenScopeRead}, nil)
+	require.NoError(t, err)
+
+	data, err := c.Account.Export(context.Background(), u.ID)
+	require.NoError(t, err)
+
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		b, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[f.Name] = string(b)
+	}
+
+	// Every edge is included, except for previous exports
+	assert.Contains(t, files, "user.json")
+	assert.Contains(t, files, "roles.json")
+	assert.Contains(t, files, "personal_access_tokens.json")
+	assert.NotContains(t, files, "data_exports.json")
+	assert.Contains(t, files["user.json"], u.Email)
+	assert.Contains(t, files["personal_access_tokens.json"], `"export"`)
+
+	// Sensitive fields are never included
+	assert.NotContains(t, files["user.json"], "password")
+	assert.NotContains(t, files["personal_access_tokens.json"], "hash")
+}
+
+func TestAccountClient_DataExport(t *testing.T) {
+	u, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	token, de, err := c.Account.CreateDataExport(context.Background(), u.ID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, de.Data)
+
+	got, err := c.Account.GetValidDataExport(context.Background(), u.ID, token)
+	require.NoError(t, err)
+	assert.Equal(t, de.ID, got.ID)
+
+	// Only the owner can download the export
+	_, err = c.Account.GetValidDataExport(context.Background(), usr.ID, token)
+	assert.True(t, errors.Is(err, InvalidDataExportError{}))
+
+	// Expired exports are invalid and can be deleted
+	cfg := &c.Config.App.DataExport
+	expiration := cfg.Expiration
+	cfg.Expiration = -time.Minute
+	_, err = c.Account.GetValidDataExport(context.Background(), u.ID, token)
+	assert.True(t, errors.Is(err, InvalidDataExportError{}))
+	n, err := c.Account.DeleteExpiredDataExports(context.Background())
+	cfg.Expiration = expiration
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, n, 1)
+}
+
+func TestAccountClient_ScheduleDeletion(t *testing.T) {
+	u, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+	assert.False(t, c.Account.IsDeletionDue(u))
+
+	at, err := c.Account.ScheduleDeletion(context.Background(), u.ID)
+	require.NoError(t, err)
+	assert.True(t, at.After(time.Now()))
+
+	u, err = c.ORM.User.Get(context.Background(), u.ID)
+	require.NoError(t, err)
+	require.NotNil(t, u.DeletionScheduledAt)
+	assert.False(t, c.Account.IsDeletionDue(u))
+
+	past := time.Now().Add(-time.Minute)
+	u.DeletionScheduledAt = &past
+	assert.True(t, c.Account.IsDeletionDue(u))
+
+	require.NoError(t, c.Account.CancelDeletion(context.Background(), u.ID))
+	u, err = c.ORM.User.Get(context.Background(), u.ID)
+	require.NoError(t, err)
+	assert.Nil(t, u.DeletionScheduledAt)
+}
+
+func TestAccountClient_Delete(t *testing.T) {
+	ctx := context.Background()
+	cfg := &c.Config.App.AccountDeletion
+	mode := cfg.Mode
+	defer func() {
+		cfg.Mode = mode
+	}()
+
+	create := func() *ent.User {
+		u, err := tests.CreateUser(c.ORM)
+		require.NoError(t, err)
+		_, _, err = c.Auth.GeneratePersonalAccessToken(ctx, u.ID, "test", []string{AccessTokenScopeRead}, nil)
+		require.NoError(t, err)
+		_, _, err = c.Account.CreateDataExport(ctx, u.ID)
+		require.NoError(t, err)
+		_, err = c.ORM.Impersonation.Create().SetImpersonatorID(usr.ID).SetUserID(u.ID).SetEndedAt(time.Now()).Save(ctx)
+		require.NoError(t, err)
+		org, err := c.Organizations.Create(ctx, u.ID, "Deleted")
+		require.NoError(t, err)
+		_, _, err = c.Organizations.Invite(ctx, org.ID, u.ID, "invited@localhost", "member")
+		require.NoError(t, err)
+		return u
+	}
+
+	t.Run("delete", func(t *testing.T) {
+		cfg.Mode = AccountDeletionModeDelete
+		u := create()
+		orgID, err := u.QueryMemberships().QueryOrganization().OnlyID(ctx)
+		require.NoError(t, err)
+		require.NoError(t, c.Account.Delete(ctx, u.ID))
+
+		_, err = c.ORM.User.Get(ctx, u.ID)
+		assert.True(t, ent.IsNotFound(err))
+
+		// Organizations left without members are deleted
+		_, err = c.ORM.Organization.Get(ctx, orgID)
+		assert.True(t, ent.IsNotFound(err))
+	})
+
+	t.Run("anonymize", func(t *testing.T) {
+		cfg.Mode = AccountDeletionModeAnonymize
+		u := create()
+		require.NoError(t, c.Account.Delete(ctx, u.ID))
+
+		got, err := c.ORM.User.Get(ctx, u.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, u.Name, got.Name)
+		assert.NotEqual(t, u.Email, got.Email)
+		assert.NotEqual(t, u.Password, got.Password)
+
+		count, err := got.QueryPersonalAccessTokens().Count(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		count, err = got.QueryDataExports().Count(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		count, err = got.QueryMemberships().Count(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		// Records which reference the user are kept
+		count, err = got.QueryImpersonationsReceived().Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		cfg.Mode = "invalid"
+		u := create()
+		assert.Error(t, c.Account.Delete(ctx, u.ID))
+
+		// Nothing is deleted
+		count, err := u.QueryPersonalAccessTokens().Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
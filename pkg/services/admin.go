@@ -0,0 +1,697 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	entsql "entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/schema"
+	"entgo.io/ent/schema/field"
+	"github.com/go-openapi/inflect"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/migrate"
+)
+
+const (
+	// adminTimeFormat is the format used to display time values
+	adminTimeFormat = "2006-01-02 15:04:05 MST"
+
+	// adminTimeInputFormat is the format used for time values in datetime-local form inputs
+	adminTimeInputFormat = "2006-01-02T15:04:05"
+)
+
+// adminReadOnly stores the entity types which can be viewed but never changed or deleted in the admin panel, since
+// they record what users, including admins, have done
+var adminReadOnly = map[string]bool{
+	"AuditEvent":    true,
+	"Impersonation": true,
+}
+
+// ErrAdminReadOnly is returned when attempting to change or delete an entity which is read-only in the admin panel
+var ErrAdminReadOnly = errors.New("entity is read-only")
+
+type (
+	// AdminClient provides generic access to all ent entities in order to power the admin panel.
+	// Entities are discovered by introspecting the ent.Client and the generated migrate tables, so any new entity
+	// type is included automatically without any code changes.
+	// Reads are performed directly on the entity tables while all writes go through the ent.Client, so hooks and
+	// validators are executed.
+	AdminClient struct {
+		// db stores the database connection
+		db *sql.DB
+
+		// dialect stores the SQL dialect of the database
+		dialect string
+
+		// entities stores all discovered entities, sorted by name
+		entities []*AdminEntity
+	}
+
+	// AdminEntity describes an ent entity type
+	AdminEntity struct {
+		// Name is the name of the entity type, ie "PasswordToken"
+		Name string
+
+		// Table is the name of the database table, ie "password_tokens"
+		Table string
+
+		// Label is the human-readable plural name of the entity type, ie "Password tokens"
+		Label string
+
+		// Fields contains all fields that can be displayed. Sensitive fields are omitted.
+		Fields []*AdminField
+
+		// ReadOnly indicates if the entities can only be viewed, and not changed or deleted
+		ReadOnly bool
+
+		// pk stores the name of the primary key column
+		pk string
+
+		// client stores the entity client, ie *ent.UserClient
+		client reflect.Value
+	}
+
+	// AdminField describes a field, or column, of an ent entity
+	AdminField struct {
+		// Name is the name of the database column, ie "created_at"
+		Name string
+
+		// Label is the human-readable name of the field
+		Label string
+
+		// Type is the field type
+		Type field.Type
+
+		// Enums contains the allowed values, if the field is an enum
+		Enums []string
+
+		// Nullable indicates if the field can be cleared
+		Nullable bool
+
+		// Editable indicates if the field can be changed, which excludes the ID, immutable fields, edge columns and
+		// fields of unsupported types
+		Editable bool
+
+		// goName stores the name of the field on the entity struct, ie "CreatedAt"
+		goName string
+	}
+
+	// AdminListParams contains the parameters for listing entities
+	AdminListParams struct {
+		// Filters contains values to filter by, keyed by field name. String fields match if they contain the value,
+		// while other fields must be equal to it.
+		Filters map[string]string
+
+		// Sort is the name of the field to sort by, which defaults to the ID
+		Sort string
+
+		// Desc indicates if the sort order should be descending
+		Desc bool
+
+		// Offset is the amount of rows to skip
+		Offset int
+
+		// Limit is the maximum amount of rows to return
+		Limit int
+	}
+
+	// AdminRow contains the values of a single entity, keyed by field name
+	AdminRow map[string]any
+
+	// AdminFieldErrors contains error messages keyed by field name, returned when provided values are invalid
+	AdminFieldErrors map[string]string
+)
+
+// Error implements the error interface.
+func (e AdminFieldErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for name, msg := range e {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", name, msg))
+	}
+	sort.Strings(msgs)
+	return strings.Join(msgs, ", ")
+}
+
+// NewAdminClient creates a new AdminClient by introspecting all entity types of a given ent.Client
+func NewAdminClient(orm *ent.Client, db *sql.DB, dialect string) *AdminClient {
+	c := &AdminClient{
+		db:      db,
+		dialect: dialect,
+	}
+
+	tables := make(map[string]*schema.Table, len(migrate.Tables))
+	for _, t := range migrate.Tables {
+		tables[t.Name] = t
+	}
+
+	// Each entity type has a client field on the ent.Client, ie: User *UserClient
+	cv := reflect.ValueOf(orm).Elem()
+	for i := 0; i < cv.NumField(); i++ {
+		f := cv.Type().Field(i)
+		if !f.IsExported() || f.Type.Kind() != reflect.Ptr || f.Type.Elem().Name() != f.Name+"Client" {
+			continue
+		}
+
+		// This mirrors how ent names tables, so entities with a custom table name will not be included
+		t, ok := tables[adminSnake(inflect.Pluralize(f.Name))]
+		if !ok {
+			continue
+		}
+
+		c.entities = append(c.entities, newAdminEntity(f.Name, t, cv.Field(i)))
+	}
+
+	sort.Slice(c.entities, func(i, j int) bool {
+		return c.entities[i].Name < c.entities[j].Name
+	})
+
+	return c
+}
+
+// newAdminEntity creates a new AdminEntity for a given entity type, table and entity client
+func newAdminEntity(name string, t *schema.Table, client reflect.Value) *AdminEntity {
+	e := &AdminEntity{
+		Name:     name,
+		Table:    t.Name,
+		Label:    inflect.Humanize(t.Name),
+		ReadOnly: adminReadOnly[name],
+		pk:       t.PrimaryKey[0].Name,
+		client:   client,
+	}
+
+	// Map the columns to the entity struct fields in order to find sensitive fields, which ent excludes from JSON
+	entity := client.MethodByName("Get").Type().Out(0).Elem()
+	goNames := make(map[string]string)
+	sensitive := make(map[string]bool)
+	for i := 0; i < entity.NumField(); i++ {
+		f := entity.Field(i)
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		col := adminSnake(f.Name)
+		goNames[col] = f.Name
+		sensitive[col] = f.Tag.Get("json") == "-"
+	}
+
+	// The update builder only has setters for mutable fields
+	update := client.MethodByName("UpdateOneID").Type().Out(0)
+
+	for _, col := range t.Columns {
+		if sensitive[col.Name] {
+			continue
+		}
+
+		f := &AdminField{
+			Name:     col.Name,
+			Label:    adminLabel(col.Name),
+			Type:     col.Type,
+			Enums:    col.Enums,
+			Nullable: col.Nullable,
+			goName:   goNames[col.Name],
+		}
+
+		if f.goName != "" && col.Name != e.pk && !e.ReadOnly {
+			if setter, ok := update.MethodByName("Set" + f.goName); ok {
+				f.Editable = adminCompatible(f.Type, setter.Type.In(1))
+			}
+			if _, ok := update.MethodByName("Clear" + f.goName); !ok {
+				f.Nullable = false
+			}
+		}
+
+		e.Fields = append(e.Fields, f)
+	}
+
+	return e
+}
+
+// Entities returns all entity types
+func (c *AdminClient) Entities() []*AdminEntity {
+	return c.entities
+}
+
+// Entity returns the entity type stored in a given table
+func (c *AdminClient) Entity(table string) (*AdminEntity, bool) {
+	for _, e := range c.entities {
+		if e.Table == table {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// Count returns the amount of entities matching the filters of the given parameters
+func (c *AdminClient) Count(ctx context.Context, e *AdminEntity, params AdminListParams) (int, error) {
+	t := entsql.Table(e.Table)
+	sel := entsql.Dialect(c.dialect).
+		Select(entsql.Count("*")).
+		From(t)
+
+	if p := e.filter(t, params.Filters); p != nil {
+		sel.Where(p)
+	}
+
+	query, args := sel.Query()
+
+	var count int
+	err := c.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+// List returns the entities matching the given parameters
+func (c *AdminClient) List(ctx context.Context, e *AdminEntity, params AdminListParams) ([]AdminRow, error) {
+	t := entsql.Table(e.Table)
+	sel := c.selector(e, t)
+
+	if p := e.filter(t, params.Filters); p != nil {
+		sel.Where(p)
+	}
+
+	sortBy := e.pk
+	if f, ok := e.Field(params.Sort); ok {
+		sortBy = f.Name
+	}
+
+	if params.Desc {
+		sel.OrderBy(entsql.Desc(t.C(sortBy)))
+	} else {
+		sel.OrderBy(entsql.Asc(t.C(sortBy)))
+	}
+
+	// Keep the order stable when sorting by a field with duplicate values
+	if sortBy != e.pk {
+		sel.OrderBy(t.C(e.pk))
+	}
+
+	if params.Limit > 0 {
+		sel.Limit(params.Limit)
+	}
+	if params.Offset > 0 {
+		sel.Offset(params.Offset)
+	}
+
+	return c.query(ctx, e, sel)
+}
+
+// Get returns the entity of a given ID
+func (c *AdminClient) Get(ctx context.Context, e *AdminEntity, id string) (AdminRow, error) {
+	pk, err := e.parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	t := entsql.Table(e.Table)
+	sel := c.selector(e, t).
+		Where(entsql.EQ(t.C(e.pk), pk.Interface()))
+
+	rows, err := c.query(ctx, e, sel)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(rows) == 0:
+		return nil, &ent.NotFoundError{}
+	default:
+		return rows[0], nil
+	}
+}
+
+// Update updates the editable fields of the entity of a given ID with the provided values, keyed by field name.
+// Values are parsed from their string representation, and an empty value clears a nullable field.
+// AdminFieldErrors is returned if any of the values are invalid.
+func (c *AdminClient) Update(ctx context.Context, e *AdminEntity, id string, values map[string]string) error {
+	if e.ReadOnly {
+		return ErrAdminReadOnly
+	}
+
+	pk, err := e.parseID(id)
+	if err != nil {
+		return err
+	}
+
+	builder := e.client.MethodByName("UpdateOneID").Call([]reflect.Value{pk})[0]
+	errs := make(AdminFieldErrors)
+
+	for _, f := range e.Fields {
+		if !f.Editable {
+			continue
+		}
+
+		v := values[f.Name]
+		if v == "" && f.Nullable {
+			builder.MethodByName("Clear" + f.goName).Call(nil)
+			continue
+		}
+
+		parsed, err := f.Parse(v)
+		if err != nil {
+			errs[f.Name] = err.Error()
+			continue
+		}
+
+		setter := builder.MethodByName("Set" + f.goName)
+		setter.Call([]reflect.Value{reflect.ValueOf(parsed).Convert(setter.Type().In(0))})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	err = adminError(builder.MethodByName("Exec").Call([]reflect.Value{reflect.ValueOf(ctx)})[0])
+
+	var verr *ent.ValidationError
+	if errors.As(err, &verr) {
+		return AdminFieldErrors{verr.Name: verr.Unwrap().Error()}
+	}
+
+	return err
+}
+
+// Delete deletes the entity of a given ID
+func (c *AdminClient) Delete(ctx context.Context, e *AdminEntity, id string) error {
+	if e.ReadOnly {
+		return ErrAdminReadOnly
+	}
+
+	pk, err := e.parseID(id)
+	if err != nil {
+		return err
+	}
+
+	builder := e.client.MethodByName("DeleteOneID").Call([]reflect.Value{pk})[0]
+	return adminError(builder.MethodByName("Exec").Call([]reflect.Value{reflect.ValueOf(ctx)})[0])
+}
+
+// selector returns a selector for all displayable fields of an entity
+func (c *AdminClient) selector(e *AdminEntity, t *entsql.SelectTable) *entsql.Selector {
+	cols := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		cols = append(cols, t.C(f.Name))
+	}
+
+	return entsql.Dialect(c.dialect).
+		Select(cols...).
+		From(t)
+}
+
+// query executes a given selector and scans the rows
+func (c *AdminClient) query(ctx context.Context, e *AdminEntity, sel *entsql.Selector) ([]AdminRow, error) {
+	query, args := sel.Query()
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]AdminRow, 0)
+	for rows.Next() {
+		values := make([]any, len(e.Fields))
+		dest := make([]any, len(e.Fields))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := make(AdminRow, len(e.Fields))
+		for i, f := range e.Fields {
+			row[f.Name] = values[i]
+		}
+		list = append(list, row)
+	}
+
+	return list, rows.Err()
+}
+
+// Field returns the field of a given name
+func (e *AdminEntity) Field(name string) (*AdminField, bool) {
+	for _, f := range e.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// ID returns the ID of a given row
+func (e *AdminEntity) ID(row AdminRow) any {
+	return row[e.pk]
+}
+
+// IsEditable determines if any of the fields are editable
+func (e *AdminEntity) IsEditable() bool {
+	for _, f := range e.Fields {
+		if f.Editable {
+			return true
+		}
+	}
+	return false
+}
+
+// filter returns a predicate matching the provided filters, or nil if there are none
+func (e *AdminEntity) filter(t *entsql.SelectTable, filters map[string]string) *entsql.Predicate {
+	preds := make([]*entsql.Predicate, 0, len(filters))
+
+	for _, f := range e.Fields {
+		v := filters[f.Name]
+		if v == "" || !f.IsFilterable() {
+			continue
+		}
+
+		switch f.Type {
+		case field.TypeString:
+			preds = append(preds, entsql.ContainsFold(t.C(f.Name), v))
+		default:
+			// Ignore values that cannot be parsed, rather than matching nothing
+			if parsed, err := f.Parse(v); err == nil {
+				preds = append(preds, entsql.EQ(t.C(f.Name), parsed))
+			}
+		}
+	}
+
+	if len(preds) == 0 {
+		return nil
+	}
+
+	return entsql.And(preds...)
+}
+
+// parseID parses a given ID in to the type used by the entity client
+func (e *AdminEntity) parseID(id string) (reflect.Value, error) {
+	typ := e.client.MethodByName("UpdateOneID").Type().In(0)
+	v := reflect.New(typ).Elem()
+
+	switch typ.Kind() {
+	case reflect.String:
+		v.SetString(id)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return v, &ent.NotFoundError{}
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return v, &ent.NotFoundError{}
+		}
+		v.SetUint(i)
+	default:
+		return v, fmt.Errorf("unsupported ID type: %s", typ)
+	}
+
+	return v, nil
+}
+
+// IsFilterable determines if the field can be filtered
+func (f *AdminField) IsFilterable() bool {
+	return f.Type != field.TypeTime && adminTypes[f.Type] != nil
+}
+
+// IsBool determines if the field is a boolean
+func (f *AdminField) IsBool() bool {
+	return f.Type == field.TypeBool
+}
+
+// IsTime determines if the field is a time
+func (f *AdminField) IsTime() bool {
+	return f.Type == field.TypeTime
+}
+
+// IsNumeric determines if the field is a number
+func (f *AdminField) IsNumeric() bool {
+	return f.Type.Numeric()
+}
+
+// Parse parses a value of the field from its string representation
+func (f *AdminField) Parse(v string) (any, error) {
+	switch f.Type {
+	case field.TypeString:
+		return v, nil
+	case field.TypeEnum:
+		for _, e := range f.Enums {
+			if v == e {
+				return v, nil
+			}
+		}
+		return nil, errors.New("invalid value")
+	case field.TypeBool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.New("must be true or false")
+		}
+		return b, nil
+	case field.TypeTime:
+		t, err := time.ParseInLocation(adminTimeInputFormat, v, time.UTC)
+		if err != nil {
+			// Browsers omit the seconds when they are zero
+			if t, err = time.ParseInLocation(adminTimeInputFormat[:16], v, time.UTC); err != nil {
+				return nil, errors.New("must be a valid date and time")
+			}
+		}
+		return t, nil
+	case field.TypeInt, field.TypeInt8, field.TypeInt16, field.TypeInt32, field.TypeInt64:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, errors.New("must be a whole number")
+		}
+		return i, nil
+	case field.TypeUint, field.TypeUint8, field.TypeUint16, field.TypeUint32, field.TypeUint64:
+		i, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, errors.New("must be a positive whole number")
+		}
+		return i, nil
+	case field.TypeFloat32, field.TypeFloat64:
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, errors.New("must be a number")
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("unsupported field type: %s", f.Type)
+	}
+}
+
+// Format formats a value of the field for display
+func (f *AdminField) Format(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return t.UTC().Format(adminTimeFormat)
+	case []byte:
+		return string(t)
+	case int64:
+		// Some databases, such as SQLite, store booleans as integers
+		if f.Type == field.TypeBool {
+			return strconv.FormatBool(t != 0)
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+// Input formats a value of the field for use in a form input
+func (f *AdminField) Input(v any) string {
+	if t, ok := v.(time.Time); ok {
+		return t.UTC().Format(adminTimeInputFormat)
+	}
+	return f.Format(v)
+}
+
+// adminTypes stores the Go type that each supported field type is parsed in to
+var adminTypes = map[field.Type]reflect.Type{
+	field.TypeString:  reflect.TypeOf(""),
+	field.TypeEnum:    reflect.TypeOf(""),
+	field.TypeBool:    reflect.TypeOf(false),
+	field.TypeTime:    reflect.TypeOf(time.Time{}),
+	field.TypeInt:     reflect.TypeOf(int64(0)),
+	field.TypeInt8:    reflect.TypeOf(int64(0)),
+	field.TypeInt16:   reflect.TypeOf(int64(0)),
+	field.TypeInt32:   reflect.TypeOf(int64(0)),
+	field.TypeInt64:   reflect.TypeOf(int64(0)),
+	field.TypeUint:    reflect.TypeOf(uint64(0)),
+	field.TypeUint8:   reflect.TypeOf(uint64(0)),
+	field.TypeUint16:  reflect.TypeOf(uint64(0)),
+	field.TypeUint32:  reflect.TypeOf(uint64(0)),
+	field.TypeUint64:  reflect.TypeOf(uint64(0)),
+	field.TypeFloat32: reflect.TypeOf(float64(0)),
+	field.TypeFloat64: reflect.TypeOf(float64(0)),
+}
+
+// adminCompatible determines if parsed values of a given field type can be converted to a given setter parameter type
+func adminCompatible(typ field.Type, param reflect.Type) bool {
+	src, ok := adminTypes[typ]
+	if !ok {
+		return false
+	}
+
+	// Conversions between kinds, such as int to string, are allowed by reflection but not wanted here
+	if src.Kind() == reflect.Struct {
+		return src == param
+	}
+
+	kinds := func(t reflect.Type) reflect.Kind {
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return reflect.Int64
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return reflect.Uint64
+		case reflect.Float32, reflect.Float64:
+			return reflect.Float64
+		default:
+			return t.Kind()
+		}
+	}
+
+	return kinds(src) == kinds(param) && src.ConvertibleTo(param)
+}
+
+// adminError extracts an error from a reflected return value
+func adminError(v reflect.Value) error {
+	if v.IsNil() {
+		return nil
+	}
+	return v.Interface().(error)
+}
+
+// adminLabel returns a human-readable label for a given column name
+func adminLabel(name string) string {
+	words := strings.Split(name, "_")
+	for i, w := range words {
+		if w == "id" {
+			words[i] = "ID"
+		}
+	}
+	words[0] = inflect.Capitalize(words[0])
+	return strings.Join(words, " ")
+}
+
+// adminSnake converts a given Go name in to snake case, the same way ent names tables and columns, ie:
+// PasswordToken => password_token, HTTPCode => http_code
+func adminSnake(s string) string {
+	var b strings.Builder
+	j := 0
+	for i := 0; i < len(s); i++ {
+		r := rune(s[i])
+		if i > 0 && i < len(s)-1 && unicode.IsUpper(r) {
+			if unicode.IsLower(rune(s[i-1])) ||
+				j != i-1 && unicode.IsLower(rune(s[i+1])) && unicode.IsLetter(rune(s[i-1])) {
+				j = i
+				b.WriteString("_")
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"entgo.io/ent/schema/field"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/pkg/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminClient_Entities(t *testing.T) {
+	names := make([]string, 0)
+	for _, e := range c.Admin.Entities() {
+		names = append(names, e.Name)
+	}
+	assert.Equal(t, []string{"AuditEvent", "DataExport", "Device", "Impersonation", "Invitation", "InviteCode", "LoginToken", "MailSuppression", "Membership", "Organization", "PasswordToken", "Permission", "PersonalAccessToken", "Role", "User"}, names)
+
+	e, ok := c.Admin.Entity("users")
+	require.True(t, ok)
+	assert.Equal(t, "User", e.Name)
+	assert.Equal(t, "Users", e.Label)
+
+	// Sensitive fields should be omitted
+	_, ok = e.Field("password")
+	assert.False(t, ok)
+
+	editable := func(name string) bool {
+		f, ok := e.Field(name)
+		require.True(t, ok, name)
+		return f.Editable
+	}
+	assert.False(t, editable("id"))
+	assert.True(t, editable("name"))
+	assert.True(t, editable("verified"))
+	assert.True(t, editable("locked_until"))
+	assert.False(t, editable("created_at"))
+
+	f, _ := e.Field("locked_until")
+	assert.True(t, f.Nullable)
+	assert.Equal(t, field.TypeTime, f.Type)
+
+	e, ok = c.Admin.Entity("password_tokens")
+	require.True(t, ok)
+	_, ok = e.Field("hash")
+	assert.False(t, ok)
+	assert.False(t, e.ReadOnly)
+
+	// Entities which record what admins have done cannot be changed or deleted
+	for _, table := range []string{"audit_events", "impersonations"} {
+		e, ok = c.Admin.Entity(table)
+		require.True(t, ok)
+		assert.True(t, e.ReadOnly, table)
+		assert.False(t, e.IsEditable(), table)
+		assert.Equal(t, ErrAdminReadOnly, c.Admin.Update(context.Background(), e, "1", map[string]string{}))
+		assert.Equal(t, ErrAdminReadOnly, c.Admin.Delete(context.Background(), e, "1"))
+	}
+}
+
+func TestAdminClient_List(t *testing.T) {
+	ctx := context.Background()
+	e, _ := c.Admin.Entity("users")
+
+	u, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	params := AdminListParams{
+		Filters: map[string]string{"email": strings.ToUpper(u.Email)},
+	}
+
+	count, err := c.Admin.Count(ctx, e, params)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	rows, err := c.Admin.List(ctx, e, params)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, fmt.Sprint(u.ID), fmt.Sprint(e.ID(rows[0])))
+	assert.Equal(t, u.Name, rows[0]["name"])
+	_, ok := rows[0]["password"]
+	assert.False(t, ok)
+
+	// Sort and limit
+	rows, err = c.Admin.List(ctx, e, AdminListParams{Sort: "id", Desc: true, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, fmt.Sprint(u.ID), fmt.Sprint(e.ID(rows[0])))
+}
+
+func TestAdminClient_UpdateDelete(t *testing.T) {
+	ctx := context.Background()
+	e, _ := c.Admin.Entity("users")
+
+	u, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+	id := fmt.Sprint(u.ID)
+
+	// Invalid values
+	err = c.Admin.Update(ctx, e, id, map[string]string{
+		"name":            "Updated",
+		"email":           u.Email,
+		"verified":        "abc",
+		"session_version": "0",
+	})
+	var ferr AdminFieldErrors
+	require.True(t, errors.As(err, &ferr))
+	assert.Contains(t, ferr, "verified")
+
+	// Validators should run
+	err = c.Admin.Update(ctx, e, id, map[string]string{
+		"name":            "",
+		"email":           u.Email,
+		"verified":        "true",
+		"session_version": "0",
+	})
+	require.True(t, errors.As(err, &ferr))
+	assert.Contains(t, ferr, "name")
+
+	// Hooks should run, which lowercase the email address
+	err = c.Admin.Update(ctx, e, id, map[string]string{
+		"name":            "Updated",
+		"email":           strings.ToUpper(u.Email),
+		"verified":        "true",
+		"locked_until":    "2030-01-02T03:04",
+		"session_version": "0",
+	})
+	require.NoError(t, err)
+
+	row, err := c.Admin.Get(ctx, e, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", row["name"])
+	assert.Equal(t, u.Email, row["email"])
+	f, _ := e.Field("verified")
+	assert.Equal(t, "true", f.Format(row["verified"]))
+	f, _ = e.Field("locked_until")
+	assert.Equal(t, "2030-01-02T03:04:00", f.Input(row["locked_until"]))
+
+	// Empty values clear nullable fields
+	err = c.Admin.Update(ctx, e, id, map[string]string{
+		"name":            "Updated",
+		"email":           u.Email,
+		"verified":        "true",
+		"session_version": "0",
+	})
+	require.NoError(t, err)
+	row, err = c.Admin.Get(ctx, e, id)
+	require.NoError(t, err)
+	assert.Nil(t, row["locked_until"])
+
+	// Delete
+	require.NoError(t, c.Admin.Delete(ctx, e, id))
+	_, err = c.Admin.Get(ctx, e, id)
+	assert.True(t, ent.IsNotFound(err))
+	assert.True(t, ent.IsNotFound(c.Admin.Delete(ctx, e, id)))
+}
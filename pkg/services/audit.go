@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/auditevent"
+	"github.com/mikestefanello/pagoda/ent/predicate"
+	"github.com/mikestefanello/pagoda/ent/user"
+	pctx "github.com/mikestefanello/pagoda/pkg/context"
+	"github.com/mikestefanello/pagoda/pkg/log"
+)
+
+const (
+	// AuditActionLogin is recorded when a user logs in
+	AuditActionLogin = "auth.login"
+
+	// AuditActionLoginFailed is recorded when a login attempt fails
+	AuditActionLoginFailed = "auth.login_failed"
+
+	// AuditActionLogout is recorded when a user logs out
+	AuditActionLogout = "auth.logout"
+
+	// AuditActionLocked is recorded when an account is locked due to too many failed logins
+	AuditActionLocked = "auth.locked"
+
+	// AuditActionRegister is recorded when a user registers
+	AuditActionRegister = "auth.register"
+
+	// AuditActionLoginLinkRequested is recorded when a login link is emailed to a user
+	AuditActionLoginLinkRequested = "auth.login_link_requested"
+
+	// AuditActionPasswordResetRequested is recorded when a password reset link is emailed to a user
+	AuditActionPasswordResetRequested = "auth.password_reset_requested"
+
+	// AuditActionPasswordReset is recorded when a user resets their password via a password reset link
+	AuditActionPasswordReset = "auth.password_reset"
+
+	// AuditActionEmailVerified is recorded when a user verifies their email address
+	AuditActionEmailVerified = "auth.email_verified"
+
+	// AuditActionReauthenticated is recorded when a user re-enters their password to perform a sensitive action
+	AuditActionReauthenticated = "auth.reauthenticated"
+
+	// AuditActionSessionsRevoked is recorded when a user logs out every device via the link in a security notification
+	AuditActionSessionsRevoked = "auth.sessions_revoked"
+
+	// AuditActionImpersonationStarted is recorded when an admin starts impersonating a user
+	AuditActionImpersonationStarted = "auth.impersonation_started"
+
+	// AuditActionImpersonationStopped is recorded when an admin stops impersonating a user
+	AuditActionImpersonationStopped = "auth.impersonation_stopped"
+
+	// AuditActionPasswordChanged is recorded when a user changes their password from their account settings
+	AuditActionPasswordChanged = "account.password_changed"
+
+	// AuditActionEmailChanged is recorded when a user confirms a change of their email address
+	AuditActionEmailChanged = "account.email_changed"
+
+	// AuditActionAccessTokenCreated is recorded when a user creates a personal access token
+	AuditActionAccessTokenCreated = "account.access_token_created"
+
+	// AuditActionAccessTokenRevoked is recorded when a user revokes a personal access token
+	AuditActionAccessTokenRevoked = "account.access_token_revoked"
+
+	// AuditActionDeletionScheduled is recorded when a user schedules their account to be deleted
+	AuditActionDeletionScheduled = "account.deletion_scheduled"
+
+	// AuditActionDeletionCancelled is recorded when a user cancels the scheduled deletion of their account
+	AuditActionDeletionCancelled = "account.deletion_cancelled"
+
+	// AuditActionOrganizationCreated is recorded when a user creates an organization
+	AuditActionOrganizationCreated = "org.created"
+
+	// AuditActionMemberInvited is recorded when a member of an organization invites someone to join it
+	AuditActionMemberInvited = "org.member_invited"
+
+	// AuditActionInvitationAccepted is recorded when a user accepts an invitation to join an organization
+	AuditActionInvitationAccepted = "org.invitation_accepted"
+
+	// AuditActionInvitationRevoked is recorded when a member of an organization revokes a pending invitation
+	AuditActionInvitationRevoked = "org.invitation_revoked"
+
+	// AuditActionMemberRemoved is recorded when a member is removed from an organization
+	AuditActionMemberRemoved = "org.member_removed"
+
+	// AuditActionInviteCodeCreated is recorded when an admin generates an invite code
+	AuditActionInviteCodeCreated = "admin.invite_code_created"
+)
+
+type (
+	// AuditLogger records security-relevant events, such as logins and password resets, as AuditEvent entities
+	AuditLogger struct {
+		config *config.Config
+		orm    *ent.Client
+	}
+
+	// AuditEventBuilder builds an audit event to be recorded
+	AuditEventBuilder struct {
+		logger  *AuditLogger
+		action  string
+		actor   *int
+		target  *int
+		payload map[string]any
+	}
+
+	// AuditLogParams contains the parameters for listing audit events
+	AuditLogParams struct {
+		// Action filters events by action
+		Action string
+
+		// User filters events by the email address of the actor or the target, which matches if it contains the value
+		User string
+
+		// IP filters events by IP address
+		IP string
+
+		// RequestID filters events by request ID
+		RequestID string
+
+		// Since filters events which were created at or after this time
+		Since *time.Time
+
+		// Until filters events which were created before this time
+		Until *time.Time
+
+		// Offset is the amount of events to skip
+		Offset int
+
+		// Limit is the maximum amount of events to return
+		Limit int
+	}
+)
+
+// NewAuditLogger creates a new AuditLogger
+func NewAuditLogger(cfg *config.Config, orm *ent.Client) *AuditLogger {
+	return &AuditLogger{
+		config: cfg,
+		orm:    orm,
+	}
+}
+
+// Event starts building an audit event of a given action, which is recorded by calling Save()
+func (l *AuditLogger) Event(action string) *AuditEventBuilder {
+	return &AuditEventBuilder{
+		logger:  l,
+		action:  action,
+		payload: make(map[string]any),
+	}
+}
+
+// List returns the audit events matching the given parameters, newest first, with the actor and target loaded
+func (l *AuditLogger) List(ctx context.Context, params AuditLogParams) ([]*ent.AuditEvent, error) {
+	q := l.orm.AuditEvent.
+		Query().
+		Where(params.predicates()...).
+		WithActor().
+		WithTarget().
+		Order(ent.Desc(auditevent.FieldCreatedAt), ent.Desc(auditevent.FieldID))
+
+	if params.Limit > 0 {
+		q.Limit(params.Limit)
+	}
+	if params.Offset > 0 {
+		q.Offset(params.Offset)
+	}
+
+	return q.All(ctx)
+}
+
+// Count returns the amount of audit events matching the given parameters
+func (l *AuditLogger) Count(ctx context.Context, params AuditLogParams) (int, error) {
+	return l.orm.AuditEvent.
+		Query().
+		Where(params.predicates()...).
+		Count(ctx)
+}
+
+// Actions returns all distinct actions which have been recorded
+func (l *AuditLogger) Actions(ctx context.Context) ([]string, error) {
+	return l.orm.AuditEvent.
+		Query().
+		Unique(true).
+		Order(ent.Asc(auditevent.FieldAction)).
+		Select(auditevent.FieldAction).
+		Strings(ctx)
+}
+
+// DeleteExpired deletes all audit events older than the configured retention period and returns the amount deleted
+func (l *AuditLogger) DeleteExpired(ctx context.Context) (int, error) {
+	return l.orm.AuditEvent.
+		Delete().
+		Where(auditevent.CreatedAtLT(time.Now().Add(-l.config.App.AuditLog.Retention))).
+		Exec(ctx)
+}
+
+// Actor sets the ID of the user who performed the action, which defaults to the authenticated user, if there is one
+func (b *AuditEventBuilder) Actor(userID int) *AuditEventBuilder {
+	b.actor = &userID
+	return b
+}
+
+// Target sets the ID of the user whose account the action affected, if any
+func (b *AuditEventBuilder) Target(userID int) *AuditEventBuilder {
+	b.target = &userID
+	return b
+}
+
+// With adds a key and value to the payload of the event
+func (b *AuditEventBuilder) With(key string, value any) *AuditEventBuilder {
+	b.payload[key] = value
+	return b
+}
+
+// Save records the event along with the IP address, user agent and ID of the given request.
+// Failing to record an event should not prevent the action from completing, so errors are logged rather than
+// returned.
+func (b *AuditEventBuilder) Save(ctx echo.Context) {
+	if b.actor == nil {
+		if u, ok := ctx.Get(pctx.AuthenticatedUserKey).(*ent.User); ok {
+			b.Actor(u.ID)
+		}
+	}
+
+	// Attribute actions taken while impersonating to the admin
+	if u, ok := ctx.Get(pctx.ImpersonatorKey).(*ent.User); ok {
+		b.payload["impersonator_id"] = u.ID
+	}
+
+	op := b.logger.orm.AuditEvent.
+		Create().
+		SetAction(b.action).
+		SetIP(ctx.RealIP()).
+		SetUserAgent(ctx.Request().UserAgent()).
+		SetRequestID(ctx.Response().Header().Get(echo.HeaderXRequestID)).
+		SetNillableActorID(b.actor).
+		SetNillableTargetID(b.target)
+
+	if len(b.payload) > 0 {
+		op.SetPayload(b.payload)
+	}
+
+	if err := op.Exec(ctx.Request().Context()); err != nil {
+		log.Ctx(ctx).Error("unable to record audit event",
+			"action", b.action,
+			"error", err,
+		)
+	}
+}
+
+// predicates returns the predicates matching the filters of the parameters
+func (p AuditLogParams) predicates() []predicate.AuditEvent {
+	preds := make([]predicate.AuditEvent, 0)
+
+	if p.Action != "" {
+		preds = append(preds, auditevent.Action(p.Action))
+	}
+	if p.User != "" {
+		preds = append(preds, auditevent.Or(
+			auditevent.HasActorWith(user.EmailContainsFold(p.User)),
+			auditevent.HasTargetWith(user.EmailContainsFold(p.User)),
+		))
+	}
+	if p.IP != "" {
+		preds = append(preds, auditevent.IP(p.IP))
+	}
+	if p.RequestID != "" {
+		preds = append(preds, auditevent.RequestID(p.RequestID))
+	}
+	if p.Since != nil {
+		preds = append(preds, auditevent.CreatedAtGTE(*p.Since))
+	}
+	if p.Until != nil {
+		preds = append(preds, auditevent.CreatedAtLT(*p.Until))
+	}
+
+	return preds
+}
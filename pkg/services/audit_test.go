@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/ent/auditevent"
+	pctx "github.com/mikestefanello/pagoda/pkg/context"
+	"github.com/mikestefanello/pagoda/pkg/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogger_Save(t *testing.T) {
+	u, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	ctx, _ := tests.NewContext(c.Web, "/")
+	ctx.Request().Header.Set("User-Agent", "test-agent")
+	ctx.Response().Header().Set(echo.HeaderXRequestID, "test-request")
+
+	// Anonymous actor
+	c.Audit.Event(AuditActionLoginFailed).
+		Target(u.ID).
+		With("reason", "invalid_password").
+		Save(ctx)
+
+	e, err := c.ORM.AuditEvent.
+		Query().
+		Where(auditevent.RequestID("test-request")).
+		WithActor().
+		WithTarget().
+		Only(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, AuditActionLoginFailed, e.Action)
+	assert.Equal(t, ctx.RealIP(), e.IP)
+	assert.Equal(t, "test-agent", e.UserAgent)
+	assert.Equal(t, map[string]any{"reason": "invalid_password"}, e.Payload)
+	assert.Nil(t, e.Edges.Actor)
+	require.NotNil(t, e.Edges.Target)
+	assert.Equal(t, u.ID, e.Edges.Target.ID)
+
+	// The actor defaults to the authenticated user
+	ctx.Response().Header().Set(echo.HeaderXRequestID, "test-request-auth")
+	ctx.Set(pctx.AuthenticatedUserKey, u)
+	c.Audit.Event(AuditActionLogout).Save(ctx)
+
+	e, err = c.ORM.AuditEvent.
+		Query().
+		Where(auditevent.RequestID("test-request-auth")).
+		WithActor().
+		Only(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, e.Edges.Actor)
+	assert.Equal(t, u.ID, e.Edges.Actor.ID)
+	assert.Empty(t, e.Payload)
+}
+
+func TestAuditLogger_List(t *testing.T) {
+	u, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	ctx, _ := tests.NewContext(c.Web, "/")
+	c.Audit.Event(AuditActionLogin).Actor(u.ID).Target(u.ID).Save(ctx)
+	c.Audit.Event(AuditActionLogout).Actor(u.ID).Target(u.ID).Save(ctx)
+
+	params := AuditLogParams{User: u.Email}
+	count, err := c.Audit.Count(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// Newest first
+	events, err := c.Audit.List(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, AuditActionLogout, events[0].Action)
+	assert.Equal(t, u.Email, events[0].Edges.Actor.Email)
+
+	params.Action = AuditActionLogin
+	events, err = c.Audit.List(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, AuditActionLogin, events[0].Action)
+
+	until := time.Now().Add(-time.Hour)
+	params.Until = &until
+	count, err = c.Audit.Count(context.Background(), params)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	actions, err := c.Audit.Actions(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, actions, AuditActionLogin)
+	assert.Contains(t, actions, AuditActionLogout)
+}
+
+func TestAuditLogger_DeleteExpired(t *testing.T) {
+	u, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	expired, err := c.ORM.AuditEvent.
+		Create().
+		SetAction(AuditActionLogin).
+		SetTargetID(u.ID).
+		SetCreatedAt(time.Now().Add(-c.Config.App.AuditLog.Retention - time.Minute)).
+		Save(context.Background())
+	require.NoError(t, err)
+
+	current, err := c.ORM.AuditEvent.
+		Create().
+		SetAction(AuditActionLogin).
+		SetTargetID(u.ID).
+		Save(context.Background())
+	require.NoError(t, err)
+
+	n, err := c.Audit.DeleteExpired(context.Background())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, n, 1)
+
+	_, err = c.ORM.AuditEvent.Get(context.Background(), expired.ID)
+	assert.Error(t, err)
+	_, err = c.ORM.AuditEvent.Get(context.Background(), current.ID)
+	assert.NoError(t, err)
+}
@@ -1,22 +1,70 @@
 package services
 
 import (
+	"context"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 	"github.com/mikestefanello/pagoda/config"
 	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/device"
+	"github.com/mikestefanello/pagoda/ent/logintoken"
 	"github.com/mikestefanello/pagoda/ent/passwordtoken"
+	"github.com/mikestefanello/pagoda/ent/personalaccesstoken"
 	"github.com/mikestefanello/pagoda/ent/user"
-	"github.com/mikestefanello/pagoda/pkg/context"
+	pctx "github.com/mikestefanello/pagoda/pkg/context"
 	"github.com/mikestefanello/pagoda/pkg/session"
 
 	"github.com/labstack/echo/v4"
-	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// AccessTokenScopeRead is the personal access token scope which allows read-only requests
+	AccessTokenScopeRead = "read"
+
+	// AccessTokenScopeWrite is the personal access token scope which allows requests that make changes
+	AccessTokenScopeWrite = "write"
+
+	// accessTokenPrefix is the prefix of all personal access tokens, which makes them easy to identify, ie when
+	// scanning for leaked secrets
+	accessTokenPrefix = "pat_"
+
+	// accessTokenLength is the length of the random part of personal access tokens
+	accessTokenLength = 40
+
+	// accessTokenLastUsedInterval is how often the time a personal access token was last used is updated,
+	// to avoid a database write on every request
+	accessTokenLastUsedInterval = time.Minute
+)
+
+const (
+	// emailTokenClaimUserID is the JWT claim which stores the ID of the user changing their email address
+	emailTokenClaimUserID = "uid"
+
+	// emailTokenClaimCurrentEmail is the JWT claim which stores the email address being changed
+	emailTokenClaimCurrentEmail = "current"
+
+	// emailTokenClaimNonce is the JWT claim which stores the nonce that makes email verification tokens single-use
+	emailTokenClaimNonce = "nonce"
+
+	// emailTokenClaimSessionVersion is the JWT claim which stores the session version of the user a session
+	// revocation token was issued to
+	emailTokenClaimSessionVersion = "sv"
+
+	// emailTokenClaimRestoreEmail is the JWT claim which stores the email address a session revocation token
+	// restores, when it was sent to the previous address of a user whose email address was changed
+	emailTokenClaimRestoreEmail = "restore"
+
+	// verificationNonceLength is the length of the nonce contained in email verification tokens
+	verificationNonceLength = 32
 )
 
 const (
@@ -28,8 +76,50 @@ const (
 
 	// authSessionKeyAuthenticated stores the key used to store the authentication status in the session
 	authSessionKeyAuthenticated = "authenticated"
+
+	// authSessionKeyImpersonatorID stores the key used to store the ID of the admin impersonating the user in the
+	// session
+	authSessionKeyImpersonatorID = "impersonator_id"
+
+	// authSessionKeyImpersonationID stores the key used to store the ID of the impersonation entity in the session
+	authSessionKeyImpersonationID = "impersonation_id"
+
+	// authSessionKeyLoginNonce stores the key used to store the nonce which binds login links to the browser that
+	// requested them in the session
+	authSessionKeyLoginNonce = "login_nonce"
+
+	// authSessionKeyLoggedInAt stores the key used to store when the user logged in, as a unix timestamp, in the
+	// session
+	authSessionKeyLoggedInAt = "logged_in_at"
+
+	// authSessionKeyLastActiveAt stores the key used to store when the user last made a request, as a unix
+	// timestamp, in the session
+	authSessionKeyLastActiveAt = "last_active_at"
+
+	// authSessionKeyAuthenticatedAt stores the key used to store when the user last proved who they are, by logging
+	// in or re-entering their password, as a unix timestamp, in the session
+	authSessionKeyAuthenticatedAt = "authenticated_at"
+
+	// authSessionKeySessionVersion stores the key used to store the session version of the user when they logged in
+	// in the session
+	authSessionKeySessionVersion = "session_version"
 )
 
+const (
+	// deviceSessionName stores the name of the session which identifies the device, which is kept after logging out
+	deviceSessionName = "ud"
+
+	// deviceSessionKeyID stores the key used to store the random identifier of the device in the session
+	deviceSessionKeyID = "device_id"
+
+	// deviceIDLength is the length of the random identifier of devices
+	deviceIDLength = 64
+)
+
+// sessionActivityInterval is how often the last activity of a session is updated, so the session cookie does not
+// have to be written on every request
+const sessionActivityInterval = time.Minute
+
 // NotAuthenticatedError is an error returned when a user is not authenticated
 type NotAuthenticatedError struct{}
 
@@ -38,6 +128,31 @@ func (e NotAuthenticatedError) Error() string {
 	return "user not authenticated"
 }
 
+// NotImpersonatingError is an error returned when the user is not being impersonated
+type NotImpersonatingError struct{}
+
+// Error implements the error interface.
+func (e NotImpersonatingError) Error() string {
+	return "user not being impersonated"
+}
+
+// InvalidAccessTokenError is an error returned when an invalid, expired or revoked personal access token is provided
+type InvalidAccessTokenError struct{}
+
+// Error implements the error interface.
+func (e InvalidAccessTokenError) Error() string {
+	return "invalid access token"
+}
+
+// InvalidLoginTokenError is an error returned when an invalid or expired login token is provided, or the token was
+// requested from a different browser
+type InvalidLoginTokenError struct{}
+
+// Error implements the error interface.
+func (e InvalidLoginTokenError) Error() string {
+	return "invalid login token"
+}
+
 // InvalidPasswordTokenError is an error returned when an invalid token is provided
 type InvalidPasswordTokenError struct{}
 
@@ -46,41 +161,295 @@ func (e InvalidPasswordTokenError) Error() string {
 	return "invalid password token"
 }
 
+// InvalidVerificationTokenError is an error returned when an invalid, expired or already used email verification
+// token is provided
+type InvalidVerificationTokenError struct{}
+
+// Error implements the error interface.
+func (e InvalidVerificationTokenError) Error() string {
+	return "invalid verification token"
+}
+
+// SessionExpiredError is an error returned when the session of an authenticated user has expired, either because the
+// user was idle for too long or because too much time passed since the user logged in
+type SessionExpiredError struct{}
+
+// Error implements the error interface.
+func (e SessionExpiredError) Error() string {
+	return "session expired"
+}
+
+// SessionRevokedError is an error returned when the session of an authenticated user was revoked, because every
+// session of the user was revoked after it was created
+type SessionRevokedError struct{}
+
+// Error implements the error interface.
+func (e SessionRevokedError) Error() string {
+	return "session revoked"
+}
+
+// InvalidRevokeTokenError is an error returned when an invalid, expired or already used session revocation token is
+// provided
+type InvalidRevokeTokenError struct{}
+
+// Error implements the error interface.
+func (e InvalidRevokeTokenError) Error() string {
+	return "invalid revoke token"
+}
+
+// EmailRestoreConflictError is an error returned when sessions were revoked but the previous email address of the
+// user could not be restored since it now belongs to another account
+type EmailRestoreConflictError struct{}
+
+// Error implements the error interface.
+func (e EmailRestoreConflictError) Error() string {
+	return "email address belongs to another account"
+}
+
+// PasswordMismatchError is an error returned when a password does not match a hash
+type PasswordMismatchError struct{}
+
+// Error implements the error interface.
+func (e PasswordMismatchError) Error() string {
+	return "password does not match"
+}
+
 // AuthClient is the client that handles authentication requests
 type AuthClient struct {
-	config *config.Config
-	orm    *ent.Client
+	config  *config.Config
+	orm     *ent.Client
+	keyring *Keyring
+
+	// hasher is the password hasher used to create new password hashes
+	hasher PasswordHasher
+
+	// hashers contains the password hashers for all supported algorithms, used to verify existing hashes
+	hashers []PasswordHasher
 }
 
 // NewAuthClient creates a new authentication client
-func NewAuthClient(cfg *config.Config, orm *ent.Client) *AuthClient {
-	return &AuthClient{
-		config: cfg,
-		orm:    orm,
+func NewAuthClient(cfg *config.Config, orm *ent.Client, keyring *Keyring) (*AuthClient, error) {
+	hasher, hashers, err := NewPasswordHashers(cfg)
+	if err != nil {
+		return nil, err
 	}
+
+	return &AuthClient{
+		config:  cfg,
+		orm:     orm,
+		keyring: keyring,
+		hasher:  hasher,
+		hashers: hashers,
+	}, nil
 }
 
 // Login logs in a user of a given ID
 func (c *AuthClient) Login(ctx echo.Context, userID int) error {
+	version, err := c.orm.User.
+		Query().
+		Where(user.ID(userID)).
+		Select(user.FieldSessionVersion).
+		Int(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
 	sess, err := session.Get(ctx, authSessionName)
 	if err != nil {
 		return err
 	}
+	now := time.Now().Unix()
 	sess.Values[authSessionKeyUserID] = userID
+	sess.Values[authSessionKeySessionVersion] = version
 	sess.Values[authSessionKeyAuthenticated] = true
+	sess.Values[authSessionKeyLoggedInAt] = now
+	sess.Values[authSessionKeyLastActiveAt] = now
+	sess.Values[authSessionKeyAuthenticatedAt] = now
+	delete(sess.Values, authSessionKeyImpersonatorID)
+	delete(sess.Values, authSessionKeyImpersonationID)
+	return sess.Save(ctx.Request(), ctx.Response())
+}
+
+// Reauthenticate records that the authenticated user proved who they are again, such as by re-entering their
+// password, which is required by actions that need a recent login
+func (c *AuthClient) Reauthenticate(ctx echo.Context) error {
+	if _, err := c.GetAuthenticatedUserID(ctx); err != nil {
+		return err
+	}
+
+	sess, err := session.Get(ctx, authSessionName)
+	if err != nil {
+		return err
+	}
+	sess.Values[authSessionKeyAuthenticatedAt] = time.Now().Unix()
+	return sess.Save(ctx.Request(), ctx.Response())
+}
+
+// GetAuthenticatedAt returns when the authenticated user last proved who they are, by logging in or reauthenticating
+func (c *AuthClient) GetAuthenticatedAt(ctx echo.Context) (time.Time, error) {
+	if _, err := c.GetAuthenticatedUserID(ctx); err != nil {
+		return time.Time{}, err
+	}
+
+	sess, err := session.Get(ctx, authSessionName)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	at, _ := sess.Values[authSessionKeyAuthenticatedAt].(int64)
+	return time.Unix(at, 0), nil
+}
+
+// RefreshSession enforces the configured idle and absolute timeouts of the session of the authenticated user, and
+// records the activity of the user. If the session expired, the user is logged out and a SessionExpiredError is
+// returned. A NotAuthenticatedError is returned if the user is not authenticated.
+func (c *AuthClient) RefreshSession(ctx echo.Context) error {
+	if _, err := c.GetAuthenticatedUserID(ctx); err != nil {
+		return err
+	}
+
+	sess, err := session.Get(ctx, authSessionName)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	cfg := c.config.App.Session
+	loggedInAt, ok := sess.Values[authSessionKeyLoggedInAt].(int64)
+	lastActiveAt, _ := sess.Values[authSessionKeyLastActiveAt].(int64)
+
+	// Sessions created before the timestamps were tracked start being tracked now
+	if !ok {
+		loggedInAt, lastActiveAt = now.Unix(), now.Unix()
+		sess.Values[authSessionKeyLoggedInAt] = loggedInAt
+	}
+
+	switch {
+	case cfg.IdleTimeout > 0 && now.Sub(time.Unix(lastActiveAt, 0)) > cfg.IdleTimeout,
+		cfg.AbsoluteTimeout > 0 && now.Sub(time.Unix(loggedInAt, 0)) > cfg.AbsoluteTimeout:
+		if err = c.Logout(ctx); err != nil {
+			return err
+		}
+		return SessionExpiredError{}
+	}
+
+	if ok && now.Sub(time.Unix(lastActiveAt, 0)) < sessionActivityInterval {
+		return nil
+	}
+
+	sess.Values[authSessionKeyLastActiveAt] = now.Unix()
 	return sess.Save(ctx.Request(), ctx.Response())
 }
 
-// Logout logs the requesting user out
+// Logout logs the requesting user out, which also ends any impersonation
 func (c *AuthClient) Logout(ctx echo.Context) error {
 	sess, err := session.Get(ctx, authSessionName)
 	if err != nil {
 		return err
 	}
+
+	if id, ok := sess.Values[authSessionKeyImpersonationID].(int); ok {
+		if err = c.endImpersonation(ctx, id); err != nil {
+			return err
+		}
+	}
+
 	sess.Values[authSessionKeyAuthenticated] = false
+	delete(sess.Values, authSessionKeyImpersonatorID)
+	delete(sess.Values, authSessionKeyImpersonationID)
+	return sess.Save(ctx.Request(), ctx.Response())
+}
+
+// StartImpersonation logs the authenticated admin in as a given user while storing the admin's ID in the session
+// so the admin can later return to their own account. An Impersonation entity is created as an audit trail.
+// This does not check if the authenticated user is allowed to impersonate; that must be done by the caller.
+func (c *AuthClient) StartImpersonation(ctx echo.Context, userID int) error {
+	adminID, err := c.GetAuthenticatedUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if adminID == userID {
+		return errors.New("cannot impersonate yourself")
+	}
+
+	if _, err = c.GetImpersonatorID(ctx); err == nil {
+		return errors.New("already impersonating a user")
+	}
+
+	imp, err := c.orm.Impersonation.
+		Create().
+		SetImpersonatorID(adminID).
+		SetUserID(userID).
+		Save(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	sess, err := session.Get(ctx, authSessionName)
+	if err != nil {
+		return err
+	}
+	sess.Values[authSessionKeyUserID] = userID
+	sess.Values[authSessionKeyImpersonatorID] = adminID
+	sess.Values[authSessionKeyImpersonationID] = imp.ID
 	return sess.Save(ctx.Request(), ctx.Response())
 }
 
+// StopImpersonation logs the impersonating admin back in to their own account, records the end of the
+// impersonation, and returns the ID of the user that was being impersonated
+func (c *AuthClient) StopImpersonation(ctx echo.Context) (int, error) {
+	adminID, err := c.GetImpersonatorID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sess, err := session.Get(ctx, authSessionName)
+	if err != nil {
+		return 0, err
+	}
+
+	userID := sess.Values[authSessionKeyUserID].(int)
+
+	if id, ok := sess.Values[authSessionKeyImpersonationID].(int); ok {
+		if err = c.endImpersonation(ctx, id); err != nil {
+			return 0, err
+		}
+	}
+
+	sess.Values[authSessionKeyUserID] = adminID
+	delete(sess.Values, authSessionKeyImpersonatorID)
+	delete(sess.Values, authSessionKeyImpersonationID)
+	return userID, sess.Save(ctx.Request(), ctx.Response())
+}
+
+// GetImpersonatorID returns the ID of the admin impersonating the authenticated user, if the user is being
+// impersonated
+func (c *AuthClient) GetImpersonatorID(ctx echo.Context) (int, error) {
+	if _, err := c.GetAuthenticatedUserID(ctx); err != nil {
+		return 0, err
+	}
+
+	sess, err := session.Get(ctx, authSessionName)
+	if err != nil {
+		return 0, err
+	}
+
+	if id, ok := sess.Values[authSessionKeyImpersonatorID].(int); ok {
+		return id, nil
+	}
+
+	return 0, NotImpersonatingError{}
+}
+
+// endImpersonation records the end of an impersonation of a given ID
+func (c *AuthClient) endImpersonation(ctx echo.Context, impersonationID int) error {
+	return c.orm.Impersonation.
+		UpdateOneID(impersonationID).
+		SetEndedAt(time.Now()).
+		Exec(ctx.Request().Context())
+}
+
 // GetAuthenticatedUserID returns the authenticated user's ID, if the user is logged in
 func (c *AuthClient) GetAuthenticatedUserID(ctx echo.Context) (int, error) {
 	sess, err := session.Get(ctx, authSessionName)
@@ -95,34 +464,258 @@ func (c *AuthClient) GetAuthenticatedUserID(ctx echo.Context) (int, error) {
 	return 0, NotAuthenticatedError{}
 }
 
-// GetAuthenticatedUser returns the authenticated user if the user is logged in
-func (c *AuthClient) GetAuthenticatedUser(ctx echo.Context) (*ent.User, error) {
-	if userID, err := c.GetAuthenticatedUserID(ctx); err == nil {
-		return c.orm.User.Query().
-			Where(user.ID(userID)).
-			Only(ctx.Request().Context())
+// GetAuthenticatedUser returns the authenticated user if the user is logged in.
+// If the user is being impersonated, the impersonating admin is also returned, otherwise it will be nil.
+// If the sessions of the user who logged in were revoked since, the user is logged out and a SessionRevokedError is
+// returned.
+func (c *AuthClient) GetAuthenticatedUser(ctx echo.Context) (*ent.User, *ent.User, error) {
+	userID, err := c.GetAuthenticatedUserID(ctx)
+	if err != nil {
+		return nil, nil, NotAuthenticatedError{}
+	}
+
+	ids := []int{userID}
+	adminID, err := c.GetImpersonatorID(ctx)
+	if err == nil {
+		ids = append(ids, adminID)
+	}
+
+	users, err := c.orm.User.Query().
+		Where(user.IDIn(ids...)).
+		All(ctx.Request().Context())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var usr, admin *ent.User
+	for _, u := range users {
+		switch u.ID {
+		case userID:
+			usr = u
+		case adminID:
+			admin = u
+		}
+	}
+
+	// Both users must exist
+	if usr == nil || len(users) != len(ids) {
+		return nil, nil, &ent.NotFoundError{}
+	}
+
+	// The session belongs to the admin while impersonating, since the admin is the one who logged in
+	owner := usr
+	if admin != nil {
+		owner = admin
 	}
 
-	return nil, NotAuthenticatedError{}
+	sess, err := session.Get(ctx, authSessionName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Sessions created before the version was tracked have the initial version
+	version, _ := sess.Values[authSessionKeySessionVersion].(int)
+	if version != owner.SessionVersion {
+		if err = c.Logout(ctx); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, SessionRevokedError{}
+	}
+
+	return usr, admin, nil
 }
 
-// HashPassword returns a hash of a given password
-func (c *AuthClient) HashPassword(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+// RevokeSessions revokes every session of a given user, on every device, and forgets the devices the user logged in
+// from, along with any login links, password reset links and personal access tokens, so nobody remains logged in to
+// the account and the next login from any device is notified. This is done within a single transaction, so a
+// failure cannot leave the account partially secured.
+// If a restore email is given, the email address of the user is then changed back to it, since the account may have
+// been taken over by changing it, which is what allowed the new address to reset the password. The sessions remain
+// revoked even if that fails, and EmailRestoreConflictError is returned if the address now belongs to another account.
+func (c *AuthClient) RevokeSessions(ctx context.Context, userID int, restoreEmail string) error {
+	tx, err := c.orm.Tx(ctx)
 	if err != nil {
-		return "", err
+		return err
+	}
+
+	if err = c.revokeSessions(ctx, tx, userID); err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			err = fmt.Errorf("%w: %v", err, rerr)
+		}
+		return err
 	}
-	return string(hash), nil
+
+	if err = tx.Commit(); err != nil || restoreEmail == "" {
+		return err
+	}
+
+	// Receiving the notification at the address proves ownership of it
+	err = c.orm.User.
+		UpdateOneID(userID).
+		SetEmail(restoreEmail).
+		SetVerified(true).
+		Exec(ctx)
+
+	if ent.IsConstraintError(err) {
+		return EmailRestoreConflictError{}
+	}
+
+	return err
 }
 
-// CheckPassword check if a given password matches a given hash
+// revokeSessions revokes every session of a given user within a given transaction
+func (c *AuthClient) revokeSessions(ctx context.Context, tx *ent.Tx, userID int) error {
+	err := tx.User.
+		UpdateOneID(userID).
+		AddSessionVersion(1).
+		Exec(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Device.Delete().Where(device.HasUserWith(user.ID(userID))).Exec(ctx); err != nil {
+		return err
+	}
+
+	if _, err = tx.LoginToken.Delete().Where(logintoken.HasUserWith(user.ID(userID))).Exec(ctx); err != nil {
+		return err
+	}
+
+	if _, err = tx.PasswordToken.Delete().Where(passwordtoken.HasUserWith(user.ID(userID))).Exec(ctx); err != nil {
+		return err
+	}
+
+	_, err = tx.PersonalAccessToken.Delete().Where(personalaccesstoken.HasUserWith(user.ID(userID))).Exec(ctx)
+	return err
+}
+
+// RecordDevice records that a given user logged in from the requesting device, which is identified by a random
+// identifier stored in a long-lived session that is kept after logging out. Only a hash of the identifier is stored.
+// True is returned if the device is new to the user and the user logged in from another device before, in which case
+// the user should be notified, since it may not have been them. The first device of a user is never new, since it
+// is the one they registered with.
+func (c *AuthClient) RecordDevice(ctx echo.Context, userID int) (bool, error) {
+	sess, err := session.Get(ctx, deviceSessionName)
+	if err != nil {
+		return false, err
+	}
+
+	id, ok := sess.Values[deviceSessionKeyID].(string)
+	if !ok {
+		if id, err = c.RandomToken(deviceIDLength); err != nil {
+			return false, err
+		}
+		sess.Values[deviceSessionKeyID] = id
+	}
+
+	// Saving the session on every login keeps the cookie from expiring while the device is in use
+	if err = sess.Save(ctx.Request(), ctx.Response()); err != nil {
+		return false, err
+	}
+
+	d, err := c.orm.Device.
+		Query().
+		Where(device.HashIn(c.tokenHashes(id)...)).
+		Where(device.HasUserWith(user.ID(userID))).
+		First(ctx.Request().Context())
+
+	switch err.(type) {
+	case nil:
+		return false, d.
+			Update().
+			SetUserAgent(ctx.Request().UserAgent()).
+			SetIPAddress(ctx.RealIP()).
+			SetLastLoginAt(time.Now()).
+			Exec(ctx.Request().Context())
+	case *ent.NotFoundError:
+	default:
+		return false, err
+	}
+
+	known, err := c.orm.Device.
+		Query().
+		Where(device.HasUserWith(user.ID(userID))).
+		Exist(ctx.Request().Context())
+	if err != nil {
+		return false, err
+	}
+
+	err = c.orm.Device.
+		Create().
+		SetHash(c.hashToken(id)).
+		SetUserAgent(ctx.Request().UserAgent()).
+		SetIPAddress(ctx.RealIP()).
+		SetUserID(userID).
+		Exec(ctx.Request().Context())
+
+	return known, err
+}
+
+// HashPassword returns a hash of a given password using the configured password hashing algorithm
+func (c *AuthClient) HashPassword(password string) (string, error) {
+	return c.hasher.Hash(password)
+}
+
+// CheckPassword check if a given password matches a given hash.
+// The hash can be from any of the supported algorithms, not just the one currently configured.
 func (c *AuthClient) CheckPassword(password, hash string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	for _, h := range c.hashers {
+		if !h.Identifies(hash) {
+			continue
+		}
+
+		ok, err := h.Verify(password, hash)
+		switch {
+		case err != nil:
+			return err
+		case !ok:
+			return PasswordMismatchError{}
+		default:
+			return nil
+		}
+	}
+
+	return ErrUnknownPasswordHash
+}
+
+// PasswordNeedsRehash determines if a given password hash was not created with the configured password hashing
+// algorithm and parameters. If so, the password should be hashed again, which can only be done when the password
+// is provided, such as after a successful login.
+func (c *AuthClient) PasswordNeedsRehash(hash string) bool {
+	return !c.hasher.Identifies(hash) || c.hasher.NeedsRehash(hash)
+}
+
+// IsLocked determines if a given user's account is currently locked
+func (c *AuthClient) IsLocked(u *ent.User) bool {
+	return u.LockedUntil != nil && u.LockedUntil.After(time.Now())
+}
+
+// LockUser locks the account of a given user for the duration stored in configuration and returns the time
+// at which the lock will expire
+func (c *AuthClient) LockUser(ctx context.Context, userID int) (time.Time, error) {
+	until := time.Now().Add(c.config.App.LoginThrottle.LockoutDuration)
+
+	err := c.orm.User.
+		UpdateOneID(userID).
+		SetLockedUntil(until).
+		Exec(ctx)
+
+	return until, err
+}
+
+// UnlockUser removes the lock from the account of a given user
+func (c *AuthClient) UnlockUser(ctx context.Context, userID int) error {
+	return c.orm.User.
+		UpdateOneID(userID).
+		ClearLockedUntil().
+		Exec(ctx)
 }
 
 // GeneratePasswordResetToken generates a password reset token for a given user.
 // For security purposes, the token itself is not stored in the database but rather
-// a hash of the token, exactly how passwords are handled. This method returns both
+// an HMAC of the token. Since the token is long and random, a slow password hashing
+// algorithm is not required. This method returns both
 // the generated token as well as the token entity which only contains the hash.
 func (c *AuthClient) GeneratePasswordResetToken(ctx echo.Context, userID int) (string, *ent.PasswordToken, error) {
 	// Generate the token, which is what will go in the URL, but not the database
@@ -132,10 +725,7 @@ func (c *AuthClient) GeneratePasswordResetToken(ctx echo.Context, userID int) (s
 	}
 
 	// Hash the token, which is what will be stored in the database
-	hash, err := c.HashPassword(token)
-	if err != nil {
-		return "", nil, err
-	}
+	hash := c.hashToken(token)
 
 	// Create and save the password reset token
 	pt, err := c.orm.PasswordToken.
@@ -166,11 +756,11 @@ func (c *AuthClient) GetValidPasswordToken(ctx echo.Context, userID, tokenID int
 	case *ent.NotFoundError:
 	case nil:
 		// Check the token for a hash match
-		if err := c.CheckPassword(token, pt.Hash); err == nil {
+		if c.tokenHashMatches(token, pt.Hash) || c.legacyPasswordTokenHashMatches(token, pt.Hash) {
 			return pt, nil
 		}
 	default:
-		if !context.IsCanceledError(err) {
+		if !pctx.IsCanceledError(err) {
 			return nil, err
 		}
 	}
@@ -178,6 +768,13 @@ func (c *AuthClient) GetValidPasswordToken(ctx echo.Context, userID, tokenID int
 	return nil, InvalidPasswordTokenError{}
 }
 
+// legacyPasswordTokenHashMatches checks if a given hash is a bcrypt hash of a given token, which is how password
+// tokens were hashed before they were hashed with an HMAC, so reset links sent before upgrading keep working until
+// they expire
+func (c *AuthClient) legacyPasswordTokenHashMatches(token, hash string) bool {
+	return (&BcryptHasher{}).Identifies(hash) && c.CheckPassword(token, hash) == nil
+}
+
 // DeletePasswordTokens deletes all password tokens in the database for a belonging to a given user.
 // This should be called after a successful password reset.
 func (c *AuthClient) DeletePasswordTokens(ctx echo.Context, userID int) error {
@@ -189,6 +786,208 @@ func (c *AuthClient) DeletePasswordTokens(ctx echo.Context, userID int) error {
 	return err
 }
 
+// GenerateLoginToken generates a single-use login token for a given user, which allows them to log in without a
+// password. Just like password tokens, only a hash of the token is stored.
+// The token is bound to the browser that requested it by storing a random nonce in the auth session, and a hash of
+// the nonce with the token, so the token cannot be used from any other browser, ie if the email was forwarded.
+// The nonce is reused for all tokens requested from the same browser, so requesting another link does not
+// invalidate the previous ones.
+func (c *AuthClient) GenerateLoginToken(ctx echo.Context, userID int) (string, *ent.LoginToken, error) {
+	sess, err := session.Get(ctx, authSessionName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	nonce, ok := sess.Values[authSessionKeyLoginNonce].(string)
+	if !ok {
+		nonce, err = c.RandomToken(c.config.App.LoginToken.Length)
+		if err != nil {
+			return "", nil, err
+		}
+
+		sess.Values[authSessionKeyLoginNonce] = nonce
+		if err = sess.Save(ctx.Request(), ctx.Response()); err != nil {
+			return "", nil, err
+		}
+	}
+
+	token, err := c.RandomToken(c.config.App.LoginToken.Length)
+	if err != nil {
+		return "", nil, err
+	}
+
+	lt, err := c.orm.LoginToken.
+		Create().
+		SetHash(c.hashToken(token)).
+		SetBrowserHash(c.hashToken(nonce)).
+		SetUserID(userID).
+		Save(ctx.Request().Context())
+
+	return token, lt, err
+}
+
+// LoginTokenExpiration returns how long login tokens are valid for after they are generated
+func (c *AuthClient) LoginTokenExpiration() time.Duration {
+	return c.config.App.LoginToken.Expiration
+}
+
+// GetValidLoginToken returns the valid, non-expired login token entity matching a given token, with the user loaded,
+// as long as it was requested from the same browser
+func (c *AuthClient) GetValidLoginToken(ctx echo.Context, token string) (*ent.LoginToken, error) {
+	sess, err := session.Get(ctx, authSessionName)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, ok := sess.Values[authSessionKeyLoginNonce].(string)
+	if !ok {
+		return nil, InvalidLoginTokenError{}
+	}
+
+	// Ensure expired tokens are never returned
+	expiration := time.Now().Add(-c.config.App.LoginToken.Expiration)
+
+	lt, err := c.orm.LoginToken.
+		Query().
+		Where(logintoken.HashIn(c.tokenHashes(token)...)).
+		Where(logintoken.CreatedAtGTE(expiration)).
+		WithUser().
+		Only(ctx.Request().Context())
+
+	switch err.(type) {
+	case *ent.NotFoundError:
+	case nil:
+		if c.tokenHashMatches(nonce, lt.BrowserHash) {
+			return lt, nil
+		}
+	default:
+		if !pctx.IsCanceledError(err) {
+			return nil, err
+		}
+	}
+
+	return nil, InvalidLoginTokenError{}
+}
+
+// DeleteLoginTokens deletes all login tokens belonging to a given user.
+// This should be called after a successful login via a login token, so each link can only be used once.
+func (c *AuthClient) DeleteLoginTokens(ctx echo.Context, userID int) error {
+	_, err := c.orm.LoginToken.
+		Delete().
+		Where(logintoken.HasUserWith(user.ID(userID))).
+		Exec(ctx.Request().Context())
+
+	return err
+}
+
+// GeneratePersonalAccessToken generates a named personal access token for a given user with the given scopes and an
+// optional expiration. Just like password tokens, only a hash of the token is stored, so the token that is returned
+// cannot be retrieved again.
+func (c *AuthClient) GeneratePersonalAccessToken(
+	ctx context.Context,
+	userID int,
+	name string,
+	scopes []string,
+	expiresAt *time.Time,
+) (string, *ent.PersonalAccessToken, error) {
+	random, err := c.RandomToken(accessTokenLength)
+	if err != nil {
+		return "", nil, err
+	}
+	token := accessTokenPrefix + random
+
+	pat, err := c.orm.PersonalAccessToken.
+		Create().
+		SetName(name).
+		SetHash(c.hashToken(token)).
+		SetScopes(scopes).
+		SetNillableExpiresAt(expiresAt).
+		SetUserID(userID).
+		Save(ctx)
+
+	return token, pat, err
+}
+
+// GetValidPersonalAccessToken returns the valid, non-expired personal access token entity matching a given token,
+// with the owning user loaded, and records that the token was used
+func (c *AuthClient) GetValidPersonalAccessToken(ctx context.Context, token string) (*ent.PersonalAccessToken, error) {
+	if !strings.HasPrefix(token, accessTokenPrefix) {
+		return nil, InvalidAccessTokenError{}
+	}
+
+	pat, err := c.orm.PersonalAccessToken.
+		Query().
+		Where(personalaccesstoken.HashIn(c.tokenHashes(token)...)).
+		Where(personalaccesstoken.Or(
+			personalaccesstoken.ExpiresAtIsNil(),
+			personalaccesstoken.ExpiresAtGT(time.Now()),
+		)).
+		WithUser().
+		Only(ctx)
+
+	switch err.(type) {
+	case nil:
+	case *ent.NotFoundError:
+		return nil, InvalidAccessTokenError{}
+	default:
+		return nil, err
+	}
+
+	if pat.LastUsedAt == nil || time.Since(*pat.LastUsedAt) > accessTokenLastUsedInterval {
+		now := time.Now()
+		err = c.orm.PersonalAccessToken.
+			UpdateOneID(pat.ID).
+			SetLastUsedAt(now).
+			Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+		pat.LastUsedAt = &now
+	}
+
+	return pat, nil
+}
+
+// GetPersonalAccessTokens returns all personal access tokens belonging to a given user, newest first
+func (c *AuthClient) GetPersonalAccessTokens(ctx context.Context, userID int) ([]*ent.PersonalAccessToken, error) {
+	return c.orm.PersonalAccessToken.
+		Query().
+		Where(personalaccesstoken.HasUserWith(user.ID(userID))).
+		Order(ent.Desc(personalaccesstoken.FieldID)).
+		All(ctx)
+}
+
+// RevokePersonalAccessToken deletes the personal access token of a given ID, if it belongs to a given user
+func (c *AuthClient) RevokePersonalAccessToken(ctx context.Context, userID, tokenID int) error {
+	n, err := c.orm.PersonalAccessToken.
+		Delete().
+		Where(personalaccesstoken.ID(tokenID)).
+		Where(personalaccesstoken.HasUserWith(user.ID(userID))).
+		Exec(ctx)
+
+	if err == nil && n == 0 {
+		return InvalidAccessTokenError{}
+	}
+
+	return err
+}
+
+// AccessTokenAllows determines if a given personal access token's scopes allow a request of a given HTTP method.
+// The read scope allows safe methods, such as GET, and the write scope allows all methods.
+func (c *AuthClient) AccessTokenAllows(pat *ent.PersonalAccessToken, method string) bool {
+	for _, scope := range pat.Scopes {
+		switch scope {
+		case AccessTokenScopeWrite:
+			return true
+		case AccessTokenScopeRead:
+			if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // RandomToken generates a random token string of a given length
 func (c *AuthClient) RandomToken(length int) (string, error) {
 	b := make([]byte, (length/2)+1)
@@ -199,35 +998,253 @@ func (c *AuthClient) RandomToken(length int) (string, error) {
 	return token[:length], nil
 }
 
-// GenerateEmailVerificationToken generates an email verification token for a given email address using JWT which
-// is set to expire based on the duration stored in configuration
-func (c *AuthClient) GenerateEmailVerificationToken(email string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"email": email,
-		"exp":   time.Now().Add(c.config.App.EmailVerificationTokenExpiration).Unix(),
+// hashToken returns a hex-encoded HMAC-SHA256 of a given token, keyed with the primary token hash key, which is
+// what should be stored
+func (c *AuthClient) hashToken(token string) string {
+	return hmacHex(c.keyring.Primary(KeyPurposeTokenHash), token)
+}
+
+// tokenHashes returns the hashes of a given token for every token hash key, including retired ones and the legacy
+// key, so tokens which were stored before a key rotation can still be looked up
+func (c *AuthClient) tokenHashes(token string) []string {
+	keys := c.keyring.All(KeyPurposeTokenHash)
+	if legacy, ok := c.keyring.Legacy(); ok {
+		keys = append(keys, legacy)
+	}
+
+	hashes := make([]string, 0, len(keys))
+	for _, key := range keys {
+		hashes = append(hashes, hmacHex(key, token))
+	}
+	return hashes
+}
+
+// tokenHashMatches checks if a given hash is a hash of a given token for any token hash key
+func (c *AuthClient) tokenHashMatches(token, hash string) bool {
+	for _, h := range c.tokenHashes(token) {
+		if hmac.Equal([]byte(h), []byte(hash)) {
+			return true
+		}
+	}
+	return false
+}
+
+// hmacHex returns a hex-encoded HMAC-SHA256 of a given value using a given key
+func hmacHex(key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateEmailVerificationToken generates an email verification token for the current email address of a given
+// user using JWT which is set to expire based on the duration stored in configuration.
+// The token contains a random nonce, a hash of which is stored on the user, so the token can only be used once and
+// only while the user's email address has not changed. Generating a new token invalidates any previous ones.
+func (c *AuthClient) GenerateEmailVerificationToken(ctx context.Context, usr *ent.User) (string, error) {
+	nonce, err := c.RandomToken(verificationNonceLength)
+	if err != nil {
+		return "", err
+	}
+
+	err = c.orm.User.
+		UpdateOneID(usr.ID).
+		SetVerificationNonce(c.hashToken(nonce)).
+		Exec(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	return c.generateEmailToken(jwt.MapClaims{
+		"email":              usr.Email,
+		emailTokenClaimNonce: nonce,
 	})
+}
+
+// ValidateEmailVerificationToken validates an email verification token and returns the user it was issued to if the
+// token is valid, has not expired and has not been used.
+// The caller must clear the user's verification nonce once the email address is verified so the token cannot be
+// used again.
+func (c *AuthClient) ValidateEmailVerificationToken(ctx context.Context, token string) (*ent.User, error) {
+	claims, err := c.validateEmailToken(token)
+	if err != nil {
+		return nil, InvalidVerificationTokenError{}
+	}
+
+	// Email change tokens must not be accepted as verification tokens
+	if _, ok := claims[emailTokenClaimUserID]; ok {
+		return nil, InvalidVerificationTokenError{}
+	}
 
-	return token.SignedString([]byte(c.config.App.EncryptionKey))
+	nonce, ok := claims[emailTokenClaimNonce].(string)
+	if !ok {
+		return nil, InvalidVerificationTokenError{}
+	}
+
+	u, err := c.orm.User.
+		Query().
+		Where(user.Email(claims["email"].(string))).
+		Where(user.VerificationNonceIn(c.tokenHashes(nonce)...)).
+		Only(ctx)
+
+	switch err.(type) {
+	case nil:
+		return u, nil
+	case *ent.NotFoundError:
+		return nil, InvalidVerificationTokenError{}
+	default:
+		return nil, err
+	}
+}
+
+// GenerateEmailChangeToken generates a token which confirms that a given user wants to change their email address
+// from a given current address to a given new address, which should be sent to the new address.
+// This uses the same JWT flow as email verification tokens, with additional claims for the user ID and the
+// current address, so the token can only be used by that user and only while the address has not changed since.
+func (c *AuthClient) GenerateEmailChangeToken(userID int, currentEmail, newEmail string) (string, error) {
+	return c.generateEmailToken(jwt.MapClaims{
+		"email":                     newEmail,
+		emailTokenClaimUserID:       userID,
+		emailTokenClaimCurrentEmail: currentEmail,
+	})
+}
+
+// ValidateEmailChangeToken validates an email change token and returns the associated user ID, current email
+// address and new email address if the token is valid and has not expired
+func (c *AuthClient) ValidateEmailChangeToken(token string) (userID int, currentEmail, newEmail string, err error) {
+	claims, err := c.validateEmailToken(token)
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	// Numbers are decoded as floats
+	id, ok := claims[emailTokenClaimUserID].(float64)
+	if !ok {
+		return 0, "", "", errors.New("invalid token")
+	}
+
+	currentEmail, ok = claims[emailTokenClaimCurrentEmail].(string)
+	if !ok {
+		return 0, "", "", errors.New("invalid token")
+	}
+
+	return int(id), currentEmail, claims["email"].(string), nil
+}
+
+// GenerateRevokeToken generates a token which allows a given user to revoke every session of their account without
+// logging in, which is included in security notifications so the user can act if they did not make the change.
+// This uses the same JWT flow as email verification tokens, with additional claims for the user ID and the session
+// version of the user, so the token can only be used once, since revoking the sessions changes the version.
+// If the notification is sent to the previous address of a user whose email address was changed, that address should
+// be given as the restore email, so revoking also changes it back.
+func (c *AuthClient) GenerateRevokeToken(usr *ent.User, restoreEmail string) (string, error) {
+	claims := jwt.MapClaims{
+		"email":                       usr.Email,
+		emailTokenClaimUserID:         usr.ID,
+		emailTokenClaimSessionVersion: usr.SessionVersion,
+	}
+
+	if restoreEmail != "" {
+		claims[emailTokenClaimRestoreEmail] = restoreEmail
+	}
+
+	return c.generateEmailTokenWithExpiration(claims, c.config.App.SecurityNotification.RevokeTokenExpiration)
 }
 
-// ValidateEmailVerificationToken validates an email verification token and returns the associated email address if
-// the token is valid and has not expired
-func (c *AuthClient) ValidateEmailVerificationToken(token string) (string, error) {
+// ValidateRevokeToken validates a session revocation token and returns the user it was issued to, along with the
+// email address to restore, if any, if the token is valid, has not expired and has not been used
+func (c *AuthClient) ValidateRevokeToken(ctx context.Context, token string) (usr *ent.User, restoreEmail string, err error) {
+	claims, err := c.validateEmailToken(token)
+	if err != nil {
+		return nil, "", InvalidRevokeTokenError{}
+	}
+
+	// Numbers are decoded as floats
+	id, ok := claims[emailTokenClaimUserID].(float64)
+	if !ok {
+		return nil, "", InvalidRevokeTokenError{}
+	}
+
+	version, ok := claims[emailTokenClaimSessionVersion].(float64)
+	if !ok {
+		return nil, "", InvalidRevokeTokenError{}
+	}
+
+	if restore, ok := claims[emailTokenClaimRestoreEmail]; ok {
+		if restoreEmail, ok = restore.(string); !ok {
+			return nil, "", InvalidRevokeTokenError{}
+		}
+	}
+
+	u, err := c.orm.User.
+		Query().
+		Where(user.ID(int(id))).
+		Where(user.Email(claims["email"].(string))).
+		Where(user.SessionVersion(int(version))).
+		Only(ctx)
+
+	switch err.(type) {
+	case nil:
+		return u, restoreEmail, nil
+	case *ent.NotFoundError:
+		return nil, "", InvalidRevokeTokenError{}
+	default:
+		return nil, "", err
+	}
+}
+
+// generateEmailToken generates a signed JWT containing given claims which is set to expire based on the email
+// verification duration stored in configuration
+func (c *AuthClient) generateEmailToken(claims jwt.MapClaims) (string, error) {
+	return c.generateEmailTokenWithExpiration(claims, c.config.App.EmailVerificationTokenExpiration)
+}
+
+// generateEmailTokenWithExpiration generates a signed JWT containing given claims which is set to expire after a
+// given duration
+func (c *AuthClient) generateEmailTokenWithExpiration(claims jwt.MapClaims, expiration time.Duration) (string, error) {
+	claims["exp"] = time.Now().Add(expiration).Unix()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	// Include the ID of the key so the token can still be verified after the key has been rotated
+	token.Header["kid"] = c.keyring.PrimaryID()
+
+	return token.SignedString(c.keyring.Primary(KeyPurposeJWT))
+}
+
+// validateEmailToken validates a JWT generated by generateEmailToken and returns its claims if the token is valid,
+// has not expired and contains an email address
+func (c *AuthClient) validateEmailToken(token string) (jwt.MapClaims, error) {
 	t, err := jwt.Parse(token, func(t *jwt.Token) (any, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
 
-		return []byte(c.config.App.EncryptionKey), nil
+		kid, ok := t.Header["kid"].(string)
+		if !ok {
+			// Tokens issued before the keyring existed do not have a key ID and were signed with the legacy key
+			if legacy, ok := c.keyring.Legacy(); ok {
+				return legacy, nil
+			}
+			return nil, errors.New("missing key ID")
+		}
+
+		key, ok := c.keyring.Get(kid, KeyPurposeJWT)
+		if !ok {
+			return nil, UnknownKeyError{}
+		}
+
+		return key, nil
 	})
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if claims, ok := t.Claims.(jwt.MapClaims); ok && t.Valid {
-		return claims["email"].(string), nil
+		if _, ok := claims["email"].(string); ok {
+			return claims, nil
+		}
 	}
 
-	return "", errors.New("invalid or expired token")
+	return nil, errors.New("invalid or expired token")
 }
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/permission"
+	"github.com/mikestefanello/pagoda/ent/role"
+	"github.com/mikestefanello/pagoda/ent/user"
+)
+
+const (
+	// RoleAdmin is the name of the role which has every permission
+	RoleAdmin = "admin"
+
+	// PermissionAdminAccess is the permission required to access the admin panel
+	PermissionAdminAccess = "admin.access"
+
+	// PermissionUsersEdit is the permission required to edit other users
+	PermissionUsersEdit = "users.edit"
+
+	// PermissionUsersImpersonate is the permission required to impersonate other users
+	PermissionUsersImpersonate = "users.impersonate"
+)
+
+// DefaultPermissions stores the description of each permission which is seeded in to the database
+var DefaultPermissions = map[string]string{
+	PermissionAdminAccess:      "Access the admin panel",
+	PermissionUsersEdit:        "Edit other users",
+	PermissionUsersImpersonate: "Impersonate other users",
+}
+
+// DefaultRoles stores the permissions of each role which is seeded in to the database
+var DefaultRoles = map[string][]string{
+	RoleAdmin: {
+		PermissionAdminAccess,
+		PermissionUsersEdit,
+		PermissionUsersImpersonate,
+	},
+}
+
+// AuthzClient is the client that handles authorization through roles and permissions.
+// Users are assigned roles and roles are granted permissions, and access should always be checked against a
+// permission rather than a role.
+type AuthzClient struct {
+	orm *ent.Client
+}
+
+// NewAuthzClient creates a new authorization client
+func NewAuthzClient(orm *ent.Client) *AuthzClient {
+	return &AuthzClient{
+		orm: orm,
+	}
+}
+
+// GetPermissions returns the names of all permissions granted to a given user through their roles, as a set,
+// using a single query
+func (c *AuthzClient) GetPermissions(ctx context.Context, userID int) (map[string]bool, error) {
+	names, err := c.orm.Permission.
+		Query().
+		Where(permission.HasRolesWith(role.HasUsersWith(user.ID(userID)))).
+		Unique(true).
+		Select(permission.FieldName).
+		Strings(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	perms := make(map[string]bool, len(names))
+	for _, name := range names {
+		perms[name] = true
+	}
+
+	return perms, nil
+}
+
+// Seed creates the default roles and permissions, if they do not already exist, and grants each default role its
+// default permissions
+func (c *AuthzClient) Seed(ctx context.Context) error {
+	perms := make(map[string]*ent.Permission, len(DefaultPermissions))
+	for name, description := range DefaultPermissions {
+		p, err := c.orm.Permission.
+			Query().
+			Where(permission.Name(name)).
+			Only(ctx)
+
+		switch err.(type) {
+		case nil:
+		case *ent.NotFoundError:
+			p, err = c.orm.Permission.
+				Create().
+				SetName(name).
+				SetDescription(description).
+				Save(ctx)
+			if err != nil {
+				return fmt.Errorf("unable to create permission %s: %w", name, err)
+			}
+		default:
+			return err
+		}
+
+		perms[name] = p
+	}
+
+	for name, names := range DefaultRoles {
+		r, err := c.getOrCreateRole(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]int, 0, len(names))
+		for _, n := range names {
+			ids = append(ids, perms[n].ID)
+		}
+
+		err = r.Update().
+			RemovePermissionIDs(ids...).
+			AddPermissionIDs(ids...).
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to grant permissions to role %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// AssignRole assigns a role of a given name to a given user
+func (c *AuthzClient) AssignRole(ctx context.Context, userID int, roleName string) error {
+	r, err := c.orm.Role.
+		Query().
+		Where(role.Name(roleName)).
+		Only(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Remove first so assigning a role the user already has is not an error
+	return r.Update().
+		RemoveUserIDs(userID).
+		AddUserIDs(userID).
+		Exec(ctx)
+}
+
+// RevokeRole removes a role of a given name from a given user
+func (c *AuthzClient) RevokeRole(ctx context.Context, userID int, roleName string) error {
+	r, err := c.orm.Role.
+		Query().
+		Where(role.Name(roleName)).
+		Only(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.Update().
+		RemoveUserIDs(userID).
+		Exec(ctx)
+}
+
+// getOrCreateRole loads a role of a given name and creates it if it does not exist
+func (c *AuthzClient) getOrCreateRole(ctx context.Context, name string) (*ent.Role, error) {
+	r, err := c.orm.Role.
+		Query().
+		Where(role.Name(name)).
+		Only(ctx)
+
+	switch err.(type) {
+	case nil:
+		return r, nil
+	case *ent.NotFoundError:
+		return c.orm.Role.
+			Create().
+			SetName(name).
+			Save(ctx)
+	default:
+		return nil, err
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mikestefanello/pagoda/pkg/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthzClient(t *testing.T) {
+	ctx := context.Background()
+	u, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	// Seeding should be repeatable
+	require.NoError(t, c.Authz.Seed(ctx))
+	require.NoError(t, c.Authz.Seed(ctx))
+
+	perms, err := c.Authz.GetPermissions(ctx, u.ID)
+	require.NoError(t, err)
+	assert.Empty(t, perms)
+
+	// Assigning a role should grant its permissions
+	require.NoError(t, c.Authz.AssignRole(ctx, u.ID, RoleAdmin))
+	require.NoError(t, c.Authz.AssignRole(ctx, u.ID, RoleAdmin))
+	perms, err = c.Authz.GetPermissions(ctx, u.ID)
+	require.NoError(t, err)
+	assert.Len(t, perms, len(DefaultRoles[RoleAdmin]))
+	for _, p := range DefaultRoles[RoleAdmin] {
+		assert.True(t, perms[p])
+	}
+
+	// Revoking the role should remove them
+	require.NoError(t, c.Authz.RevokeRole(ctx, u.ID, RoleAdmin))
+	perms, err = c.Authz.GetPermissions(ctx, u.ID)
+	require.NoError(t, err)
+	assert.Empty(t, perms)
+
+	// Roles must exist
+	assert.Error(t, c.Authz.AssignRole(ctx, u.ID, "invalid"))
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/pkg/bot"
+)
+
+// botTokenCacheGroup is the cache group used to store the tokens which were already used
+const botTokenCacheGroup = "bot_token"
+
+// BotProtectionClient issues and verifies the tokens used to protect forms against spam bots, along with the
+// proof-of-work challenges which are solved in the browser. This satisfies the bot.Checker interface.
+// Tokens contain the time they were issued and are encrypted, so they cannot be forged, and can only be used once.
+type BotProtectionClient struct {
+	config  *config.Config
+	keyring *Keyring
+	cache   *CacheClient
+
+	// mu ensures that a token cannot be used more than once by concurrent requests
+	mu sync.Mutex
+}
+
+// NewBotProtectionClient creates a new BotProtectionClient
+func NewBotProtectionClient(cfg *config.Config, keyring *Keyring, cache *CacheClient) *BotProtectionClient {
+	return &BotProtectionClient{
+		config:  cfg,
+		keyring: keyring,
+		cache:   cache,
+	}
+}
+
+// DefaultPolicy returns the policy applied to forms which do not provide their own, from configuration
+func (c *BotProtectionClient) DefaultPolicy() bot.Policy {
+	return bot.Policy{
+		MinFillTime: c.config.App.BotProtection.MinFillTime,
+		ProofOfWork: c.config.App.BotProtection.ProofOfWork,
+	}
+}
+
+// Issue issues a challenge for a form with a given policy
+func (c *BotProtectionClient) Issue(policy bot.Policy) (bot.Challenge, error) {
+	var challenge bot.Challenge
+
+	issuedAt := strconv.FormatInt(time.Now().UnixNano(), 10)
+	token, err := c.keyring.Encrypt(KeyPurposeBotProtection, []byte(issuedAt))
+	if err != nil {
+		return challenge, err
+	}
+
+	challenge.Token = token
+	if policy.ProofOfWork {
+		challenge.Difficulty = c.config.App.BotProtection.Difficulty
+	}
+
+	return challenge, nil
+}
+
+// Verify verifies the token and proof-of-work solution submitted with a form with a given policy.
+// Returns a bot.DetectedError if a check failed.
+func (c *BotProtectionClient) Verify(ctx context.Context, policy bot.Policy, token, solution string) error {
+	if token == "" {
+		return bot.DetectedError{Reason: "missing token"}
+	}
+
+	issuedAt, err := c.keyring.Decrypt(KeyPurposeBotProtection, token)
+	if err != nil {
+		return bot.DetectedError{Reason: "invalid token"}
+	}
+
+	nanos, err := strconv.ParseInt(string(issuedAt), 10, 64)
+	if err != nil {
+		return bot.DetectedError{Reason: "invalid token"}
+	}
+
+	age := time.Since(time.Unix(0, nanos))
+	switch {
+	case age < policy.MinFillTime:
+		return bot.DetectedError{Reason: "submitted too quickly"}
+	case age > c.config.App.BotProtection.MaxAge:
+		return bot.DetectedError{Reason: "token expired"}
+	case policy.ProofOfWork && !bot.VerifyProofOfWork(token, solution, c.config.App.BotProtection.Difficulty):
+		return bot.DetectedError{Reason: "invalid proof-of-work"}
+	}
+
+	return c.useToken(ctx, token)
+}
+
+// useToken marks a given token as used, unless it already was
+func (c *BotProtectionClient) useToken(ctx context.Context, token string) error {
+	hash := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(hash[:])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.cache.
+		Get().
+		Group(botTokenCacheGroup).
+		Key(key).
+		Fetch(ctx)
+
+	switch {
+	case err == nil:
+		return bot.DetectedError{Reason: "token already used"}
+	case !errors.Is(err, ErrCacheMiss):
+		return err
+	}
+
+	// Tokens only have to be remembered until they expire
+	return c.cache.
+		Set().
+		Group(botTokenCacheGroup).
+		Key(key).
+		Data(true).
+		Expiration(c.config.App.BotProtection.MaxAge).
+		Save(ctx)
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mikestefanello/pagoda/pkg/bot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBotProtectionClient(t *testing.T) {
+	ctx := context.Background()
+	cfg := *c.Config
+	cfg.App.BotProtection.MaxAge = time.Hour
+	cfg.App.BotProtection.Difficulty = 8
+	client := NewBotProtectionClient(&cfg, c.Keyring, c.Cache)
+
+	policy := bot.Policy{}
+	issue := func(policy bot.Policy) bot.Challenge {
+		challenge, err := client.Issue(policy)
+		require.NoError(t, err)
+		return challenge
+	}
+
+	assert.Equal(t, cfg.App.BotProtection.MinFillTime, client.DefaultPolicy().MinFillTime)
+	assert.Equal(t, cfg.App.BotProtection.ProofOfWork, client.DefaultPolicy().ProofOfWork)
+
+	t.Run("valid", func(t *testing.T) {
+		challenge := issue(policy)
+		assert.NotEmpty(t, challenge.Token)
+		assert.Zero(t, challenge.Difficulty)
+		require.NoError(t, client.Verify(ctx, policy, challenge.Token, ""))
+
+		// Tokens can only be used once
+		assert.Equal(t, bot.DetectedError{Reason: "token already used"}, client.Verify(ctx, policy, challenge.Token, ""))
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		assert.Equal(t, bot.DetectedError{Reason: "missing token"}, client.Verify(ctx, policy, "", ""))
+		assert.Equal(t, bot.DetectedError{Reason: "invalid token"}, client.Verify(ctx, policy, "abc", ""))
+
+		// Tokens issued for other purposes are rejected
+		token, err := c.Keyring.Encrypt(KeyPurposeFieldEncryption, []byte(strconv.FormatInt(time.Now().UnixNano(), 10)))
+		require.NoError(t, err)
+		assert.Equal(t, bot.DetectedError{Reason: "invalid token"}, client.Verify(ctx, policy, token, ""))
+	})
+
+	t.Run("too quick", func(t *testing.T) {
+		policy := bot.Policy{MinFillTime: time.Hour}
+		challenge := issue(policy)
+		assert.Equal(t, bot.DetectedError{Reason: "submitted too quickly"}, client.Verify(ctx, policy, challenge.Token, ""))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		challenge := issue(policy)
+		cfg.App.BotProtection.MaxAge = time.Nanosecond
+		defer func() {
+			cfg.App.BotProtection.MaxAge = time.Hour
+		}()
+		time.Sleep(time.Millisecond)
+		assert.Equal(t, bot.DetectedError{Reason: "token expired"}, client.Verify(ctx, policy, challenge.Token, ""))
+	})
+
+	t.Run("proof of work", func(t *testing.T) {
+		policy := bot.Policy{ProofOfWork: true}
+		challenge := issue(policy)
+		assert.Equal(t, 8, challenge.Difficulty)
+
+		var solution string
+		for i := 0; solution == ""; i++ {
+			if bot.VerifyProofOfWork(challenge.Token, strconv.Itoa(i), challenge.Difficulty) {
+				solution = strconv.Itoa(i)
+			}
+		}
+
+		assert.Equal(t, bot.DetectedError{Reason: "invalid proof-of-work"}, client.Verify(ctx, policy, challenge.Token, ""))
+		require.NoError(t, client.Verify(ctx, policy, challenge.Token, solution))
+	})
+}
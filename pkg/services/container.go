@@ -45,9 +45,42 @@ type Container struct {
 	// Mail stores an email sending client
 	Mail *MailClient
 
+	// MailSuppression stores a client which manages the addresses and categories email must not be sent to
+	MailSuppression *MailSuppressionClient
+
+	// Notification stores a client which notifies users of events which affect the security of their account
+	Notification *NotificationClient
+
+	// Keyring stores the keys used to sign and encrypt data
+	Keyring *Keyring
+
 	// Auth stores an authentication client
 	Auth *AuthClient
 
+	// Throttle stores a client to throttle repeated attempts, such as failed logins
+	Throttle *ThrottleClient
+
+	// Authz stores a client for checking roles and permissions
+	Authz *AuthzClient
+
+	// Admin stores a client for generic access to all entities, used by the admin panel
+	Admin *AdminClient
+
+	// Account stores a client for self-service account operations, such as data exports and deletion
+	Account *AccountClient
+
+	// Audit stores a logger which records security-relevant events in the audit log
+	Audit *AuditLogger
+
+	// Organizations stores a client for organizations, their members and invitations
+	Organizations *OrganizationClient
+
+	// BotProtection stores a client which protects forms against spam bots
+	BotProtection *BotProtectionClient
+
+	// Registration stores a client which determines who is allowed to register, and manages invite codes
+	Registration *RegistrationClient
+
 	// TemplateRenderer stores a service to easily render and cache templates
 	TemplateRenderer *TemplateRenderer
 
@@ -64,10 +97,20 @@ func NewContainer() *Container {
 	c.initCache()
 	c.initDatabase()
 	c.initORM()
+	c.initKeyring()
 	c.initAuth()
+	c.initThrottle()
+	c.initAuthz()
+	c.initAdmin()
+	c.initAccount()
+	c.initAudit()
+	c.initOrganizations()
+	c.initRegistration()
+	c.initBotProtection()
 	c.initTemplateRenderer()
-	c.initMail()
 	c.initTasks()
+	c.initMail()
+	c.initNotification()
 	return c
 }
 
@@ -81,6 +124,7 @@ func (c *Container) Shutdown() error {
 		return err
 	}
 	c.Cache.Close()
+	c.Mail.Close()
 
 	return nil
 }
@@ -105,6 +149,15 @@ func (c *Container) initConfig() {
 // initValidator initializes the validator
 func (c *Container) initValidator() {
 	c.Validator = NewValidator()
+
+	policy, err := NewPasswordPolicy(c.Config)
+	if err != nil {
+		panic(err)
+	}
+
+	if err = c.Validator.RegisterPasswordPolicy(policy); err != nil {
+		panic(fmt.Sprintf("failed to register password policy: %v", err))
+	}
 }
 
 // initWeb initializes the web framework
@@ -154,9 +207,66 @@ func (c *Container) initORM() {
 	}
 }
 
+// initKeyring initializes the keyring
+func (c *Container) initKeyring() {
+	var err error
+	c.Keyring, err = NewKeyring(c.Config.App.Keyring)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create keyring: %v", err))
+	}
+}
+
 // initAuth initializes the authentication client
 func (c *Container) initAuth() {
-	c.Auth = NewAuthClient(c.Config, c.ORM)
+	var err error
+	c.Auth, err = NewAuthClient(c.Config, c.ORM, c.Keyring)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create auth client: %v", err))
+	}
+}
+
+// initThrottle initializes the throttle client
+func (c *Container) initThrottle() {
+	c.Throttle = NewThrottleClient(c.Config, c.Cache)
+}
+
+// initAuthz initializes the authorization client
+func (c *Container) initAuthz() {
+	c.Authz = NewAuthzClient(c.ORM)
+}
+
+// initAdmin initializes the admin client
+func (c *Container) initAdmin() {
+	c.Admin = NewAdminClient(c.ORM, c.Database, c.Config.Database.Driver)
+}
+
+// initAccount initializes the account client
+func (c *Container) initAccount() {
+	c.Account = NewAccountClient(c.Config, c.ORM, c.Auth)
+}
+
+// initAudit initializes the audit logger
+func (c *Container) initAudit() {
+	c.Audit = NewAuditLogger(c.Config, c.ORM)
+}
+
+// initOrganizations initializes the organization client
+func (c *Container) initOrganizations() {
+	c.Organizations = NewOrganizationClient(c.Config, c.ORM, c.Auth)
+}
+
+// initRegistration initializes the registration client
+func (c *Container) initRegistration() {
+	var err error
+	c.Registration, err = NewRegistrationClient(c.Config, c.ORM, c.Auth)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create registration client: %v", err))
+	}
+}
+
+// initBotProtection initializes the bot protection client
+func (c *Container) initBotProtection() {
+	c.BotProtection = NewBotProtectionClient(c.Config, c.Keyring, c.Cache)
 }
 
 // initTemplateRenderer initializes the template renderer
@@ -166,11 +276,47 @@ func (c *Container) initTemplateRenderer() {
 
 // initMail initialize the mail client
 func (c *Container) initMail() {
-	var err error
-	c.Mail, err = NewMailClient(c.Config, c.TemplateRenderer)
+	cfg := c.Config.Mail
+	tasks := c.Tasks
+
+	switch c.Config.App.Environment {
+	case config.EnvTest:
+		// Never deliver email while testing, so tests can inspect the inbox instead, and do so immediately since
+		// the task dispatcher is not running
+		cfg.Transport = config.MailTransportMemory
+		tasks = nil
+	case config.EnvLocal:
+	default:
+		// Captured email is only meant to be viewed during local development, so it should never pile up in memory
+		// while nobody is looking
+		if cfg.Transport == config.MailTransportMemory {
+			panic(fmt.Sprintf("the %s mail transport cannot be used in the %s environment", cfg.Transport, c.Config.App.Environment))
+		}
+
+		if c.Config.App.Environment == config.EnvProduction && cfg.Transport == config.MailTransportFile {
+			log.Default().Warn("email will not be delivered",
+				"transport", cfg.Transport,
+			)
+		}
+	}
+
+	transport, err := NewMailTransport(cfg)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create mail transport: %v", err))
+	}
+
+	dkim, err := NewDKIMSigner(cfg)
 	if err != nil {
-		panic(fmt.Sprintf("failed to create mail client: %v", err))
+		panic(fmt.Sprintf("failed to create dkim signer: %v", err))
 	}
+
+	c.MailSuppression = NewMailSuppressionClient(c.Config, c.ORM, c.Keyring, c.Web)
+	c.Mail = NewMailClient(c.Config, c.TemplateRenderer, transport, tasks, dkim, c.MailSuppression)
+}
+
+// initNotification initializes the notification client
+func (c *Container) initNotification() {
+	c.Notification = NewNotificationClient(c.Config, c.Auth, c.Mail, c.Web)
 }
 
 // initTasks initializes the task client
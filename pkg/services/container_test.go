@@ -14,7 +14,16 @@ func TestNewContainer(t *testing.T) {
 	assert.NotNil(t, c.Database)
 	assert.NotNil(t, c.ORM)
 	assert.NotNil(t, c.Mail)
+	assert.NotNil(t, c.Keyring)
 	assert.NotNil(t, c.Auth)
+	assert.NotNil(t, c.Throttle)
+	assert.NotNil(t, c.Authz)
+	assert.NotNil(t, c.Admin)
+	assert.NotNil(t, c.Account)
+	assert.NotNil(t, c.Audit)
+	assert.NotNil(t, c.Organizations)
+	assert.NotNil(t, c.Registration)
+	assert.NotNil(t, c.BotProtection)
 	assert.NotNil(t, c.TemplateRenderer)
 	assert.NotNil(t, c.Tasks)
 }
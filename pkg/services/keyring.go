@@ -0,0 +1,236 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/mikestefanello/pagoda/config"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// KeyPurposeSessionHash is the purpose of the keys used to authenticate session cookies
+	KeyPurposeSessionHash = "session.hash"
+
+	// KeyPurposeSessionBlock is the purpose of the keys used to encrypt session cookies
+	KeyPurposeSessionBlock = "session.block"
+
+	// KeyPurposeJWT is the purpose of the keys used to sign JSON Web Tokens
+	KeyPurposeJWT = "jwt"
+
+	// KeyPurposeTokenHash is the purpose of the keys used to hash tokens before they are stored
+	KeyPurposeTokenHash = "token.hash"
+
+	// KeyPurposeFieldEncryption is the purpose of the keys used to encrypt values before they are stored
+	KeyPurposeFieldEncryption = "field.encryption"
+
+	// KeyPurposeBotProtection is the purpose of the keys used to encrypt the tokens which protect forms against bots
+	KeyPurposeBotProtection = "bot.protection"
+
+	// KeyPurposeMailPreferences is the purpose of the keys used to encrypt the tokens which allow recipients to
+	// manage the email they receive
+	KeyPurposeMailPreferences = "mail.preferences"
+)
+
+// keyringKeyLength is the length, in bytes, of each derived key
+const keyringKeyLength = 32
+
+// keyringSeparator separates the key ID from the rest of an encrypted value
+const keyringSeparator = ":"
+
+// UnknownKeyError is an error returned when data was signed or encrypted with a key which is not in the keyring
+type UnknownKeyError struct{}
+
+// Error implements the error interface.
+func (e UnknownKeyError) Error() string {
+	return "unknown key"
+}
+
+type (
+	// Keyring holds the secret keys used to sign and encrypt data, such as sessions and tokens.
+	// The primary key is used to sign and encrypt, while all keys, including retired ones, are used to verify and
+	// decrypt, so keys can be rotated without invalidating existing data.
+	// Secrets are never used directly. Instead, a separate key is derived from each secret for each purpose, so a
+	// key used for one purpose cannot be used to forge or read data of another.
+	Keyring struct {
+		// keys stores all keys, with the primary key first
+		keys []keyringKey
+
+		// legacy stores the key used directly before the keyring existed, which is only used to verify
+		legacy []byte
+
+		// derived caches the keys derived from each secret, keyed by key ID and purpose
+		derived sync.Map
+	}
+
+	// keyringKey is a secret key along with its ID
+	keyringKey struct {
+		id     string
+		secret []byte
+	}
+)
+
+// NewKeyring creates a new Keyring from configuration
+func NewKeyring(cfg config.KeyringConfig) (*Keyring, error) {
+	k := &Keyring{
+		keys: make([]keyringKey, 0, len(cfg.Retired)+1),
+	}
+
+	ids := make(map[string]bool)
+	for _, key := range append([]config.KeyConfig{cfg.Primary}, cfg.Retired...) {
+		switch {
+		case key.ID == "":
+			return nil, errors.New("keyring: key ID is required")
+		case strings.Contains(key.ID, keyringSeparator):
+			return nil, fmt.Errorf("keyring: key ID %q must not contain %q", key.ID, keyringSeparator)
+		case key.Secret == "":
+			return nil, fmt.Errorf("keyring: secret of key %q is required", key.ID)
+		case ids[key.ID]:
+			return nil, fmt.Errorf("keyring: duplicate key ID %q", key.ID)
+		}
+
+		ids[key.ID] = true
+		k.keys = append(k.keys, keyringKey{
+			id:     key.ID,
+			secret: []byte(key.Secret),
+		})
+	}
+
+	if cfg.Legacy != "" {
+		k.legacy = []byte(cfg.Legacy)
+	}
+
+	return k, nil
+}
+
+// PrimaryID returns the ID of the primary key
+func (k *Keyring) PrimaryID() string {
+	return k.keys[0].id
+}
+
+// Primary returns the key derived from the primary key for a given purpose, which should be used to sign and encrypt
+func (k *Keyring) Primary(purpose string) []byte {
+	return k.derive(k.keys[0], purpose)
+}
+
+// Get returns the key derived from the key of a given ID for a given purpose, and if the key exists
+func (k *Keyring) Get(id, purpose string) ([]byte, bool) {
+	for _, key := range k.keys {
+		if key.id == id {
+			return k.derive(key, purpose), true
+		}
+	}
+	return nil, false
+}
+
+// All returns the keys derived from every key for a given purpose, with the primary key first, which should be used
+// to verify data that does not identify the key it was signed with
+func (k *Keyring) All(purpose string) [][]byte {
+	keys := make([][]byte, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, k.derive(key, purpose))
+	}
+	return keys
+}
+
+// Legacy returns the key used directly before the keyring existed, and if it is set.
+// It is not derived for any purpose, since that is how it was used, and must only be used to verify.
+func (k *Keyring) Legacy() ([]byte, bool) {
+	return k.legacy, k.legacy != nil
+}
+
+// SessionKeys returns the pairs of authentication and encryption keys for every key, with the primary key first,
+// for use with cookie stores.
+// If there is a legacy key, it is last, without an encryption key, since cookies were only authenticated with it.
+func (k *Keyring) SessionKeys() [][]byte {
+	pairs := make([][]byte, 0, len(k.keys)*2+2)
+	for _, key := range k.keys {
+		pairs = append(pairs, k.derive(key, KeyPurposeSessionHash), k.derive(key, KeyPurposeSessionBlock))
+	}
+	if legacy, ok := k.Legacy(); ok {
+		pairs = append(pairs, legacy, nil)
+	}
+	return pairs
+}
+
+// Encrypt encrypts a given value with AES-GCM using the primary key derived for a given purpose.
+// The returned value is prefixed with the ID of the key so it can be decrypted after the key has been rotated.
+func (k *Keyring) Encrypt(purpose string, plaintext []byte) (string, error) {
+	gcm, err := k.cipher(k.Primary(purpose))
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+
+	return k.PrimaryID() + keyringSeparator + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value encrypted by Encrypt for a given purpose, using whichever key it was encrypted with
+func (k *Keyring) Decrypt(purpose, ciphertext string) ([]byte, error) {
+	id, encoded, ok := strings.Cut(ciphertext, keyringSeparator)
+	if !ok {
+		return nil, errors.New("keyring: invalid ciphertext")
+	}
+
+	key, ok := k.Get(id, purpose)
+	if !ok {
+		return nil, UnknownKeyError{}
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("keyring: invalid ciphertext")
+	}
+
+	gcm, err := k.cipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("keyring: invalid ciphertext")
+	}
+
+	nonce, sealed := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, nil)
+}
+
+// derive derives a key for a given purpose from a given key using HKDF, caching the result
+func (k *Keyring) derive(key keyringKey, purpose string) []byte {
+	cacheKey := key.id + keyringSeparator + purpose
+	if derived, ok := k.derived.Load(cacheKey); ok {
+		return derived.([]byte)
+	}
+
+	derived := make([]byte, keyringKeyLength)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, key.secret, nil, []byte(purpose)), derived); err != nil {
+		// This can only happen if far more bytes are requested than HKDF allows
+		panic(err)
+	}
+
+	k.derived.Store(cacheKey, derived)
+	return derived
+}
+
+// cipher returns an AES-GCM cipher for a given key
+func (k *Keyring) cipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
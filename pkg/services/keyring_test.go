@@ -0,0 +1,187 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/gorilla/sessions"
+	"github.com/mikestefanello/pagoda/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyring(t *testing.T) {
+	key := func(id, secret string) config.KeyConfig {
+		return config.KeyConfig{ID: id, Secret: secret}
+	}
+
+	cases := map[string]config.KeyringConfig{
+		"missing ID":     {Primary: key("", "secret")},
+		"missing secret": {Primary: key("1", "")},
+		"invalid ID":     {Primary: key("a:b", "secret")},
+		"duplicate ID":   {Primary: key("1", "a"), Retired: []config.KeyConfig{key("1", "b")}},
+	}
+
+	for name, cfg := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewKeyring(cfg)
+			assert.Error(t, err)
+		})
+	}
+
+	k, err := NewKeyring(config.KeyringConfig{
+		Primary: key("2", "new"),
+		Retired: []config.KeyConfig{key("1", "old")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2", k.PrimaryID())
+	assert.Len(t, k.All(KeyPurposeJWT), 2)
+	assert.Len(t, k.SessionKeys(), 4)
+}
+
+func TestKeyring_DerivedKeys(t *testing.T) {
+	k, err := NewKeyring(config.KeyringConfig{Primary: config.KeyConfig{ID: "1", Secret: "secret"}})
+	require.NoError(t, err)
+
+	// Keys are derived per purpose and never equal the secret
+	jwtKey := k.Primary(KeyPurposeJWT)
+	assert.Len(t, jwtKey, keyringKeyLength)
+	assert.NotEqual(t, jwtKey, k.Primary(KeyPurposeTokenHash))
+	assert.NotEqual(t, []byte("secret"), jwtKey)
+	assert.Equal(t, jwtKey, k.Primary(KeyPurposeJWT))
+
+	got, ok := k.Get("1", KeyPurposeJWT)
+	assert.True(t, ok)
+	assert.Equal(t, jwtKey, got)
+
+	_, ok = k.Get("2", KeyPurposeJWT)
+	assert.False(t, ok)
+}
+
+func TestKeyring_Encrypt(t *testing.T) {
+	old, err := NewKeyring(config.KeyringConfig{Primary: config.KeyConfig{ID: "1", Secret: "old"}})
+	require.NoError(t, err)
+
+	encrypted, err := old.Encrypt(KeyPurposeFieldEncryption, []byte("value"))
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "value")
+
+	decrypted, err := old.Decrypt(KeyPurposeFieldEncryption, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), decrypted)
+
+	// Keys of other purposes cannot decrypt the value
+	_, err = old.Decrypt(KeyPurposeJWT, encrypted)
+	assert.Error(t, err)
+
+	// Values encrypted with a retired key can still be decrypted
+	rotated, err := NewKeyring(config.KeyringConfig{
+		Primary: config.KeyConfig{ID: "2", Secret: "new"},
+		Retired: []config.KeyConfig{{ID: "1", Secret: "old"}},
+	})
+	require.NoError(t, err)
+	decrypted, err = rotated.Decrypt(KeyPurposeFieldEncryption, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), decrypted)
+
+	// New values are encrypted with the primary key
+	encrypted, err = rotated.Encrypt(KeyPurposeFieldEncryption, []byte("value"))
+	require.NoError(t, err)
+	_, err = old.Decrypt(KeyPurposeFieldEncryption, encrypted)
+	assert.Equal(t, UnknownKeyError{}, err)
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	oldCfg := config.KeyringConfig{Primary: config.KeyConfig{ID: "1", Secret: "old"}}
+	newCfg := config.KeyringConfig{
+		Primary: config.KeyConfig{ID: "2", Secret: "new"},
+		Retired: []config.KeyConfig{oldCfg.Primary},
+	}
+
+	authClient := func(cfg config.KeyringConfig) *AuthClient {
+		k, err := NewKeyring(cfg)
+		require.NoError(t, err)
+		a, err := NewAuthClient(c.Config, c.ORM, k)
+		require.NoError(t, err)
+		return a
+	}
+	oldAuth, newAuth := authClient(oldCfg), authClient(newCfg)
+
+	// Tokens signed with a retired key are still valid
+	token, err := oldAuth.GenerateEmailChangeToken(1, "old@localhost", "new@localhost")
+	require.NoError(t, err)
+	_, _, email, err := newAuth.ValidateEmailChangeToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "new@localhost", email)
+
+	// Tokens signed with a key which is no longer in the keyring are not
+	token, err = newAuth.GenerateEmailChangeToken(1, "old@localhost", "new@localhost")
+	require.NoError(t, err)
+	_, _, _, err = oldAuth.ValidateEmailChangeToken(token)
+	assert.Error(t, err)
+
+	// Hashes stored with a retired key still match
+	hash := oldAuth.hashToken("token")
+	assert.NotEqual(t, hash, newAuth.hashToken("token"))
+	assert.True(t, newAuth.tokenHashMatches("token", hash))
+	assert.False(t, newAuth.tokenHashMatches("other", hash))
+}
+
+func TestKeyring_Legacy(t *testing.T) {
+	cfg := config.KeyringConfig{
+		Primary: config.KeyConfig{ID: "1", Secret: "secret"},
+		Legacy:  "legacy",
+	}
+	k, err := NewKeyring(cfg)
+	require.NoError(t, err)
+	legacy, ok := k.Legacy()
+	assert.True(t, ok)
+	assert.Equal(t, []byte("legacy"), legacy)
+	assert.Len(t, k.SessionKeys(), 4)
+
+	// Session cookies issued before the keyring existed can still be read
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	legacyStore := sessions.NewCookieStore([]byte("legacy"))
+	sess, err := legacyStore.New(req, "session")
+	require.NoError(t, err)
+	sess.Values["key"] = "value"
+	require.NoError(t, sess.Save(req, rec))
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range rec.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	sess, err = sessions.NewCookieStore(k.SessionKeys()...).New(req, "session")
+	require.NoError(t, err)
+	assert.False(t, sess.IsNew)
+	assert.Equal(t, "value", sess.Values["key"])
+
+	a, err := NewAuthClient(c.Config, c.ORM, k)
+	require.NoError(t, err)
+
+	// Tokens signed before the keyring existed, which have no key ID, can still be verified
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"email": "legacy@localhost",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("legacy"))
+	require.NoError(t, err)
+	claims, err := a.validateEmailToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy@localhost", claims["email"])
+
+	// But not without the legacy key
+	_, err = c.Auth.validateEmailToken(token)
+	assert.Error(t, err)
+
+	// Hashes stored before the keyring existed still match
+	hash := hmacHex([]byte("legacy"), "token")
+	assert.True(t, a.tokenHashMatches("token", hash))
+	assert.False(t, c.Auth.tokenHashMatches("token", hash))
+
+	// New data is never signed with the legacy key
+	assert.NotEqual(t, hash, a.hashToken("token"))
+}
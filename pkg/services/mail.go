@@ -1,46 +1,104 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"path"
 
+	"github.com/mikestefanello/backlite"
 	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/ent"
 	"github.com/mikestefanello/pagoda/pkg/log"
 
 	"github.com/labstack/echo/v4"
 )
 
 type (
-	// MailClient provides a client for sending email
-	// This is purposely not completed because there are many different methods and services
-	// for sending email, many of which are very different. Choose what works best for you
-	// and populate the methods below
+	// MailClient provides a client for sending email, which is queued to be delivered in the background by the
+	// transport in configuration
 	MailClient struct {
 		// config stores application configuration
 		config *config.Config
 
 		// templates stores the template renderer
 		templates *TemplateRenderer
+
+		// transport delivers the email
+		transport MailTransport
+
+		// tasks queues email to be delivered in the background
+		tasks *backlite.Client
+
+		// dkim signs email, if signing is enabled
+		dkim *DKIMSigner
+
+		// suppressions prevents email from being sent to suppressed addresses
+		suppressions *MailSuppressionClient
 	}
 
 	// mail represents an email to be sent
 	mail struct {
 		client       *MailClient
 		from         string
-		to           string
+		to           []string
+		cc           []string
+		bcc          []string
+		replyTo      []string
+		category     string
 		subject      string
 		body         string
 		template     string
 		templateData any
+		headers      [][2]string
+		attachments  []mailAttachment
+		tx           *ent.Tx
+		now          bool
+	}
+
+	// mailAttachment is a file to be attached to an email, read from either a reader or a file system
+	mailAttachment struct {
+		name   string
+		reader io.Reader
+		fsys   fs.FS
+		path   string
+		inline bool
 	}
 )
 
-// NewMailClient creates a new MailClient
-func NewMailClient(cfg *config.Config, templates *TemplateRenderer) (*MailClient, error) {
+// NewMailClient creates a new MailClient.
+// If no task client is provided, email is always delivered immediately rather than queued, if no DKIM signer is
+// provided, email is not signed, and if no suppression client is provided, email is never suppressed.
+func NewMailClient(
+	cfg *config.Config,
+	templates *TemplateRenderer,
+	transport MailTransport,
+	tasks *backlite.Client,
+	dkim *DKIMSigner,
+	suppressions *MailSuppressionClient,
+) *MailClient {
 	return &MailClient{
-		config:    cfg,
-		templates: templates,
-	}, nil
+		config:       cfg,
+		templates:    templates,
+		transport:    transport,
+		tasks:        tasks,
+		dkim:         dkim,
+		suppressions: suppressions,
+	}
+}
+
+// Close releases the resources held by the transport, such as the connection to the mail server
+func (m *MailClient) Close() {
+	m.transport.Close()
+}
+
+// Inbox returns the transport capturing email in memory, or nil if email is delivered by another transport
+func (m *MailClient) Inbox() *MemoryTransport {
+	inbox, _ := m.transport.(*MemoryTransport)
+	return inbox
 }
 
 // Compose creates a new email
@@ -51,16 +109,11 @@ func (m *MailClient) Compose() *mail {
 	}
 }
 
-// skipSend determines if mail sending should be skipped
-func (m *MailClient) skipSend() bool {
-	return m.config.App.Environment != config.EnvProduction
-}
-
 // send attempts to send the email
-func (m *MailClient) send(email *mail, ctx echo.Context) error {
+func (m *MailClient) send(ctx context.Context, email *mail, logger *slog.Logger) error {
 	switch {
-	case email.to == "":
-		return errors.New("email cannot be sent without a to address")
+	case len(email.to) == 0 && len(email.cc) == 0 && len(email.bcc) == 0:
+		return errors.New("email cannot be sent without a recipient")
 	case email.body == "" && email.template == "":
 		return errors.New("email cannot be sent without a body or template")
 	}
@@ -83,15 +136,112 @@ func (m *MailClient) send(email *mail, ctx echo.Context) error {
 		email.body = buf.String()
 	}
 
-	// Check if mail sending should be skipped
-	if m.skipSend() {
-		log.Ctx(ctx).Debug("skipping email delivery",
-			"to", email.to,
-		)
-		return nil
+	msg, err := newMessage(email)
+	if err != nil {
+		return err
+	}
+
+	if email.category != "" {
+		if _, ok := GetMailCategory(email.category); !ok {
+			return fmt.Errorf("invalid mail category: %q", email.category)
+		}
+
+		// Unsubscribe links are specific to the recipient
+		if len(msg.to) != 1 || len(msg.cc) != 0 || len(msg.bcc) != 0 {
+			return errors.New("email with a category must be sent to a single recipient")
+		}
+	}
+
+	if m.suppressions != nil {
+		// Within a transaction, the check must be made by it, since it may hold the only connection to the database
+		suppressed, err := m.suppressions.suppressed(ctx, email.tx, msg.recipients(), email.category)
+		if err != nil {
+			return fmt.Errorf("unable to check suppressed addresses: %w", err)
+		}
+
+		if len(suppressed) > 0 {
+			addrs := make([]string, 0, len(suppressed))
+			for addr := range suppressed {
+				addrs = append(addrs, addr)
+			}
+
+			logger.Info("email suppressed",
+				"to", addrs,
+				"category", email.category,
+			)
+
+			if !msg.suppress(suppressed) {
+				return nil
+			}
+		}
+
+		if email.category != "" {
+			url, err := m.suppressions.UnsubscribeURL(msg.to[0].Address, email.category)
+			if err != nil {
+				return err
+			}
+
+			msg.headers = append(msg.headers,
+				[2]string{"List-Unsubscribe", "<" + url + ">"},
+				[2]string{"List-Unsubscribe-Post", "List-Unsubscribe=One-Click"},
+			)
+		}
+	}
+
+	data, err := msg.bytes()
+	if err != nil {
+		return err
+	}
+
+	if m.dkim != nil {
+		if data, err = m.dkim.Sign(data); err != nil {
+			return err
+		}
+	}
+
+	from, to := msg.envelope()
+	task := MailTask{
+		MessageID: msg.id,
+		From:      from,
+		To:        to,
+		Message:   data,
+	}
+
+	queue := !email.now && m.tasks != nil
+
+	switch {
+	case email.tx == nil && queue:
+		return m.enqueue(m.tasks.Add(task).Ctx(ctx), task, logger)
+	case email.tx == nil:
+		return m.deliver(ctx, task, logger)
+	case queue:
+		// Queue the task within the transaction, so it is only queued if the transaction is committed
+		tx, ok := email.tx.SQLTx()
+		if !ok {
+			return errors.New("email can only be queued within a transaction started by a database/sql driver")
+		}
+		return m.enqueue(m.tasks.Add(task).Ctx(ctx).Tx(tx), task, logger)
 	}
 
-	// TODO: Finish based on your mail sender of choice!
+	// Wait until the transaction is committed to deliver, so nothing is sent if it is rolled back
+	email.tx.OnCommit(func(next ent.Committer) ent.Committer {
+		return ent.CommitFunc(func(txCtx context.Context, tx *ent.Tx) error {
+			if err := next.Commit(txCtx, tx); err != nil {
+				return err
+			}
+
+			// The transaction cannot be undone at this point, so failing to send is only logged
+			if err := m.deliver(ctx, task, logger); err != nil {
+				logger.Error("unable to send email after transaction commit",
+					"to", email.to,
+					"message_id", msg.id,
+					"error", err,
+				)
+			}
+			return nil
+		})
+	})
+
 	return nil
 }
 
@@ -101,9 +251,69 @@ func (m *mail) From(from string) *mail {
 	return m
 }
 
-// To sets the email address this email will be sent to
-func (m *mail) To(to string) *mail {
-	m.to = to
+// To adds addresses this email will be sent to.
+// Each address can include a display name, ie: "Jane <jane@example.com>", or be a comma-separated list of addresses.
+func (m *mail) To(to ...string) *mail {
+	m.to = append(m.to, to...)
+	return m
+}
+
+// Cc adds addresses this email will be copied to
+func (m *mail) Cc(cc ...string) *mail {
+	m.cc = append(m.cc, cc...)
+	return m
+}
+
+// Bcc adds addresses this email will be blind copied to, which are not visible to the other recipients
+func (m *mail) Bcc(bcc ...string) *mail {
+	m.bcc = append(m.bcc, bcc...)
+	return m
+}
+
+// ReplyTo adds addresses that replies to this email should be sent to, rather than the from address
+func (m *mail) ReplyTo(replyTo ...string) *mail {
+	m.replyTo = append(m.replyTo, replyTo...)
+	return m
+}
+
+// Category sets the category of notifications the email belongs to, which recipients can unsubscribe from, such as
+// MailCategorySecurity.
+// The email must be sent to a single recipient, since it includes a link which unsubscribes them.
+func (m *mail) Category(category string) *mail {
+	m.category = category
+	return m
+}
+
+// Header adds a custom header to the email.
+// Headers set by the mail client, such as Subject, cannot be added.
+func (m *mail) Header(key, value string) *mail {
+	m.headers = append(m.headers, [2]string{key, value})
+	return m
+}
+
+// Attach attaches a file with a given name, which is read from a given reader when the email is sent
+func (m *mail) Attach(name string, r io.Reader) *mail {
+	m.attachments = append(m.attachments, mailAttachment{name: name, reader: r})
+	return m
+}
+
+// AttachFile attaches the file at a given path within a given file system
+func (m *mail) AttachFile(fsys fs.FS, name string) *mail {
+	m.attachments = append(m.attachments, mailAttachment{name: path.Base(name), fsys: fsys, path: name})
+	return m
+}
+
+// Inline attaches a file with a given name, which is read from a given reader when the email is sent, to be
+// displayed within an HTML body by referencing its name as the content ID, ie: <img src="cid:logo.png">
+func (m *mail) Inline(name string, r io.Reader) *mail {
+	m.attachments = append(m.attachments, mailAttachment{name: name, reader: r, inline: true})
+	return m
+}
+
+// InlineFile attaches the file at a given path within a given file system, to be displayed within an HTML body by
+// referencing its file name as the content ID, ie: <img src="cid:logo.png">
+func (m *mail) InlineFile(fsys fs.FS, name string) *mail {
+	m.attachments = append(m.attachments, mailAttachment{name: path.Base(name), fsys: fsys, path: name, inline: true})
 	return m
 }
 
@@ -136,7 +346,32 @@ func (m *mail) TemplateData(data any) *mail {
 	return m
 }
 
-// Send attempts to send the email
+// Tx joins a given transaction, so the email is only sent once it is committed, and never if it is rolled back
+func (m *mail) Tx(tx *ent.Tx) *mail {
+	m.tx = tx
+	return m
+}
+
+// Send queues the email to be delivered in the background
 func (m *mail) Send(ctx echo.Context) error {
-	return m.client.send(m, ctx)
+	return m.client.send(ctx.Request().Context(), m, log.Ctx(ctx))
+}
+
+// SendContext queues the email to be delivered in the background, outside of an HTTP request, such as from
+// within a task
+func (m *mail) SendContext(ctx context.Context) error {
+	return m.client.send(ctx, m, log.Default())
+}
+
+// SendNow delivers the email immediately rather than queuing it, for cases where the request must not complete
+// until the email has been accepted by the mail server
+func (m *mail) SendNow(ctx echo.Context) error {
+	m.now = true
+	return m.client.send(ctx.Request().Context(), m, log.Ctx(ctx))
+}
+
+// SendNowContext delivers the email immediately rather than queuing it, outside of an HTTP request
+func (m *mail) SendNowContext(ctx context.Context) error {
+	m.now = true
+	return m.client.send(ctx, m, log.Default())
 }
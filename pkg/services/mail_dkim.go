@@ -0,0 +1,313 @@
+package services
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mikestefanello/pagoda/config"
+)
+
+// dkimHeaders are the headers which are signed, if they are present in the message
+var dkimHeaders = []string{
+	"From",
+	"To",
+	"Cc",
+	"Reply-To",
+	"Subject",
+	"Date",
+	"Message-ID",
+	"MIME-Version",
+	"Content-Type",
+	"Content-Transfer-Encoding",
+	"List-Unsubscribe",
+	"List-Unsubscribe-Post",
+}
+
+// DKIMSigner signs messages with DKIM (RFC 6376), which allows mail servers to verify that a message was sent on
+// behalf of a domain and was not modified on the way, by checking the signature against a public key published in
+// the DNS records of the domain
+type DKIMSigner struct {
+	// domain is the domain the messages are signed on behalf of
+	domain string
+
+	// selector identifies the key within the DNS records of the domain
+	selector string
+
+	// key is the private key, either RSA or Ed25519
+	key crypto.Signer
+
+	// now returns the current time, used as the signature timestamp
+	now func() time.Time
+}
+
+// NewDKIMSigner creates a new DKIMSigner using the private key in a given mail configuration.
+// Nil is returned if no domain is configured, since signing is optional.
+func NewDKIMSigner(cfg config.MailConfig) (*DKIMSigner, error) {
+	if cfg.DKIM.Domain == "" {
+		return nil, nil
+	}
+
+	if cfg.DKIM.Selector == "" {
+		return nil, errors.New("dkim selector is required")
+	}
+
+	data := []byte(cfg.DKIM.PrivateKey)
+	if cfg.DKIM.PrivateKeyFile != "" {
+		var err error
+		if data, err = os.ReadFile(cfg.DKIM.PrivateKeyFile); err != nil {
+			return nil, fmt.Errorf("unable to read dkim private key: %w", err)
+		}
+	}
+
+	key, err := ParseDKIMPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DKIMSigner{
+		domain:   strings.ToLower(cfg.DKIM.Domain),
+		selector: cfg.DKIM.Selector,
+		key:      key,
+		now:      time.Now,
+	}, nil
+}
+
+// GenerateDKIMKey generates a private key of a given type, either "rsa" or "ed25519", and encodes it as PEM.
+// RSA keys are the most widely supported, while Ed25519 keys are much shorter but not yet verified by every
+// mail server, so they are best used alongside an RSA key with a different selector.
+func GenerateDKIMKey(keyType string, bits int) (crypto.Signer, []byte, error) {
+	var key crypto.Signer
+	var err error
+
+	switch keyType {
+	case "rsa":
+		if bits < 1024 {
+			return nil, nil, errors.New("rsa keys must be at least 1024 bits")
+		}
+		key, err = rsa.GenerateKey(rand.Reader, bits)
+	case "ed25519":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, nil, fmt.Errorf("invalid dkim key type: %q", keyType)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// ParseDKIMPrivateKey parses a PEM encoded RSA or Ed25519 private key, in either PKCS #8 or PKCS #1 form
+func ParseDKIMPrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("dkim private key is not PEM encoded")
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse dkim private key: %w", err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported dkim private key type: %T", key)
+	}
+}
+
+// DKIMRecord returns the value of the DNS TXT record which publishes the public key of a given private key
+func DKIMRecord(key crypto.Signer) (string, error) {
+	switch pub := key.Public().(type) {
+	case *rsa.PublicKey:
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			return "", err
+		}
+		return "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(der), nil
+	case ed25519.PublicKey:
+		return "v=DKIM1; k=ed25519; p=" + base64.StdEncoding.EncodeToString(pub), nil
+	default:
+		return "", fmt.Errorf("unsupported dkim public key type: %T", pub)
+	}
+}
+
+// RecordName returns the name of the DNS TXT record which the public key must be published at
+func (d *DKIMSigner) RecordName() string {
+	return fmt.Sprintf("%s._domainkey.%s", d.selector, d.domain)
+}
+
+// Sign signs a given encoded message and returns it with the DKIM-Signature header prepended.
+// Both the headers and the body are canonicalized with the relaxed algorithm, which tolerates the whitespace changes
+// that mail servers commonly make, such as re-folding headers.
+func (d *DKIMSigner) Sign(msg []byte) ([]byte, error) {
+	i := bytes.Index(msg, []byte("\r\n\r\n"))
+	if i == -1 {
+		return nil, errors.New("message has no body")
+	}
+
+	header, body := msg[:i+2], msg[i+4:]
+
+	var algorithm string
+	switch d.key.(type) {
+	case *rsa.PrivateKey:
+		algorithm = "rsa-sha256"
+	case ed25519.PrivateKey:
+		algorithm = "ed25519-sha256"
+	default:
+		return nil, fmt.Errorf("unsupported dkim private key type: %T", d.key)
+	}
+
+	// Sign every instance of the headers which are present, from the bottom up
+	fields := splitHeaderFields(header)
+	var names []string
+	var signed bytes.Buffer
+	for _, name := range dkimHeaders {
+		key := strings.ToLower(name)
+		instances := fields[key]
+		for i := len(instances) - 1; i >= 0; i-- {
+			names = append(names, key)
+			signed.WriteString(relaxedHeader(instances[i]) + "\r\n")
+		}
+	}
+
+	// Listing From once more than it is present asserts that there are no other instances, so a second From header
+	// cannot be added to the message without breaking the signature
+	names = append(names, "from")
+
+	bh := sha256.Sum256(relaxedBody(body))
+	value := fmt.Sprintf("v=1; a=%s; c=relaxed/relaxed; d=%s; s=%s; t=%d; h=%s; bh=%s; b=",
+		algorithm,
+		d.domain,
+		d.selector,
+		d.now().Unix(),
+		strings.Join(names, ":"),
+		base64.StdEncoding.EncodeToString(bh[:]),
+	)
+
+	// The signature covers its own header, with an empty signature and without the trailing line break
+	signed.WriteString(relaxedHeader("DKIM-Signature: " + value))
+	hash := sha256.Sum256(signed.Bytes())
+
+	var sig []byte
+	var err error
+	if algorithm == "rsa-sha256" {
+		sig, err = d.key.Sign(rand.Reader, hash[:], crypto.SHA256)
+	} else {
+		sig, err = d.key.Sign(rand.Reader, hash[:], crypto.Hash(0))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign message: %w", err)
+	}
+
+	// Split the signature with whitespace, which is ignored, so the header can be folded
+	b := base64.StdEncoding.EncodeToString(sig)
+	for len(b) > 64 {
+		value += b[:64] + " "
+		b = b[64:]
+	}
+	value += b
+
+	var buf bytes.Buffer
+	writeHeader(&buf, "DKIM-Signature", value)
+	buf.Write(msg)
+	return buf.Bytes(), nil
+}
+
+// splitHeaderFields splits a given header section into its fields, including any folded lines, keyed by lowercase
+// name in the order they appear
+func splitHeaderFields(header []byte) map[string][]string {
+	fields := make(map[string][]string)
+	last := ""
+
+	for _, line := range strings.Split(strings.TrimSuffix(string(header), "\r\n"), "\r\n") {
+		// Lines starting with whitespace continue the previous field
+		if last != "" && line != "" && (line[0] == ' ' || line[0] == '\t') {
+			n := len(fields[last]) - 1
+			fields[last][n] += "\r\n" + line
+			continue
+		}
+
+		name, _, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		last = strings.ToLower(strings.TrimRight(name, " \t"))
+		fields[last] = append(fields[last], line)
+	}
+
+	return fields
+}
+
+// relaxedHeader canonicalizes a given header field with the relaxed algorithm by lowercasing its name, unfolding it
+// and reducing whitespace
+func relaxedHeader(field string) string {
+	name, value, _ := strings.Cut(field, ":")
+	value = reduceWhitespace(strings.ReplaceAll(value, "\r\n", ""))
+	return strings.ToLower(strings.TrimRight(name, " \t")) + ":" + strings.Trim(value, " ")
+}
+
+// relaxedBody canonicalizes a given body with the relaxed algorithm by reducing whitespace within lines, removing it
+// from the end of lines, and removing empty lines from the end of the body
+func relaxedBody(body []byte) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(reduceWhitespace(line), " ")
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+// reduceWhitespace replaces every sequence of spaces and tabs within a given string with a single space
+func reduceWhitespace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
@@ -0,0 +1,477 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/http"
+	netmail "net/mail"
+	"net/textproto"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxHeaderLineLength is the length header lines are folded at, as recommended by RFC 5322
+const maxHeaderLineLength = 78
+
+// reservedHeaders are the headers which are set by the mail client, so they cannot be set as custom headers
+var reservedHeaders = map[string]bool{
+	"From":                      true,
+	"To":                        true,
+	"Cc":                        true,
+	"Bcc":                       true,
+	"Reply-To":                  true,
+	"Subject":                   true,
+	"Date":                      true,
+	"Message-Id":                true,
+	"Mime-Version":              true,
+	"Content-Type":              true,
+	"Content-Transfer-Encoding": true,
+	"Content-Disposition":       true,
+	"Content-Id":                true,
+}
+
+type (
+	// message is a MIME message built from an email, ready to be delivered
+	message struct {
+		// from is the address the message is sent from
+		from *netmail.Address
+
+		// to, cc and bcc are the addresses the message is sent to
+		to  []*netmail.Address
+		cc  []*netmail.Address
+		bcc []*netmail.Address
+
+		// replyTo are the addresses replies should be sent to, if not the from address
+		replyTo []*netmail.Address
+
+		// subject is the subject line of the message
+		subject string
+
+		// body is the body of the message
+		body string
+
+		// html indicates that the body is HTML rather than plain text
+		html bool
+
+		// headers are custom headers, in the order they were added
+		headers [][2]string
+
+		// attachments are the files attached to the message, including inline ones
+		attachments []attachment
+
+		// date is when the message was created
+		date time.Time
+
+		// id is the unique ID of the message, without angle brackets
+		id string
+	}
+
+	// attachment is a file attached to a message
+	attachment struct {
+		// name is the file name of the attachment
+		name string
+
+		// contentType is the media type of the attachment
+		contentType string
+
+		// data is the content of the attachment
+		data []byte
+
+		// contentID identifies inline attachments so the body can reference them, ie: <img src="cid:logo.png">
+		contentID string
+	}
+
+	// entity is a MIME entity, either the whole body of a message or a part of a multipart one
+	entity struct {
+		header textproto.MIMEHeader
+		body   []byte
+	}
+)
+
+// newMessage creates a message for a given email, parsing, validating and normalizing its addresses and reading its
+// attachments
+func newMessage(email *mail) (*message, error) {
+	from, err := parseAddresses(email.from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+	if len(from) != 1 {
+		return nil, fmt.Errorf("exactly one from address is required: %q", email.from)
+	}
+
+	msg := &message{
+		from:    from[0],
+		subject: email.subject,
+		body:    email.body,
+		html:    email.template != "",
+		date:    time.Now(),
+	}
+
+	recipients := []struct {
+		name  string
+		addrs []string
+		dst   *[]*netmail.Address
+	}{
+		{"to", email.to, &msg.to},
+		{"cc", email.cc, &msg.cc},
+		{"bcc", email.bcc, &msg.bcc},
+		{"reply-to", email.replyTo, &msg.replyTo},
+	}
+
+	for _, r := range recipients {
+		if *r.dst, err = parseAddresses(r.addrs...); err != nil {
+			return nil, fmt.Errorf("invalid %s address: %w", r.name, err)
+		}
+	}
+
+	for _, h := range email.headers {
+		if err = validateHeader(h[0], h[1]); err != nil {
+			return nil, err
+		}
+		msg.headers = append(msg.headers, [2]string{textproto.CanonicalMIMEHeaderKey(h[0]), h[1]})
+	}
+
+	for _, a := range email.attachments {
+		att, err := a.read()
+		if err != nil {
+			return nil, err
+		}
+		msg.attachments = append(msg.attachments, att)
+	}
+
+	if msg.id, err = newMessageID(msg.from.Address); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// parseAddresses parses given address lists, such as "Jane <jane@example.com>, john@example.com", and normalizes
+// the addresses by lowercasing their domain and collapsing whitespace within their display name
+func parseAddresses(lists ...string) ([]*netmail.Address, error) {
+	var addrs []*netmail.Address
+	for _, list := range lists {
+		parsed, err := netmail.ParseAddressList(list)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", list, err)
+		}
+
+		for _, addr := range parsed {
+			i := strings.LastIndex(addr.Address, "@")
+			addrs = append(addrs, &netmail.Address{
+				Name:    strings.Join(strings.Fields(addr.Name), " "),
+				Address: addr.Address[:i] + strings.ToLower(addr.Address[i:]),
+			})
+		}
+	}
+	return addrs, nil
+}
+
+// validateHeader checks that a given custom header can be added to a message
+func validateHeader(key, value string) error {
+	switch {
+	case key == "" || strings.ContainsAny(key, ": \t\r\n"):
+		return fmt.Errorf("invalid header name: %q", key)
+	case reservedHeaders[textproto.CanonicalMIMEHeaderKey(key)]:
+		return fmt.Errorf("header %q is set by the mail client", key)
+	case strings.ContainsAny(value, "\r\n"):
+		return fmt.Errorf("header %q cannot contain line breaks", key)
+	}
+	return nil
+}
+
+// newMessageID generates a unique message ID using the domain of a given address
+func newMessageID(address string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	domain := "localhost"
+	if i := strings.LastIndex(address, "@"); i != -1 {
+		domain = address[i+1:]
+	}
+
+	return fmt.Sprintf("%s@%s", hex.EncodeToString(b), domain), nil
+}
+
+// recipients returns the addresses of every recipient of the message
+func (m *message) recipients() []string {
+	var addrs []string
+	for _, list := range [][]*netmail.Address{m.to, m.cc, m.bcc} {
+		for _, addr := range list {
+			addrs = append(addrs, addr.Address)
+		}
+	}
+	return addrs
+}
+
+// suppress removes given lowercase addresses from the recipients of the message, and returns if any remain
+func (m *message) suppress(addrs map[string]bool) bool {
+	remaining := 0
+	for _, list := range []*[]*netmail.Address{&m.to, &m.cc, &m.bcc} {
+		kept := (*list)[:0]
+		for _, addr := range *list {
+			if !addrs[strings.ToLower(addr.Address)] {
+				kept = append(kept, addr)
+			}
+		}
+		*list = kept
+		remaining += len(kept)
+	}
+	return remaining > 0
+}
+
+// envelope returns the addresses used by the mail server to deliver the message, which includes every recipient
+// only once
+func (m *message) envelope() (string, []string) {
+	seen := make(map[string]bool)
+	var to []string
+	for _, list := range [][]*netmail.Address{m.to, m.cc, m.bcc} {
+		for _, addr := range list {
+			if key := strings.ToLower(addr.Address); !seen[key] {
+				seen[key] = true
+				to = append(to, addr.Address)
+			}
+		}
+	}
+	return m.from.Address, to
+}
+
+// bytes encodes the message in the MIME format.
+// Headers which may contain non-ASCII characters are encoded as per RFC 2047, and the body is encoded as
+// quoted-printable so lines are never too long and any character set can be delivered over any mail server.
+// Inline attachments are placed with the body in a multipart/related entity, and other attachments are placed
+// after it in a multipart/mixed entity.
+func (m *message) bytes() ([]byte, error) {
+	var buf bytes.Buffer
+
+	body, err := m.entity()
+	if err != nil {
+		return nil, err
+	}
+
+	headers := [][2]string{
+		{"From", m.from.String()},
+		{"To", formatAddresses(m.to)},
+		{"Cc", formatAddresses(m.cc)},
+		{"Reply-To", formatAddresses(m.replyTo)},
+		{"Subject", mime.QEncoding.Encode("utf-8", m.subject)},
+		{"Date", m.date.Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s>", m.id)},
+	}
+
+	for _, h := range m.headers {
+		headers = append(headers, [2]string{h[0], mime.QEncoding.Encode("utf-8", h[1])})
+	}
+
+	headers = append(headers, [2]string{"MIME-Version", "1.0"})
+	for _, key := range sortedKeys(body.header) {
+		headers = append(headers, [2]string{key, body.header.Get(key)})
+	}
+
+	for _, h := range headers {
+		// Address lists are omitted when empty, such as when there are only BCC recipients
+		if h[1] != "" {
+			writeHeader(&buf, h[0], h[1])
+		}
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body.body)
+
+	return buf.Bytes(), nil
+}
+
+// entity builds the MIME entity containing the body and attachments of the message
+func (m *message) entity() (entity, error) {
+	body, err := m.textEntity()
+	if err != nil {
+		return entity{}, err
+	}
+
+	var inline, attached []entity
+	for _, a := range m.attachments {
+		if a.contentID != "" {
+			inline = append(inline, a.entity())
+		} else {
+			attached = append(attached, a.entity())
+		}
+	}
+
+	if len(inline) > 0 {
+		if body, err = multipartEntity("related", append([]entity{body}, inline...)); err != nil {
+			return entity{}, err
+		}
+	}
+
+	if len(attached) > 0 {
+		if body, err = multipartEntity("mixed", append([]entity{body}, attached...)); err != nil {
+			return entity{}, err
+		}
+	}
+
+	return body, nil
+}
+
+// textEntity builds the MIME entity containing the body of the message
+func (m *message) textEntity() (entity, error) {
+	contentType := "text/plain"
+	if m.html {
+		contentType = "text/html"
+	}
+
+	var buf bytes.Buffer
+	w := quotedprintable.NewWriter(&buf)
+	if _, err := w.Write([]byte(m.body)); err != nil {
+		return entity{}, err
+	}
+	if err := w.Close(); err != nil {
+		return entity{}, err
+	}
+
+	return entity{
+		header: textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"charset": "utf-8"})},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		},
+		body: buf.Bytes(),
+	}, nil
+}
+
+// entity builds the MIME entity containing the attachment, encoded as base64
+func (a attachment) entity() entity {
+	disposition := "attachment"
+	header := textproto.MIMEHeader{
+		"Content-Type":              {a.contentType},
+		"Content-Transfer-Encoding": {"base64"},
+	}
+
+	if a.contentID != "" {
+		disposition = "inline"
+		header.Set("Content-ID", fmt.Sprintf("<%s>", a.contentID))
+	}
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": a.name}))
+
+	// Lines of base64 must not exceed 76 characters
+	encoded := base64.StdEncoding.EncodeToString(a.data)
+	var buf bytes.Buffer
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+
+	return entity{header: header, body: buf.Bytes()}
+}
+
+// multipartEntity builds a multipart MIME entity of a given subtype containing given parts.
+// The parts are written here, rather than with a multipart.Writer, so their headers are folded like the headers of
+// the message.
+func multipartEntity(subtype string, parts []entity) (entity, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return entity{}, err
+	}
+	boundary := hex.EncodeToString(b)
+
+	var buf bytes.Buffer
+	for _, part := range parts {
+		buf.WriteString("--" + boundary + "\r\n")
+		for _, key := range sortedKeys(part.header) {
+			writeHeader(&buf, key, part.header.Get(key))
+		}
+		buf.WriteString("\r\n")
+		buf.Write(part.body)
+		buf.WriteString("\r\n")
+	}
+	buf.WriteString("--" + boundary + "--\r\n")
+
+	return entity{
+		header: textproto.MIMEHeader{
+			"Content-Type": {mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": boundary})},
+		},
+		body: buf.Bytes(),
+	}, nil
+}
+
+// formatAddresses formats given addresses for an address list header
+func formatAddresses(addrs []*netmail.Address) string {
+	formatted := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		formatted = append(formatted, addr.String())
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// writeHeader writes a given header, folding the value at spaces so lines do not exceed the maximum length, where
+// possible
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	line := key + ":"
+	for _, word := range strings.Split(value, " ") {
+		if len(line)+1+len(word) > maxHeaderLineLength && line != "" {
+			buf.WriteString(line + "\r\n")
+			line = ""
+		}
+		line += " " + word
+	}
+	buf.WriteString(line + "\r\n")
+}
+
+// sortedKeys returns the keys of a given header in order, so messages are encoded consistently
+func sortedKeys(header textproto.MIMEHeader) []string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// detectContentType determines the media type of a file with a given name and content
+func detectContentType(name string, data []byte) string {
+	if t := mime.TypeByExtension(path.Ext(name)); t != "" {
+		return t
+	}
+	return http.DetectContentType(data)
+}
+
+// read reads the content of the file
+func (a mailAttachment) read() (attachment, error) {
+	r := a.reader
+	if a.fsys != nil {
+		f, err := a.fsys.Open(a.path)
+		if err != nil {
+			return attachment{}, fmt.Errorf("unable to open attachment %q: %w", a.path, err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return attachment{}, fmt.Errorf("unable to read attachment %q: %w", a.name, err)
+	}
+
+	att := attachment{
+		name:        a.name,
+		contentType: detectContentType(a.name, data),
+		data:        data,
+	}
+
+	if a.inline {
+		if strings.ContainsAny(a.name, "<> \t\r\n") {
+			return attachment{}, fmt.Errorf("invalid inline attachment name: %q", a.name)
+		}
+		att.contentID = a.name
+	}
+
+	return att, nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mikestefanello/backlite"
+	"github.com/mikestefanello/pagoda/pkg/log"
+)
+
+// MailTask delivers a rendered email in the background, so a slow or unavailable mail server neither blocks the
+// request which sent it nor causes it to be lost, since failed deliveries are retried
+type MailTask struct {
+	// MessageID is the ID of the message, used for logging
+	MessageID string
+
+	// From is the address the message is sent from
+	From string
+
+	// To are the addresses the message is sent to
+	To []string
+
+	// Message is the encoded MIME message
+	Message []byte
+}
+
+// Config satisfies the backlite.Task interface by providing configuration for the queue that these items will be
+// placed into for execution.
+// Failed deliveries are retried for roughly an hour and then retained for a week so they can be inspected. The
+// message itself is not retained, since it can contain secrets, such as password reset links.
+func (t MailTask) Config() backlite.QueueConfig {
+	return backlite.QueueConfig{
+		Name:        "MailTask",
+		MaxAttempts: 6,
+		Timeout:     time.Minute,
+		Backoff:     10 * time.Minute,
+		Retention: &backlite.Retention{
+			Duration:   7 * 24 * time.Hour,
+			OnlyFailed: true,
+		},
+	}
+}
+
+// Deliver delivers the message of a given MailTask via the transport, which is how queued email is processed
+func (m *MailClient) Deliver(ctx context.Context, task MailTask) error {
+	return m.deliver(ctx, task, log.Default())
+}
+
+// deliver delivers the message of a given MailTask via the transport
+func (m *MailClient) deliver(ctx context.Context, task MailTask, logger *slog.Logger) error {
+	if err := m.transport.Send(ctx, task.From, task.To, task.Message); err != nil {
+		return fmt.Errorf("unable to deliver email: %w", err)
+	}
+
+	logger.Info("email delivered",
+		"to", task.To,
+		"message_id", task.MessageID,
+	)
+
+	return nil
+}
+
+// enqueue queues a given MailTask, added by a given operation, to be delivered in the background
+func (m *MailClient) enqueue(op *backlite.TaskAddOp, task MailTask, logger *slog.Logger) error {
+	if err := op.Save(); err != nil {
+		return fmt.Errorf("unable to queue email: %w", err)
+	}
+
+	logger.Info("email queued",
+		"to", task.To,
+		"message_id", task.MessageID,
+	)
+
+	return nil
+}
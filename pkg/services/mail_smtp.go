@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mikestefanello/pagoda/config"
+)
+
+// SMTPTransport is a MailTransport which delivers messages to an SMTP server.
+// The connection is kept open for the configured keep-alive duration so that consecutive messages, such as those
+// sent by a task, do not each require connecting, negotiating TLS and authenticating again.
+type SMTPTransport struct {
+	config config.MailConfig
+
+	// tlsConfig is the TLS configuration used to connect to the server
+	tlsConfig *tls.Config
+
+	// mu ensures the connection is only used to send one message at a time
+	mu sync.Mutex
+
+	// conn and client are the open connection to the server, if any
+	conn   net.Conn
+	client *smtp.Client
+
+	// idle closes the connection once it has been idle for the keep-alive duration
+	idle *time.Timer
+}
+
+// NewSMTPTransport creates a new SMTPTransport
+func NewSMTPTransport(cfg config.MailConfig) (*SMTPTransport, error) {
+	switch cfg.Encryption {
+	case config.MailEncryptionSTARTTLS, config.MailEncryptionTLS, config.MailEncryptionNone:
+	default:
+		return nil, fmt.Errorf("invalid mail encryption: %q", cfg.Encryption)
+	}
+
+	return &SMTPTransport{
+		config: cfg,
+		tlsConfig: &tls.Config{
+			ServerName: cfg.Hostname,
+			MinVersion: tls.VersionTLS12,
+		},
+	}, nil
+}
+
+// Send delivers a given message from a given address to given recipients
+func (s *SMTPTransport) Send(ctx context.Context, from string, to []string, msg []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if s.idle != nil {
+		s.idle.Stop()
+	}
+
+	// Reuse the open connection, if the server did not close it in the meantime
+	if s.client != nil {
+		s.setDeadline()
+		if err := s.client.Noop(); err != nil {
+			s.close()
+		}
+	}
+
+	if s.client == nil {
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.send(from, to, msg); err != nil {
+		// The state of the connection is unknown, so it cannot be reused
+		s.close()
+		return err
+	}
+
+	if s.config.KeepAlive <= 0 {
+		s.quit()
+		return nil
+	}
+
+	s.idle = time.AfterFunc(s.config.KeepAlive, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.quit()
+	})
+
+	return nil
+}
+
+// Close closes the connection to the server, if one is open
+func (s *SMTPTransport) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.idle != nil {
+		s.idle.Stop()
+	}
+	s.quit()
+}
+
+// connect opens a connection to the server, encrypts it as configured and authenticates, if credentials are
+// provided
+func (s *SMTPTransport) connect() error {
+	addr := net.JoinHostPort(s.config.Hostname, strconv.Itoa(int(s.config.Port)))
+	dialer := &net.Dialer{Timeout: s.config.Timeout}
+
+	var err error
+	if s.config.Encryption == config.MailEncryptionTLS {
+		s.conn, err = tls.DialWithDialer(dialer, "tcp", addr, s.tlsConfig)
+	} else {
+		s.conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to connect to smtp server: %w", err)
+	}
+
+	s.setDeadline()
+	s.client, err = smtp.NewClient(s.conn, s.config.Hostname)
+	if err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+		return fmt.Errorf("unable to start smtp session: %w", err)
+	}
+
+	if err = s.handshake(); err != nil {
+		s.close()
+		return err
+	}
+
+	return nil
+}
+
+// handshake encrypts the connection with STARTTLS, if configured, and authenticates, if credentials are provided
+func (s *SMTPTransport) handshake() error {
+	if s.config.Encryption == config.MailEncryptionSTARTTLS {
+		if ok, _ := s.client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := s.client.StartTLS(s.tlsConfig); err != nil {
+			return fmt.Errorf("unable to start tls: %w", err)
+		}
+	}
+
+	if s.config.User == "" {
+		return nil
+	}
+
+	auth := smtp.PlainAuth("", s.config.User, s.config.Password, s.config.Hostname)
+	if err := s.client.Auth(auth); err != nil {
+		return fmt.Errorf("unable to authenticate with smtp server: %w", err)
+	}
+
+	return nil
+}
+
+// send performs the transaction which delivers a given message
+func (s *SMTPTransport) send(from string, to []string, msg []byte) error {
+	s.setDeadline()
+
+	if err := s.client.Mail(from); err != nil {
+		return err
+	}
+
+	for _, rcpt := range to {
+		if err := s.client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+
+	w, err := s.client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(msg); err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+// setDeadline limits how long the next operations on the connection can take
+func (s *SMTPTransport) setDeadline() {
+	if s.config.Timeout > 0 {
+		_ = s.conn.SetDeadline(time.Now().Add(s.config.Timeout))
+	}
+}
+
+// quit politely ends the session and closes the connection, if one is open
+func (s *SMTPTransport) quit() {
+	if s.client != nil {
+		s.setDeadline()
+		_ = s.client.Quit()
+	}
+	s.close()
+}
+
+// close closes the connection, if one is open
+func (s *SMTPTransport) close() {
+	if s.client != nil {
+		_ = s.client.Close()
+	}
+	s.client, s.conn = nil, nil
+}
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/mailsuppression"
+)
+
+const (
+	// MailCategorySecurity is the category of notifications about the security of an account which recipients can
+	// opt out of, which are logins from new devices. Changes which could mean an account was taken over, such as a
+	// password reset, are always sent.
+	MailCategorySecurity = "security"
+
+	// MailCategoryInvitations is the category of invitations to join organizations
+	MailCategoryInvitations = "invitations"
+)
+
+// mailUnsubscribeRoute is the name of the route which handles unsubscribe links
+const mailUnsubscribeRoute = "mail.unsubscribe"
+
+// MailCategory is a category of notification email which recipients can choose whether to receive
+type MailCategory struct {
+	// Name identifies the category
+	Name string
+
+	// Label is the name of the category shown to recipients
+	Label string
+
+	// Description describes the email within the category
+	Description string
+}
+
+// MailCategories are the categories of notification email, in the order they are shown to recipients
+var MailCategories = []MailCategory{
+	{
+		Name:        MailCategorySecurity,
+		Label:       "Security alerts",
+		Description: "Logins from new devices. Changes to your email address or password are always sent.",
+	},
+	{
+		Name:        MailCategoryInvitations,
+		Label:       "Invitations",
+		Description: "Invitations to join organizations.",
+	},
+}
+
+// GetMailCategory returns the MailCategory with a given name, if it exists
+func GetMailCategory(name string) (MailCategory, bool) {
+	for _, category := range MailCategories {
+		if category.Name == name {
+			return category, true
+		}
+	}
+	return MailCategory{}, false
+}
+
+type (
+	// MailSuppressionClient manages the suppression list, which prevents email from being sent to addresses which
+	// cannot receive it, because they bounced or complained, and to recipients who unsubscribed from a category of
+	// notifications.
+	// Every address is stored and compared in lowercase.
+	MailSuppressionClient struct {
+		config  *config.Config
+		orm     *ent.Client
+		keyring *Keyring
+		web     *echo.Echo
+	}
+
+	// mailPreferencesToken is the content of the token which allows a recipient to manage the email they receive
+	mailPreferencesToken struct {
+		Email    string `json:"e"`
+		Category string `json:"c,omitempty"`
+	}
+)
+
+// NewMailSuppressionClient creates a new MailSuppressionClient
+func NewMailSuppressionClient(
+	cfg *config.Config,
+	orm *ent.Client,
+	keyring *Keyring,
+	web *echo.Echo,
+) *MailSuppressionClient {
+	return &MailSuppressionClient{
+		config:  cfg,
+		orm:     orm,
+		keyring: keyring,
+		web:     web,
+	}
+}
+
+// Suppress suppresses every email to a given address for a given reason, which should be used when email to the
+// address bounced or the recipient complained, ie, marked it as spam
+func (c *MailSuppressionClient) Suppress(ctx context.Context, email string, reason mailsuppression.Reason) error {
+	if reason == mailsuppression.ReasonUnsubscribed {
+		return errors.New("use Unsubscribe to unsubscribe from a category")
+	}
+	return c.add(ctx, email, reason, "")
+}
+
+// Unsubscribe suppresses the notifications of a given category to a given address
+func (c *MailSuppressionClient) Unsubscribe(ctx context.Context, email, category string) error {
+	if _, ok := GetMailCategory(category); !ok {
+		return fmt.Errorf("invalid mail category: %q", category)
+	}
+	return c.add(ctx, email, mailsuppression.ReasonUnsubscribed, category)
+}
+
+// Resubscribe removes the suppression of the notifications of a given category to a given address
+func (c *MailSuppressionClient) Resubscribe(ctx context.Context, email, category string) error {
+	_, err := c.orm.MailSuppression.
+		Delete().
+		Where(
+			mailsuppression.Email(strings.ToLower(email)),
+			mailsuppression.ReasonEQ(mailsuppression.ReasonUnsubscribed),
+			mailsuppression.Category(category),
+		).
+		Exec(ctx)
+	return err
+}
+
+// Remove removes every suppression of a given address, including those for bounces and complaints, and returns how
+// many were removed
+func (c *MailSuppressionClient) Remove(ctx context.Context, email string) (int, error) {
+	return c.orm.MailSuppression.
+		Delete().
+		Where(mailsuppression.Email(strings.ToLower(email))).
+		Exec(ctx)
+}
+
+// Suppressed returns which of given addresses email of a given category must not be sent to, in lowercase.
+// Email without a category, such as password resets, is only suppressed for addresses which bounced or complained,
+// since recipients cannot unsubscribe from it.
+func (c *MailSuppressionClient) Suppressed(ctx context.Context, emails []string, category string) (map[string]bool, error) {
+	return c.suppressed(ctx, nil, emails, category)
+}
+
+// suppressed returns which of given addresses email of a given category must not be sent to, querying within a given
+// transaction, if provided
+func (c *MailSuppressionClient) suppressed(
+	ctx context.Context,
+	tx *ent.Tx,
+	emails []string,
+	category string,
+) (map[string]bool, error) {
+	orm := c.orm
+	if tx != nil {
+		orm = tx.Client()
+	}
+
+	lower := make([]string, 0, len(emails))
+	for _, email := range emails {
+		lower = append(lower, strings.ToLower(email))
+	}
+
+	applies := mailsuppression.ReasonIn(mailsuppression.ReasonBounced, mailsuppression.ReasonComplained)
+	if category != "" {
+		applies = mailsuppression.Or(
+			applies,
+			mailsuppression.And(
+				mailsuppression.ReasonEQ(mailsuppression.ReasonUnsubscribed),
+				mailsuppression.Category(category),
+			),
+		)
+	}
+
+	suppressions, err := orm.MailSuppression.
+		Query().
+		Where(
+			mailsuppression.EmailIn(lower...),
+			applies,
+		).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	suppressed := make(map[string]bool, len(suppressions))
+	for _, s := range suppressions {
+		suppressed[s.Email] = true
+	}
+	return suppressed, nil
+}
+
+// Unsubscribed returns the categories a given address unsubscribed from
+func (c *MailSuppressionClient) Unsubscribed(ctx context.Context, email string) (map[string]bool, error) {
+	categories, err := c.orm.MailSuppression.
+		Query().
+		Where(
+			mailsuppression.Email(strings.ToLower(email)),
+			mailsuppression.ReasonEQ(mailsuppression.ReasonUnsubscribed),
+		).
+		Select(mailsuppression.FieldCategory).
+		Strings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unsubscribed := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		unsubscribed[category] = true
+	}
+	return unsubscribed, nil
+}
+
+// Blocked returns the suppression which prevents every email from being sent to a given address, because it
+// bounced or complained, or nil if there is none
+func (c *MailSuppressionClient) Blocked(ctx context.Context, email string) (*ent.MailSuppression, error) {
+	s, err := c.orm.MailSuppression.
+		Query().
+		Where(
+			mailsuppression.Email(strings.ToLower(email)),
+			mailsuppression.ReasonIn(mailsuppression.ReasonBounced, mailsuppression.ReasonComplained),
+		).
+		First(ctx)
+	if ent.IsNotFound(err) {
+		return nil, nil
+	}
+	return s, err
+}
+
+// GenerateToken generates a token which allows the recipient at a given address to manage the email they receive
+// without logging in, and to unsubscribe from a given category with one click.
+// Tokens are encrypted, so they cannot be forged, and do not expire, since the links within email must keep working.
+func (c *MailSuppressionClient) GenerateToken(email, category string) (string, error) {
+	b, err := json.Marshal(mailPreferencesToken{
+		Email:    strings.ToLower(email),
+		Category: category,
+	})
+	if err != nil {
+		return "", err
+	}
+	return c.keyring.Encrypt(KeyPurposeMailPreferences, b)
+}
+
+// ValidateToken validates a token generated by GenerateToken and returns the address and category within it
+func (c *MailSuppressionClient) ValidateToken(token string) (email, category string, err error) {
+	b, err := c.keyring.Decrypt(KeyPurposeMailPreferences, token)
+	if err != nil {
+		return "", "", err
+	}
+
+	var t mailPreferencesToken
+	if err = json.Unmarshal(b, &t); err != nil {
+		return "", "", err
+	}
+
+	if t.Email == "" {
+		return "", "", errors.New("invalid token")
+	}
+
+	return t.Email, t.Category, nil
+}
+
+// UnsubscribeURL returns the absolute URL which unsubscribes a given address from a given category with one click,
+// as per RFC 8058, and lets the recipient manage the email they receive when opened in a browser
+func (c *MailSuppressionClient) UnsubscribeURL(email, category string) (string, error) {
+	token, err := c.GenerateToken(email, category)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(c.config.App.URL, "/") + c.web.Reverse(mailUnsubscribeRoute, token), nil
+}
+
+// add adds a suppression, unless it already exists
+func (c *MailSuppressionClient) add(
+	ctx context.Context,
+	email string,
+	reason mailsuppression.Reason,
+	category string,
+) error {
+	err := c.orm.MailSuppression.
+		Create().
+		SetEmail(strings.ToLower(email)).
+		SetReason(reason).
+		SetCategory(category).
+		Exec(ctx)
+
+	if ent.IsConstraintError(err) {
+		return nil
+	}
+	return err
+}
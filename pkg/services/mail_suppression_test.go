@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mikestefanello/pagoda/ent/mailsuppression"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMailSuppressionClient_Suppressed(t *testing.T) {
+	ctx := context.Background()
+	unsubscribed := "Suppressed-Unsubscribed@example.com"
+	bounced := "suppressed-bounced@example.com"
+	other := "suppressed-other@example.com"
+	all := []string{unsubscribed, bounced, other}
+
+	require.NoError(t, c.MailSuppression.Unsubscribe(ctx, unsubscribed, MailCategorySecurity))
+	require.NoError(t, c.MailSuppression.Suppress(ctx, strings.ToUpper(bounced), mailsuppression.ReasonBounced))
+
+	// Adding a suppression which already exists does nothing
+	require.NoError(t, c.MailSuppression.Unsubscribe(ctx, unsubscribed, MailCategorySecurity))
+	require.NoError(t, c.MailSuppression.Suppress(ctx, bounced, mailsuppression.ReasonBounced))
+
+	assert.Error(t, c.MailSuppression.Unsubscribe(ctx, unsubscribed, "invalid"))
+	assert.Error(t, c.MailSuppression.Suppress(ctx, unsubscribed, mailsuppression.ReasonUnsubscribed))
+
+	// Unsubscribing only suppresses email of the category
+	suppressed, err := c.MailSuppression.Suppressed(ctx, all, MailCategorySecurity)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{strings.ToLower(unsubscribed): true, bounced: true}, suppressed)
+
+	suppressed, err = c.MailSuppression.Suppressed(ctx, all, MailCategoryInvitations)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{bounced: true}, suppressed)
+
+	suppressed, err = c.MailSuppression.Suppressed(ctx, all, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{bounced: true}, suppressed)
+
+	categories, err := c.MailSuppression.Unsubscribed(ctx, unsubscribed)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{MailCategorySecurity: true}, categories)
+
+	blocked, err := c.MailSuppression.Blocked(ctx, unsubscribed)
+	require.NoError(t, err)
+	assert.Nil(t, blocked)
+
+	blocked, err = c.MailSuppression.Blocked(ctx, bounced)
+	require.NoError(t, err)
+	require.NotNil(t, blocked)
+	assert.Equal(t, mailsuppression.ReasonBounced, blocked.Reason)
+
+	// Suppressions can be removed
+	require.NoError(t, c.MailSuppression.Resubscribe(ctx, unsubscribed, MailCategorySecurity))
+	n, err := c.MailSuppression.Remove(ctx, bounced)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	suppressed, err = c.MailSuppression.Suppressed(ctx, all, MailCategorySecurity)
+	require.NoError(t, err)
+	assert.Empty(t, suppressed)
+}
+
+func TestMailSuppressionClient_Token(t *testing.T) {
+	token, err := c.MailSuppression.GenerateToken("User@Example.com", MailCategorySecurity)
+	require.NoError(t, err)
+
+	email, category, err := c.MailSuppression.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", email)
+	assert.Equal(t, MailCategorySecurity, category)
+
+	_, _, err = c.MailSuppression.ValidateToken(token[:len(token)-2])
+	assert.Error(t, err)
+
+	// Tokens of other purposes cannot be used
+	other, err := c.Keyring.Encrypt(KeyPurposeBotProtection, []byte(`{"e":"user@example.com"}`))
+	require.NoError(t, err)
+	_, _, err = c.MailSuppression.ValidateToken(other)
+	assert.Error(t, err)
+}
+
+func TestMailClient_Suppression(t *testing.T) {
+	ctx := context.Background()
+	inbox := NewMemoryTransport(100)
+	client := NewMailClient(c.Config, c.TemplateRenderer, inbox, nil, nil, c.MailSuppression)
+
+	// The unsubscribe route is registered by the handlers, which are not loaded here
+	if c.Web.Reverse(mailUnsubscribeRoute, "x") == "" {
+		c.Web.GET("/email/unsubscribe/:token", nil).Name = mailUnsubscribeRoute
+	}
+
+	bounced := "mail-bounced@example.com"
+	unsubscribed := "mail-unsubscribed@example.com"
+	other := "mail-other@example.com"
+	require.NoError(t, c.MailSuppression.Suppress(ctx, bounced, mailsuppression.ReasonComplained))
+	require.NoError(t, c.MailSuppression.Unsubscribe(ctx, unsubscribed, MailCategorySecurity))
+
+	// Nothing is sent if every recipient is suppressed
+	err := client.Compose().
+		To(bounced).
+		Subject("Reset your password").
+		Body("Hello").
+		SendContext(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, inbox.Messages())
+
+	// Suppressed recipients are removed from the rest
+	err = client.Compose().
+		To(other).
+		Cc(bounced, unsubscribed).
+		Subject("Hello").
+		Body("Hello").
+		SendContext(ctx)
+	require.NoError(t, err)
+	msgs := inbox.Messages()
+	require.Len(t, msgs, 1)
+	assert.Equal(t, []string{other, unsubscribed}, msgs[0].To)
+	content, err := msgs[0].Content()
+	require.NoError(t, err)
+	assert.NotContains(t, content.Header.Get("Cc"), bounced)
+
+	// Unsubscribing does not suppress email without a category
+	inbox.Clear()
+	err = client.Compose().
+		To(unsubscribed).
+		Subject("Reset your password").
+		Body("Hello").
+		SendContext(ctx)
+	require.NoError(t, err)
+	assert.Len(t, inbox.Messages(), 1)
+
+	// But does suppress email of the category
+	inbox.Clear()
+	err = client.Compose().
+		To(unsubscribed).
+		Category(MailCategorySecurity).
+		Subject("New login").
+		Body("Hello").
+		SendContext(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, inbox.Messages())
+
+	// Email with a category can be unsubscribed from
+	err = client.Compose().
+		To(other).
+		Category(MailCategorySecurity).
+		Subject("New login").
+		Body("Hello").
+		SendContext(ctx)
+	require.NoError(t, err)
+	msgs = inbox.Messages()
+	require.Len(t, msgs, 1)
+	content, err = msgs[0].Content()
+	require.NoError(t, err)
+	assert.Equal(t, "List-Unsubscribe=One-Click", content.Header.Get("List-Unsubscribe-Post"))
+
+	unsubscribe := content.Header.Get("List-Unsubscribe")
+	prefix := "<" + c.Config.App.URL + "/email/unsubscribe/"
+	require.True(t, strings.HasPrefix(unsubscribe, prefix), unsubscribe)
+	email, category, err := c.MailSuppression.ValidateToken(strings.TrimSuffix(strings.TrimPrefix(unsubscribe, prefix), ">"))
+	require.NoError(t, err)
+	assert.Equal(t, other, email)
+	assert.Equal(t, MailCategorySecurity, category)
+
+	// Since the link is specific to the recipient, there can only be one
+	err = client.Compose().
+		To(other, unsubscribed).
+		Category(MailCategorySecurity).
+		Body("Hello").
+		SendContext(ctx)
+	assert.Error(t, err)
+
+	err = client.Compose().
+		To(other).
+		Category("invalid").
+		Body("Hello").
+		SendContext(ctx)
+	assert.Error(t, err)
+}
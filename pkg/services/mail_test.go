@@ -1,3 +1,923 @@
 package services
 
-// Fill this in once you implement your mail client
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/pkg/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSMTPTransport creates an SMTPTransport for a given test SMTP server
+func newTestSMTPTransport(t *testing.T, srv *tests.SMTPServer, encryption config.MailEncryption) *SMTPTransport {
+	s, err := NewSMTPTransport(config.MailConfig{
+		Hostname:   srv.Host(),
+		Port:       srv.Port(),
+		User:       srv.Username,
+		Password:   srv.Password,
+		Encryption: encryption,
+		Timeout:    5 * time.Second,
+		KeepAlive:  time.Minute,
+	})
+	require.NoError(t, err)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// readMessage parses a given MIME message and decodes its body
+func readMessage(t *testing.T, data []byte) (*netmail.Message, string) {
+	msg, err := netmail.ReadMessage(bytes.NewReader(data))
+	require.NoError(t, err)
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	require.NoError(t, err)
+	return msg, string(body)
+}
+
+func TestNewSMTPTransport(t *testing.T) {
+	_, err := NewSMTPTransport(config.MailConfig{Encryption: "ssl"})
+	assert.Error(t, err)
+}
+
+func TestMessage(t *testing.T) {
+	email := &mail{
+		from:    "Pagoda <admin@example.com>",
+		to:      []string{"user@example.com"},
+		subject: "Héllo wörld",
+		body:    "Line one\nLine two with ümlauts and a long line " + strings.Repeat("x", 100),
+	}
+
+	msg, err := newMessage(email)
+	require.NoError(t, err)
+
+	from, to := msg.envelope()
+	assert.Equal(t, "admin@example.com", from)
+	assert.Equal(t, []string{"user@example.com"}, to)
+	assert.True(t, strings.HasSuffix(msg.id, "@example.com"))
+
+	data, err := msg.bytes()
+	require.NoError(t, err)
+	for _, line := range strings.Split(string(data), "\r\n") {
+		assert.LessOrEqual(t, len(line), 78)
+	}
+
+	parsed, body := readMessage(t, data)
+	assert.Equal(t, `"Pagoda" <admin@example.com>`, parsed.Header.Get("From"))
+	assert.Equal(t, "<user@example.com>", parsed.Header.Get("To"))
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Héllo wörld", subject)
+	assert.Equal(t, "<"+msg.id+">", parsed.Header.Get("Message-ID"))
+	assert.Equal(t, "text/plain; charset=utf-8", parsed.Header.Get("Content-Type"))
+	assert.Equal(t, strings.ReplaceAll(email.body, "\n", "\r\n"), body)
+
+	email.template = "test"
+	msg, err = newMessage(email)
+	require.NoError(t, err)
+	data, err = msg.bytes()
+	require.NoError(t, err)
+	parsed, _ = readMessage(t, data)
+	assert.Equal(t, "text/html; charset=utf-8", parsed.Header.Get("Content-Type"))
+
+	_, err = newMessage(&mail{from: "invalid", to: []string{"user@example.com"}})
+	assert.Error(t, err)
+	_, err = newMessage(&mail{from: "admin@example.com", to: []string{"invalid"}})
+	assert.Error(t, err)
+}
+
+func TestMessage_Recipients(t *testing.T) {
+	email := &mail{
+		from:    "admin@example.com",
+		to:      []string{"  Jane   Doe <Jane@EXAMPLE.com>, john@example.com", "Jöhn Smith <smith@example.com>"},
+		cc:      []string{"\"Doe, Jane\" <jane@example.com>", "sales@example.com"},
+		bcc:     []string{"audit@example.com"},
+		replyTo: []string{"Support <support@example.com>"},
+		subject: "Hello",
+		body:    "Hi",
+	}
+
+	msg, err := newMessage(email)
+	require.NoError(t, err)
+
+	// Every recipient is included only once, and domains are normalized
+	from, to := msg.envelope()
+	assert.Equal(t, "admin@example.com", from)
+	assert.Equal(t, []string{"Jane@example.com", "john@example.com", "smith@example.com", "sales@example.com", "audit@example.com"}, to)
+
+	data, err := msg.bytes()
+	require.NoError(t, err)
+	for _, line := range strings.Split(string(data), "\r\n") {
+		assert.LessOrEqual(t, len(line), 78)
+	}
+
+	parsed, _ := readMessage(t, data)
+	addrs, err := parsed.Header.AddressList("To")
+	require.NoError(t, err)
+	require.Len(t, addrs, 3)
+	assert.Equal(t, "Jane Doe", addrs[0].Name)
+	assert.Equal(t, "Jöhn Smith", addrs[2].Name)
+	addrs, err = parsed.Header.AddressList("Cc")
+	require.NoError(t, err)
+	assert.Equal(t, "Doe, Jane", addrs[0].Name)
+	assert.Equal(t, `"Support" <support@example.com>`, parsed.Header.Get("Reply-To"))
+
+	// BCC recipients are not visible
+	assert.Empty(t, parsed.Header.Get("Bcc"))
+	assert.NotContains(t, string(data), "audit@example.com")
+
+	// Only BCC recipients
+	msg, err = newMessage(&mail{from: "admin@example.com", bcc: []string{"audit@example.com"}})
+	require.NoError(t, err)
+	data, err = msg.bytes()
+	require.NoError(t, err)
+	parsed, _ = readMessage(t, data)
+	assert.Empty(t, parsed.Header.Get("To"))
+
+	_, err = newMessage(&mail{from: "a@example.com, b@example.com", to: []string{"user@example.com"}})
+	assert.Error(t, err)
+	_, err = newMessage(&mail{from: "admin@example.com", cc: []string{"invalid"}})
+	assert.Error(t, err)
+	_, err = newMessage(&mail{from: "admin@example.com", to: []string{"user@example.com"}, replyTo: []string{"invalid"}})
+	assert.Error(t, err)
+}
+
+func TestMessage_Headers(t *testing.T) {
+	email := &mail{
+		from:    "admin@example.com",
+		to:      []string{"user@example.com"},
+		headers: [][2]string{{"x-campaign", "welcome"}, {"X-Note", "Grüße"}},
+	}
+
+	msg, err := newMessage(email)
+	require.NoError(t, err)
+	data, err := msg.bytes()
+	require.NoError(t, err)
+	parsed, _ := readMessage(t, data)
+	assert.Equal(t, "welcome", parsed.Header.Get("X-Campaign"))
+	note, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("X-Note"))
+	require.NoError(t, err)
+	assert.Equal(t, "Grüße", note)
+
+	for _, h := range [][2]string{
+		{"Subject", "Overridden"},
+		{"bcc", "spy@example.com"},
+		{"X-Injected", "a\r\nBcc: spy@example.com"},
+		{"X Spaced", "value"},
+		{"", "value"},
+	} {
+		email.headers = [][2]string{h}
+		_, err = newMessage(email)
+		assert.Error(t, err, h[0])
+	}
+}
+
+func TestMessage_Attachments(t *testing.T) {
+	fsys := fstest.MapFS{
+		"images/logo.png":  &fstest.MapFile{Data: []byte("\x89PNG\r\n\x1a\nlogo")},
+		"files/report.pdf": &fstest.MapFile{Data: bytes.Repeat([]byte("%PDF"), 100)},
+	}
+
+	email := &mail{
+		from:     "admin@example.com",
+		to:       []string{"user@example.com"},
+		subject:  "Attachments",
+		body:     `<p>Hello</p><img src="cid:logo.png">`,
+		template: "test",
+		attachments: []mailAttachment{
+			{name: "notes.txt", reader: strings.NewReader("Some notes")},
+			{name: "report.pdf", fsys: fsys, path: "files/report.pdf"},
+			{name: "logo.png", fsys: fsys, path: "images/logo.png", inline: true},
+		},
+	}
+
+	msg, err := newMessage(email)
+	require.NoError(t, err)
+	data, err := msg.bytes()
+	require.NoError(t, err)
+	for _, line := range strings.Split(string(data), "\r\n") {
+		assert.LessOrEqual(t, len(line), 78)
+	}
+
+	parsed, err := netmail.ReadMessage(bytes.NewReader(data))
+	require.NoError(t, err)
+	mediaType, _, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	content, err := InboxMessage{Data: data}.Content()
+	require.NoError(t, err)
+	assert.Equal(t, email.body, content.HTML)
+	require.Len(t, content.Attachments, 3)
+
+	// The inline image is related to the body
+	assert.Equal(t, "logo.png", content.Attachments[0].Filename)
+	assert.Equal(t, "image/png", content.Attachments[0].ContentType)
+	assert.Equal(t, "logo.png", content.Attachments[0].ContentID)
+	assert.Equal(t, fsys["images/logo.png"].Data, content.Attachments[0].Data)
+
+	assert.Equal(t, "notes.txt", content.Attachments[1].Filename)
+	assert.Equal(t, "text/plain; charset=utf-8", content.Attachments[1].ContentType)
+	assert.Empty(t, content.Attachments[1].ContentID)
+	assert.Equal(t, "Some notes", string(content.Attachments[1].Data))
+
+	assert.Equal(t, "report.pdf", content.Attachments[2].Filename)
+	assert.Equal(t, "application/pdf", content.Attachments[2].ContentType)
+	assert.Equal(t, fsys["files/report.pdf"].Data, content.Attachments[2].Data)
+
+	// Files must exist
+	email.attachments = []mailAttachment{{name: "missing.pdf", fsys: fsys, path: "missing.pdf"}}
+	_, err = newMessage(email)
+	assert.Error(t, err)
+}
+
+func TestSMTPTransport(t *testing.T) {
+	srv := &tests.SMTPServer{}
+	srv.Start(t)
+	s := newTestSMTPTransport(t, srv, config.MailEncryptionNone)
+
+	require.NoError(t, s.Send(context.Background(), "a@example.com", []string{"b@example.com", "c@example.com"}, []byte("Subject: 1\r\n\r\nOne")))
+	require.NoError(t, s.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("Subject: 2\r\n\r\nTwo")))
+
+	msgs := srv.Messages()
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "a@example.com", msgs[0].From)
+	assert.Equal(t, []string{"b@example.com", "c@example.com"}, msgs[0].To)
+	assert.Equal(t, "Subject: 1\r\n\r\nOne\r\n", string(msgs[0].Data))
+
+	// The connection is reused
+	assert.Equal(t, 1, srv.Connections())
+
+	// Until it is closed
+	s.Close()
+	require.NoError(t, s.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("Subject: 3\r\n\r\nThree")))
+	assert.Equal(t, 2, srv.Connections())
+
+	// Or kept open for no time at all, once the open connection is used
+	s.config.KeepAlive = 0
+	require.NoError(t, s.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("Subject: 4\r\n\r\nFour")))
+	require.NoError(t, s.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("Subject: 5\r\n\r\nFive")))
+	assert.Equal(t, 3, srv.Connections())
+	assert.Len(t, srv.Messages(), 5)
+}
+
+func TestSMTPTransport_STARTTLS(t *testing.T) {
+	serverTLS, pool := tests.NewTLSConfig(t)
+	srv := &tests.SMTPServer{
+		TLSConfig: serverTLS,
+		Username:  "user",
+		Password:  "secret",
+	}
+	srv.Start(t)
+
+	s := newTestSMTPTransport(t, srv, config.MailEncryptionSTARTTLS)
+	s.tlsConfig.RootCAs = pool
+	require.NoError(t, s.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("Subject: TLS\r\n\r\nHi")))
+	assert.Len(t, srv.Messages(), 1)
+
+	// The server certificate must be trusted
+	s = newTestSMTPTransport(t, srv, config.MailEncryptionSTARTTLS)
+	assert.Error(t, s.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("Subject: TLS\r\n\r\nHi")))
+
+	// The credentials must be correct
+	s = newTestSMTPTransport(t, srv, config.MailEncryptionSTARTTLS)
+	s.tlsConfig.RootCAs = pool
+	s.config.Password = "wrong"
+	assert.Error(t, s.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("Subject: TLS\r\n\r\nHi")))
+
+	// STARTTLS is required
+	plain := &tests.SMTPServer{}
+	plain.Start(t)
+	s = newTestSMTPTransport(t, plain, config.MailEncryptionSTARTTLS)
+	assert.Error(t, s.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("Subject: TLS\r\n\r\nHi")))
+	assert.Empty(t, plain.Messages())
+}
+
+func TestSMTPTransport_ImplicitTLS(t *testing.T) {
+	serverTLS, pool := tests.NewTLSConfig(t)
+	srv := &tests.SMTPServer{
+		TLSConfig:   serverTLS,
+		ImplicitTLS: true,
+		Username:    "user",
+		Password:    "secret",
+	}
+	srv.Start(t)
+
+	s := newTestSMTPTransport(t, srv, config.MailEncryptionTLS)
+	s.tlsConfig.RootCAs = pool
+	require.NoError(t, s.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("Subject: TLS\r\n\r\nHi")))
+	assert.Len(t, srv.Messages(), 1)
+}
+
+func TestSMTPTransport_Timeout(t *testing.T) {
+	// A server which accepts connections but never responds
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	s, err := NewSMTPTransport(config.MailConfig{
+		Hostname:   "127.0.0.1",
+		Port:       uint16(l.Addr().(*net.TCPAddr).Port),
+		Encryption: config.MailEncryptionNone,
+		Timeout:    100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	assert.Error(t, s.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("Hi")))
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestNewMailTransport(t *testing.T) {
+	cfg := config.MailConfig{
+		Encryption: config.MailEncryptionNone,
+	}
+
+	cfg.Transport = config.MailTransportSMTP
+	tr, err := NewMailTransport(cfg)
+	require.NoError(t, err)
+	assert.IsType(t, &SMTPTransport{}, tr)
+
+	cfg.Transport = config.MailTransportSendmail
+	tr, err = NewMailTransport(cfg)
+	require.NoError(t, err)
+	assert.IsType(t, &SendmailTransport{}, tr)
+
+	cfg.Transport = config.MailTransportFile
+	cfg.File.Directory = t.TempDir()
+	tr, err = NewMailTransport(cfg)
+	require.NoError(t, err)
+	assert.IsType(t, &FileTransport{}, tr)
+
+	cfg.Transport = config.MailTransportMemory
+	tr, err = NewMailTransport(cfg)
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryTransport{}, tr)
+
+	cfg.Transport = "pigeon"
+	_, err = NewMailTransport(cfg)
+	assert.Error(t, err)
+}
+
+func TestSendmailTransport(t *testing.T) {
+	// A fake sendmail binary which records its arguments and input
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "sendmail")
+	script := fmt.Sprintf("#!/bin/sh\necho \"$@\" > %s/args\ncat > %s/input\n", dir, dir)
+	require.NoError(t, os.WriteFile(bin, []byte(script), 0o755))
+
+	s := NewSendmailTransport(bin, "-i")
+	msg := []byte("Subject: Hello\r\n\r\nHi")
+	require.NoError(t, s.Send(context.Background(), "a@example.com", []string{"b@example.com", "c@example.com"}, msg))
+
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	require.NoError(t, err)
+	assert.Equal(t, "-i -f a@example.com -- b@example.com c@example.com\n", string(args))
+
+	input, err := os.ReadFile(filepath.Join(dir, "input"))
+	require.NoError(t, err)
+	assert.Equal(t, msg, input)
+
+	// Failures include the output of the binary
+	require.NoError(t, os.WriteFile(bin, []byte("#!/bin/sh\necho 'no recipients' >&2\nexit 1\n"), 0o755))
+	err = s.Send(context.Background(), "a@example.com", []string{"b@example.com"}, msg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recipients")
+}
+
+func TestFileTransport(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	f, err := NewFileTransport(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, f.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("One")))
+	require.NoError(t, f.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("Two")))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	// Files are sorted by the time they were written
+	for i, expected := range []string{"One", "Two"} {
+		data, err := os.ReadFile(files[i])
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(data))
+	}
+
+	_, err = NewFileTransport("")
+	assert.Error(t, err)
+}
+
+func TestMemoryTransport(t *testing.T) {
+	m := NewMemoryTransport(100)
+	require.NoError(t, m.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("Subject: 1\r\n\r\nOne")))
+	require.NoError(t, m.Send(context.Background(), "a@example.com", []string{"C@example.com"}, []byte("Subject: 2\r\n\r\nTwo")))
+
+	msgs := m.Messages()
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "a@example.com", msgs[0].From)
+	assert.Equal(t, []string{"b@example.com"}, msgs[0].To)
+	assert.WithinDuration(t, time.Now(), msgs[0].ReceivedAt, time.Minute)
+
+	parsed, err := msgs[1].Parse()
+	require.NoError(t, err)
+	assert.Equal(t, "2", parsed.Header.Get("Subject"))
+
+	msgs = m.MessagesTo("c@example.com")
+	require.Len(t, msgs, 1)
+	assert.Equal(t, []string{"C@example.com"}, msgs[0].To)
+	assert.Empty(t, m.MessagesTo("d@example.com"))
+
+	msg, ok := m.Message(msgs[0].ID)
+	require.True(t, ok)
+	assert.Equal(t, msgs[0], msg)
+
+	// IDs are not reused once cleared
+	m.Clear()
+	assert.Empty(t, m.Messages())
+	_, ok = m.Message(msgs[0].ID)
+	assert.False(t, ok)
+	require.NoError(t, m.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("Subject: 3\r\n\r\nThree")))
+	assert.Equal(t, 3, m.Messages()[0].ID)
+
+	// The oldest messages are discarded once the inbox is full
+	m = NewMemoryTransport(2)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, m.Send(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("Subject: 1\r\n\r\nOne")))
+	}
+	msgs = m.Messages()
+	require.Len(t, msgs, 2)
+	assert.Equal(t, 2, msgs[0].ID)
+	assert.Equal(t, 3, msgs[1].ID)
+}
+
+func TestInboxMessage_Content(t *testing.T) {
+	// A message built by the mail client
+	email := &mail{
+		from:    "admin@example.com",
+		to:      []string{"user@example.com"},
+		subject: "Héllo",
+		body:    "Hi there",
+	}
+	msg, err := newMessage(email)
+	require.NoError(t, err)
+	data, err := msg.bytes()
+	require.NoError(t, err)
+
+	content, err := InboxMessage{Data: data}.Content()
+	require.NoError(t, err)
+	assert.Equal(t, "Héllo", content.Subject)
+	assert.Equal(t, "Hi there", content.Text)
+	assert.Empty(t, content.HTML)
+	assert.Equal(t, "<"+msg.id+">", content.Header.Get("Message-ID"))
+
+	// A multipart message with alternative bodies and an attachment
+	data = []byte(strings.Join([]string{
+		"Subject: Multipart",
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/mixed; boundary="outer"`,
+		"",
+		"--outer",
+		`Content-Type: multipart/alternative; boundary="inner"`,
+		"",
+		"--inner",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: quoted-printable",
+		"",
+		"Plain =C3=BCmlaut",
+		"--inner",
+		"Content-Type: text/html; charset=utf-8",
+		"Content-Transfer-Encoding: base64",
+		"",
+		base64.StdEncoding.EncodeToString([]byte("<p>HTML</p>")),
+		"--inner--",
+		"--outer",
+		"Content-Type: text/plain",
+		"Content-Disposition: attachment; filename=notes.txt",
+		"",
+		"Not a body",
+		"--outer--",
+		"",
+	}, "\r\n"))
+
+	content, err = InboxMessage{Data: data}.Content()
+	require.NoError(t, err)
+	assert.Equal(t, "Multipart", content.Subject)
+	assert.Equal(t, "Plain ümlaut", content.Text)
+	assert.Equal(t, "<p>HTML</p>", content.HTML)
+}
+
+func TestMailClient_Send(t *testing.T) {
+	srv := &tests.SMTPServer{}
+	srv.Start(t)
+
+	cfg := *c.Config
+	cfg.Mail.Hostname = srv.Host()
+	cfg.Mail.Port = srv.Port()
+	cfg.Mail.User = ""
+	cfg.Mail.Encryption = config.MailEncryptionNone
+	cfg.Mail.FromAddress = "admin@example.com"
+
+	transport, err := NewSMTPTransport(cfg.Mail)
+	require.NoError(t, err)
+	client := NewMailClient(&cfg, c.TemplateRenderer, transport, nil, nil, nil)
+	t.Cleanup(client.Close)
+	assert.Nil(t, client.Inbox())
+
+	err = client.
+		Compose().
+		To("user@example.com").
+		Subject("Test").
+		Template("test").
+		SendContext(context.Background())
+	require.NoError(t, err)
+
+	msgs := srv.Messages()
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "admin@example.com", msgs[0].From)
+	assert.Equal(t, []string{"user@example.com"}, msgs[0].To)
+
+	parsed, body := readMessage(t, msgs[0].Data)
+	assert.Equal(t, "Test", parsed.Header.Get("Subject"))
+	assert.Equal(t, "text/html; charset=utf-8", parsed.Header.Get("Content-Type"))
+	assert.Contains(t, body, "Test email template")
+
+	// Delivery failures are returned
+	srv.Close()
+	client.Close()
+	err = client.
+		Compose().
+		To("user@example.com").
+		Body("Hello").
+		SendContext(context.Background())
+	assert.Error(t, err)
+}
+
+func TestMailClient_Inbox(t *testing.T) {
+	// Email is captured in memory while testing
+	inbox := c.Mail.Inbox()
+	require.NotNil(t, inbox)
+	inbox.Clear()
+
+	err := c.Mail.
+		Compose().
+		To("user@example.com").
+		Subject("Hello").
+		Body("Hi there").
+		Send(ctx)
+	require.NoError(t, err)
+
+	msgs := inbox.MessagesTo("user@example.com")
+	require.Len(t, msgs, 1)
+	parsed, body := readMessage(t, msgs[0].Data)
+	assert.Equal(t, "Hello", parsed.Header.Get("Subject"))
+	assert.Equal(t, "Hi there", body)
+}
+
+func TestMailClient_Queue(t *testing.T) {
+	inbox := NewMemoryTransport(100)
+	client := NewMailClient(c.Config, c.TemplateRenderer, inbox, c.Tasks, nil, nil)
+
+	// Email is queued rather than delivered
+	err := client.
+		Compose().
+		To("user@example.com").
+		Subject("Queued").
+		Body("Hello").
+		Send(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, inbox.Messages())
+
+	tasks := queuedMailTasks(t)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, []string{"user@example.com"}, tasks[0].To)
+
+	// The queued message is delivered when the task is processed
+	require.NoError(t, client.Deliver(context.Background(), tasks[0]))
+	msgs := inbox.Messages()
+	require.Len(t, msgs, 1)
+	parsed, body := readMessage(t, msgs[0].Data)
+	assert.Equal(t, "Queued", parsed.Header.Get("Subject"))
+	assert.Equal(t, "<"+tasks[0].MessageID+">", parsed.Header.Get("Message-ID"))
+	assert.Equal(t, "Hello", body)
+
+	// Unless it is sent now
+	err = client.
+		Compose().
+		To("user@example.com").
+		Body("Hello").
+		SendNow(ctx)
+	require.NoError(t, err)
+	assert.Len(t, inbox.Messages(), 2)
+	assert.Empty(t, queuedMailTasks(t))
+}
+
+func TestMailClient_Tx(t *testing.T) {
+	inbox := c.Mail.Inbox()
+	inbox.Clear()
+
+	send := func(tx *ent.Tx) {
+		err := c.Mail.
+			Compose().
+			To("user@example.com").
+			Body("Hello").
+			Tx(tx).
+			SendContext(context.Background())
+		require.NoError(t, err)
+	}
+
+	// Nothing is sent if the transaction is rolled back
+	tx, err := c.ORM.Tx(context.Background())
+	require.NoError(t, err)
+	send(tx)
+	assert.Empty(t, inbox.Messages())
+	require.NoError(t, tx.Rollback())
+	assert.Empty(t, inbox.Messages())
+
+	// The email is sent once the transaction is committed
+	tx, err = c.ORM.Tx(context.Background())
+	require.NoError(t, err)
+	send(tx)
+	assert.Empty(t, inbox.Messages())
+	require.NoError(t, tx.Commit())
+	assert.Len(t, inbox.Messages(), 1)
+
+	// When email is queued, the task is added within the transaction
+	inbox = NewMemoryTransport(100)
+	client := NewMailClient(c.Config, c.TemplateRenderer, inbox, c.Tasks, nil, nil)
+	queue := func(tx *ent.Tx) {
+		err := client.
+			Compose().
+			To("user@example.com").
+			Body("Hello").
+			Tx(tx).
+			SendContext(context.Background())
+		require.NoError(t, err)
+	}
+
+	tx, err = c.ORM.Tx(context.Background())
+	require.NoError(t, err)
+	queue(tx)
+	require.NoError(t, tx.Rollback())
+	assert.Empty(t, queuedMailTasks(t))
+
+	tx, err = c.ORM.Tx(context.Background())
+	require.NoError(t, err)
+	queue(tx)
+	require.NoError(t, tx.Commit())
+	tasks := queuedMailTasks(t)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, []string{"user@example.com"}, tasks[0].To)
+	assert.Empty(t, inbox.Messages())
+}
+
+// queuedMailTasks removes and returns the queued mail tasks
+func queuedMailTasks(t *testing.T) []MailTask {
+	rows, err := c.Database.Query("SELECT task FROM backlite_tasks WHERE queue = ?", MailTask{}.Config().Name)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var tasks []MailTask
+	for rows.Next() {
+		var payload []byte
+		require.NoError(t, rows.Scan(&payload))
+		var task MailTask
+		require.NoError(t, json.Unmarshal(payload, &task))
+		tasks = append(tasks, task)
+	}
+	require.NoError(t, rows.Err())
+
+	_, err = c.Database.Exec("DELETE FROM backlite_tasks WHERE queue = ?", MailTask{}.Config().Name)
+	require.NoError(t, err)
+	return tasks
+}
+
+// verifyDKIM verifies the DKIM signature of a given message using the public key in a given DNS TXT record value.
+// This is implemented separately from the signer, following RFC 6376, so the signer is not used to check itself.
+func verifyDKIM(msg []byte, record string) error {
+	tags := func(s string) map[string]string {
+		m := make(map[string]string)
+		for _, tag := range strings.Split(s, ";") {
+			if k, v, ok := strings.Cut(tag, "="); ok {
+				m[strings.TrimSpace(k)] = regexp.MustCompile(`\s+`).ReplaceAllString(v, "")
+			}
+		}
+		return m
+	}
+
+	wsp := regexp.MustCompile(`[ \t]+`)
+	canonHeader := func(field string) string {
+		name, value, _ := strings.Cut(field, ":")
+		return strings.ToLower(strings.TrimSpace(name)) + ":" + strings.TrimSpace(wsp.ReplaceAllString(value, " "))
+	}
+
+	raw := string(msg)
+	i := strings.Index(raw, "\r\n\r\n")
+	if i == -1 {
+		return errors.New("no body")
+	}
+
+	// Unfold the headers so each line is a field
+	header := regexp.MustCompile(`\r\n([ \t])`).ReplaceAllString(raw[:i], "$1")
+	fields := strings.Split(header, "\r\n")
+	if !strings.HasPrefix(strings.ToLower(fields[0]), "dkim-signature:") {
+		return errors.New("no signature")
+	}
+	_, sigValue, _ := strings.Cut(fields[0], ":")
+	sig := tags(sigValue)
+
+	if sig["v"] != "1" || sig["c"] != "relaxed/relaxed" {
+		return fmt.Errorf("unexpected tags: %v", sig)
+	}
+
+	// Verify the body hash
+	body := wsp.ReplaceAllString(raw[i+4:], " ")
+	body = strings.ReplaceAll(body, " \r\n", "\r\n")
+	body = regexp.MustCompile(`(\r\n)+$`).ReplaceAllString(body, "\r\n")
+	bh := sha256.Sum256([]byte(body))
+	if base64.StdEncoding.EncodeToString(bh[:]) != sig["bh"] {
+		return errors.New("body hash does not match")
+	}
+
+	// Collect the signed headers, taking repeated headers from the bottom up
+	used := make(map[string]int)
+	var signed strings.Builder
+	for _, name := range strings.Split(sig["h"], ":") {
+		var instances []string
+		for _, field := range fields[1:] {
+			if strings.EqualFold(strings.TrimSpace(strings.SplitN(field, ":", 2)[0]), name) {
+				instances = append(instances, field)
+			}
+		}
+		if n := len(instances) - 1 - used[name]; n >= 0 {
+			signed.WriteString(canonHeader(instances[n]) + "\r\n")
+		}
+		used[name]++
+	}
+	signed.WriteString(canonHeader(regexp.MustCompile(`([;\s]b=)[^;]*`).ReplaceAllString(fields[0], "$1")))
+	hash := sha256.Sum256([]byte(signed.String()))
+
+	b, err := base64.StdEncoding.DecodeString(sig["b"])
+	if err != nil {
+		return err
+	}
+	key, err := base64.StdEncoding.DecodeString(tags(record)["p"])
+	if err != nil {
+		return err
+	}
+
+	switch sig["a"] {
+	case "rsa-sha256":
+		pub, err := x509.ParsePKIXPublicKey(key)
+		if err != nil {
+			return err
+		}
+		return rsa.VerifyPKCS1v15(pub.(*rsa.PublicKey), crypto.SHA256, hash[:], b)
+	case "ed25519-sha256":
+		if !ed25519.Verify(key, hash[:], b) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported algorithm: %s", sig["a"])
+	}
+}
+
+func TestDKIMSigner(t *testing.T) {
+	for _, keyType := range []string{"rsa", "ed25519"} {
+		t.Run(keyType, func(t *testing.T) {
+			key, pem, err := GenerateDKIMKey(keyType, 1024)
+			require.NoError(t, err)
+			record, err := DKIMRecord(key)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(record, "v=DKIM1; k="+keyType+"; p="))
+
+			cfg := *c.Config
+			cfg.Mail.FromAddress = "Admin <admin@example.com>"
+			cfg.Mail.DKIM.Domain = "Example.com"
+			cfg.Mail.DKIM.Selector = "pagoda"
+			cfg.Mail.DKIM.PrivateKey = string(pem)
+			signer, err := NewDKIMSigner(cfg.Mail)
+			require.NoError(t, err)
+			assert.Equal(t, "pagoda._domainkey.example.com", signer.RecordName())
+
+			inbox := NewMemoryTransport(100)
+			client := NewMailClient(&cfg, c.TemplateRenderer, inbox, nil, signer, nil)
+			err = client.
+				Compose().
+				To("user@example.com").
+				Cc("Another   User <other@example.com>").
+				Subject("A long subject with ümlauts which will need to be folded across more than one line").
+				Body("Hello  there\t \r\n\r\nBye\r\n\r\n\r\n").
+				AttachFile(fstest.MapFS{"report.txt": {Data: []byte("Report")}}, "report.txt").
+				SendContext(context.Background())
+			require.NoError(t, err)
+
+			msgs := inbox.Messages()
+			require.Len(t, msgs, 1)
+			data := msgs[0].Data
+			require.NoError(t, verifyDKIM(data, record))
+
+			parsed, err := netmail.ReadMessage(bytes.NewReader(data))
+			require.NoError(t, err)
+			sig := parsed.Header.Get("DKIM-Signature")
+			assert.Contains(t, sig, "a="+keyType+"-sha256;")
+			assert.Contains(t, sig, "d=example.com;")
+			assert.Contains(t, sig, "s=pagoda;")
+			assert.Contains(t, sig, "h=from:to:cc:subject:date:message-id:mime-version:content-type:from;")
+			for _, line := range strings.Split(string(data), "\r\n") {
+				assert.LessOrEqual(t, len(line), 78)
+			}
+
+			// Whitespace changes made by mail servers do not break the signature
+			refolded := bytes.Replace(data, []byte("Subject: "), []byte("Subject:\r\n\t"), 1)
+			refolded = append(bytes.TrimSuffix(refolded, []byte("\r\n")), " \t\r\n\r\n\r\n"...)
+			assert.NoError(t, verifyDKIM(refolded, record))
+
+			// But changing the content does
+			tampered := bytes.Replace(data, []byte("To: <user@example.com>"), []byte("To: <attacker@example.com>"), 1)
+			require.NotEqual(t, data, tampered)
+			assert.Error(t, verifyDKIM(tampered, record))
+
+			tampered = bytes.Replace(data, []byte("UmVwb3J0"), []byte("UmVwb3J1"), 1)
+			require.NotEqual(t, data, tampered)
+			assert.Error(t, verifyDKIM(tampered, record))
+
+			// As does adding a second From header
+			tampered = bytes.Replace(data, []byte("\r\nTo:"), []byte("\r\nFrom: attacker@example.com\r\nTo:"), 1)
+			assert.Error(t, verifyDKIM(tampered, record))
+
+			// Or verifying with a different key
+			other, _, err := GenerateDKIMKey(keyType, 1024)
+			require.NoError(t, err)
+			otherRecord, err := DKIMRecord(other)
+			require.NoError(t, err)
+			assert.Error(t, verifyDKIM(data, otherRecord))
+		})
+	}
+}
+
+func TestNewDKIMSigner(t *testing.T) {
+	// Signing is disabled without a domain
+	signer, err := NewDKIMSigner(config.MailConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, signer)
+
+	var cfg config.MailConfig
+	cfg.DKIM.Domain = "example.com"
+	cfg.DKIM.Selector = "pagoda"
+	cfg.DKIM.PrivateKey = "invalid"
+	_, err = NewDKIMSigner(cfg)
+	assert.Error(t, err)
+
+	// Keys can be loaded from a file, including PKCS #1 RSA keys
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	cfg.DKIM.PrivateKey = ""
+	cfg.DKIM.PrivateKeyFile = filepath.Join(t.TempDir(), "dkim.pem")
+	err = os.WriteFile(cfg.DKIM.PrivateKeyFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}), 0o600)
+	require.NoError(t, err)
+	signer, err = NewDKIMSigner(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, key.Public(), signer.key.Public())
+
+	cfg.DKIM.Selector = ""
+	_, err = NewDKIMSigner(cfg)
+	assert.Error(t, err)
+}
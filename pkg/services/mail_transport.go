@@ -0,0 +1,374 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mikestefanello/pagoda/config"
+)
+
+type (
+	// MailTransport delivers encoded MIME messages
+	MailTransport interface {
+		// Send delivers a given message from a given address to given recipients
+		Send(ctx context.Context, from string, to []string, msg []byte) error
+
+		// Close releases any resources held by the transport, such as open connections
+		Close()
+	}
+
+	// SendmailTransport is a MailTransport which delivers messages by piping them to the sendmail binary, or any
+	// other binary which is compatible with it
+	SendmailTransport struct {
+		// path is the path to the binary
+		path string
+
+		// args are the arguments passed before the sender and recipients
+		args []string
+	}
+
+	// FileTransport is a MailTransport which writes messages as .eml files to a directory rather than delivering
+	// them, which can be opened by most email clients
+	FileTransport struct {
+		// dir is the directory the files are written to
+		dir string
+	}
+
+	// MemoryTransport is a MailTransport which captures messages in memory rather than delivering them, serving as
+	// an inbox which can be inspected, such as within tests
+	MemoryTransport struct {
+		mu       sync.RWMutex
+		messages []InboxMessage
+		lastID   int
+
+		// capacity is the maximum amount of messages kept, after which the oldest are discarded
+		capacity int
+	}
+
+	// InboxMessage is a message captured by the MemoryTransport
+	InboxMessage struct {
+		// ID identifies the message within the inbox
+		ID int
+
+		// From is the address the message was sent from
+		From string
+
+		// To are the addresses the message was sent to
+		To []string
+
+		// Data is the encoded MIME message
+		Data []byte
+
+		// ReceivedAt is when the message was captured
+		ReceivedAt time.Time
+	}
+
+	// InboxContent is the decoded content of an InboxMessage
+	InboxContent struct {
+		// Header contains the headers of the message
+		Header netmail.Header
+
+		// Subject is the decoded subject of the message
+		Subject string
+
+		// Text is the plain text body of the message, if it has one
+		Text string
+
+		// HTML is the HTML body of the message, if it has one
+		HTML string
+
+		// Attachments are the files attached to the message, including inline ones
+		Attachments []InboxAttachment
+	}
+
+	// InboxAttachment is a file attached to an InboxMessage
+	InboxAttachment struct {
+		// Filename is the name of the file
+		Filename string
+
+		// ContentType is the media type of the file
+		ContentType string
+
+		// ContentID identifies inline attachments, which are referenced by the HTML body, without angle brackets
+		ContentID string
+
+		// Data is the decoded content of the file
+		Data []byte
+	}
+)
+
+// NewMailTransport creates the MailTransport in a given mail configuration
+func NewMailTransport(cfg config.MailConfig) (MailTransport, error) {
+	switch cfg.Transport {
+	case config.MailTransportSMTP:
+		return NewSMTPTransport(cfg)
+	case config.MailTransportSendmail:
+		return NewSendmailTransport(cfg.Sendmail.Path, cfg.Sendmail.Args...), nil
+	case config.MailTransportFile:
+		return NewFileTransport(cfg.File.Directory)
+	case config.MailTransportMemory:
+		return NewMemoryTransport(cfg.Memory.Capacity), nil
+	default:
+		return nil, fmt.Errorf("invalid mail transport: %q", cfg.Transport)
+	}
+}
+
+// NewSendmailTransport creates a new SendmailTransport
+func NewSendmailTransport(path string, args ...string) *SendmailTransport {
+	return &SendmailTransport{
+		path: path,
+		args: args,
+	}
+}
+
+// Send delivers a given message from a given address to given recipients
+func (s *SendmailTransport) Send(ctx context.Context, from string, to []string, msg []byte) error {
+	args := append([]string{}, s.args...)
+	args = append(args, "-f", from, "--")
+	args = append(args, to...)
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.path, args...)
+	cmd.Stdin = bytes.NewReader(msg)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("sendmail failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// Close does nothing since each message is delivered by a separate process
+func (s *SendmailTransport) Close() {}
+
+// NewFileTransport creates a new FileTransport, creating the directory if it does not exist
+func NewFileTransport(dir string) (*FileTransport, error) {
+	if dir == "" {
+		return nil, errors.New("mail file transport requires a directory")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create mail directory: %w", err)
+	}
+
+	return &FileTransport{dir: dir}, nil
+}
+
+// Send writes a given message to a new file, named so files are sorted by the time they were written
+func (f *FileTransport) Send(ctx context.Context, from string, to []string, msg []byte) error {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), hex.EncodeToString(b))
+	if err := os.WriteFile(filepath.Join(f.dir, name), msg, 0o644); err != nil {
+		return fmt.Errorf("unable to write email file: %w", err)
+	}
+
+	return nil
+}
+
+// Close does nothing since each file is closed once written
+func (f *FileTransport) Close() {}
+
+// NewMemoryTransport creates a new MemoryTransport which keeps up to a given amount of messages, discarding the
+// oldest once it is full, so it cannot grow without bound
+func NewMemoryTransport(capacity int) *MemoryTransport {
+	return &MemoryTransport{
+		capacity: max(capacity, 1),
+	}
+}
+
+// Send captures a given message
+func (m *MemoryTransport) Send(ctx context.Context, from string, to []string, msg []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+	m.messages = append(m.messages, InboxMessage{
+		ID:         m.lastID,
+		From:       from,
+		To:         append([]string{}, to...),
+		Data:       append([]byte{}, msg...),
+		ReceivedAt: time.Now(),
+	})
+
+	if over := len(m.messages) - m.capacity; over > 0 {
+		m.messages = slices.Delete(m.messages, 0, over)
+	}
+
+	return nil
+}
+
+// Close does nothing since there is nothing to release
+func (m *MemoryTransport) Close() {}
+
+// Messages returns the captured messages, in the order they were sent
+func (m *MemoryTransport) Messages() []InboxMessage {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]InboxMessage{}, m.messages...)
+}
+
+// MessagesTo returns the captured messages sent to a given address, in the order they were sent
+func (m *MemoryTransport) MessagesTo(address string) []InboxMessage {
+	var msgs []InboxMessage
+	for _, msg := range m.Messages() {
+		for _, to := range msg.To {
+			if strings.EqualFold(to, address) {
+				msgs = append(msgs, msg)
+				break
+			}
+		}
+	}
+	return msgs
+}
+
+// Message returns the captured message with a given ID, if it exists
+func (m *MemoryTransport) Message(id int) (InboxMessage, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, msg := range m.messages {
+		if msg.ID == id {
+			return msg, true
+		}
+	}
+	return InboxMessage{}, false
+}
+
+// Clear removes all captured messages
+func (m *MemoryTransport) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = nil
+}
+
+// Parse parses the message so its headers and body can be inspected
+func (i InboxMessage) Parse() (*netmail.Message, error) {
+	return netmail.ReadMessage(bytes.NewReader(i.Data))
+}
+
+// Content parses the message and decodes its subject and bodies, including those within multipart messages
+func (i InboxMessage) Content() (*InboxContent, error) {
+	msg, err := i.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	content := &InboxContent{Header: msg.Header}
+	if content.Subject, err = new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); err != nil {
+		content.Subject = msg.Header.Get("Subject")
+	}
+
+	header := textproto.MIMEHeader(msg.Header)
+	body, err := decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = content.read(header.Get("Content-Type"), body); err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// read reads a given part of a message with a given content type, descending into multipart parts, and keeps the
+// first plain text and HTML bodies found, along with any attachments
+func (c *InboxContent) read(contentType string, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		r := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := r.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			// Quoted-printable parts are decoded by the reader
+			pb, err := decodeTransferEncoding(part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return err
+			}
+
+			// Attachments are not bodies, even if they are text
+			contentID := strings.Trim(part.Header.Get("Content-ID"), "<>")
+			disposition, _, _ := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+			if disposition == "attachment" || contentID != "" {
+				data, err := io.ReadAll(pb)
+				if err != nil {
+					return err
+				}
+
+				c.Attachments = append(c.Attachments, InboxAttachment{
+					Filename:    part.FileName(),
+					ContentType: part.Header.Get("Content-Type"),
+					ContentID:   contentID,
+					Data:        data,
+				})
+				continue
+			}
+
+			if err = c.read(part.Header.Get("Content-Type"), pb); err != nil {
+				return err
+			}
+		}
+
+	case mediaType == "text/plain" && c.Text == "", mediaType == "text/html" && c.HTML == "":
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+
+		if mediaType == "text/html" {
+			c.HTML = string(b)
+		} else {
+			c.Text = string(b)
+		}
+	}
+
+	return nil
+}
+
+// decodeTransferEncoding decodes a given body which uses a given content transfer encoding
+func decodeTransferEncoding(encoding string, body io.Reader) (io.Reader, error) {
+	switch strings.ToLower(encoding) {
+	case "quoted-printable":
+		return quotedprintable.NewReader(body), nil
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body), nil
+	case "", "7bit", "8bit", "binary":
+		return body, nil
+	default:
+		return nil, fmt.Errorf("unsupported content transfer encoding: %q", encoding)
+	}
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/ent"
+)
+
+// SecurityEvent is an event which affects the security of an account, which the user is notified of
+type SecurityEvent string
+
+const (
+	// SecurityEventPasswordChanged is when a user changes their password from their account settings
+	SecurityEventPasswordChanged SecurityEvent = "password_changed"
+
+	// SecurityEventPasswordReset is when a user resets their password via a password reset link
+	SecurityEventPasswordReset SecurityEvent = "password_reset"
+
+	// SecurityEventEmailChanged is when a user changes their email address
+	SecurityEventEmailChanged SecurityEvent = "email_changed"
+
+	// SecurityEventNewDevice is when a user logs in from a device they have not logged in from before
+	SecurityEventNewDevice SecurityEvent = "new_device"
+)
+
+const (
+	// securityRevokeRoute is the name of the route which handles the link that revokes every session of a user
+	securityRevokeRoute = "security.revoke"
+
+	// securityNotificationTemplate is the template of security notifications, within the emails directory
+	securityNotificationTemplate = "security-notification"
+)
+
+type (
+	// NotificationClient notifies users of events which affect the security of their account, along with the device,
+	// IP address and time of the request which caused them, so users can tell if it was not them.
+	// Every notification contains a link which logs out every device and starts a password reset, and which changes
+	// the email address back if it was changed.
+	NotificationClient struct {
+		config *config.Config
+		auth   *AuthClient
+		mail   *MailClient
+		web    *echo.Echo
+	}
+
+	// SecurityNotification is the data of the template of security notifications
+	SecurityNotification struct {
+		// Event is the event the user is being notified of
+		Event SecurityEvent
+
+		// Name is the name of the user
+		Name string
+
+		// Email is the new email address of the user, if it changed
+		Email string
+
+		// Device describes the browser and operating system of the request
+		Device string
+
+		// IP is the IP address of the request
+		IP string
+
+		// Time is when the event happened, formatted in UTC
+		Time string
+
+		// RevokeURL is the absolute URL which logs out every device and starts a password reset
+		RevokeURL string
+	}
+)
+
+// NewNotificationClient creates a new NotificationClient
+func NewNotificationClient(cfg *config.Config, auth *AuthClient, mail *MailClient, web *echo.Echo) *NotificationClient {
+	return &NotificationClient{
+		config: cfg,
+		auth:   auth,
+		mail:   mail,
+		web:    web,
+	}
+}
+
+// PasswordChanged notifies a given user that their password was changed from their account settings
+func (c *NotificationClient) PasswordChanged(ctx echo.Context, usr *ent.User) error {
+	return c.send(ctx, usr, usr.Email, SecurityEventPasswordChanged, "Your password was changed")
+}
+
+// PasswordReset notifies a given user that their password was reset via a password reset link
+func (c *NotificationClient) PasswordReset(ctx echo.Context, usr *ent.User) error {
+	return c.send(ctx, usr, usr.Email, SecurityEventPasswordReset, "Your password was reset")
+}
+
+// EmailChanged notifies a given user, whose email address was changed, at their previous address, since the owner
+// of that address is the one who needs to know if they did not make the change
+func (c *NotificationClient) EmailChanged(ctx echo.Context, usr *ent.User, previous string) error {
+	return c.send(ctx, usr, previous, SecurityEventEmailChanged, "Your email address was changed")
+}
+
+// NewDevice notifies a given user that their account was logged in to from a new device
+func (c *NotificationClient) NewDevice(ctx echo.Context, usr *ent.User) error {
+	return c.send(ctx, usr, usr.Email, SecurityEventNewDevice, "New login to your account")
+}
+
+// send sends a notification of a given event to a given user at a given address
+func (c *NotificationClient) send(
+	ctx echo.Context,
+	usr *ent.User,
+	to string,
+	event SecurityEvent,
+	subject string,
+) error {
+	// The previous address of a user whose email address was changed is restored when they revoke their sessions,
+	// since the account may have been taken over by changing it
+	var restore string
+	if event == SecurityEventEmailChanged {
+		restore = to
+	}
+
+	token, err := c.auth.GenerateRevokeToken(usr, restore)
+	if err != nil {
+		return fmt.Errorf("unable to generate revoke token: %w", err)
+	}
+
+	data := SecurityNotification{
+		Event:     event,
+		Name:      usr.Name,
+		Device:    DescribeDevice(ctx.Request().UserAgent()),
+		IP:        ctx.RealIP(),
+		Time:      time.Now().UTC().Format("January 2, 2006 at 15:04 MST"),
+		RevokeURL: strings.TrimSuffix(c.config.App.URL, "/") + c.web.Reverse(securityRevokeRoute, token),
+	}
+
+	if event == SecurityEventEmailChanged {
+		data.Email = usr.Email
+	}
+
+	msg := c.mail.
+		Compose().
+		To(to).
+		Subject(subject).
+		Template(securityNotificationTemplate).
+		TemplateData(data)
+
+	// Only logins from new devices can be opted out of. Changes which could mean the account was taken over are
+	// always sent, so they are only suppressed if the address bounced or complained.
+	if event == SecurityEventNewDevice {
+		msg.Category(MailCategorySecurity)
+	}
+
+	return msg.Send(ctx)
+}
+
+// DescribeDevice returns a short description of the browser and operating system of a given user agent, such as
+// "Firefox on Windows", which is easier for users to recognize than the user agent itself
+func DescribeDevice(userAgent string) string {
+	// The order matters, since user agents also name the browsers they are based on
+	var browser string
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "Firefox/"), strings.Contains(userAgent, "FxiOS/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/"), strings.Contains(userAgent, "CriOS/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	}
+
+	var os string
+	switch {
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"):
+		os = "iOS"
+	case strings.Contains(userAgent, "Android"):
+		os = "Android"
+	case strings.Contains(userAgent, "CrOS"):
+		os = "ChromeOS"
+	case strings.Contains(userAgent, "Windows"):
+		os = "Windows"
+	case strings.Contains(userAgent, "Macintosh"):
+		os = "macOS"
+	case strings.Contains(userAgent, "Linux"):
+		os = "Linux"
+	}
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	case userAgent != "":
+		return userAgent
+	default:
+		return "Unknown device"
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mikestefanello/pagoda/pkg/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationClient(t *testing.T) {
+	// The routes are registered by the handlers, which are not loaded here
+	if c.Web.Reverse(securityRevokeRoute, "x") == "" {
+		c.Web.GET("/security/revoke/:token", nil).Name = securityRevokeRoute
+	}
+	if c.Web.Reverse(mailUnsubscribeRoute, "x") == "" {
+		c.Web.GET("/email/unsubscribe/:token", nil).Name = mailUnsubscribeRoute
+	}
+
+	u, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	ctx, _ := tests.NewContext(c.Web, "/")
+	ctx.Request().Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0")
+	ctx.Request().Header.Set("X-Real-IP", "203.0.113.7")
+
+	inbox := c.Mail.Inbox()
+	inbox.Clear()
+
+	require.NoError(t, c.Notification.EmailChanged(ctx, u, "previous@localhost"))
+
+	msgs := inbox.Messages()
+	require.Len(t, msgs, 1)
+	assert.Equal(t, []string{"previous@localhost"}, msgs[0].To)
+
+	content, err := msgs[0].Content()
+	require.NoError(t, err)
+	assert.Equal(t, "Your email address was changed", content.Subject)
+	assert.Contains(t, content.HTML, u.Email)
+	assert.Contains(t, content.HTML, "Firefox on Linux")
+	assert.Contains(t, content.HTML, "203.0.113.7")
+	assert.Empty(t, content.Header.Get("List-Unsubscribe"))
+
+	// The link revokes the sessions of the user and restores the previous email address
+	link := regexp.MustCompile(`href="([^"]+)"`).FindStringSubmatch(content.HTML)
+	require.Len(t, link, 2)
+	prefix := c.Config.App.URL + "/security/revoke/"
+	require.True(t, strings.HasPrefix(link[1], prefix))
+	got, restore, err := c.Auth.ValidateRevokeToken(ctx.Request().Context(), strings.TrimPrefix(link[1], prefix))
+	require.NoError(t, err)
+	assert.Equal(t, u.ID, got.ID)
+	assert.Equal(t, "previous@localhost", restore)
+
+	// Logins from new devices can be unsubscribed from
+	inbox.Clear()
+	require.NoError(t, c.Notification.NewDevice(ctx, u))
+	msgs = inbox.MessagesTo(u.Email)
+	require.Len(t, msgs, 1)
+	content, err = msgs[0].Content()
+	require.NoError(t, err)
+	assert.NotEmpty(t, content.Header.Get("List-Unsubscribe"))
+
+	// Users who unsubscribed from security alerts are not notified of new devices
+	inbox.Clear()
+	require.NoError(t, c.MailSuppression.Unsubscribe(ctx.Request().Context(), u.Email, MailCategorySecurity))
+	require.NoError(t, c.Notification.NewDevice(ctx, u))
+	assert.Empty(t, inbox.Messages())
+
+	// But are still notified of changes which could mean their account was taken over
+	require.NoError(t, c.Notification.PasswordChanged(ctx, u))
+	require.NoError(t, c.Notification.PasswordReset(ctx, u))
+	assert.Len(t, inbox.MessagesTo(u.Email), 2)
+}
+
+func TestDescribeDevice(t *testing.T) {
+	cases := map[string]string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36":                         "Chrome on Windows",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36 Edg/126.0.0.0":           "Edge on Windows",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Safari/605.1.15":                   "Safari on macOS",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1": "Safari on iOS",
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36":                   "Chrome on Android",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0":                                                                  "Firefox on Linux",
+		"Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36":                          "Chrome on ChromeOS",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36 OPR/111.0.0.0":           "Opera on Windows",
+		"curl/8.8.0": "curl/8.8.0",
+		"":           "Unknown device",
+	}
+
+	for ua, expected := range cases {
+		assert.Equal(t, expected, DescribeDevice(ua), ua)
+	}
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/invitation"
+	"github.com/mikestefanello/pagoda/ent/membership"
+	"github.com/mikestefanello/pagoda/ent/organization"
+	"github.com/mikestefanello/pagoda/ent/user"
+	"github.com/mikestefanello/pagoda/pkg/session"
+)
+
+const (
+	// organizationSessionName stores the name of the session which contains the organization data
+	organizationSessionName = "org"
+
+	// organizationSessionKeyID stores the key used to store the ID of the current organization in the session
+	organizationSessionKeyID = "organization_id"
+)
+
+// InvalidInvitationError is an error returned when an invalid, expired or already used invitation is provided
+type InvalidInvitationError struct{}
+
+// Error implements the error interface.
+func (e InvalidInvitationError) Error() string {
+	return "invalid invitation"
+}
+
+// AlreadyMemberError is an error returned when inviting someone who is already a member of the organization
+type AlreadyMemberError struct{}
+
+// Error implements the error interface.
+func (e AlreadyMemberError) Error() string {
+	return "already a member"
+}
+
+// LastOwnerError is an error returned when removing the only owner of an organization
+type LastOwnerError struct{}
+
+// Error implements the error interface.
+func (e LastOwnerError) Error() string {
+	return "an organization must have an owner"
+}
+
+// OrganizationClient handles organizations, which users belong to through memberships, along with the invitations
+// used to add new members and the organization the user is currently working in
+type OrganizationClient struct {
+	config *config.Config
+	orm    *ent.Client
+	auth   *AuthClient
+}
+
+// NewOrganizationClient creates a new OrganizationClient
+func NewOrganizationClient(cfg *config.Config, orm *ent.Client, auth *AuthClient) *OrganizationClient {
+	return &OrganizationClient{
+		config: cfg,
+		orm:    orm,
+		auth:   auth,
+	}
+}
+
+// CanManageMembers determines if a given role is allowed to invite and remove members of an organization
+func CanManageMembers(role membership.Role) bool {
+	return role == membership.RoleOwner || role == membership.RoleAdmin
+}
+
+// CanAssignRole determines if a given role is allowed to invite or remove members with another given role.
+// Only owners can manage other owners.
+func CanAssignRole(role, other membership.Role) bool {
+	return CanManageMembers(role) && (role == membership.RoleOwner || other != membership.RoleOwner)
+}
+
+// Create creates an organization of a given name with a given user as its owner
+func (c *OrganizationClient) Create(ctx context.Context, userID int, name string) (*ent.Organization, error) {
+	tx, err := c.orm.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := tx.Organization.
+		Create().
+		SetName(name).
+		Save(ctx)
+
+	if err == nil {
+		err = tx.Membership.
+			Create().
+			SetOrganization(org).
+			SetUserID(userID).
+			SetRole(membership.RoleOwner).
+			Exec(ctx)
+	}
+
+	if err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			err = fmt.Errorf("%w: %v", err, rerr)
+		}
+		return nil, err
+	}
+
+	return org, tx.Commit()
+}
+
+// GetMemberships returns the memberships of a given user, with the organization loaded, ordered by the name of
+// the organization
+func (c *OrganizationClient) GetMemberships(ctx context.Context, userID int) ([]*ent.Membership, error) {
+	return c.orm.Membership.
+		Query().
+		Where(membership.HasUserWith(user.ID(userID))).
+		WithOrganization().
+		Order(membership.ByOrganizationField(organization.FieldName), ent.Asc(membership.FieldID)).
+		All(ctx)
+}
+
+// GetMembers returns the memberships of a given organization, with the user loaded, oldest first
+func (c *OrganizationClient) GetMembers(ctx context.Context, orgID int) ([]*ent.Membership, error) {
+	return c.orm.Membership.
+		Query().
+		Where(membership.HasOrganizationWith(organization.ID(orgID))).
+		WithUser().
+		Order(ent.Asc(membership.FieldCreatedAt), ent.Asc(membership.FieldID)).
+		All(ctx)
+}
+
+// GetMember returns the membership of a given ID belonging to a given organization
+func (c *OrganizationClient) GetMember(ctx context.Context, orgID, membershipID int) (*ent.Membership, error) {
+	return c.orm.Membership.
+		Query().
+		Where(membership.ID(membershipID)).
+		Where(membership.HasOrganizationWith(organization.ID(orgID))).
+		Only(ctx)
+}
+
+// RemoveMember removes a given membership from a given organization.
+// The only owner of an organization cannot be removed.
+func (c *OrganizationClient) RemoveMember(ctx context.Context, orgID int, m *ent.Membership) error {
+	if m.Role == membership.RoleOwner {
+		owners, err := c.orm.Membership.
+			Query().
+			Where(membership.HasOrganizationWith(organization.ID(orgID))).
+			Where(membership.RoleEQ(membership.RoleOwner)).
+			Count(ctx)
+		if err != nil {
+			return err
+		}
+
+		if owners <= 1 {
+			return LastOwnerError{}
+		}
+	}
+
+	return c.orm.Membership.DeleteOne(m).Exec(ctx)
+}
+
+// Invite invites a given email address to join a given organization with a given role, replacing any pending
+// invitation of that address. Just like password tokens, only a hash of the returned token, which is required to
+// accept the invitation, is stored.
+func (c *OrganizationClient) Invite(
+	ctx context.Context,
+	orgID, inviterID int,
+	email string,
+	role membership.Role,
+) (string, *ent.Invitation, error) {
+	email = strings.ToLower(email)
+
+	exists, err := c.orm.Membership.
+		Query().
+		Where(membership.HasOrganizationWith(organization.ID(orgID))).
+		Where(membership.HasUserWith(user.Email(email))).
+		Exist(ctx)
+	switch {
+	case err != nil:
+		return "", nil, err
+	case exists:
+		return "", nil, AlreadyMemberError{}
+	}
+
+	token, err := c.auth.RandomToken(c.config.App.Invitation.Length)
+	if err != nil {
+		return "", nil, err
+	}
+
+	_, err = c.orm.Invitation.
+		Delete().
+		Where(invitation.HasOrganizationWith(organization.ID(orgID))).
+		Where(invitation.Email(email)).
+		Exec(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	inv, err := c.orm.Invitation.
+		Create().
+		SetOrganizationID(orgID).
+		SetInviterID(inviterID).
+		SetEmail(email).
+		SetRole(invitation.Role(role)).
+		SetHash(c.auth.hashToken(token)).
+		Save(ctx)
+
+	return token, inv, err
+}
+
+// GetInvitations returns the pending, non-expired invitations of a given organization, newest first
+func (c *OrganizationClient) GetInvitations(ctx context.Context, orgID int) ([]*ent.Invitation, error) {
+	return c.orm.Invitation.
+		Query().
+		Where(invitation.HasOrganizationWith(organization.ID(orgID))).
+		Where(invitation.CreatedAtGTE(c.invitationExpiration())).
+		Order(ent.Desc(invitation.FieldCreatedAt), ent.Desc(invitation.FieldID)).
+		All(ctx)
+}
+
+// GetValidInvitation returns a valid, non-expired invitation of a given ID, with the organization loaded, if the
+// given token matches it
+func (c *OrganizationClient) GetValidInvitation(ctx context.Context, invitationID int, token string) (*ent.Invitation, error) {
+	inv, err := c.orm.Invitation.
+		Query().
+		Where(invitation.ID(invitationID)).
+		Where(invitation.CreatedAtGTE(c.invitationExpiration())).
+		WithOrganization().
+		Only(ctx)
+
+	switch err.(type) {
+	case *ent.NotFoundError:
+	case nil:
+		if c.auth.tokenHashMatches(token, inv.Hash) {
+			return inv, nil
+		}
+	default:
+		return nil, err
+	}
+
+	return nil, InvalidInvitationError{}
+}
+
+// AcceptInvitation adds a given user to the organization of a given invitation and deletes the invitation.
+// Invitations can only be accepted by the user with the email address that was invited. If the user is already a
+// member, their existing membership is returned.
+func (c *OrganizationClient) AcceptInvitation(ctx context.Context, inv *ent.Invitation, usr *ent.User) (*ent.Membership, error) {
+	if !strings.EqualFold(inv.Email, usr.Email) {
+		return nil, InvalidInvitationError{}
+	}
+
+	orgID, err := inv.QueryOrganization().OnlyID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.orm.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := tx.Membership.
+		Query().
+		Where(membership.HasOrganizationWith(organization.ID(orgID))).
+		Where(membership.HasUserWith(user.ID(usr.ID))).
+		Only(ctx)
+
+	switch err.(type) {
+	case nil:
+	case *ent.NotFoundError:
+		m, err = tx.Membership.
+			Create().
+			SetOrganizationID(orgID).
+			SetUserID(usr.ID).
+			SetRole(membership.Role(inv.Role)).
+			Save(ctx)
+	}
+
+	if err == nil {
+		err = tx.Invitation.DeleteOneID(inv.ID).Exec(ctx)
+	}
+
+	if err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			err = fmt.Errorf("%w: %v", err, rerr)
+		}
+		return nil, err
+	}
+
+	return m, tx.Commit()
+}
+
+// RevokeInvitation deletes an invitation of a given ID belonging to a given organization
+func (c *OrganizationClient) RevokeInvitation(ctx context.Context, orgID, invitationID int) error {
+	n, err := c.orm.Invitation.
+		Delete().
+		Where(invitation.ID(invitationID)).
+		Where(invitation.HasOrganizationWith(organization.ID(orgID))).
+		Exec(ctx)
+
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return InvalidInvitationError{}
+	default:
+		return nil
+	}
+}
+
+// SetCurrent stores a given organization as the one the requesting user is currently working in.
+// This does not check if the user is a member; that is done when the organization is loaded.
+func (c *OrganizationClient) SetCurrent(ctx echo.Context, orgID int) error {
+	sess, err := session.Get(ctx, organizationSessionName)
+	if err != nil {
+		return err
+	}
+	sess.Values[organizationSessionKeyID] = orgID
+	return sess.Save(ctx.Request(), ctx.Response())
+}
+
+// GetCurrent returns the membership of the organization the requesting user is currently working in, out of given
+// memberships of that user. If no organization was chosen, or the user is no longer a member of it, the first
+// membership is returned. Nil is returned if the user has no memberships.
+func (c *OrganizationClient) GetCurrent(ctx echo.Context, memberships []*ent.Membership) *ent.Membership {
+	if len(memberships) == 0 {
+		return nil
+	}
+
+	if sess, err := session.Get(ctx, organizationSessionName); err == nil {
+		if id, ok := sess.Values[organizationSessionKeyID].(int); ok {
+			for _, m := range memberships {
+				if m.Edges.Organization != nil && m.Edges.Organization.ID == id {
+					return m
+				}
+			}
+		}
+	}
+
+	return memberships[0]
+}
+
+// invitationExpiration returns the time before which invitations have expired
+func (c *OrganizationClient) invitationExpiration() time.Time {
+	return time.Now().Add(-c.config.App.Invitation.Expiration)
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/membership"
+	"github.com/mikestefanello/pagoda/pkg/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizationClient_Create(t *testing.T) {
+	u, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	org, err := c.Organizations.Create(context.Background(), u.ID, "Acme")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", org.Name)
+
+	memberships, err := c.Organizations.GetMemberships(context.Background(), u.ID)
+	require.NoError(t, err)
+	require.Len(t, memberships, 1)
+	assert.Equal(t, membership.RoleOwner, memberships[0].Role)
+	assert.Equal(t, org.ID, memberships[0].Edges.Organization.ID)
+}
+
+func TestOrganizationClient_Invitation(t *testing.T) {
+	ctx := context.Background()
+	owner, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+	invitee, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+	other, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	org, err := c.Organizations.Create(ctx, owner.ID, "Invitations")
+	require.NoError(t, err)
+
+	// Members cannot be invited again
+	_, _, err = c.Organizations.Invite(ctx, org.ID, owner.ID, owner.Email, membership.RoleMember)
+	assert.Equal(t, AlreadyMemberError{}, err)
+
+	token, inv, err := c.Organizations.Invite(ctx, org.ID, owner.ID, invitee.Email, membership.RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, c.Auth.hashToken(token), inv.Hash)
+
+	invitations, err := c.Organizations.GetInvitations(ctx, org.ID)
+	require.NoError(t, err)
+	require.Len(t, invitations, 1)
+
+	_, err = c.Organizations.GetValidInvitation(ctx, inv.ID, "bad")
+	assert.Equal(t, InvalidInvitationError{}, err)
+
+	inv, err = c.Organizations.GetValidInvitation(ctx, inv.ID, token)
+	require.NoError(t, err)
+	assert.Equal(t, org.ID, inv.Edges.Organization.ID)
+
+	// Only the invited user can accept
+	_, err = c.Organizations.AcceptInvitation(ctx, inv, other)
+	assert.Equal(t, InvalidInvitationError{}, err)
+
+	m, err := c.Organizations.AcceptInvitation(ctx, inv, invitee)
+	require.NoError(t, err)
+	assert.Equal(t, membership.RoleAdmin, m.Role)
+
+	// Invitations can only be used once
+	_, err = c.Organizations.GetValidInvitation(ctx, inv.ID, token)
+	assert.Equal(t, InvalidInvitationError{}, err)
+
+	// Expired invitations are not valid
+	inv, err = c.ORM.Invitation.
+		Create().
+		SetOrganization(org).
+		SetEmail(other.Email).
+		SetHash(c.Auth.hashToken("expired")).
+		SetCreatedAt(time.Now().Add(-c.Config.App.Invitation.Expiration - time.Minute)).
+		Save(ctx)
+	require.NoError(t, err)
+	_, err = c.Organizations.GetValidInvitation(ctx, inv.ID, "expired")
+	assert.Equal(t, InvalidInvitationError{}, err)
+
+	err = c.Organizations.RevokeInvitation(ctx, org.ID, inv.ID)
+	require.NoError(t, err)
+	err = c.Organizations.RevokeInvitation(ctx, org.ID, inv.ID)
+	assert.Equal(t, InvalidInvitationError{}, err)
+}
+
+func TestOrganizationClient_RemoveMember(t *testing.T) {
+	ctx := context.Background()
+	owner, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+	member, err := tests.CreateUser(c.ORM)
+	require.NoError(t, err)
+
+	org, err := c.Organizations.Create(ctx, owner.ID, "Remove")
+	require.NoError(t, err)
+	err = c.ORM.Membership.
+		Create().
+		SetOrganization(org).
+		SetUser(member).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	members, err := c.Organizations.GetMembers(ctx, org.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.Equal(t, owner.ID, members[0].Edges.User.ID)
+
+	// The only owner cannot be removed
+	err = c.Organizations.RemoveMember(ctx, org.ID, members[0])
+	assert.Equal(t, LastOwnerError{}, err)
+
+	m, err := c.Organizations.GetMember(ctx, org.ID, members[1].ID)
+	require.NoError(t, err)
+	require.NoError(t, c.Organizations.RemoveMember(ctx, org.ID, m))
+
+	members, err = c.Organizations.GetMembers(ctx, org.ID)
+	require.NoError(t, err)
+	assert.Len(t, members, 1)
+}
+
+func TestOrganizationClient_Current(t *testing.T) {
+	ctx, _ := tests.NewContext(c.Web, "/")
+	tests.InitSession(ctx)
+
+	assert.Nil(t, c.Organizations.GetCurrent(ctx, nil))
+
+	memberships := []*ent.Membership{
+		{ID: 1, Edges: ent.MembershipEdges{Organization: &ent.Organization{ID: 1}}},
+		{ID: 2, Edges: ent.MembershipEdges{Organization: &ent.Organization{ID: 2}}},
+	}
+
+	// The first organization is used until another is chosen
+	assert.Equal(t, memberships[0], c.Organizations.GetCurrent(ctx, memberships))
+
+	require.NoError(t, c.Organizations.SetCurrent(ctx, 2))
+	assert.Equal(t, memberships[1], c.Organizations.GetCurrent(ctx, memberships))
+
+	// Organizations the user is not a member of are ignored
+	require.NoError(t, c.Organizations.SetCurrent(ctx, 3))
+	assert.Equal(t, memberships[0], c.Organizations.GetCurrent(ctx, memberships))
+}
+
+func TestCanAssignRole(t *testing.T) {
+	assert.True(t, CanAssignRole(membership.RoleOwner, membership.RoleOwner))
+	assert.True(t, CanAssignRole(membership.RoleAdmin, membership.RoleAdmin))
+	assert.False(t, CanAssignRole(membership.RoleAdmin, membership.RoleOwner))
+	assert.False(t, CanAssignRole(membership.RoleMember, membership.RoleMember))
+}
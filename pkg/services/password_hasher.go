@@ -0,0 +1,232 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mikestefanello/pagoda/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// PasswordHashArgon2id is the name of the Argon2id password hashing algorithm
+	PasswordHashArgon2id = "argon2id"
+
+	// PasswordHashBcrypt is the name of the bcrypt password hashing algorithm
+	PasswordHashBcrypt = "bcrypt"
+)
+
+// ErrUnknownPasswordHash is returned when a password hash was not created by any of the supported algorithms
+var ErrUnknownPasswordHash = errors.New("unknown password hash format")
+
+type (
+	// PasswordHasher hashes and verifies passwords using a single algorithm.
+	// Hashes are self-describing, meaning they encode the algorithm and parameters used to create them,
+	// so they can still be verified after the configuration changes.
+	PasswordHasher interface {
+		// Hash returns an encoded hash of a given password
+		Hash(password string) (string, error)
+
+		// Verify determines if a given password matches a given encoded hash
+		Verify(password, hash string) (bool, error)
+
+		// Identifies determines if a given encoded hash was created by this algorithm
+		Identifies(hash string) bool
+
+		// NeedsRehash determines if a given encoded hash, created by this algorithm, was created with parameters that
+		// differ from the current ones
+		NeedsRehash(hash string) bool
+	}
+
+	// Argon2idHasher hashes passwords with Argon2id and encodes them in the PHC string format, ie:
+	// $argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>
+	Argon2idHasher struct {
+		// Memory is the amount of memory used, in KiB
+		Memory uint32
+
+		// Iterations is the amount of passes over the memory
+		Iterations uint32
+
+		// Parallelism is the amount of threads used
+		Parallelism uint8
+
+		// SaltLength is the length of the random salt, in bytes
+		SaltLength uint32
+
+		// KeyLength is the length of the generated key, in bytes
+		KeyLength uint32
+	}
+
+	// BcryptHasher hashes passwords with bcrypt, which produces hashes in the modular crypt format, ie:
+	// $2a$10$<salt and hash>
+	BcryptHasher struct {
+		// Cost is the bcrypt cost factor
+		Cost int
+	}
+)
+
+// NewPasswordHashers creates the password hashers for all supported algorithms, using the parameters stored in
+// configuration, and returns them along with the hasher of the configured algorithm, which should be used to
+// create all new hashes
+func NewPasswordHashers(cfg *config.Config) (PasswordHasher, []PasswordHasher, error) {
+	c := cfg.App.PasswordHashing
+
+	hashers := map[string]PasswordHasher{
+		PasswordHashArgon2id: &Argon2idHasher{
+			Memory:      c.Argon2id.Memory,
+			Iterations:  c.Argon2id.Iterations,
+			Parallelism: c.Argon2id.Parallelism,
+			SaltLength:  c.Argon2id.SaltLength,
+			KeyLength:   c.Argon2id.KeyLength,
+		},
+		PasswordHashBcrypt: &BcryptHasher{
+			Cost: c.Bcrypt.Cost,
+		},
+	}
+
+	preferred, ok := hashers[c.Algorithm]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported password hashing algorithm: %s", c.Algorithm)
+	}
+
+	all := make([]PasswordHasher, 0, len(hashers))
+	for _, h := range hashers {
+		all = append(all, h)
+	}
+
+	return preferred, all, nil
+}
+
+// Hash returns an encoded hash of a given password
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+
+	return fmt.Sprintf(
+		"$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		PasswordHashArgon2id,
+		argon2.Version,
+		h.Memory,
+		h.Iterations,
+		h.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify determines if a given password matches a given encoded hash
+func (h *Argon2idHasher) Verify(password, hash string) (bool, error) {
+	params, salt, key, err := h.decode(hash)
+	if err != nil {
+		return false, err
+	}
+
+	other := argon2.IDKey(
+		[]byte(password),
+		salt,
+		params.Iterations,
+		params.Memory,
+		params.Parallelism,
+		uint32(len(key)),
+	)
+
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+// Identifies determines if a given encoded hash was created by this algorithm
+func (h *Argon2idHasher) Identifies(hash string) bool {
+	return strings.HasPrefix(hash, "$"+PasswordHashArgon2id+"$")
+}
+
+// NeedsRehash determines if a given encoded hash was created with parameters that differ from the current ones
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, salt, key, err := h.decode(hash)
+	if err != nil {
+		return true
+	}
+
+	return params.Memory != h.Memory ||
+		params.Iterations != h.Iterations ||
+		params.Parallelism != h.Parallelism ||
+		uint32(len(salt)) != h.SaltLength ||
+		uint32(len(key)) != h.KeyLength
+}
+
+// decode parses an encoded Argon2id hash in to its parameters, salt and key
+func (h *Argon2idHasher) decode(hash string) (params Argon2idHasher, salt, key []byte, err error) {
+	// The leading $ results in an empty first part
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != PasswordHashArgon2id {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2id version: %d", version)
+	}
+
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id key: %w", err)
+	}
+
+	return params, salt, key, nil
+}
+
+// Hash returns an encoded hash of a given password
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Verify determines if a given password matches a given encoded hash
+func (h *BcryptHasher) Verify(password, hash string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// Identifies determines if a given encoded hash was created by this algorithm
+func (h *BcryptHasher) Identifies(hash string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(hash, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsRehash determines if a given encoded hash was created with a cost that differs from the current one
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.Cost
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestArgon2idHasher(t *testing.T) {
+	h := &Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	hash, err := h.Hash("abc123")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.True(t, h.Identifies(hash))
+	assert.False(t, h.NeedsRehash(hash))
+
+	ok, err := h.Verify("abc123", hash)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = h.Verify("abc1234", hash)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Hashes remain valid when the parameters change
+	h2 := &Argon2idHasher{Memory: 2048, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	assert.True(t, h2.NeedsRehash(hash))
+	ok, err = h2.Verify("abc123", hash)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = h.Verify("abc123", "$argon2id$v=19$bad")
+	assert.Error(t, err)
+}
+
+func TestBcryptHasher(t *testing.T) {
+	h := &BcryptHasher{Cost: bcrypt.MinCost}
+
+	hash, err := h.Hash("abc123")
+	require.NoError(t, err)
+	assert.True(t, h.Identifies(hash))
+	assert.False(t, h.NeedsRehash(hash))
+
+	ok, err := h.Verify("abc123", hash)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = h.Verify("abc1234", hash)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	h2 := &BcryptHasher{Cost: bcrypt.MinCost + 1}
+	assert.True(t, h2.NeedsRehash(hash))
+	assert.False(t, h2.Identifies("$argon2id$v=19$m=1024,t=1,p=1$salt$key"))
+}
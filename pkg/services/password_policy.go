@@ -0,0 +1,284 @@
+package services
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mikestefanello/pagoda/config"
+)
+
+const (
+	// PasswordPolicyTag is the validation tag which applies the complete password policy to a field
+	PasswordPolicyTag = "password"
+
+	// PasswordMinLengthTag is the validation tag which checks the minimum amount of characters
+	PasswordMinLengthTag = "pwmin"
+
+	// PasswordMaxLengthTag is the validation tag which checks the maximum amount of bytes
+	PasswordMaxLengthTag = "pwmax"
+
+	// PasswordPersonalTag is the validation tag which checks that the password does not contain the values of the
+	// Name or Email fields of the same struct
+	PasswordPersonalTag = "pwpersonal"
+
+	// PasswordStrengthTag is the validation tag which checks the minimum estimated strength
+	PasswordStrengthTag = "pwstrength"
+
+	// PasswordBreachedTag is the validation tag which checks that the password is not on the breached password list
+	PasswordBreachedTag = "pwbreached"
+)
+
+type (
+	// PasswordPolicy enforces the requirements for new passwords
+	PasswordPolicy struct {
+		// config stores application configuration
+		config *config.Config
+
+		// breached stores the list of breached passwords, if one is configured
+		breached *breachedPasswords
+	}
+
+	// breachedPasswords stores SHA-1 hashes of breached passwords indexed by the first five characters of the hash,
+	// mirroring the k-anonymity model used by the Pwned Passwords range API, so this can easily be swapped for a
+	// remote lookup without having to change the callers
+	breachedPasswords struct {
+		ranges map[string]map[string]struct{}
+	}
+)
+
+// NewPasswordPolicy creates a new PasswordPolicy and loads the breached password list, if configured
+func NewPasswordPolicy(cfg *config.Config) (*PasswordPolicy, error) {
+	p := &PasswordPolicy{
+		config: cfg,
+	}
+
+	if path := cfg.App.PasswordPolicy.BreachedList; path != "" {
+		list, err := loadBreachedPasswords(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load breached password list: %w", err)
+		}
+		p.breached = list
+	}
+
+	return p, nil
+}
+
+// Register registers the password validation tags with a given validator.
+// Use the PasswordPolicyTag on a field to apply all of the rules.
+func (p *PasswordPolicy) Register(v *validator.Validate) error {
+	cfg := p.config.App.PasswordPolicy
+
+	validations := map[string]validator.Func{
+		PasswordMinLengthTag: p.validateMinLength,
+		PasswordMaxLengthTag: p.validateMaxLength,
+		PasswordPersonalTag:  p.validatePersonal,
+		PasswordStrengthTag:  p.validateStrength,
+		PasswordBreachedTag:  p.validateBreached,
+	}
+
+	for tag, fn := range validations {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+
+	v.RegisterAlias(PasswordPolicyTag, fmt.Sprintf(
+		"%s=%d,%s=%d,%s,%s=%d,%s",
+		PasswordMinLengthTag, cfg.MinLength,
+		PasswordMaxLengthTag, cfg.MaxLength,
+		PasswordPersonalTag,
+		PasswordStrengthTag, cfg.MinStrength,
+		PasswordBreachedTag,
+	))
+
+	return nil
+}
+
+// IsBreached determines if a given password is on the breached password list
+func (p *PasswordPolicy) IsBreached(password string) bool {
+	if p.breached == nil {
+		return false
+	}
+	return p.breached.contains(password)
+}
+
+// Strength estimates the strength of a given password on a scale from 0 (very weak) to 4 (very strong).
+// This is a rough estimate of the entropy based on the character sets used and the length of the password,
+// where repeated and sequential characters (ie, "aaa" or "abc") count for less.
+func (p *PasswordPolicy) Strength(password string) int {
+	var lower, upper, digit, symbol bool
+	var length float64
+	var prev rune
+
+	for i, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+
+		if i > 0 && (r == prev || r == prev+1 || r == prev-1) {
+			length += 0.5
+		} else {
+			length++
+		}
+		prev = r
+	}
+
+	var pool float64
+	if lower {
+		pool += 26
+	}
+	if upper {
+		pool += 26
+	}
+	if digit {
+		pool += 10
+	}
+	if symbol {
+		pool += 33
+	}
+
+	if pool == 0 {
+		return 0
+	}
+
+	switch bits := length * math.Log2(pool); {
+	case bits < 25:
+		return 0
+	case bits < 35:
+		return 1
+	case bits < 50:
+		return 2
+	case bits < 70:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func (p *PasswordPolicy) validateMinLength(fl validator.FieldLevel) bool {
+	return len([]rune(fl.Field().String())) >= paramInt(fl)
+}
+
+func (p *PasswordPolicy) validateMaxLength(fl validator.FieldLevel) bool {
+	return len(fl.Field().String()) <= paramInt(fl)
+}
+
+func (p *PasswordPolicy) validatePersonal(fl validator.FieldLevel) bool {
+	password := strings.ToLower(fl.Field().String())
+
+	parent := fl.Parent()
+	if parent.Kind() == reflect.Ptr {
+		parent = parent.Elem()
+	}
+	if parent.Kind() != reflect.Struct {
+		return true
+	}
+
+	var personal []string
+
+	if f := parent.FieldByName("Name"); f.IsValid() && f.Kind() == reflect.String {
+		name := strings.ToLower(f.String())
+		personal = append(personal, strings.ReplaceAll(name, " ", ""))
+		personal = append(personal, strings.Fields(name)...)
+	}
+
+	if f := parent.FieldByName("Email"); f.IsValid() && f.Kind() == reflect.String {
+		email := strings.ToLower(f.String())
+		personal = append(personal, email)
+		if local, _, found := strings.Cut(email, "@"); found {
+			personal = append(personal, local)
+		}
+	}
+
+	for _, v := range personal {
+		// Ignore very short values, such as initials, which would reject too many passwords
+		if len(v) >= 3 && strings.Contains(password, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *PasswordPolicy) validateStrength(fl validator.FieldLevel) bool {
+	return p.Strength(fl.Field().String()) >= paramInt(fl)
+}
+
+func (p *PasswordPolicy) validateBreached(fl validator.FieldLevel) bool {
+	return !p.IsBreached(fl.Field().String())
+}
+
+// paramInt returns the parameter of a validation tag as an integer
+func paramInt(fl validator.FieldLevel) int {
+	v, _ := strconv.Atoi(fl.Param())
+	return v
+}
+
+// loadBreachedPasswords loads a list of breached passwords from a file containing one uppercase hex SHA-1 hash per
+// line, optionally followed by a colon and the amount of times it was seen. Empty lines and lines beginning with #
+// are ignored.
+func loadBreachedPasswords(path string) (*breachedPasswords, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	list := &breachedPasswords{
+		ranges: make(map[string]map[string]struct{}),
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(line, ":")
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("invalid hash: %s", hash)
+		}
+		list.add(strings.ToUpper(hash))
+	}
+
+	return list, scanner.Err()
+}
+
+// add adds a SHA-1 hash to the list
+func (b *breachedPasswords) add(hash string) {
+	prefix, suffix := hash[:5], hash[5:]
+	if _, ok := b.ranges[prefix]; !ok {
+		b.ranges[prefix] = make(map[string]struct{})
+	}
+	b.ranges[prefix][suffix] = struct{}{}
+}
+
+// contains determines if a given password is in the list
+func (b *breachedPasswords) contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	if suffixes, ok := b.ranges[hash[:5]]; ok {
+		_, found := suffixes[hash[5:]]
+		return found
+	}
+
+	return false
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	type example struct {
+		Name     string
+		Email    string
+		Password string `validate:"password"`
+	}
+
+	tests := map[string]struct {
+		password string
+		tag      string
+	}{
+		"valid":          {password: "Gentle-Harbor-Lamp-42", tag: ""},
+		"too short":      {password: "xQ9!", tag: PasswordMinLengthTag},
+		"too long":       {password: "Gentle-Harbor-Lamp-42-Gentle-Harbor-Lamp-42-Gentle-Harbor-Lamp-42-Gentle-Harbor", tag: PasswordMaxLengthTag},
+		"contains name":  {password: "Xq9!margaret#Zt", tag: PasswordPersonalTag},
+		"contains email": {password: "Xq9!mhamilton#Zt", tag: PasswordPersonalTag},
+		"weak":           {password: "abcdefgh", tag: PasswordStrengthTag},
+		"breached":       {password: "password123", tag: PasswordBreachedTag},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := c.Validator.Validate(example{
+				Name:     "Margaret Hamilton",
+				Email:    "mhamilton@localhost.localhost",
+				Password: test.password,
+			})
+
+			if test.tag == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			ves, ok := err.(validator.ValidationErrors)
+			require.True(t, ok)
+			require.Len(t, ves, 1)
+			assert.Equal(t, PasswordPolicyTag, ves[0].Tag())
+			assert.Equal(t, test.tag, ves[0].ActualTag())
+		})
+	}
+}
+
+func TestPasswordPolicy_IsBreached(t *testing.T) {
+	policy, err := NewPasswordPolicy(c.Config)
+	require.NoError(t, err)
+	assert.True(t, policy.IsBreached("password"))
+	assert.True(t, policy.IsBreached("qwerty123"))
+	assert.False(t, policy.IsBreached("Gentle-Harbor-Lamp-42"))
+}
+
+func TestPasswordPolicy_Strength(t *testing.T) {
+	policy, err := NewPasswordPolicy(c.Config)
+	require.NoError(t, err)
+	assert.Equal(t, 0, policy.Strength(""))
+	assert.Equal(t, 0, policy.Strength("abcdefgh"))
+	assert.Equal(t, 1, policy.Strength("aaaaaaaaaaaa"))
+	assert.Equal(t, 2, policy.Strength("hunter22"))
+	assert.Equal(t, 3, policy.Strength("correcthorse"))
+	assert.Equal(t, 4, policy.Strength("Tr0ub4dor&3x"))
+}
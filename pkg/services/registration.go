@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/ent/invitecode"
+)
+
+// inviteCodeLength is the length of generated invite codes
+const inviteCodeLength = 16
+
+// InvalidInviteCodeError is an error returned when an invalid, expired or used up invite code is provided
+type InvalidInviteCodeError struct{}
+
+// Error implements the error interface.
+func (e InvalidInviteCodeError) Error() string {
+	return "invalid invite code"
+}
+
+// EmailDomainNotAllowedError is an error returned when an email address is not of one of the allowed domains
+type EmailDomainNotAllowedError struct{}
+
+// Error implements the error interface.
+func (e EmailDomainNotAllowedError) Error() string {
+	return "email domain not allowed"
+}
+
+type (
+	// RegistrationClient handles who is allowed to register, based on the configured registration mode and allowed
+	// email domains, along with the invite codes required to register when registration is invite-only
+	RegistrationClient struct {
+		config *config.Config
+		orm    *ent.Client
+		auth   *AuthClient
+	}
+
+	// Registration contains the data required to register a user
+	Registration struct {
+		Name     string
+		Email    string
+		Password string
+
+		// InviteCode is the invite code provided by the user, which is only required when registration is
+		// invite-only
+		InviteCode string
+	}
+
+	// InviteCodeParams contains the parameters for generating an invite code
+	InviteCodeParams struct {
+		// CreatorID is the ID of the user generating the code, if any
+		CreatorID *int
+
+		// MaxUses is the amount of times the code can be used, with zero meaning unlimited
+		MaxUses int
+
+		// ExpiresAt is when the code expires, if ever
+		ExpiresAt *time.Time
+
+		// Note describes who or what the code is for
+		Note string
+	}
+)
+
+// NewRegistrationClient creates a new RegistrationClient
+func NewRegistrationClient(cfg *config.Config, orm *ent.Client, auth *AuthClient) (*RegistrationClient, error) {
+	switch cfg.App.Registration.Mode {
+	case config.RegistrationModeOpen, config.RegistrationModeInvite, config.RegistrationModeClosed:
+	default:
+		return nil, fmt.Errorf("invalid registration mode: %q", cfg.App.Registration.Mode)
+	}
+
+	return &RegistrationClient{
+		config: cfg,
+		orm:    orm,
+		auth:   auth,
+	}, nil
+}
+
+// Mode returns the configured registration mode
+func (c *RegistrationClient) Mode() config.RegistrationMode {
+	return c.config.App.Registration.Mode
+}
+
+// IsEnabled determines if users are allowed to register
+func (c *RegistrationClient) IsEnabled() bool {
+	return c.Mode() != config.RegistrationModeClosed
+}
+
+// RequiresInviteCode determines if an invite code is required to register
+func (c *RegistrationClient) RequiresInviteCode() bool {
+	return c.Mode() == config.RegistrationModeInvite
+}
+
+// IsEmailAllowed determines if a given email address is of one of the allowed domains, which is always the case
+// if no domains are configured
+func (c *RegistrationClient) IsEmailAllowed(email string) bool {
+	domains := c.config.App.Registration.AllowedDomains
+	if len(domains) == 0 {
+		return true
+	}
+
+	i := strings.LastIndex(email, "@")
+	if i == -1 {
+		return false
+	}
+
+	for _, domain := range domains {
+		if strings.EqualFold(email[i+1:], domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Register creates a user with given registration data, after checking that registration is enabled, the email
+// address is allowed and, if required, that the invite code is valid, in which case the code is redeemed.
+// The password must already be hashed.
+func (c *RegistrationClient) Register(ctx context.Context, reg Registration) (*ent.User, error) {
+	switch {
+	case !c.IsEnabled():
+		return nil, fmt.Errorf("registration is closed")
+	case !c.IsEmailAllowed(reg.Email):
+		return nil, EmailDomainNotAllowedError{}
+	}
+
+	tx, err := c.orm.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var u *ent.User
+
+	if c.RequiresInviteCode() {
+		err = c.redeemInviteCode(ctx, tx, reg.InviteCode)
+	}
+
+	if err == nil {
+		u, err = tx.User.
+			Create().
+			SetName(reg.Name).
+			SetEmail(reg.Email).
+			SetPassword(reg.Password).
+			Save(ctx)
+	}
+
+	if err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			err = fmt.Errorf("%w: %v", err, rerr)
+		}
+		return nil, err
+	}
+
+	return u, tx.Commit()
+}
+
+// CreateInviteCode generates an invite code with given parameters.
+// Just like password tokens, only a hash of the returned code is stored, so it can only be shown once.
+func (c *RegistrationClient) CreateInviteCode(ctx context.Context, params InviteCodeParams) (string, *ent.InviteCode, error) {
+	code, err := c.auth.RandomToken(inviteCodeLength)
+	if err != nil {
+		return "", nil, err
+	}
+
+	ic, err := c.orm.InviteCode.
+		Create().
+		SetHash(c.auth.hashToken(code)).
+		SetNote(params.Note).
+		SetMaxUses(params.MaxUses).
+		SetNillableExpiresAt(params.ExpiresAt).
+		SetNillableCreatorID(params.CreatorID).
+		Save(ctx)
+
+	return code, ic, err
+}
+
+// GetInviteCodes returns all invite codes, newest first, with the creator loaded
+func (c *RegistrationClient) GetInviteCodes(ctx context.Context) ([]*ent.InviteCode, error) {
+	return c.orm.InviteCode.
+		Query().
+		WithCreator().
+		Order(ent.Desc(invitecode.FieldCreatedAt), ent.Desc(invitecode.FieldID)).
+		All(ctx)
+}
+
+// IsInviteCodeUsable determines if a given invite code has not expired and has uses remaining
+func IsInviteCodeUsable(ic *ent.InviteCode) bool {
+	if ic.ExpiresAt != nil && !ic.ExpiresAt.After(time.Now()) {
+		return false
+	}
+	return ic.MaxUses == 0 || ic.Uses < ic.MaxUses
+}
+
+// redeemInviteCode uses a given invite code within a given transaction, if it is valid.
+// The amount of uses is incremented by a single conditional update so a code cannot be used more times than
+// allowed by concurrent requests.
+func (c *RegistrationClient) redeemInviteCode(ctx context.Context, tx *ent.Tx, code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return InvalidInviteCodeError{}
+	}
+
+	n, err := tx.InviteCode.
+		Update().
+		Where(invitecode.HashIn(c.auth.tokenHashes(code)...)).
+		Where(invitecode.Or(
+			invitecode.ExpiresAtIsNil(),
+			invitecode.ExpiresAtGT(time.Now()),
+		)).
+		Where(invitecode.Or(
+			invitecode.MaxUses(0),
+			func(s *sql.Selector) {
+				s.Where(sql.ColumnsLT(s.C(invitecode.FieldUses), s.C(invitecode.FieldMaxUses)))
+			},
+		)).
+		AddUses(1).
+		Save(ctx)
+
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return InvalidInviteCodeError{}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mikestefanello/pagoda/config"
+	"github.com/mikestefanello/pagoda/ent"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setRegistration changes the registration config for the duration of a test
+func setRegistration(t *testing.T, mode config.RegistrationMode, domains ...string) {
+	original := c.Config.App.Registration
+	t.Cleanup(func() {
+		c.Config.App.Registration = original
+	})
+	c.Config.App.Registration.Mode = mode
+	c.Config.App.Registration.AllowedDomains = domains
+}
+
+func TestNewRegistrationClient(t *testing.T) {
+	cfg := *c.Config
+	cfg.App.Registration.Mode = "invalid"
+	_, err := NewRegistrationClient(&cfg, c.ORM, c.Auth)
+	assert.Error(t, err)
+}
+
+func TestRegistrationClient_Modes(t *testing.T) {
+	setRegistration(t, config.RegistrationModeOpen)
+	assert.True(t, c.Registration.IsEnabled())
+	assert.False(t, c.Registration.RequiresInviteCode())
+
+	setRegistration(t, config.RegistrationModeInvite)
+	assert.True(t, c.Registration.IsEnabled())
+	assert.True(t, c.Registration.RequiresInviteCode())
+
+	setRegistration(t, config.RegistrationModeClosed)
+	assert.False(t, c.Registration.IsEnabled())
+	_, err := c.Registration.Register(context.Background(), Registration{
+		Name:     "Closed",
+		Email:    "closed@example.com",
+		Password: "hash",
+	})
+	assert.Error(t, err)
+}
+
+func TestRegistrationClient_IsEmailAllowed(t *testing.T) {
+	setRegistration(t, config.RegistrationModeOpen)
+	assert.True(t, c.Registration.IsEmailAllowed("a@anything.com"))
+
+	setRegistration(t, config.RegistrationModeOpen, "example.com", "example.org")
+	assert.True(t, c.Registration.IsEmailAllowed("a@example.com"))
+	assert.True(t, c.Registration.IsEmailAllowed("a@EXAMPLE.org"))
+	assert.False(t, c.Registration.IsEmailAllowed("a@sub.example.com"))
+	assert.False(t, c.Registration.IsEmailAllowed("a@example.com.evil.com"))
+	assert.False(t, c.Registration.IsEmailAllowed("example.com"))
+
+	_, err := c.Registration.Register(context.Background(), Registration{
+		Name:     "Domain",
+		Email:    "domain@other.com",
+		Password: "hash",
+	})
+	assert.Equal(t, EmailDomainNotAllowedError{}, err)
+
+	u, err := c.Registration.Register(context.Background(), Registration{
+		Name:     "Domain",
+		Email:    "domain@example.com",
+		Password: "hash",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "domain@example.com", u.Email)
+}
+
+func TestRegistrationClient_InviteCodes(t *testing.T) {
+	ctx := context.Background()
+	setRegistration(t, config.RegistrationModeInvite)
+
+	register := func(email, code string) error {
+		_, err := c.Registration.Register(ctx, Registration{
+			Name:       "Invited",
+			Email:      email,
+			Password:   "hash",
+			InviteCode: code,
+		})
+		return err
+	}
+
+	code, ic, err := c.Registration.CreateInviteCode(ctx, InviteCodeParams{
+		CreatorID: &usr.ID,
+		MaxUses:   2,
+		Note:      "test",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, c.Auth.hashToken(code), ic.Hash)
+	assert.True(t, IsInviteCodeUsable(ic))
+
+	// A code is required
+	assert.Equal(t, InvalidInviteCodeError{}, register("invite1@example.com", ""))
+	assert.Equal(t, InvalidInviteCodeError{}, register("invite1@example.com", "bad"))
+
+	require.NoError(t, register("invite1@example.com", code))
+	require.NoError(t, register("invite2@example.com", code))
+
+	// The code is used up
+	assert.Equal(t, InvalidInviteCodeError{}, register("invite3@example.com", code))
+	ic, err = c.ORM.InviteCode.Get(ctx, ic.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, ic.Uses)
+	assert.False(t, IsInviteCodeUsable(ic))
+
+	// Failing to create the user does not use the code
+	code, ic, err = c.Registration.CreateInviteCode(ctx, InviteCodeParams{MaxUses: 1})
+	require.NoError(t, err)
+	assert.True(t, ent.IsConstraintError(register("invite1@example.com", code)))
+	ic, err = c.ORM.InviteCode.Get(ctx, ic.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, ic.Uses)
+
+	// Unlimited codes
+	code, _, err = c.Registration.CreateInviteCode(ctx, InviteCodeParams{MaxUses: 0})
+	require.NoError(t, err)
+	require.NoError(t, register("invite4@example.com", code))
+	require.NoError(t, register("invite5@example.com", code))
+
+	// Expired codes
+	expiresAt := time.Now().Add(-time.Minute)
+	code, ic, err = c.Registration.CreateInviteCode(ctx, InviteCodeParams{MaxUses: 1, ExpiresAt: &expiresAt})
+	require.NoError(t, err)
+	assert.False(t, IsInviteCodeUsable(ic))
+	assert.Equal(t, InvalidInviteCodeError{}, register("invite6@example.com", code))
+
+	codes, err := c.Registration.GetInviteCodes(ctx)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(codes), 4)
+	assert.Equal(t, ic.ID, codes[0].ID)
+}
@@ -111,6 +111,11 @@ func (t *TemplateRenderer) RenderPage(ctx echo.Context, page page.Page) error {
 		page.AppName = t.config.App.Name
 	}
 
+	// Use the registration mode in configuration if a value was not set
+	if page.RegistrationMode == "" {
+		page.RegistrationMode = t.config.App.Registration.Mode
+	}
+
 	// Check if this is an HTMX non-boosted request which indicates that only partial
 	// content should be rendered
 	if page.HTMX.Request.Enabled && !page.HTMX.Request.Boosted {
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mikestefanello/pagoda/config"
+)
+
+// throttleCacheGroup stores the cache group for throttle attempts
+const throttleCacheGroup = "throttle"
+
+// ThrottleClient counts attempts of a given action within a sliding window in order to slow down and limit
+// brute-force attacks, such as password guessing against the login form.
+// Attempts are stored in the cache, so if you run multiple instances of your application you will want to
+// use a shared cache store.
+type ThrottleClient struct {
+	// config stores application configuration
+	config *config.Config
+
+	// cache stores the cache client
+	cache *CacheClient
+
+	// mu synchronizes reading and writing attempts for a key
+	mu sync.Mutex
+}
+
+// NewThrottleClient creates a new ThrottleClient
+func NewThrottleClient(cfg *config.Config, cache *CacheClient) *ThrottleClient {
+	return &ThrottleClient{
+		config: cfg,
+		cache:  cache,
+	}
+}
+
+// Hit records an attempt for a given key and returns all attempts made within the window, including this one
+func (t *ThrottleClient) Hit(ctx context.Context, key string, window time.Duration) ([]time.Time, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	attempts, err := t.attempts(ctx, key, window)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts = append(attempts, time.Now())
+
+	err = t.cache.
+		Set().
+		Group(throttleCacheGroup).
+		Key(key).
+		Data(attempts).
+		Expiration(window).
+		Save(ctx)
+
+	return attempts, err
+}
+
+// Attempts returns all attempts made for a given key within the window
+func (t *ThrottleClient) Attempts(ctx context.Context, key string, window time.Duration) ([]time.Time, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.attempts(ctx, key, window)
+}
+
+// Reset removes all attempts for a given key
+func (t *ThrottleClient) Reset(ctx context.Context, key string) error {
+	return t.cache.
+		Flush().
+		Group(throttleCacheGroup).
+		Key(key).
+		Execute(ctx)
+}
+
+// attempts loads the attempts for a given key from the cache and discards any that fall outside the window
+func (t *ThrottleClient) attempts(ctx context.Context, key string, window time.Duration) ([]time.Time, error) {
+	v, err := t.cache.
+		Get().
+		Group(throttleCacheGroup).
+		Key(key).
+		Fetch(ctx)
+
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrCacheMiss):
+		return []time.Time{}, nil
+	default:
+		return nil, err
+	}
+
+	start := time.Now().Add(-window)
+	attempts := make([]time.Time, 0)
+	for _, at := range v.([]time.Time) {
+		if at.After(start) {
+			attempts = append(attempts, at)
+		}
+	}
+
+	return attempts, nil
+}
+
+// LoginRetryAfter returns how long the requester must wait before attempting to log in to a given account from a
+// given IP address. Zero is returned if the attempt is allowed.
+// After the amount of free attempts have failed for an account, each additional failure doubles the delay
+// required before the next attempt. Once the maximum amount of failures from an IP address has been reached,
+// no more attempts are allowed from it until the oldest failure leaves the window.
+func (t *ThrottleClient) LoginRetryAfter(ctx context.Context, email, ip string) (time.Duration, error) {
+	cfg := t.config.App.LoginThrottle
+
+	ipAttempts, err := t.Attempts(ctx, t.loginIPKey(ip), cfg.Window)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(ipAttempts) >= cfg.MaxIPAttempts {
+		return time.Until(ipAttempts[0].Add(cfg.Window)), nil
+	}
+
+	attempts, err := t.Attempts(ctx, t.loginAccountKey(email), cfg.Window)
+	if err != nil || len(attempts) == 0 {
+		return 0, err
+	}
+
+	delay := t.loginDelay(len(attempts))
+	if wait := time.Until(attempts[len(attempts)-1].Add(delay)); wait > 0 {
+		return wait, nil
+	}
+
+	return 0, nil
+}
+
+// LoginFailed records a failed login attempt for a given account and IP address and returns the amount of failed
+// attempts made for the account within the window
+func (t *ThrottleClient) LoginFailed(ctx context.Context, email, ip string) (int, error) {
+	window := t.config.App.LoginThrottle.Window
+
+	if _, err := t.Hit(ctx, t.loginIPKey(ip), window); err != nil {
+		return 0, err
+	}
+
+	attempts, err := t.Hit(ctx, t.loginAccountKey(email), window)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(attempts), nil
+}
+
+// ResetLogin clears the failed login attempts for a given account.
+// This should be called after a successful login or once the account has been locked.
+func (t *ThrottleClient) ResetLogin(ctx context.Context, email string) error {
+	return t.Reset(ctx, t.loginAccountKey(email))
+}
+
+// ShouldLockout determines if an account should be locked based on the amount of failed login attempts
+func (t *ThrottleClient) ShouldLockout(attempts int) bool {
+	return attempts >= t.config.App.LoginThrottle.LockoutAttempts
+}
+
+// ForgotPasswordAllowed records a forgot password request for a given email and IP address and returns if the
+// request is allowed for each of them.
+// This protects users from having their inbox flooded with password reset emails.
+func (t *ThrottleClient) ForgotPasswordAllowed(ctx context.Context, email, ip string) (emailAllowed, ipAllowed bool, err error) {
+	return t.emailAllowed(ctx, "forgot", t.config.App.ForgotPasswordThrottle, email, ip)
+}
+
+// LoginLinkAllowed records a login link request for a given email and IP address and returns if the request is
+// allowed for each of them.
+// This protects users from having their inbox flooded with login links.
+func (t *ThrottleClient) LoginLinkAllowed(ctx context.Context, email, ip string) (emailAllowed, ipAllowed bool, err error) {
+	return t.emailAllowed(ctx, "login_link", t.config.App.LoginLinkThrottle, email, ip)
+}
+
+// VerificationResendAllowed records a request to resend the email verification link to a given user and returns
+// if the request is allowed
+func (t *ThrottleClient) VerificationResendAllowed(ctx context.Context, userID int) (bool, error) {
+	cfg := t.config.App.VerificationResendThrottle
+
+	attempts, err := t.Hit(ctx, fmt.Sprintf("verification_resend:user:%d", userID), cfg.Window)
+	if err != nil {
+		return false, err
+	}
+
+	return len(attempts) <= cfg.MaxAttempts, nil
+}
+
+// emailAllowed records a request of a given action, which sends an email to a given address, for the email and a
+// given IP address and returns if the request is allowed for each of them based on given limits
+func (t *ThrottleClient) emailAllowed(
+	ctx context.Context,
+	action string,
+	cfg config.EmailThrottleConfig,
+	email, ip string,
+) (emailAllowed, ipAllowed bool, err error) {
+	ipAttempts, err := t.Hit(ctx, fmt.Sprintf("%s:ip:%s", action, ip), cfg.Window)
+	if err != nil {
+		return false, false, err
+	}
+
+	emailAttempts, err := t.Hit(ctx, fmt.Sprintf("%s:email:%s", action, strings.ToLower(email)), cfg.Window)
+	if err != nil {
+		return false, false, err
+	}
+
+	return len(emailAttempts) <= cfg.MaxEmailAttempts, len(ipAttempts) <= cfg.MaxIPAttempts, nil
+}
+
+// loginDelay calculates the delay required after the most recent of a given amount of failed login attempts
+func (t *ThrottleClient) loginDelay(attempts int) time.Duration {
+	cfg := t.config.App.LoginThrottle
+
+	if attempts < cfg.FreeAttempts {
+		return 0
+	}
+
+	delay := cfg.BaseDelay
+	for i := cfg.FreeAttempts; i < attempts; i++ {
+		delay *= 2
+		if delay >= cfg.MaxDelay {
+			return cfg.MaxDelay
+		}
+	}
+
+	return delay
+}
+
+// loginAccountKey returns the throttle key for login attempts of a given account
+func (t *ThrottleClient) loginAccountKey(email string) string {
+	return fmt.Sprintf("login:email:%s", strings.ToLower(email))
+}
+
+// loginIPKey returns the throttle key for login attempts from a given IP address
+func (t *ThrottleClient) loginIPKey(ip string) string {
+	return fmt.Sprintf("login:ip:%s", ip)
+}
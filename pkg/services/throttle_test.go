@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottleClient_Hit(t *testing.T) {
+	key := "test-hit"
+	window := 100 * time.Millisecond
+
+	for i := 1; i <= 3; i++ {
+		attempts, err := c.Throttle.Hit(context.Background(), key, window)
+		require.NoError(t, err)
+		assert.Len(t, attempts, i)
+	}
+
+	attempts, err := c.Throttle.Attempts(context.Background(), key, window)
+	require.NoError(t, err)
+	assert.Len(t, attempts, 3)
+
+	// Attempts outside the window should be discarded
+	time.Sleep(window)
+	attempts, err = c.Throttle.Hit(context.Background(), key, window)
+	require.NoError(t, err)
+	assert.Len(t, attempts, 1)
+
+	err = c.Throttle.Reset(context.Background(), key)
+	require.NoError(t, err)
+	attempts, err = c.Throttle.Attempts(context.Background(), key, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, attempts)
+}
+
+func TestThrottleClient_Login(t *testing.T) {
+	cfg := &c.Config.App.LoginThrottle
+	original := *cfg
+	defer func() {
+		*cfg = original
+	}()
+	cfg.Window = time.Minute
+	cfg.FreeAttempts = 2
+	cfg.BaseDelay = time.Second
+	cfg.MaxDelay = 3 * time.Second
+	cfg.MaxIPAttempts = 6
+	cfg.LockoutAttempts = 5
+
+	ctx := context.Background()
+	email, ip := "throttle@localhost.localhost", "10.0.0.1"
+
+	assertRetryAfter := func(min, max time.Duration) {
+		retry, err := c.Throttle.LoginRetryAfter(ctx, email, ip)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, retry, min)
+		assert.LessOrEqual(t, retry, max)
+	}
+
+	// Free attempts have no delay
+	for i := 1; i < cfg.FreeAttempts; i++ {
+		attempts, err := c.Throttle.LoginFailed(ctx, email, ip)
+		require.NoError(t, err)
+		assert.Equal(t, i, attempts)
+		assertRetryAfter(0, 0)
+	}
+
+	// Delays increase until the max
+	_, err := c.Throttle.LoginFailed(ctx, email, ip)
+	require.NoError(t, err)
+	assertRetryAfter(time.Millisecond, time.Second)
+	_, err = c.Throttle.LoginFailed(ctx, email, ip)
+	require.NoError(t, err)
+	assertRetryAfter(time.Second, 2*time.Second)
+	_, err = c.Throttle.LoginFailed(ctx, email, ip)
+	require.NoError(t, err)
+	assertRetryAfter(2*time.Second, 3*time.Second)
+
+	// Lockout
+	assert.False(t, c.Throttle.ShouldLockout(4))
+	assert.True(t, c.Throttle.ShouldLockout(5))
+
+	// Resetting the account clears the delay
+	require.NoError(t, c.Throttle.ResetLogin(ctx, email))
+	assertRetryAfter(0, 0)
+
+	// Exceed the IP limit with a different account
+	for i := 0; i < 2; i++ {
+		_, err = c.Throttle.LoginFailed(ctx, "other@localhost.localhost", ip)
+		require.NoError(t, err)
+	}
+	assertRetryAfter(time.Second, time.Minute)
+}
+
+func TestThrottleClient_ForgotPasswordAllowed(t *testing.T) {
+	cfg := &c.Config.App.ForgotPasswordThrottle
+	original := *cfg
+	defer func() {
+		*cfg = original
+	}()
+	cfg.Window = time.Minute
+	cfg.MaxEmailAttempts = 1
+	cfg.MaxIPAttempts = 2
+
+	ctx := context.Background()
+	ip := "10.0.0.2"
+
+	emailAllowed, ipAllowed, err := c.Throttle.ForgotPasswordAllowed(ctx, "forgot1@localhost.localhost", ip)
+	require.NoError(t, err)
+	assert.True(t, emailAllowed)
+	assert.True(t, ipAllowed)
+
+	emailAllowed, ipAllowed, err = c.Throttle.ForgotPasswordAllowed(ctx, "FORGOT1@localhost.localhost", ip)
+	require.NoError(t, err)
+	assert.False(t, emailAllowed)
+	assert.True(t, ipAllowed)
+
+	emailAllowed, ipAllowed, err = c.Throttle.ForgotPasswordAllowed(ctx, "forgot2@localhost.localhost", ip)
+	require.NoError(t, err)
+	assert.True(t, emailAllowed)
+	assert.False(t, ipAllowed)
+}
+
+func TestThrottleClient_LoginLinkAllowed(t *testing.T) {
+	cfg := &c.Config.App.LoginLinkThrottle
+	original := *cfg
+	defer func() {
+		*cfg = original
+	}()
+	cfg.Window = time.Minute
+	cfg.MaxEmailAttempts = 1
+	cfg.MaxIPAttempts = 2
+
+	ctx := context.Background()
+	ip := "10.0.0.3"
+
+	emailAllowed, ipAllowed, err := c.Throttle.LoginLinkAllowed(ctx, "link1@localhost.localhost", ip)
+	require.NoError(t, err)
+	assert.True(t, emailAllowed)
+	assert.True(t, ipAllowed)
+
+	emailAllowed, ipAllowed, err = c.Throttle.LoginLinkAllowed(ctx, "LINK1@localhost.localhost", ip)
+	require.NoError(t, err)
+	assert.False(t, emailAllowed)
+	assert.True(t, ipAllowed)
+
+	emailAllowed, ipAllowed, err = c.Throttle.LoginLinkAllowed(ctx, "link2@localhost.localhost", ip)
+	require.NoError(t, err)
+	assert.True(t, emailAllowed)
+	assert.False(t, ipAllowed)
+}
+
+func TestThrottleClient_VerificationResendAllowed(t *testing.T) {
+	cfg := &c.Config.App.VerificationResendThrottle
+	original := *cfg
+	defer func() {
+		*cfg = original
+	}()
+	cfg.Window = time.Minute
+	cfg.MaxAttempts = 1
+
+	allowed, err := c.Throttle.VerificationResendAllowed(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = c.Throttle.VerificationResendAllowed(context.Background(), usr.ID)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
@@ -17,6 +17,11 @@ func NewValidator() *Validator {
 	}
 }
 
+// RegisterPasswordPolicy registers the validation tags of a given password policy
+func (v *Validator) RegisterPasswordPolicy(policy *PasswordPolicy) error {
+	return policy.Register(v.validator)
+}
+
 // Validate validates a struct
 func (v *Validator) Validate(i any) error {
 	if err := v.validator.Struct(i); err != nil {
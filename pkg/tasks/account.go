@@ -0,0 +1,129 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mikestefanello/backlite"
+	"github.com/mikestefanello/pagoda/ent"
+	"github.com/mikestefanello/pagoda/pkg/log"
+	"github.com/mikestefanello/pagoda/pkg/services"
+)
+
+// DataExportTask exports the personal data of a user and emails them a link to download it
+type DataExportTask struct {
+	UserID int
+}
+
+// Config satisfies the backlite.Task interface by providing configuration for the queue that these items will be
+// placed into for execution.
+func (t DataExportTask) Config() backlite.QueueConfig {
+	return backlite.QueueConfig{
+		Name:        "DataExportTask",
+		MaxAttempts: 3,
+		Timeout:     time.Minute,
+		Backoff:     time.Minute,
+		Retention: &backlite.Retention{
+			Duration:   24 * time.Hour,
+			OnlyFailed: true,
+		},
+	}
+}
+
+// NewDataExportTaskQueue provides a Queue that can process DataExportTask tasks.
+// Expired exports are deleted each time a new export is processed.
+func NewDataExportTaskQueue(c *services.Container) backlite.Queue {
+	return backlite.NewQueue[DataExportTask](func(ctx context.Context, task DataExportTask) error {
+		if n, err := c.Account.DeleteExpiredDataExports(ctx); err != nil {
+			log.Default().Error("unable to delete expired data exports",
+				"error", err,
+			)
+		} else if n > 0 {
+			log.Default().Info("deleted expired data exports",
+				"count", n,
+			)
+		}
+
+		u, err := c.ORM.User.Get(ctx, task.UserID)
+		switch {
+		case err == nil:
+		case ent.IsNotFound(err):
+			return nil
+		default:
+			return err
+		}
+
+		token, _, err := c.Account.CreateDataExport(ctx, u.ID)
+		if err != nil {
+			return err
+		}
+
+		log.Default().Info("data export created",
+			"user_id", u.ID,
+		)
+
+		url := c.Web.Reverse("account.export.download", token)
+		return c.Mail.
+			Compose().
+			To(u.Email).
+			Subject("Your data export is ready").
+			Body(fmt.Sprintf(
+				"Your data export is ready. Go here to download it: %s\n\nThe link expires in %s.",
+				url,
+				c.Config.App.DataExport.Expiration,
+			)).
+			SendContext(ctx)
+	})
+}
+
+// AccountDeletionTask deletes the account of a user once the grace period of their deletion request has passed
+type AccountDeletionTask struct {
+	UserID int
+}
+
+// Config satisfies the backlite.Task interface by providing configuration for the queue that these items will be
+// placed into for execution.
+func (t AccountDeletionTask) Config() backlite.QueueConfig {
+	return backlite.QueueConfig{
+		Name:        "AccountDeletionTask",
+		MaxAttempts: 5,
+		Timeout:     time.Minute,
+		Backoff:     time.Hour,
+		Retention: &backlite.Retention{
+			Duration:   7 * 24 * time.Hour,
+			OnlyFailed: true,
+		},
+	}
+}
+
+// NewAccountDeletionTaskQueue provides a Queue that can process AccountDeletionTask tasks.
+// If the user cancelled the deletion, or requested it again later, the task does nothing since another task
+// will have been scheduled.
+func NewAccountDeletionTaskQueue(c *services.Container) backlite.Queue {
+	return backlite.NewQueue[AccountDeletionTask](func(ctx context.Context, task AccountDeletionTask) error {
+		u, err := c.ORM.User.Get(ctx, task.UserID)
+		switch {
+		case err == nil:
+		case ent.IsNotFound(err):
+			return nil
+		default:
+			return err
+		}
+
+		if !c.Account.IsDeletionDue(u) {
+			return nil
+		}
+
+		if err = c.Account.Delete(ctx, u.ID); err != nil {
+			return err
+		}
+
+		log.Default().Info("account deleted",
+			"user_id", u.ID,
+			"mode", c.Config.App.AccountDeletion.Mode,
+		)
+
+		return nil
+	})
+}
@@ -0,0 +1,47 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"github.com/mikestefanello/backlite"
+	"github.com/mikestefanello/pagoda/pkg/log"
+	"github.com/mikestefanello/pagoda/pkg/services"
+)
+
+// auditLogRetentionInterval is how often the AuditLogRetentionTask is queued
+const auditLogRetentionInterval = 24 * time.Hour
+
+// AuditLogRetentionTask deletes audit events which are older than the configured retention period
+type AuditLogRetentionTask struct{}
+
+// Config satisfies the backlite.Task interface by providing configuration for the queue that these items will be
+// placed into for execution.
+func (t AuditLogRetentionTask) Config() backlite.QueueConfig {
+	return backlite.QueueConfig{
+		Name:        "AuditLogRetentionTask",
+		MaxAttempts: 3,
+		Timeout:     5 * time.Minute,
+		Backoff:     time.Minute,
+		Retention: &backlite.Retention{
+			Duration:   24 * time.Hour,
+			OnlyFailed: true,
+		},
+	}
+}
+
+// NewAuditLogRetentionTaskQueue provides a Queue that can process AuditLogRetentionTask tasks
+func NewAuditLogRetentionTaskQueue(c *services.Container) backlite.Queue {
+	return backlite.NewQueue[AuditLogRetentionTask](func(ctx context.Context, task AuditLogRetentionTask) error {
+		n, err := c.Audit.DeleteExpired(ctx)
+		if err != nil {
+			return err
+		}
+
+		log.Default().Info("deleted expired audit events",
+			"count", n,
+		)
+
+		return nil
+	})
+}
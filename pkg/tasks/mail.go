@@ -0,0 +1,11 @@
+package tasks
+
+import (
+	"github.com/mikestefanello/backlite"
+	"github.com/mikestefanello/pagoda/pkg/services"
+)
+
+// NewMailTaskQueue provides a Queue that can process services.MailTask tasks, which deliver queued email
+func NewMailTaskQueue(c *services.Container) backlite.Queue {
+	return backlite.NewQueue[services.MailTask](c.Mail.Deliver)
+}
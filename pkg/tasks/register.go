@@ -1,10 +1,48 @@
 package tasks
 
 import (
+	"context"
+	"time"
+
+	"github.com/mikestefanello/backlite"
+	"github.com/mikestefanello/pagoda/pkg/log"
 	"github.com/mikestefanello/pagoda/pkg/services"
 )
 
 // Register registers all task queues with the task client
 func Register(c *services.Container) {
 	c.Tasks.Register(NewExampleTaskQueue(c))
+	c.Tasks.Register(NewDataExportTaskQueue(c))
+	c.Tasks.Register(NewAccountDeletionTaskQueue(c))
+	c.Tasks.Register(NewAuditLogRetentionTaskQueue(c))
+	c.Tasks.Register(NewMailTaskQueue(c))
+}
+
+// Schedule queues all recurring tasks immediately, and again each time their interval passes, until the given
+// context is cancelled.
+// Since each running instance of the application schedules these, recurring tasks must be safe to run more often
+// than their interval.
+func Schedule(ctx context.Context, c *services.Container) {
+	go every(ctx, c, auditLogRetentionInterval, AuditLogRetentionTask{})
+}
+
+// every queues a given task immediately and then each time a given interval passes, until the context is cancelled
+func every(ctx context.Context, c *services.Container, interval time.Duration, task backlite.Task) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Tasks.Add(task).Save(); err != nil {
+			log.Default().Error("unable to queue recurring task",
+				"task", task.Config().Name,
+				"error", err,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
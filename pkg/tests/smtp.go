@@ -0,0 +1,323 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type (
+	// SMTPServer is an in-process SMTP server which captures the messages sent to it, for testing code which sends
+	// email without a real mail server. It supports STARTTLS and implicit TLS, when given a TLS config, and PLAIN
+	// authentication, when given credentials.
+	SMTPServer struct {
+		// TLSConfig enables STARTTLS, or is used for every connection if ImplicitTLS is set
+		TLSConfig *tls.Config
+
+		// ImplicitTLS requires connections to use TLS from the start, rather than upgrading via STARTTLS
+		ImplicitTLS bool
+
+		// Username and Password, if set, are required to authenticate before sending
+		Username string
+		Password string
+
+		listener    net.Listener
+		mu          sync.Mutex
+		messages    []SMTPMessage
+		connections int
+	}
+
+	// SMTPMessage is a message captured by the SMTPServer
+	SMTPMessage struct {
+		From string
+		To   []string
+		Data []byte
+	}
+)
+
+// Start starts listening on a random local port and closes the server once the test completes
+func (s *SMTPServer) Start(t *testing.T) {
+	var err error
+	if s.ImplicitTLS {
+		s.listener, err = tls.Listen("tcp", "127.0.0.1:0", s.TLSConfig)
+	} else {
+		s.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+	if err != nil {
+		t.Fatalf("failed to start SMTP server: %v", err)
+	}
+
+	go func() {
+		for {
+			conn, err := s.listener.Accept()
+			if err != nil {
+				return
+			}
+
+			s.mu.Lock()
+			s.connections++
+			s.mu.Unlock()
+
+			go func() {
+				defer conn.Close()
+				s.serve(conn)
+			}()
+		}
+	}()
+
+	t.Cleanup(s.Close)
+}
+
+// Close stops the server
+func (s *SMTPServer) Close() {
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+}
+
+// Host returns the host the server is listening on
+func (s *SMTPServer) Host() string {
+	return s.listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+// Port returns the port the server is listening on
+func (s *SMTPServer) Port() uint16 {
+	return uint16(s.listener.Addr().(*net.TCPAddr).Port)
+}
+
+// Messages returns the messages captured so far
+func (s *SMTPServer) Messages() []SMTPMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]SMTPMessage(nil), s.messages...)
+}
+
+// Connections returns the amount of connections accepted so far
+func (s *SMTPServer) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+// serve handles the SMTP session of a given connection
+func (s *SMTPServer) serve(conn net.Conn) {
+	text := textproto.NewConn(conn)
+	reply := func(code int, msg string) error {
+		return text.PrintfLine("%d %s", code, msg)
+	}
+
+	var (
+		from          string
+		to            []string
+		authenticated = s.Username == ""
+		isTLS         = s.ImplicitTLS
+	)
+
+	if reply(220, "localhost test SMTP server") != nil {
+		return
+	}
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		cmd, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(cmd) {
+		case "EHLO", "HELO":
+			ext := []string{"250-localhost", "250-8BITMIME"}
+			if s.TLSConfig != nil && !isTLS {
+				ext = append(ext, "250-STARTTLS")
+			}
+			if s.Username != "" {
+				ext = append(ext, "250-AUTH PLAIN")
+			}
+			ext = append(ext, "250 SMTPUTF8")
+			err = text.PrintfLine("%s", strings.Join(ext, "\r\n"))
+
+		case "STARTTLS":
+			if s.TLSConfig == nil || isTLS {
+				err = reply(502, "STARTTLS not available")
+				break
+			}
+			if err = reply(220, "ready to start TLS"); err != nil {
+				return
+			}
+			tlsConn := tls.Server(conn, s.TLSConfig)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+			conn, isTLS = tlsConn, true
+			text = textproto.NewConn(conn)
+			from, to = "", nil
+
+		case "AUTH":
+			err = s.auth(text, arg, &authenticated)
+
+		case "MAIL":
+			if !authenticated {
+				err = reply(530, "authentication required")
+				break
+			}
+			from = parsePath(arg, "FROM:")
+			to = nil
+			err = reply(250, "OK")
+
+		case "RCPT":
+			to = append(to, parsePath(arg, "TO:"))
+			err = reply(250, "OK")
+
+		case "DATA":
+			if from == "" || len(to) == 0 {
+				err = reply(503, "bad sequence of commands")
+				break
+			}
+			if err = reply(354, "end data with <CR><LF>.<CR><LF>"); err != nil {
+				return
+			}
+			var data []byte
+			if data, err = readData(text.R); err != nil {
+				return
+			}
+
+			s.mu.Lock()
+			s.messages = append(s.messages, SMTPMessage{From: from, To: to, Data: data})
+			s.mu.Unlock()
+
+			from, to = "", nil
+			err = reply(250, "OK: queued")
+
+		case "RSET":
+			from, to = "", nil
+			err = reply(250, "OK")
+
+		case "NOOP":
+			err = reply(250, "OK")
+
+		case "QUIT":
+			_ = reply(221, "bye")
+			return
+
+		default:
+			err = reply(502, "command not implemented")
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// auth handles the AUTH command, which only supports the PLAIN mechanism
+func (s *SMTPServer) auth(text *textproto.Conn, arg string, authenticated *bool) error {
+	mechanism, initial, _ := strings.Cut(arg, " ")
+	if !strings.EqualFold(mechanism, "PLAIN") || s.Username == "" {
+		return text.PrintfLine("504 unrecognized authentication type")
+	}
+
+	if initial == "" {
+		if err := text.PrintfLine("334 "); err != nil {
+			return err
+		}
+		line, err := text.ReadLine()
+		if err != nil {
+			return err
+		}
+		initial = line
+	}
+
+	creds, err := base64.StdEncoding.DecodeString(initial)
+	if err != nil {
+		return text.PrintfLine("501 invalid credentials encoding")
+	}
+
+	parts := strings.Split(string(creds), "\x00")
+	if len(parts) != 3 || parts[1] != s.Username || parts[2] != s.Password {
+		return text.PrintfLine("535 authentication failed")
+	}
+
+	*authenticated = true
+	return text.PrintfLine("235 authenticated")
+}
+
+// readData reads the message sent after the DATA command, up to the line containing a single dot, removing the
+// leading dot added to lines which start with one. Unlike textproto.Reader.ReadDotBytes, line endings are kept as
+// sent so messages can be compared byte for byte, such as to verify signatures.
+func readData(r *bufio.Reader) ([]byte, error) {
+	var data []byte
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		if string(line) == ".\r\n" {
+			return data, nil
+		}
+
+		data = append(data, bytes.TrimPrefix(line, []byte("."))...)
+	}
+}
+
+// parsePath extracts the address from the argument of a MAIL or RCPT command, such as "FROM:<a@example.com>"
+func parsePath(arg, prefix string) string {
+	if len(arg) >= len(prefix) && strings.EqualFold(arg[:len(prefix)], prefix) {
+		arg = arg[len(prefix):]
+	}
+	arg, _, _ = strings.Cut(strings.TrimSpace(arg), " ")
+	return strings.Trim(arg, "<>")
+}
+
+// NewTLSConfig generates a self-signed certificate for 127.0.0.1 and localhost, returning a TLS config for a server
+// using it, along with a pool containing it which clients can trust
+func NewTLSConfig(t *testing.T) (*tls.Config, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}, pool
+}
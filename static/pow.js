@@ -0,0 +1,131 @@
+// Solves the proof-of-work challenges of forms protected against bots, see form.BotProtection.
+// A solution is a number which, when appended to the challenge with a colon, results in a SHA-256 hash which starts
+// with the required amount of zero bits. Solving starts as soon as the form is loaded, and submitting the form is
+// delayed until it is solved.
+(function () {
+    const K = new Uint32Array([
+        0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+        0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+        0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+        0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+        0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+        0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+        0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+        0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+    ]);
+    const W = new Uint32Array(64);
+
+    // sha256 returns the first two words of the SHA-256 hash of given bytes, which is enough to count the leading
+    // zero bits for any reasonable difficulty
+    function sha256(bytes) {
+        const length = ((bytes.length + 9 + 63) >> 6) << 6;
+        const padded = new Uint8Array(length);
+        padded.set(bytes);
+        padded[bytes.length] = 0x80;
+        const view = new DataView(padded.buffer);
+        view.setUint32(length - 4, bytes.length * 8);
+
+        let h0 = 0x6a09e667, h1 = 0xbb67ae85, h2 = 0x3c6ef372, h3 = 0xa54ff53a;
+        let h4 = 0x510e527f, h5 = 0x9b05688c, h6 = 0x1f83d9ab, h7 = 0x5be0cd19;
+
+        for (let offset = 0; offset < length; offset += 64) {
+            for (let i = 0; i < 16; i++) {
+                W[i] = view.getUint32(offset + i * 4);
+            }
+            for (let i = 16; i < 64; i++) {
+                const w15 = W[i - 15], w2 = W[i - 2];
+                const s0 = ((w15 >>> 7) | (w15 << 25)) ^ ((w15 >>> 18) | (w15 << 14)) ^ (w15 >>> 3);
+                const s1 = ((w2 >>> 17) | (w2 << 15)) ^ ((w2 >>> 19) | (w2 << 13)) ^ (w2 >>> 10);
+                W[i] = W[i - 16] + s0 + W[i - 7] + s1;
+            }
+
+            let a = h0, b = h1, c = h2, d = h3, e = h4, f = h5, g = h6, h = h7;
+            for (let i = 0; i < 64; i++) {
+                const S1 = ((e >>> 6) | (e << 26)) ^ ((e >>> 11) | (e << 21)) ^ ((e >>> 25) | (e << 7));
+                const t1 = (h + S1 + ((e & f) ^ (~e & g)) + K[i] + W[i]) | 0;
+                const S0 = ((a >>> 2) | (a << 30)) ^ ((a >>> 13) | (a << 19)) ^ ((a >>> 22) | (a << 10));
+                const t2 = (S0 + ((a & b) ^ (a & c) ^ (b & c))) | 0;
+                h = g; g = f; f = e; e = (d + t1) | 0;
+                d = c; c = b; b = a; a = (t1 + t2) | 0;
+            }
+
+            h0 = (h0 + a) | 0; h1 = (h1 + b) | 0; h2 = (h2 + c) | 0; h3 = (h3 + d) | 0;
+            h4 = (h4 + e) | 0; h5 = (h5 + f) | 0; h6 = (h6 + g) | 0; h7 = (h7 + h) | 0;
+        }
+
+        return [h0 >>> 0, h1 >>> 0];
+    }
+
+    // leadingZeros counts the leading zero bits of the first two words of a hash
+    function leadingZeros(words) {
+        return words[0] === 0 ? 32 + Math.clz32(words[1]) : Math.clz32(words[0]);
+    }
+
+    // solve finds the solution to the challenge of a given input, in batches so the page remains responsive
+    function solve(input) {
+        const encoder = new TextEncoder();
+        const challenge = input.dataset.powChallenge + ':';
+        const difficulty = parseInt(input.dataset.powDifficulty, 10);
+        let n = 0;
+
+        return new Promise(function (resolve) {
+            (function batch() {
+                for (const end = n + 5000; n < end; n++) {
+                    if (leadingZeros(sha256(encoder.encode(challenge + n))) >= difficulty) {
+                        resolve(String(n));
+                        return;
+                    }
+                }
+                setTimeout(batch, 0);
+            })();
+        });
+    }
+
+    // start starts solving the challenge of a given input, once
+    function start(input) {
+        if (input.powSolution) {
+            return;
+        }
+        input.powSolution = solve(input).then(function (solution) {
+            input.value = solution;
+            input.dataset.powSolved = 'true';
+        });
+    }
+
+    function startAll(root) {
+        root.querySelectorAll('input[data-pow-challenge]').forEach(start);
+    }
+
+    document.addEventListener('DOMContentLoaded', function () {
+        startAll(document);
+    });
+
+    // Forms can be added to the page by HTMX
+    document.addEventListener('htmx:load', function (evt) {
+        startAll(evt.detail.elt);
+    });
+
+    // Delay submitting forms until their challenge is solved. This listens during the capture phase so it runs prior
+    // to HTMX handling the submission.
+    document.addEventListener('submit', function (evt) {
+        const input = evt.target.querySelector('input[data-pow-challenge]:not([data-pow-solved])');
+        if (!input) {
+            return;
+        }
+
+        evt.preventDefault();
+        evt.stopImmediatePropagation();
+
+        // Only submit once, no matter how many times the form was submitted while waiting
+        if (input.powPending) {
+            return;
+        }
+        input.powPending = true;
+
+        start(input);
+        input.powSolution.then(function () {
+            input.powPending = false;
+            evt.target.requestSubmit(evt.submitter);
+        });
+    }, true);
+})();
@@ -21,17 +21,34 @@ const (
 )
 
 const (
-	PageAbout          Page = "about"
-	PageCache          Page = "cache"
-	PageContact        Page = "contact"
-	PageError          Page = "error"
-	PageForgotPassword Page = "forgot-password"
-	PageHome           Page = "home"
-	PageLogin          Page = "login"
-	PageRegister       Page = "register"
-	PageResetPassword  Page = "reset-password"
-	PageSearch         Page = "search"
-	PageTask           Page = "task"
+	PageAbout               Page = "about"
+	PageAccount             Page = "account"
+	PageAdmin               Page = "admin"
+	PageAdminAudit          Page = "admin-audit"
+	PageAdminEdit           Page = "admin-edit"
+	PageAdminInviteCodes    Page = "admin-invite-codes"
+	PageAdminList           Page = "admin-list"
+	PageCache               Page = "cache"
+	PageContact             Page = "contact"
+	PageDevMail             Page = "dev-mail"
+	PageDevMailMessage      Page = "dev-mail-message"
+	PageError               Page = "error"
+	PageForgotPassword      Page = "forgot-password"
+	PageHome                Page = "home"
+	PageInvitation          Page = "invitation"
+	PageLogin               Page = "login"
+	PageLoginLink           Page = "login-link"
+	PageMailPreferences     Page = "mail-preferences"
+	PageOrganizationMembers Page = "organization-members"
+	PageOrganizations       Page = "organizations"
+	PageReauthenticate      Page = "reauthenticate"
+	PageRegister            Page = "register"
+	PageResetPassword       Page = "reset-password"
+	PageSearch              Page = "search"
+	PageSecureAccount       Page = "secure-account"
+	PageTask                Page = "task"
+	PageTokens              Page = "tokens"
+	PageVerifyEmail         Page = "verify-email"
 )
 
 //go:embed *